@@ -196,6 +196,21 @@ type ClusterSpec struct {
 	//
 	// +optional
 	Backup *ClusterBackup `json:"backup,omitempty"`
+
+	// Specifies the maintenance window of the Cluster.
+	//
+	// The disruptive OpsRequests, such as Restart, VerticalScaling, Upgrade and Reconfiguring of static parameters,
+	// are only started within the window. Those created outside the window stay Pending until the window opens,
+	// unless `force` is set for them. The other OpsRequests, such as Expose and Backup, are not affected.
+	//
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// Specifies how the Cluster reacts when the data-plane k8s clusters it is placed on become unavailable.
+	// It only takes effect in the multi-cluster mode.
+	//
+	// +optional
+	Placement *ClusterPlacement `json:"placement,omitempty"`
 }
 
 // ClusterStatus defines the observed state of the Cluster.
@@ -235,6 +250,12 @@ type ClusterStatus struct {
 	//
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Records the data-plane k8s clusters the Cluster is placed on, and the failover state of the unavailable ones.
+	// It is only present in the multi-cluster mode.
+	//
+	// +optional
+	Placement *ClusterPlacementStatus `json:"placement,omitempty"`
 }
 
 // TerminationPolicyType defines termination policy types.
@@ -624,6 +645,121 @@ type ClusterService struct {
 	ComponentSelector string `json:"componentSelector,omitempty"`
 }
 
+// MaintenanceWindow defines a recurring time window in which the disruptive changes are allowed.
+type MaintenanceWindow struct {
+	// The cron expression for the start of the window, e.g. "0 2 * * 6" for 02:00 every Saturday.
+	// See https://en.wikipedia.org/wiki/Cron.
+	// The time zone can be specified by the prefix "CRON_TZ=" as well, e.g. "CRON_TZ=Asia/Shanghai 0 2 * * 6",
+	// which takes precedence over the `timeZone`.
+	//
+	// +kubebuilder:validation:Required
+	CronExpression string `json:"cronExpression"`
+
+	// The duration of the window, e.g. "2h".
+	//
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// The IANA time zone the `cronExpression` is evaluated in, e.g. "Asia/Shanghai". It defaults to UTC.
+	//
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// PlacementFailoverPolicy defines what to do with the instances on an unavailable data-plane k8s cluster.
+//
+// +enum
+// +kubebuilder:validation:Enum={Wait,RePlace}
+type PlacementFailoverPolicy string
+
+const (
+	// WaitPlacementFailoverPolicy keeps the instances on the unavailable data-plane k8s cluster and waits for it to recover.
+	WaitPlacementFailoverPolicy PlacementFailoverPolicy = "Wait"
+
+	// RePlacePlacementFailoverPolicy re-places the instances on the unavailable data-plane k8s cluster to an available one,
+	// once it has been unavailable for longer than the grace period.
+	RePlacePlacementFailoverPolicy PlacementFailoverPolicy = "RePlace"
+)
+
+// ClusterPlacement defines the placement policy of the Cluster across the data-plane k8s clusters.
+type ClusterPlacement struct {
+	// Specifies what to do with the instances on a data-plane k8s cluster that becomes unavailable.
+	//
+	// - `Wait`: keeps the instances where they are and waits for the data-plane k8s cluster to recover.
+	// - `RePlace`: replaces the unavailable data-plane k8s cluster with an available one that the Cluster is not
+	//   placed on yet, once it has been unavailable for longer than `gracePeriod`.
+	//   The instances are re-created there with new volumes, the data on the unavailable one is not moved.
+	//
+	// +kubebuilder:default=Wait
+	// +optional
+	FailoverPolicy PlacementFailoverPolicy `json:"failoverPolicy,omitempty"`
+
+	// The duration that a data-plane k8s cluster should be unavailable for before the instances on it are re-placed,
+	// e.g. "10m". It defaults to 5 minutes.
+	//
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// DataClusterFailoverPhase defines the failover phase of an unavailable data-plane k8s cluster.
+//
+// +enum
+// +kubebuilder:validation:Enum={Waiting,RePlaced}
+type DataClusterFailoverPhase string
+
+const (
+	// WaitingDataClusterFailoverPhase indicates that the data-plane k8s cluster is unavailable,
+	// and the instances on it are waiting for it to recover or to be re-placed.
+	WaitingDataClusterFailoverPhase DataClusterFailoverPhase = "Waiting"
+
+	// RePlacedDataClusterFailoverPhase indicates that the instances on the unavailable data-plane k8s cluster
+	// have been re-placed to another one.
+	RePlacedDataClusterFailoverPhase DataClusterFailoverPhase = "RePlaced"
+)
+
+// ClusterPlacementStatus records the placement of the Cluster across the data-plane k8s clusters.
+type ClusterPlacementStatus struct {
+	// The data-plane k8s clusters the Cluster is placed on.
+	//
+	// +optional
+	Contexts []string `json:"contexts,omitempty"`
+
+	// The failover state of the data-plane k8s clusters that are unavailable.
+	//
+	// +listType=map
+	// +listMapKey=context
+	// +optional
+	Failovers []DataClusterFailoverStatus `json:"failovers,omitempty"`
+}
+
+// DataClusterFailoverStatus records the failover state of an unavailable data-plane k8s cluster.
+type DataClusterFailoverStatus struct {
+	// The context of the unavailable data-plane k8s cluster.
+	//
+	// +kubebuilder:validation:Required
+	Context string `json:"context"`
+
+	// The failover phase of the data-plane k8s cluster.
+	//
+	// +optional
+	Phase DataClusterFailoverPhase `json:"phase,omitempty"`
+
+	// The time since when the data-plane k8s cluster has been unavailable.
+	//
+	// +optional
+	UnavailableSince *metav1.Time `json:"unavailableSince,omitempty"`
+
+	// The data-plane k8s cluster that the instances have been re-placed to.
+	//
+	// +optional
+	RePlacedBy string `json:"rePlacedBy,omitempty"`
+
+	// Provides additional information about the failover.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 type ClusterBackup struct {
 	// Specifies whether automated backup is enabled for the Cluster.
 	//
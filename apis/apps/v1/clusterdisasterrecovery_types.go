@@ -0,0 +1,330 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories={kubeblocks},shortName=cdr
+// +kubebuilder:printcolumn:name="PRIMARY",type="string",JSONPath=".status.primaryCluster",description="the current primary cluster"
+// +kubebuilder:printcolumn:name="STANDBY",type="string",JSONPath=".status.standbyCluster",description="the current standby cluster"
+// +kubebuilder:printcolumn:name="LAG",type="integer",JSONPath=".status.replicationLagSeconds",description="replication lag in seconds"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.phase",description="status phase"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterDisasterRecovery is the Schema for the clusterdisasterrecoveries API.
+//
+// It links a primary Cluster with a standby Cluster that continuously replicates from the primary.
+// The standby Cluster is provisioned from the latest Backup of the primary Cluster, and the replication is
+// established and monitored through the `standbyReplicate`, `standbyReplicationLag` and `standbyPromote`
+// lifecycle actions of the replicated components.
+//
+// A planned switchover is requested by annotating the object with `apps.kubeblocks.io/dr-switchover: "true"`,
+// it fences the primary, waits for the standby to catch up, promotes the standby and demotes the former primary
+// into the new standby. It's aborted and the primary is switched back to read-write if the standby can't be promoted
+// within `spec.switchoverTimeoutSeconds`.
+// An emergency failover is requested by annotating the object with `apps.kubeblocks.io/dr-failover: "true"`,
+// it fences the primary best-effort and promotes the standby without waiting for the primary. The former primary
+// can't be promoted again until it replicates from the new primary.
+type ClusterDisasterRecovery struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterDisasterRecoverySpec   `json:"spec,omitempty"`
+	Status ClusterDisasterRecoveryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterDisasterRecoveryList contains a list of ClusterDisasterRecovery.
+type ClusterDisasterRecoveryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterDisasterRecovery `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterDisasterRecovery{}, &ClusterDisasterRecoveryList{})
+}
+
+// ClusterDisasterRecoverySpec defines the desired state of ClusterDisasterRecovery.
+type ClusterDisasterRecoverySpec struct {
+	// Specifies the name of the Cluster that is the primary initially, in the same namespace.
+	//
+	// This field is immutable.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="primaryCluster is immutable"
+	PrimaryCluster string `json:"primaryCluster"`
+
+	// Specifies the standby Cluster.
+	//
+	// +kubebuilder:validation:Required
+	Standby StandbyClusterSpec `json:"standby"`
+
+	// Specifies the names of the components to replicate from the primary Cluster to the standby Cluster.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Components []string `json:"components"`
+
+	// Specifies the interval in seconds to probe the replication lag of the standby Cluster.
+	//
+	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LagProbePeriodSeconds int32 `json:"lagProbePeriodSeconds,omitempty"`
+
+	// Specifies the maximum replication lag in seconds that the standby Cluster can be promoted with
+	// in a planned switchover. The switchover waits for the standby Cluster to catch up until then.
+	//
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SwitchoverMaxLagSeconds int64 `json:"switchoverMaxLagSeconds,omitempty"`
+
+	// Specifies the timeout in seconds of a planned switchover before the standby Cluster is promoted.
+	// Once it's exceeded, the switchover is aborted and the primary Cluster is switched back to read-write.
+	//
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SwitchoverTimeoutSeconds int32 `json:"switchoverTimeoutSeconds,omitempty"`
+
+	// Specifies the name of the component service that the standby Cluster replicates from the primary Cluster through,
+	// as defined in `spec.services` of the ComponentDefinition, e.g., a LoadBalancer service that exposes the component
+	// to the other data clusters.
+	//
+	// The host to replicate from is the ingress of the service if it's a LoadBalancer service,
+	// otherwise the FQDN of the service, which is reachable only if both Clusters are placed on the same data cluster.
+	// The default service of the component is used if it's not specified.
+	//
+	// +optional
+	ReplicationService string `json:"replicationService,omitempty"`
+}
+
+// StandbyClusterSpec defines the standby Cluster.
+type StandbyClusterSpec struct {
+	// Specifies the name of the standby Cluster, in the same namespace.
+	//
+	// The Cluster is created from the Backup of the primary Cluster if it doesn't exist.
+	//
+	// This field is immutable.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterName is immutable"
+	ClusterName string `json:"clusterName"`
+
+	// Specifies the data clusters to place the standby Cluster on, when the multi-cluster management is enabled.
+	// It's recommended to use the data clusters that the primary Cluster is not placed on.
+	//
+	// +optional
+	Placement []string `json:"placement,omitempty"`
+
+	// Specifies the name of the Backup to provision the standby Cluster from.
+	// The latest completed Backup of the primary Cluster is used if it's not specified.
+	//
+	// +optional
+	BackupName string `json:"backupName,omitempty"`
+}
+
+// ClusterDisasterRecoveryPhase defines the phase of the ClusterDisasterRecovery.
+//
+// +enum
+// +kubebuilder:validation:Enum={Provisioning,Replicating,SwitchingOver,FailingOver,Failed}
+type ClusterDisasterRecoveryPhase string
+
+const (
+	// DRProvisioningPhase indicates that the standby Cluster is being provisioned or the replication is being established.
+	DRProvisioningPhase ClusterDisasterRecoveryPhase = "Provisioning"
+
+	// DRReplicatingPhase indicates that the standby Cluster is replicating from the primary Cluster.
+	DRReplicatingPhase ClusterDisasterRecoveryPhase = "Replicating"
+
+	// DRSwitchingOverPhase indicates that a planned switchover is in progress.
+	DRSwitchingOverPhase ClusterDisasterRecoveryPhase = "SwitchingOver"
+
+	// DRFailingOverPhase indicates that an emergency failover is in progress.
+	DRFailingOverPhase ClusterDisasterRecoveryPhase = "FailingOver"
+
+	// DRFailedPhase indicates that the standby Cluster can't be provisioned.
+	DRFailedPhase ClusterDisasterRecoveryPhase = "Failed"
+)
+
+// ClusterDisasterRecoveryStatus defines the observed state of ClusterDisasterRecovery.
+type ClusterDisasterRecoveryStatus struct {
+	// Specifies the most recent generation observed for this ClusterDisasterRecovery object.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The current phase of the ClusterDisasterRecovery.
+	//
+	// +optional
+	Phase ClusterDisasterRecoveryPhase `json:"phase,omitempty"`
+
+	// Provides additional information about the current phase.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The name of the Cluster that serves as the primary currently.
+	//
+	// +optional
+	PrimaryCluster string `json:"primaryCluster,omitempty"`
+
+	// The name of the Cluster that serves as the standby currently.
+	//
+	// +optional
+	StandbyCluster string `json:"standbyCluster,omitempty"`
+
+	// The name of the Backup that the standby Cluster was provisioned from.
+	//
+	// +optional
+	BackupName string `json:"backupName,omitempty"`
+
+	// The maximum replication lag in seconds of the replicated components.
+	//
+	// +optional
+	ReplicationLagSeconds *int64 `json:"replicationLagSeconds,omitempty"`
+
+	// The replication status of each replicated component.
+	//
+	// +optional
+	Components []ComponentReplicationStatus `json:"components,omitempty"`
+
+	// The in-progress planned switchover or emergency failover.
+	//
+	// +optional
+	Transition *DRTransitionStatus `json:"transition,omitempty"`
+
+	// The time when the primary Cluster was changed last time.
+	//
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Whether the standby Cluster is the former primary Cluster demoted by an emergency failover,
+	// and has not replicated from the current primary Cluster since then.
+	// Such a standby Cluster may have diverged from the current primary Cluster,
+	// so it can't be promoted until the replication is re-established.
+	//
+	// +optional
+	StandbyDiverged bool `json:"standbyDiverged,omitempty"`
+
+	// Represents the latest available observations of the ClusterDisasterRecovery's current state.
+	//
+	// The `Transition` condition reports the result of the last planned switchover or emergency failover.
+	//
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// ComponentReplicationStatus records the replication status of a component.
+type ComponentReplicationStatus struct {
+	// The name of the component.
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Whether the replication from the primary Cluster has been established by the `standbyReplicate` action.
+	//
+	// +optional
+	Replicating bool `json:"replicating,omitempty"`
+
+	// The replication lag in seconds reported by the `standbyReplicationLag` action.
+	//
+	// +optional
+	ReplicationLagSeconds *int64 `json:"replicationLagSeconds,omitempty"`
+
+	// The time when the replication lag was probed last time.
+	//
+	// +optional
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+
+	// Provides the error of the last action called, if any.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DRTransitionType defines the type of the transition between the primary and standby Clusters.
+//
+// +enum
+// +kubebuilder:validation:Enum={Switchover,Failover}
+type DRTransitionType string
+
+const (
+	DRSwitchoverTransition DRTransitionType = "Switchover"
+	DRFailoverTransition   DRTransitionType = "Failover"
+)
+
+const (
+	// ConditionTypeDRTransition reports the result of the last planned switchover or emergency failover.
+	ConditionTypeDRTransition = "Transition"
+
+	ReasonDRSwitchoverSucceeded = "SwitchoverSucceeded"
+	ReasonDRFailoverSucceeded   = "FailoverSucceeded"
+	ReasonDRSwitchoverTimeout   = "SwitchoverTimeout"
+)
+
+// DRTransitionStep defines the step of the transition.
+//
+// +enum
+// +kubebuilder:validation:Enum={Fencing,CatchingUp,Promoting}
+type DRTransitionStep string
+
+const (
+	// DRFencingStep switches the primary Cluster into read-only to stop accepting writes.
+	DRFencingStep DRTransitionStep = "Fencing"
+
+	// DRCatchingUpStep waits for the replication lag of the standby Cluster to drop below the limit.
+	DRCatchingUpStep DRTransitionStep = "CatchingUp"
+
+	// DRPromotingStep promotes the standby Cluster to be the primary.
+	DRPromotingStep DRTransitionStep = "Promoting"
+)
+
+// DRTransitionStatus records the progress of a planned switchover or an emergency failover.
+type DRTransitionStatus struct {
+	// The type of the transition.
+	//
+	// +kubebuilder:validation:Required
+	Type DRTransitionType `json:"type"`
+
+	// The current step of the transition.
+	//
+	// +kubebuilder:validation:Required
+	Step DRTransitionStep `json:"step"`
+
+	// The time when the transition was started.
+	//
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
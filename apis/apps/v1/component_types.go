@@ -327,6 +327,44 @@ type ComponentStatus struct {
 	//
 	// +optional
 	Message map[string]string `json:"message,omitempty"`
+
+	// Provides the status of the Component on each data-plane k8s cluster, it's set only in the multi-cluster mode.
+	//
+	// +listType=map
+	// +listMapKey=context
+	// +optional
+	DataClusters []ComponentDataClusterStatus `json:"dataClusters,omitempty"`
+}
+
+// ComponentDataClusterStatus represents the status of the Component on a data-plane k8s cluster.
+type ComponentDataClusterStatus struct {
+	// The context of the data-plane k8s cluster.
+	//
+	// +kubebuilder:validation:Required
+	Context string `json:"context"`
+
+	// The number of replicas on the data-plane k8s cluster.
+	Replicas int32 `json:"replicas"`
+
+	// The number of ready replicas on the data-plane k8s cluster.
+	//
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// The number of available replicas on the data-plane k8s cluster.
+	//
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// The number of replicas of each role on the data-plane k8s cluster, keyed by the role name.
+	//
+	// +optional
+	Roles map[string]int32 `json:"roles,omitempty"`
+
+	// The last error met when accessing the data-plane k8s cluster, it's empty if the data-plane k8s cluster is healthy.
+	//
+	// +optional
+	LastError string `json:"lastError,omitempty"`
 }
 
 type Sidecar struct {
@@ -1506,6 +1506,23 @@ type ComponentLifecycleActions struct {
 	// +optional
 	AvailableProbe *Probe `json:"availableProbe,omitempty"`
 
+	// Defines the custom probes which are invoked regularly to report custom metrics of each replica,
+	// such as the number of connections or the queries per second.
+	//
+	// The kb-agent reports the output of each probe periodically as an event whose reason is the name of the probe,
+	// and the MetricsScaler can scale the component by it through a metric source of type Probe.
+	//
+	// Expected action output:
+	// - On Success: The value of the metric, as a number or quantity, e.g., "100" or "1.5k".
+	// - On Failure: An error message, if applicable, indicating why the action failed.
+	//
+	// Note: This field is immutable once it has been set.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	CustomProbes []CustomProbe `json:"customProbes,omitempty"`
+
 	// Defines the procedure for a controlled transition of leadership from the current leader to a new replica.
 	// This approach aims to minimize downtime and maintain availability in systems with a leader-follower topology,
 	// during events such as planned maintenance or when performing stop, shutdown, restart, or upgrade operations
@@ -1637,6 +1654,14 @@ type ComponentLifecycleActions struct {
 	// The output should be a valid data dump streamed to stdout. It must exclude any irrelevant information to ensure
 	// that only the necessary data is exported for import into the new replica.
 	//
+	// The action can optionally report the expected size of the data dump in bytes by writing a line
+	// `KB_DATA_DUMP_SIZE=<bytes>` to stderr, which is used to estimate the progress of loading the data.
+	//
+	// The transfer interrupted is resumed by running the action again and skipping the data that has been
+	// transferred, so the output should be deterministic to make the transfer resumable.
+	// The data skipped is verified against the checksum of the data transferred, and the transfer fails
+	// instead of being resumed if they mismatch.
+	//
 	// The container executing this action has access to following environment variables:
 	//
 	// - KB_TARGET_POD_NAME: The name of the replica pod into which the data will be loaded.
@@ -1666,13 +1691,41 @@ type ComponentLifecycleActions struct {
 
 	// Defines the procedure that update a replica with new configuration.
 	//
-	// Note: This field is immutable once it has been set.
+	// Use Case:
+	// This action is invoked to reload the configuration dynamically when the dynamic parameters are updated.
+	// If defined, it takes precedence over the reload action of the config-manager when the updated parameters
+	// can be applied without restarting.
 	//
-	// This Action is reserved for future versions.
+	// The container executing this action has access to following variables:
+	//
+	// - KB_CONFIG_NAME: The name of the config whose parameters have been updated.
+	// - KB_CONFIG_PARAMETERS: The updated parameters and their new values, encoded as a JSON object.
+	//
+	// Expected action output:
+	// - On Failure: An error message, if applicable, indicating why the action failed.
+	//
+	// Note: This field is immutable once it has been set.
 	//
 	// +optional
 	Reconfigure *Action `json:"reconfigure,omitempty"`
 
+	// Defines the procedure to reload the TLS certificates of a replica.
+	//
+	// Use Case:
+	// This action is invoked on each replica after the TLS certificates issued by KubeBlocks are re-issued
+	// and synced to the pods, to make the database load the new certificates without restarting.
+	// If it's not defined, the replicas are restarted one by one to load the re-issued certificates.
+	//
+	// The certificates are mounted at the same path, and the action should be idempotent.
+	//
+	// Expected action output:
+	// - On Failure: An error message, if applicable, indicating why the action failed.
+	//
+	// Note: This field is immutable once it has been set.
+	//
+	// +optional
+	TLSReload *Action `json:"tlsReload,omitempty"`
+
 	// Defines the procedure to generate a new database account.
 	//
 	// Use Case:
@@ -1689,6 +1742,63 @@ type ComponentLifecycleActions struct {
 	//
 	// +optional
 	AccountProvision *Action `json:"accountProvision,omitempty"`
+
+	// Defines the procedure to replicate a standby replica from the primary Cluster of a ClusterDisasterRecovery.
+	//
+	// Use Case:
+	// This action is invoked on the standby Cluster once it has been provisioned, and on the former primary Cluster
+	// after it's demoted by a switchover or failover, to make the component continuously replicate from the
+	// same component of the primary Cluster. The action should be idempotent.
+	//
+	// The container executing this action has access to following variables:
+	//
+	// - KB_DR_PRIMARY_CLUSTER_NAME: The name of the primary Cluster.
+	// - KB_DR_PRIMARY_COMPONENT_NAME: The name of the component to replicate from in the primary Cluster.
+	// - KB_DR_PRIMARY_HOST: The host of the replication service of the component in the primary Cluster,
+	//   see `spec.replicationService` of the ClusterDisasterRecovery.
+	//
+	// Expected action output:
+	// - On Failure: An error message, if applicable, indicating why the action failed.
+	//
+	// Note: This field is immutable once it has been set.
+	//
+	// +optional
+	StandbyReplicate *Action `json:"standbyReplicate,omitempty"`
+
+	// Defines the procedure to report the replication lag of a standby replica.
+	//
+	// The container executing this action has access to following variables:
+	//
+	// - KB_DR_PRIMARY_CLUSTER_NAME: The name of the primary Cluster.
+	// - KB_DR_PRIMARY_COMPONENT_NAME: The name of the component to replicate from in the primary Cluster.
+	//
+	// Expected action output:
+	// - On Success: The replication lag in seconds, as an integer.
+	// - On Failure: An error message, if applicable, indicating why the action failed.
+	//
+	// Note: This field is immutable once it has been set.
+	//
+	// +optional
+	StandbyReplicationLag *Action `json:"standbyReplicationLag,omitempty"`
+
+	// Defines the procedure to promote a standby replica to be the primary.
+	//
+	// Use Case:
+	// This action is invoked on the standby Cluster of a ClusterDisasterRecovery in a planned switchover,
+	// after the primary Cluster has been switched to read-only and the standby has caught up,
+	// or in an emergency failover, when the primary Cluster may be unavailable.
+	//
+	// The container executing this action has access to following variables:
+	//
+	// - KB_DR_EMERGENCY: "true" if the promotion is caused by an emergency failover, otherwise "false".
+	//
+	// Expected action output:
+	// - On Failure: An error message, if applicable, indicating why the action failed.
+	//
+	// Note: This field is immutable once it has been set.
+	//
+	// +optional
+	StandbyPromote *Action `json:"standbyPromote,omitempty"`
 }
 
 // Action defines a customizable hook or procedure tailored for different database engines,
@@ -1927,6 +2037,19 @@ type Probe struct {
 	FailureThreshold int32 `json:"failureThreshold,omitempty"`
 }
 
+// CustomProbe defines a named probe whose output is reported periodically by the kb-agent.
+type CustomProbe struct {
+	// The name of the probe, it should be unique among the custom probes and should not be the same as
+	// any of the lifecycle actions.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern:=`^[a-z][a-zA-Z0-9]*$`
+	Name string `json:"name"`
+
+	Probe `json:",inline"`
+}
+
 // ActionAssertion defines the custom assertions for evaluating the success or failure of an action.
 type ActionAssertion struct {
 	// Whether the action should succeed or fail.
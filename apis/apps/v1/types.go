@@ -18,6 +18,8 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -46,6 +48,7 @@ const (
 	ConditionTypeApplyResources      = "ApplyResources"      // ConditionTypeApplyResources the operator start to apply resources to create or change the cluster
 	ConditionTypeReady               = "Ready"               // ConditionTypeReady all components and shardings are running
 	ConditionTypeAvailable           = "Available"           // ConditionTypeAvailable indicates whether the target object is available for serving.
+	ConditionTypeVolumeProtection    = "VolumeProtection"    // ConditionTypeVolumeProtection indicates whether any replicas are switched to read-only to protect the volumes.
 )
 
 type ServiceRef struct {
@@ -200,6 +203,49 @@ type ClusterComponentVolumeClaimTemplate struct {
 	//
 	// +optional
 	Spec PersistentVolumeClaimSpec `json:"spec,omitempty"`
+
+	// Defines the policy to expand the volume automatically when its usage crosses the threshold.
+	//
+	// The usage of the volume is measured by the kb-agent of each replica, once any replica crosses the threshold,
+	// a VolumeExpansion OpsRequest will be created for the volume of the whole Component.
+	// The StorageClass of the volume must allow volume expansion.
+	// It doesn't take effect on the volume claim templates overridden by the instance templates.
+	//
+	// +optional
+	Autoscaling *VolumeAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// VolumeAutoscaling defines the policy to expand a volume automatically.
+type VolumeAutoscaling struct {
+	// The usage percentage of the volume that triggers the expansion.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +kubebuilder:validation:Required
+	Threshold int32 `json:"threshold"`
+
+	// The size to be added to the volume in each expansion, either a percentage of the current size (e.g., "10%")
+	// or an absolute quantity (e.g., "10Gi").
+	//
+	// +kubebuilder:default="10%"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?%|[0-9]+(\.[0-9]+)?([KMGTPE]i?)?)$`
+	// +optional
+	Step string `json:"step,omitempty"`
+
+	// The maximum size the volume can be expanded to.
+	// If not specified, the volume will be expanded without limit.
+	//
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// The minimum interval in seconds between two expansions of the volume.
+	// It can't be less than the interval the volume usage is reported at (60 seconds), otherwise the volume
+	// may be expanded again according to the usage measured before the last expansion.
+	//
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	CooldownSeconds int32 `json:"cooldownSeconds,omitempty"`
 }
 
 type PersistentVolumeClaimSpec struct {
@@ -538,16 +584,23 @@ type TLSConfig struct {
 }
 
 // Issuer defines the TLS certificates issuer for the Cluster.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.duration) || !has(self.renewBefore) || duration(self.renewBefore) < duration(self.duration)",message="renewBefore must be less than duration"
+// +kubebuilder:validation:XValidation:rule="self.name != 'KubeBlocks' || !has(self.duration) || duration(self.duration).getSeconds() % 86400 == 0",message="duration must be whole days when the issuer is KubeBlocks"
 type Issuer struct {
 	// The issuer for TLS certificates.
-	// It only allows two enum values: `KubeBlocks` and `UserProvided`.
+	// It only allows three enum values: `KubeBlocks`, `UserProvided` and `CertManager`.
 	//
 	// - `KubeBlocks` indicates that the self-signed TLS certificates generated by the KubeBlocks Operator will be used.
 	// - `UserProvided` means that the user is responsible for providing their own CA, Cert, and Key.
 	//   In this case, the user-provided CA certificate, server certificate, and private key will be used
 	//   for TLS communication.
+	// - `CertManager` indicates that the TLS certificates will be issued by cert-manager.
+	//   In this case, a cert-manager Certificate will be created for the Component, and the issued certificates
+	//   will be used for TLS communication. The CA certificate is mounted only if the issuer provides it,
+	//   e.g. ACME issuers don't.
 	//
-	// +kubebuilder:validation:Enum={KubeBlocks, UserProvided}
+	// +kubebuilder:validation:Enum={KubeBlocks, UserProvided, CertManager}
 	// +kubebuilder:default=KubeBlocks
 	// +kubebuilder:validation:Required
 	Name IssuerName `json:"name"`
@@ -557,11 +610,42 @@ type Issuer struct {
 	//
 	// +optional
 	SecretRef *TLSSecretRef `json:"secretRef,omitempty"`
+
+	// IssuerRef is the reference to the cert-manager Issuer or ClusterIssuer that signs the certificates.
+	// It is required when the issuer is set to `CertManager`.
+	//
+	// +optional
+	IssuerRef *CertManagerIssuerRef `json:"issuerRef,omitempty"`
+
+	// Specifies the validity period of the certificates.
+	// It is only applicable when the issuer is set to `KubeBlocks` or `CertManager`.
+	//
+	// If not specified, the certificates issued by the KubeBlocks Operator are valid for 36500 days,
+	// and the default of cert-manager is used for the certificates issued by cert-manager.
+	// The KubeBlocks Operator issues the certificates in whole days, so the duration must be whole days
+	// when the issuer is set to `KubeBlocks`.
+	//
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Specifies how long before the certificates expire they should be renewed.
+	// It is only applicable when the issuer is set to `KubeBlocks` or `CertManager`.
+	//
+	// Once the certificates issued by the KubeBlocks Operator enter the renewal window, the operator re-issues
+	// the TLS secret and then either invokes the `tlsReload` lifecycle action, if it is defined in the
+	// ComponentDefinition, or rolling restarts the Component to load the new certificates.
+	// The certificates issued by cert-manager are renewed by cert-manager itself.
+	//
+	// It must be less than the `duration`.
+	// If not specified, or not less than the validity period, it defaults to one third of the `duration`.
+	//
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
 }
 
 // IssuerName defines the name of the TLS certificates issuer.
 // +enum
-// +kubebuilder:validation:Enum={KubeBlocks,UserProvided}
+// +kubebuilder:validation:Enum={KubeBlocks,UserProvided,CertManager}
 type IssuerName string
 
 const (
@@ -570,8 +654,33 @@ const (
 
 	// IssuerUserProvided indicates that the user has provided their own CA-signed certificates.
 	IssuerUserProvided IssuerName = "UserProvided"
+
+	// IssuerCertManager indicates that the certificates are issued by cert-manager.
+	IssuerCertManager IssuerName = "CertManager"
 )
 
+// CertManagerIssuerRef defines the reference to a cert-manager issuer.
+type CertManagerIssuerRef struct {
+	// Name of the cert-manager Issuer or ClusterIssuer.
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Kind of the issuer, either `Issuer` or `ClusterIssuer`.
+	// The Issuer must be in the same namespace as the Cluster.
+	//
+	// +kubebuilder:validation:Enum={Issuer,ClusterIssuer}
+	// +kubebuilder:default=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer.
+	//
+	// +kubebuilder:default=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
 // TLSSecretRef defines Secret contains Tls certs
 type TLSSecretRef struct {
 	// Name of the Secret that contains user-provided certificates.
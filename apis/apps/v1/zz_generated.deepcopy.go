@@ -117,6 +117,21 @@ func (in *ActionOutputMatcher) DeepCopy() *ActionOutputMatcher {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -389,6 +404,11 @@ func (in *ClusterComponentStatus) DeepCopy() *ClusterComponentStatus {
 func (in *ClusterComponentVolumeClaimTemplate) DeepCopyInto(out *ClusterComponentVolumeClaimTemplate) {
 	*out = *in
 	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(VolumeAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentVolumeClaimTemplate.
@@ -497,6 +517,129 @@ func (in *ClusterDefinitionStatus) DeepCopy() *ClusterDefinitionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDisasterRecovery) DeepCopyInto(out *ClusterDisasterRecovery) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDisasterRecovery.
+func (in *ClusterDisasterRecovery) DeepCopy() *ClusterDisasterRecovery {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDisasterRecovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterDisasterRecovery) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDisasterRecoveryList) DeepCopyInto(out *ClusterDisasterRecoveryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterDisasterRecovery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDisasterRecoveryList.
+func (in *ClusterDisasterRecoveryList) DeepCopy() *ClusterDisasterRecoveryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDisasterRecoveryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterDisasterRecoveryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDisasterRecoverySpec) DeepCopyInto(out *ClusterDisasterRecoverySpec) {
+	*out = *in
+	in.Standby.DeepCopyInto(&out.Standby)
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDisasterRecoverySpec.
+func (in *ClusterDisasterRecoverySpec) DeepCopy() *ClusterDisasterRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDisasterRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDisasterRecoveryStatus) DeepCopyInto(out *ClusterDisasterRecoveryStatus) {
+	*out = *in
+	if in.ReplicationLagSeconds != nil {
+		in, out := &in.ReplicationLagSeconds, &out.ReplicationLagSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentReplicationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Transition != nil {
+		in, out := &in.Transition, &out.Transition
+		*out = new(DRTransitionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDisasterRecoveryStatus.
+func (in *ClusterDisasterRecoveryStatus) DeepCopy() *ClusterDisasterRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDisasterRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	*out = *in
@@ -554,6 +697,53 @@ func (in *ClusterObjectReference) DeepCopy() *ClusterObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPlacement) DeepCopyInto(out *ClusterPlacement) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPlacement.
+func (in *ClusterPlacement) DeepCopy() *ClusterPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPlacementStatus) DeepCopyInto(out *ClusterPlacementStatus) {
+	*out = *in
+	if in.Contexts != nil {
+		in, out := &in.Contexts, &out.Contexts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failovers != nil {
+		in, out := &in.Failovers, &out.Failovers
+		*out = make([]DataClusterFailoverStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPlacementStatus.
+func (in *ClusterPlacementStatus) DeepCopy() *ClusterPlacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPlacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterService) DeepCopyInto(out *ClusterService) {
 	*out = *in
@@ -625,6 +815,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(ClusterBackup)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(ClusterPlacement)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -661,6 +861,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(ClusterPlacementStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -1049,6 +1254,28 @@ func (in *ComponentConfigSpec) DeepCopy() *ComponentConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentDataClusterStatus) DeepCopyInto(out *ComponentDataClusterStatus) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentDataClusterStatus.
+func (in *ComponentDataClusterStatus) DeepCopy() *ComponentDataClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentDataClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentDefinition) DeepCopyInto(out *ComponentDefinition) {
 	*out = *in
@@ -1275,6 +1502,13 @@ func (in *ComponentLifecycleActions) DeepCopyInto(out *ComponentLifecycleActions
 		*out = new(Probe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CustomProbes != nil {
+		in, out := &in.CustomProbes, &out.CustomProbes
+		*out = make([]CustomProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Switchover != nil {
 		in, out := &in.Switchover, &out.Switchover
 		*out = new(Action)
@@ -1315,11 +1549,31 @@ func (in *ComponentLifecycleActions) DeepCopyInto(out *ComponentLifecycleActions
 		*out = new(Action)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLSReload != nil {
+		in, out := &in.TLSReload, &out.TLSReload
+		*out = new(Action)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AccountProvision != nil {
 		in, out := &in.AccountProvision, &out.AccountProvision
 		*out = new(Action)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StandbyReplicate != nil {
+		in, out := &in.StandbyReplicate, &out.StandbyReplicate
+		*out = new(Action)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StandbyReplicationLag != nil {
+		in, out := &in.StandbyReplicationLag, &out.StandbyReplicationLag
+		*out = new(Action)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StandbyPromote != nil {
+		in, out := &in.StandbyPromote, &out.StandbyPromote
+		*out = new(Action)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentLifecycleActions.
@@ -1364,6 +1618,30 @@ func (in *ComponentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentReplicationStatus) DeepCopyInto(out *ComponentReplicationStatus) {
+	*out = *in
+	if in.ReplicationLagSeconds != nil {
+		in, out := &in.ReplicationLagSeconds, &out.ReplicationLagSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentReplicationStatus.
+func (in *ComponentReplicationStatus) DeepCopy() *ComponentReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentService) DeepCopyInto(out *ComponentService) {
 	*out = *in
@@ -1538,6 +1816,13 @@ func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.DataClusters != nil {
+		in, out := &in.DataClusters, &out.DataClusters
+		*out = make([]ComponentDataClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
@@ -1949,6 +2234,60 @@ func (in *CredentialVars) DeepCopy() *CredentialVars {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomProbe) DeepCopyInto(out *CustomProbe) {
+	*out = *in
+	in.Probe.DeepCopyInto(&out.Probe)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomProbe.
+func (in *CustomProbe) DeepCopy() *CustomProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRTransitionStatus) DeepCopyInto(out *DRTransitionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRTransitionStatus.
+func (in *DRTransitionStatus) DeepCopy() *DRTransitionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DRTransitionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataClusterFailoverStatus) DeepCopyInto(out *DataClusterFailoverStatus) {
+	*out = *in
+	if in.UnavailableSince != nil {
+		in, out := &in.UnavailableSince, &out.UnavailableSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataClusterFailoverStatus.
+func (in *DataClusterFailoverStatus) DeepCopy() *DataClusterFailoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataClusterFailoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvVar) DeepCopyInto(out *EnvVar) {
 	*out = *in
@@ -2185,6 +2524,21 @@ func (in *Issuer) DeepCopyInto(out *Issuer) {
 		*out = new(TLSSecretRef)
 		**out = **in
 	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerRef)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Issuer.
@@ -2228,6 +2582,22 @@ func (in *LogConfig) DeepCopy() *LogConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MultipleClusterObjectCombinedOption) DeepCopyInto(out *MultipleClusterObjectCombinedOption) {
 	*out = *in
@@ -3212,6 +3582,26 @@ func (in *SidecarDefinitionStatus) DeepCopy() *SidecarDefinitionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StandbyClusterSpec) DeepCopyInto(out *StandbyClusterSpec) {
+	*out = *in
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StandbyClusterSpec.
+func (in *StandbyClusterSpec) DeepCopy() *StandbyClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StandbyClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemAccount) DeepCopyInto(out *SystemAccount) {
 	*out = *in
@@ -3322,3 +3712,23 @@ func (in *VarSource) DeepCopy() *VarSource {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeAutoscaling) DeepCopyInto(out *VolumeAutoscaling) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAutoscaling.
+func (in *VolumeAutoscaling) DeepCopy() *VolumeAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
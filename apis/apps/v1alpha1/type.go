@@ -499,7 +499,7 @@ const (
 
 // UpgradePolicy defines the policy of reconfiguring.
 // +enum
-// +kubebuilder:validation:Enum={simple,parallel,rolling,autoReload,operatorSyncUpdate,dynamicReloadBeginRestart}
+// +kubebuilder:validation:Enum={simple,parallel,rolling,autoReload,operatorSyncUpdate,dynamicReloadBeginRestart,reconfigureAction}
 type UpgradePolicy string
 
 const (
@@ -510,6 +510,7 @@ const (
 	AsyncDynamicReloadPolicy      UpgradePolicy = "autoReload"
 	SyncDynamicReloadPolicy       UpgradePolicy = "operatorSyncUpdate"
 	DynamicReloadAndRestartPolicy UpgradePolicy = "dynamicReloadBeginRestart"
+	ReconfigureActionPolicy       UpgradePolicy = "reconfigureAction"
 )
 
 // IssuerName defines the name of the TLS certificates issuer.
@@ -187,6 +187,39 @@ type BackupStatus struct {
 	//
 	// +optional
 	Extras []map[string]string `json:"extras,omitempty"`
+
+	// Records the result of the latest verification of this backup.
+	//
+	// +optional
+	Verification *BackupVerificationResult `json:"verification,omitempty"`
+}
+
+// BackupVerificationResult records the result of a backup verification.
+type BackupVerificationResult struct {
+	// Describes the phase of the verification.
+	//
+	// +optional
+	Phase BackupVerificationPhase `json:"phase,omitempty"`
+
+	// Records the name of the backup schedule that verified the backup.
+	//
+	// +optional
+	BackupScheduleName string `json:"backupScheduleName,omitempty"`
+
+	// Records the time when the verification was started.
+	//
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Records the time when the verification was finished.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Provides a human-readable message about the verification.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // BackupTimeRange records the time range of backed up data, for PITR, this is the
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -41,6 +42,12 @@ type BackupScheduleSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinItems=1
 	Schedules []SchedulePolicy `json:"schedules"`
+
+	// Specifies the policy to periodically verify the completed backups of the backupPolicy
+	// by restoring them into a scratch environment and running a check against the restored data.
+	//
+	// +optional
+	Verification *BackupVerificationPolicy `json:"verification,omitempty"`
 }
 
 type SchedulePolicy struct {
@@ -78,6 +85,86 @@ type SchedulePolicy struct {
 	RetentionPeriod RetentionPeriod `json:"retentionPeriod,omitempty"`
 }
 
+// BackupVerificationPolicy defines how the backups are verified.
+//
+// On each scheduled run, the latest completed backup of the specified method is restored
+// into the scratch volume claims, and a check job mounting the restored volumes is started.
+// The result is recorded in the status of the verified backup, and the scratch resources
+// are deleted once the run finishes.
+type BackupVerificationPolicy struct {
+	// Specifies whether the backup verification is enabled or not.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Specifies the backup method name that is defined in backupPolicy,
+	// only the backups of this method are verified.
+	//
+	// +kubebuilder:validation:Required
+	BackupMethod string `json:"backupMethod"`
+
+	// Specifies the cron expression for the verification. The timezone is in UTC.
+	// see https://en.wikipedia.org/wiki/Cron.
+	//
+	// +kubebuilder:validation:Required
+	CronExpression string `json:"cronExpression"`
+
+	// Specifies the scratch volume claims that the backup is restored into.
+	// The claims are created for each run with the name suffixed by the run,
+	// and are mounted to the check container at their `mountPath`,
+	// or at `/verification/<claim-name>` if `mountPath` is not specified.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	VolumeClaims []RestoreVolumeClaim `json:"volumeClaims"`
+
+	// Defines the restore policy for the scratch volume claims.
+	//
+	// +kubebuilder:default=Parallel
+	// +optional
+	VolumeClaimRestorePolicy VolumeClaimRestorePolicy `json:"volumeClaimRestorePolicy,omitempty"`
+
+	// Defines the check that is run against the restored data.
+	// The verification succeeds if the check container exits with zero.
+	//
+	// +kubebuilder:validation:Required
+	Check BackupVerificationCheck `json:"check"`
+
+	// Specifies the maximum duration in minutes of a run, including the restore and the check.
+	// The run is considered failed if it does not finish in time.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=60
+	// +optional
+	TimeoutMinutes int32 `json:"timeoutMinutes,omitempty"`
+}
+
+// BackupVerificationCheck defines the container that checks the restored data.
+type BackupVerificationCheck struct {
+	// Specifies the image of the check container.
+	//
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// Defines the commands to check the restored data.
+	// The name of the verified backup is injected by the environment variable `DP_BACKUP_NAME`.
+	//
+	// +kubebuilder:validation:Required
+	Command []string `json:"command"`
+
+	// Specifies a list of environment variables to be set in the check container.
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// Specifies the resource requirements of the check container.
+	//
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
 // BackupScheduleStatus defines the observed state of BackupSchedule.
 type BackupScheduleStatus struct {
 	// Describes the phase of the BackupSchedule.
@@ -101,6 +188,11 @@ type BackupScheduleStatus struct {
 	//
 	// +optional
 	Schedules map[string]ScheduleStatus `json:"schedules,omitempty"`
+
+	// Describes the status of the backup verification.
+	//
+	// +optional
+	Verification *BackupVerificationStatus `json:"verification,omitempty"`
 }
 
 // BackupSchedulePhase defines the phase of BackupSchedule
@@ -145,6 +237,55 @@ const (
 	ScheduleFailed  SchedulePhase = "Failed"
 )
 
+// BackupVerificationStatus represents the status of the backup verification.
+type BackupVerificationStatus struct {
+	// Describes the phase of the latest run.
+	//
+	// +optional
+	Phase BackupVerificationPhase `json:"phase,omitempty"`
+
+	// Records the name of the backup verified by the latest run.
+	//
+	// +optional
+	BackupName string `json:"backupName,omitempty"`
+
+	// Records the name of the restore of the latest run.
+	//
+	// +optional
+	RestoreName string `json:"restoreName,omitempty"`
+
+	// Records the last time the verification was scheduled.
+	//
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Records the time when the latest run was started.
+	//
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Records the time when the latest run was finished.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Provides a human-readable message about the latest run.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BackupVerificationPhase represents the phase of a backup verification.
+// +enum
+// +kubebuilder:validation:Enum={Running,Succeeded,Failed}
+type BackupVerificationPhase string
+
+const (
+	BackupVerificationRunning   BackupVerificationPhase = "Running"
+	BackupVerificationSucceeded BackupVerificationPhase = "Succeeded"
+	BackupVerificationFailed    BackupVerificationPhase = "Failed"
+)
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
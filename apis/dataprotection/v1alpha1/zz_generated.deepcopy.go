@@ -860,6 +860,11 @@ func (in *BackupScheduleSpec) DeepCopyInto(out *BackupScheduleSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupScheduleSpec.
@@ -882,6 +887,11 @@ func (in *BackupScheduleStatus) DeepCopyInto(out *BackupScheduleStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupScheduleStatus.
@@ -981,6 +991,11 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 			}
 		}
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationResult)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -1072,6 +1087,107 @@ func (in *BackupTimeRange) DeepCopy() *BackupTimeRange {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationCheck) DeepCopyInto(out *BackupVerificationCheck) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationCheck.
+func (in *BackupVerificationCheck) DeepCopy() *BackupVerificationCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationPolicy) DeepCopyInto(out *BackupVerificationPolicy) {
+	*out = *in
+	if in.VolumeClaims != nil {
+		in, out := &in.VolumeClaims, &out.VolumeClaims
+		*out = make([]RestoreVolumeClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Check.DeepCopyInto(&out.Check)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationPolicy.
+func (in *BackupVerificationPolicy) DeepCopy() *BackupVerificationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationResult) DeepCopyInto(out *BackupVerificationResult) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationResult.
+func (in *BackupVerificationResult) DeepCopy() *BackupVerificationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationStatus) DeepCopyInto(out *BackupVerificationStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationStatus.
+func (in *BackupVerificationStatus) DeepCopy() *BackupVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BaseJobActionSpec) DeepCopyInto(out *BaseJobActionSpec) {
 	*out = *in
@@ -0,0 +1,286 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MetricsScalerSpec defines the desired state of MetricsScaler
+type MetricsScalerSpec struct {
+	// Specified the target Cluster name this scaler applies to.
+	//
+	// +kubebuilder:validation:Required
+	TargetClusterName string `json:"targetClusterName"`
+
+	// Specified the target Component name this scaler applies to.
+	//
+	// +kubebuilder:validation:Required
+	TargetComponentName string `json:"targetComponentName"`
+
+	// The lower limit for the number of replicas to which the scaler can scale down.
+	// It defaults to 1, and is restricted by the `replicasLimit` defined in the ComponentDefinition.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// The upper limit for the number of replicas to which the scaler can scale up.
+	// It is restricted by the `replicasLimit` defined in the ComponentDefinition.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Required
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Specifies the metrics used to calculate the desired number of replicas.
+	// The desired replicas will be the largest one calculated across all metrics.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	Metrics []MetricSpec `json:"metrics"`
+
+	// Configures the scaling behavior in both up and down directions.
+	//
+	// +optional
+	Behavior *ScalingBehavior `json:"behavior,omitempty"`
+}
+
+// MetricSourceType indicates the type of metric.
+//
+// +enum
+// +kubebuilder:validation:Enum={Resource,Probe}
+type MetricSourceType string
+
+const (
+	// ResourceMetricSourceType is a resource metric known to Kubernetes, as specified in requests and limits,
+	// describing each pod of the component, e.g., CPU or memory.
+	// It is read from the resource metrics API.
+	ResourceMetricSourceType MetricSourceType = "Resource"
+
+	// ProbeMetricSourceType is a metric reported by the kb-agent probe of each pod of the component.
+	ProbeMetricSourceType MetricSourceType = "Probe"
+)
+
+// MetricSpec specifies how to scale based on a single metric.
+type MetricSpec struct {
+	// The type of metric source.
+	//
+	// +kubebuilder:validation:Required
+	Type MetricSourceType `json:"type"`
+
+	// Refers to a resource metric of the pods.
+	//
+	// +optional
+	Resource *ResourceMetricSource `json:"resource,omitempty"`
+
+	// Refers to a metric reported by the kb-agent probe of the pods.
+	//
+	// +optional
+	Probe *ProbeMetricSource `json:"probe,omitempty"`
+}
+
+// ResourceMetricSource indicates how to scale on a resource metric.
+type ResourceMetricSource struct {
+	// The name of the resource.
+	//
+	// +kubebuilder:validation:Enum={cpu,memory}
+	// +kubebuilder:validation:Required
+	Name corev1.ResourceName `json:"name"`
+
+	// The target value of the average resource utilization across all pods,
+	// represented as a percentage of the requested value of the resource.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Required
+	TargetAverageUtilization int32 `json:"targetAverageUtilization"`
+}
+
+// ProbeMetricSource indicates how to scale on a metric reported by the kb-agent probe.
+type ProbeMetricSource struct {
+	// The name of the custom probe defined in the `lifecycleActions.customProbes` of the ComponentDefinition.
+	// The output of the probe should be a number or quantity, e.g., "100" or "1.5k".
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// The target value of the average metric across all pods.
+	//
+	// +kubebuilder:validation:Required
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
+}
+
+// ScalingBehavior configures the scaling behavior for up and down directions.
+type ScalingBehavior struct {
+	// The scaling policy for scaling up.
+	//
+	// +optional
+	ScaleUp *ScalingRules `json:"scaleUp,omitempty"`
+
+	// The scaling policy for scaling down.
+	//
+	// +optional
+	ScaleDown *ScalingRules `json:"scaleDown,omitempty"`
+}
+
+// ScalingRules configures the scaling behavior for one direction.
+type ScalingRules struct {
+	// The number of seconds for which past recommendations should be considered while scaling up or scaling down,
+	// to avoid the flapping of the number of replicas.
+	// It defaults to 0 for scaling up, and 300 for scaling down.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	StabilizationWindowSeconds *int32 `json:"stabilizationWindowSeconds,omitempty"`
+}
+
+// MetricsScalerStatus defines the observed state of MetricsScaler
+type MetricsScalerStatus struct {
+	// The current number of replicas of the target Component.
+	//
+	// +optional
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// The desired number of replicas of the target Component, as last calculated by the scaler.
+	//
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// The last read state of the metrics used by the scaler.
+	//
+	// +optional
+	CurrentMetrics []MetricStatus `json:"currentMetrics,omitempty"`
+
+	// The recommendations of the number of replicas calculated in the stabilization window.
+	//
+	// +optional
+	Recommendations []ScaleRecommendation `json:"recommendations,omitempty"`
+
+	// The name of the latest HorizontalScaling OpsRequest issued by the scaler.
+	//
+	// +optional
+	OpsRequestName string `json:"opsRequestName,omitempty"`
+
+	// Represents the latest available observations of a metricsscaler's current state.
+	// Known .status.conditions.type are: "ScalingActive", "AbleToScale".
+	// ScalingActive - The metrics are available to calculate the desired number of replicas.
+	// AbleToScale - The target Component can be scaled.
+	//
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// LastScaleTime is the last time the MetricsScaler scaled the number of replicas.
+	//
+	// +optional
+	LastScaleTime metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
+// MetricStatus describes the last read state of a single metric.
+type MetricStatus struct {
+	// The type of metric source.
+	Type MetricSourceType `json:"type"`
+
+	// The name of the resource or the probe.
+	Name string `json:"name"`
+
+	// The current value of the average metric across all pods.
+	//
+	// +optional
+	CurrentAverageValue *resource.Quantity `json:"currentAverageValue,omitempty"`
+
+	// The current value of the average resource utilization across all pods,
+	// represented as a percentage of the requested value of the resource.
+	//
+	// +optional
+	CurrentAverageUtilization *int32 `json:"currentAverageUtilization,omitempty"`
+}
+
+// ScaleRecommendation records the number of replicas recommended at a time.
+type ScaleRecommendation struct {
+	// The time of the recommendation.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// The recommended number of replicas.
+	Replicas int32 `json:"replicas"`
+}
+
+const (
+	// ScalingActive is added to a metricsscaler when the metrics are available to calculate the desired replicas.
+	ScalingActive ConditionType = "ScalingActive"
+
+	// AbleToScale is added to a metricsscaler when the target component can be scaled.
+	AbleToScale ConditionType = "AbleToScale"
+)
+
+const (
+	// ReasonValidMetricFound is a reason for condition ScalingActive.
+	ReasonValidMetricFound = "ValidMetricFound"
+
+	// ReasonFailedGetMetrics is a reason for condition ScalingActive.
+	ReasonFailedGetMetrics = "FailedGetMetrics"
+
+	// ReasonScalingDisabled is a reason for condition ScalingActive.
+	ReasonScalingDisabled = "ScalingDisabled"
+
+	// ReasonReadyForNewScale is a reason for condition AbleToScale.
+	ReasonReadyForNewScale = "ReadyForNewScale"
+
+	// ReasonScaleInProgress is a reason for condition AbleToScale.
+	ReasonScaleInProgress = "ScaleInProgress"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories={kubeblocks},shortName=mts
+// +kubebuilder:printcolumn:name="TARGET-CLUSTER-NAME",type="string",JSONPath=".spec.targetClusterName",description="target cluster name."
+// +kubebuilder:printcolumn:name="TARGET-COMPONENT-NAME",type="string",JSONPath=".spec.targetComponentName",description="target component name."
+// +kubebuilder:printcolumn:name="MIN",type="integer",JSONPath=".spec.minReplicas",description="min replicas."
+// +kubebuilder:printcolumn:name="MAX",type="integer",JSONPath=".spec.maxReplicas",description="max replicas."
+// +kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".status.currentReplicas",description="current replicas."
+// +kubebuilder:printcolumn:name="LAST-SCALE-TIME",type="date",JSONPath=".status.lastScaleTime"
+
+// MetricsScaler is the Schema for the metricsscalers API
+type MetricsScaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MetricsScalerSpec   `json:"spec,omitempty"`
+	Status MetricsScalerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MetricsScalerList contains a list of MetricsScaler
+type MetricsScalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MetricsScaler `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MetricsScaler{}, &MetricsScalerList{})
+}
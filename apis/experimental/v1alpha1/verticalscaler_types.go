@@ -0,0 +1,230 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+)
+
+// VerticalScalerSpec defines the desired state of VerticalScaler
+type VerticalScalerSpec struct {
+	// Specified the target Cluster name this scaler applies to.
+	//
+	// +kubebuilder:validation:Required
+	TargetClusterName string `json:"targetClusterName"`
+
+	// Specified the target Component name this scaler applies to.
+	//
+	// +kubebuilder:validation:Required
+	TargetComponentName string `json:"targetComponentName"`
+
+	// Specifies the resources to recommend for, only cpu and memory are supported.
+	// It defaults to both cpu and memory.
+	//
+	// +kubebuilder:default={cpu,memory}
+	// +kubebuilder:validation:items:Enum={cpu,memory}
+	// +optional
+	ControlledResources []corev1.ResourceName `json:"controlledResources,omitempty"`
+
+	// The percentile of the historical usage used to recommend the requests of the resources.
+	//
+	// +kubebuilder:default=90
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RequestPercentile int32 `json:"requestPercentile,omitempty"`
+
+	// The percentile of the historical usage used to recommend the limits of the resources.
+	// It should not be less than the `requestPercentile`.
+	//
+	// +kubebuilder:default=99
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	LimitPercentile int32 `json:"limitPercentile,omitempty"`
+
+	// The extra percentage added to the percentiles of the usage, to leave headroom for the spikes.
+	//
+	// +kubebuilder:default=15
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SafetyMarginPercent int32 `json:"safetyMarginPercent,omitempty"`
+
+	// The length of the usage history, in seconds, the recommendations are calculated from.
+	//
+	// +kubebuilder:default=86400
+	// +kubebuilder:validation:Minimum=3600
+	// +kubebuilder:validation:Maximum=1209600
+	// +optional
+	HistoryWindowSeconds int32 `json:"historyWindowSeconds,omitempty"`
+
+	// The lower bound of the recommended resources.
+	//
+	// +optional
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+
+	// The upper bound of the recommended resources.
+	//
+	// +optional
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+
+	// Specifies whether the recommendations are applied to the target Component.
+	//
+	// - `Off`: the recommendations are published in the status only.
+	// - `Auto`: the recommendations are applied by VerticalScaling OpsRequests, within the maintenance window if specified.
+	//
+	// +kubebuilder:default=Off
+	// +optional
+	UpdateMode VerticalScalerUpdateMode `json:"updateMode,omitempty"`
+
+	// The time window in which the recommendations can be applied.
+	// The recommendations can be applied at any time if not specified.
+	//
+	// +optional
+	MaintenanceWindow *appsv1.MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// The minimum change, in percentage of the current value, of any resource to apply the recommendations.
+	// It avoids restarting the instances for insignificant changes.
+	//
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinChangePercent int32 `json:"minChangePercent,omitempty"`
+}
+
+// VerticalScalerUpdateMode defines how the recommendations are applied.
+//
+// +enum
+// +kubebuilder:validation:Enum={Off,Auto}
+type VerticalScalerUpdateMode string
+
+const (
+	VerticalScalerUpdateModeOff  VerticalScalerUpdateMode = "Off"
+	VerticalScalerUpdateModeAuto VerticalScalerUpdateMode = "Auto"
+)
+
+// VerticalScalerStatus defines the observed state of VerticalScaler
+type VerticalScalerStatus struct {
+	// The recommended resources of the target Component, one for each instance template.
+	//
+	// +optional
+	Recommendations []ResourceRecommendation `json:"recommendations,omitempty"`
+
+	// The name of the latest VerticalScaling OpsRequest issued by the scaler.
+	//
+	// +optional
+	OpsRequestName string `json:"opsRequestName,omitempty"`
+
+	// Represents the latest available observations of a verticalscaler's current state.
+	// Known .status.conditions.type are: "RecommendationProvided", "AbleToScale".
+	// RecommendationProvided - There is enough usage history to provide the recommendations.
+	// AbleToScale - The recommendations can be applied to the target Component.
+	//
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// LastScaleTime is the last time the VerticalScaler applied the recommendations.
+	//
+	// +optional
+	LastScaleTime metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
+// ResourceRecommendation describes the recommended resources of an instance template.
+type ResourceRecommendation struct {
+	// The name of the instance template, it is empty for the instances not created from any template.
+	//
+	// +optional
+	TemplateName string `json:"templateName,omitempty"`
+
+	// The current resources of the instances.
+	//
+	// +optional
+	Current corev1.ResourceRequirements `json:"current,omitempty"`
+
+	// The recommended resources of the instances.
+	//
+	// +optional
+	Target corev1.ResourceRequirements `json:"target,omitempty"`
+
+	// The number of usage samples the recommendation is calculated from.
+	Samples int32 `json:"samples"`
+
+	// The last time the recommendation was updated.
+	//
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+const (
+	// RecommendationProvided is added to a verticalscaler when there is enough usage history to provide recommendations.
+	RecommendationProvided ConditionType = "RecommendationProvided"
+)
+
+const (
+	// ReasonInsufficientHistory is a reason for condition RecommendationProvided.
+	ReasonInsufficientHistory = "InsufficientHistory"
+
+	// ReasonRecommendationUpdated is a reason for condition RecommendationProvided.
+	ReasonRecommendationUpdated = "RecommendationUpdated"
+
+	// ReasonUpdateModeOff is a reason for condition AbleToScale.
+	ReasonUpdateModeOff = "UpdateModeOff"
+
+	// ReasonOutOfMaintenanceWindow is a reason for condition AbleToScale.
+	ReasonOutOfMaintenanceWindow = "OutOfMaintenanceWindow"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories={kubeblocks},shortName=vts
+// +kubebuilder:printcolumn:name="TARGET-CLUSTER-NAME",type="string",JSONPath=".spec.targetClusterName",description="target cluster name."
+// +kubebuilder:printcolumn:name="TARGET-COMPONENT-NAME",type="string",JSONPath=".spec.targetComponentName",description="target component name."
+// +kubebuilder:printcolumn:name="MODE",type="string",JSONPath=".spec.updateMode",description="update mode."
+// +kubebuilder:printcolumn:name="LAST-SCALE-TIME",type="date",JSONPath=".status.lastScaleTime"
+
+// VerticalScaler is the Schema for the verticalscalers API
+type VerticalScaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerticalScalerSpec   `json:"spec,omitempty"`
+	Status VerticalScalerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// VerticalScalerList contains a list of VerticalScaler
+type VerticalScalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerticalScaler `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerticalScaler{}, &VerticalScalerList{})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// OpsApprovalPolicySpec defines the OpsRequests that require an approval before being executed.
+type OpsApprovalPolicySpec struct {
+	// Selects the namespaces by labels. The policy applies to the OpsRequests in the selected namespaces.
+	// If not specified, the OpsRequests in all namespaces are selected.
+	//
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Selects the Clusters by labels. The policy applies to the OpsRequests of the selected Clusters.
+	// If not specified, the OpsRequests of all Clusters are selected.
+	//
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// Specifies the types of the OpsRequests that require an approval.
+	// If not specified, the OpsRequests of all types require an approval.
+	//
+	// +listType=set
+	// +optional
+	OpsTypes []OpsType `json:"opsTypes,omitempty"`
+
+	// Specifies the maximum duration in seconds that an OpsRequest waits for the approval.
+	// The OpsRequest fails if it is not approved in time.
+	// If not specified or set to 0, the OpsRequest waits until it is approved or canceled.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ApprovalTimeoutSeconds *int32 `json:"approvalTimeoutSeconds,omitempty"`
+}
+
+// Matches checks whether the policy applies to the OpsRequest of the type, with the labels of its namespace and Cluster.
+func (r *OpsApprovalPolicy) Matches(opsType OpsType, namespaceLabels, clusterLabels map[string]string) (bool, error) {
+	if len(r.Spec.OpsTypes) > 0 && !slices.Contains(r.Spec.OpsTypes, opsType) {
+		return false, nil
+	}
+	matches := func(selector *metav1.LabelSelector, objLabels map[string]string) (bool, error) {
+		if selector == nil {
+			return true, nil
+		}
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return false, err
+		}
+		return labelSelector.Matches(labels.Set(objLabels)), nil
+	}
+	if ok, err := matches(r.Spec.NamespaceSelector, namespaceLabels); err != nil || !ok {
+		return false, err
+	}
+	return matches(r.Spec.ClusterSelector, clusterLabels)
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories={kubeblocks},scope=Cluster,shortName=opsap
+// +kubebuilder:printcolumn:name="OPS-TYPES",type="string",JSONPath=".spec.opsTypes",description="The types of the OpsRequests that require an approval."
+// +kubebuilder:printcolumn:name="TIMEOUT",type="integer",JSONPath=".spec.approvalTimeoutSeconds",description="The approval timeout in seconds."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OpsApprovalPolicy is the Schema for the opsapprovalpolicies API.
+// It holds the matching OpsRequests in the PendingApproval phase until they are approved by a user other than the creator.
+type OpsApprovalPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OpsApprovalPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpsApprovalPolicyList contains a list of OpsApprovalPolicy
+type OpsApprovalPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpsApprovalPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpsApprovalPolicy{}, &OpsApprovalPolicyList{})
+}
@@ -0,0 +1,241 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpsPipelineSpec defines the desired state of OpsPipeline.
+type OpsPipelineSpec struct {
+	// Specifies the name of the Cluster resource that the steps target.
+	// It is used as the `clusterName` of the steps that do not specify one.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.clusterName"
+	ClusterName string `json:"clusterName"`
+
+	// Specifies the steps of the pipeline. Each step creates an OpsRequest once all the steps it depends on
+	// have completed, so the steps form a sequence by default, or a DAG if `dependsOn` is specified.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=64
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.steps"
+	// +listType=map
+	// +listMapKey=name
+	Steps []OpsPipelineStep `json:"steps"`
+}
+
+// OpsStepFailurePolicy defines how an OpsPipeline reacts to the failure of a step.
+//
+// +enum
+// +kubebuilder:validation:Enum={Abort,Continue,Rollback}
+type OpsStepFailurePolicy string
+
+const (
+	// AbortOnFailure stops starting new steps and fails the pipeline.
+	AbortOnFailure OpsStepFailurePolicy = "Abort"
+
+	// ContinueOnFailure ignores the failure, and the steps that depend on the failed step will still run.
+	ContinueOnFailure OpsStepFailurePolicy = "Continue"
+
+	// RollbackOnFailure stops starting new steps and runs the compensations of the completed steps
+	// in the reverse order of their completion.
+	RollbackOnFailure OpsStepFailurePolicy = "Rollback"
+)
+
+// OpsPipelineStep describes a step of an OpsPipeline.
+type OpsPipelineStep struct {
+	// Specifies the name of the step, which must be unique within the pipeline.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([a-z0-9\-]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Specifies the names of the steps that must complete before this step starts.
+	// If not specified, the step depends on the previous step in the list.
+	// Set it to an empty list to start the step along with the first step.
+	// The step starts when all of them have succeeded, or failed with the "Continue" failure policy.
+	//
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Specifies how the pipeline reacts when the OpsRequest of the step fails, is cancelled or aborted.
+	// Valid values are:
+	//
+	// - Abort: stops starting new steps and fails the pipeline.
+	// - Continue: ignores the failure.
+	// - Rollback: stops starting new steps, and runs the compensations of the completed steps, including the failed one,
+	//   in the reverse order of their completion.
+	//
+	// +kubebuilder:default=Abort
+	// +optional
+	FailurePolicy OpsStepFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// Specifies the spec of the OpsRequest of the step.
+	//
+	// The schema is not validated here, it is validated when the OpsRequest is created.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec OpsRequestSpec `json:"spec"`
+
+	// Specifies the spec of the OpsRequest that compensates the step during a rollback,
+	// e.g. a downgrade for an upgrade, or a restore for a data migration.
+	// The step is skipped during a rollback if it is not specified.
+	//
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Compensation *OpsRequestSpec `json:"compensation,omitempty"`
+}
+
+// OpsPipelinePhase defines the phase of an OpsPipeline.
+//
+// +enum
+// +kubebuilder:validation:Enum={Pending,Running,Succeed,Failed,RollingBack,RolledBack}
+type OpsPipelinePhase string
+
+const (
+	OpsPipelinePendingPhase     OpsPipelinePhase = "Pending"
+	OpsPipelineRunningPhase     OpsPipelinePhase = "Running"
+	OpsPipelineSucceedPhase     OpsPipelinePhase = "Succeed"
+	OpsPipelineFailedPhase      OpsPipelinePhase = "Failed"
+	OpsPipelineRollingBackPhase OpsPipelinePhase = "RollingBack"
+	OpsPipelineRolledBackPhase  OpsPipelinePhase = "RolledBack"
+)
+
+// OpsPipelineStatus defines the observed state of OpsPipeline.
+type OpsPipelineStatus struct {
+	// Represents the phase of the OpsPipeline.
+	//
+	// +optional
+	Phase OpsPipelinePhase `json:"phase,omitempty"`
+
+	// Represents the progress of the OpsPipeline, in the form of "completed steps/total steps".
+	//
+	// +kubebuilder:validation:Pattern:=`^(\d+|\-)/(\d+|\-)$`
+	// +kubebuilder:default=-/-
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// Provides additional information about the current phase, such as the reason of the failure.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Records the status of the steps that have started.
+	//
+	// +optional
+	Steps []OpsPipelineStepStatus `json:"steps,omitempty"`
+
+	// Records the status of the compensations during a rollback, in the order they run.
+	//
+	// +optional
+	Compensations []OpsPipelineStepStatus `json:"compensations,omitempty"`
+
+	// Records the time when the OpsPipeline started.
+	//
+	// +optional
+	StartTimestamp metav1.Time `json:"startTimestamp,omitempty"`
+
+	// Records the time when the OpsPipeline completed.
+	//
+	// +optional
+	CompletionTimestamp metav1.Time `json:"completionTimestamp,omitempty"`
+}
+
+// OpsPipelineStepStatus records the status of a step, or of the compensation of a step.
+type OpsPipelineStepStatus struct {
+	// Specifies the name of the step.
+	Name string `json:"name"`
+
+	// Specifies the name of the OpsRequest created for the step.
+	OpsRequestName string `json:"opsRequestName"`
+
+	// Represents the phase of the OpsRequest.
+	//
+	// +optional
+	Phase OpsPhase `json:"phase,omitempty"`
+
+	// Records the time when the OpsRequest started processing.
+	//
+	// +optional
+	StartTimestamp metav1.Time `json:"startTimestamp,omitempty"`
+
+	// Records the time when the OpsRequest was completed.
+	//
+	// +optional
+	CompletionTimestamp metav1.Time `json:"completionTimestamp,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories={kubeblocks},shortName=opsp
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.clusterName",description="Operand cluster."
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.phase",description="Operation pipeline status phase."
+// +kubebuilder:printcolumn:name="PROGRESS",type="string",JSONPath=".status.progress",description="Operation pipeline processing progress."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// OpsPipeline is the Schema for the opspipelines API.
+// It runs a sequence or a DAG of OpsRequests against a Cluster, e.g. backup, upgrade, reconfigure and switchover.
+type OpsPipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpsPipelineSpec   `json:"spec,omitempty"`
+	Status OpsPipelineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpsPipelineList contains a list of OpsPipeline
+type OpsPipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpsPipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpsPipeline{}, &OpsPipelineList{})
+}
+
+// IsComplete checks if the OpsPipeline has completed.
+func (r *OpsPipeline) IsComplete() bool {
+	switch r.Status.Phase {
+	case OpsPipelineSucceedPhase, OpsPipelineFailedPhase, OpsPipelineRolledBackPhase:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetStepDependencies returns the names of the steps that the step at the index depends on.
+func (r *OpsPipeline) GetStepDependencies(index int) []string {
+	step := r.Spec.Steps[index]
+	if step.DependsOn != nil || index == 0 {
+		return step.DependsOn
+	}
+	return []string{r.Spec.Steps[index-1].Name}
+}
@@ -19,11 +19,13 @@ package v1alpha1
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 )
 
 const (
@@ -48,16 +50,29 @@ const (
 	ConditionTypeInstanceRebuilding = "InstancesRebuilding"
 	ConditionTypeCustomOperation    = "CustomOperation"
 
+	ConditionTypeWaitForMaintenanceWindow = "WaitForMaintenanceWindow"
+	ConditionTypeDryRun                   = "DryRun"
+	ConditionTypeApproved                 = "Approved"
+	ConditionTypeRolledBack               = "RolledBack"
+
 	// condition and event reasons
-	ReasonClusterPhaseMismatch  = "ClusterPhaseMismatch"
-	ReasonOpsTypeNotSupported   = "OpsTypeNotSupported"
-	ReasonValidateFailed        = "ValidateFailed"
-	ReasonClusterNotFound       = "ClusterNotFound"
-	ReasonOpsRequestFailed      = "OpsRequestFailed"
-	ReasonOpsCanceling          = "Canceling"
-	ReasonOpsCancelFailed       = "CancelFailed"
-	ReasonOpsCancelSucceed      = "CancelSucceed"
-	ReasonOpsCancelByController = "CancelByController"
+	ReasonClusterPhaseMismatch   = "ClusterPhaseMismatch"
+	ReasonOpsTypeNotSupported    = "OpsTypeNotSupported"
+	ReasonValidateFailed         = "ValidateFailed"
+	ReasonClusterNotFound        = "ClusterNotFound"
+	ReasonOpsRequestFailed       = "OpsRequestFailed"
+	ReasonOpsCanceling           = "Canceling"
+	ReasonOpsCancelFailed        = "CancelFailed"
+	ReasonOpsCancelSucceed       = "CancelSucceed"
+	ReasonOpsCancelByController  = "CancelByController"
+	ReasonOutOfMaintenanceWindow = "OutOfMaintenanceWindow"
+	ReasonInMaintenanceWindow    = "InMaintenanceWindow"
+	ReasonDryRunCompleted        = "DryRunCompleted"
+	ReasonWaitForApproval        = "WaitForApproval"
+	ReasonApproved               = "Approved"
+	ReasonApprovalExpired        = "ApprovalExpired"
+	ReasonApprovalRejected       = "ApprovalRejected"
+	ReasonUpgradeRolledBack      = "UpgradeRolledBack"
 )
 
 func (r *OpsRequest) SetStatusCondition(condition metav1.Condition) {
@@ -125,6 +140,105 @@ func NewCancelSucceedCondition(opsName string) *metav1.Condition {
 	}
 }
 
+// NewWaitForMaintenanceWindowCondition creates a condition that the OpsRequest waits for the maintenance window of the cluster.
+func NewWaitForMaintenanceWindowCondition(ops *OpsRequest, nextOpening time.Time) *metav1.Condition {
+	message := fmt.Sprintf("the %s OpsRequest: %s is disruptive, wait for the maintenance window of Cluster: %s",
+		ops.Spec.Type, ops.Name, ops.Spec.GetClusterName())
+	if !nextOpening.IsZero() {
+		message = fmt.Sprintf("%s, which opens at %s", message, nextOpening.Format(time.RFC3339))
+	}
+	return &metav1.Condition{
+		Type:               ConditionTypeWaitForMaintenanceWindow,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonOutOfMaintenanceWindow,
+		LastTransitionTime: metav1.Now(),
+		Message:            message,
+	}
+}
+
+// NewInMaintenanceWindowCondition creates a condition that the maintenance window of the cluster opens for the OpsRequest.
+func NewInMaintenanceWindowCondition(ops *OpsRequest) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeWaitForMaintenanceWindow,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonInMaintenanceWindow,
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("the maintenance window of Cluster: %s is open", ops.Spec.GetClusterName()),
+	}
+}
+
+// NewDryRunCompletedCondition creates a condition that the impact of the OpsRequest has been computed by a dry run.
+func NewDryRunCompletedCondition(ops *OpsRequest) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeDryRun,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonDryRunCompleted,
+		LastTransitionTime: metav1.Now(),
+		Message: fmt.Sprintf("the dry run of the OpsRequest: %s in Cluster: %s is completed, see status.dryRunResult for the impact",
+			ops.Name, ops.Spec.GetClusterName()),
+	}
+}
+
+// NewWaitForApprovalCondition creates a condition that the OpsRequest waits for the approval required by the OpsApprovalPolicy.
+func NewWaitForApprovalCondition(ops *OpsRequest, policyName string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeApproved,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonWaitForApproval,
+		LastTransitionTime: metav1.Now(),
+		Message: fmt.Sprintf(`the OpsRequest: %s requires an approval by OpsApprovalPolicy: %s, annotate it with "%s=true" to approve`,
+			ops.Name, policyName, constant.OpsApproveAnnotationKey),
+	}
+}
+
+// NewApprovedCondition creates a condition that the OpsRequest has been approved.
+func NewApprovedCondition(ops *OpsRequest, approver string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeApproved,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonApproved,
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("the OpsRequest: %s is approved by %s", ops.Name, approver),
+	}
+}
+
+// NewApprovalExpiredCondition creates a condition that the OpsRequest is not approved in time.
+func NewApprovalExpiredCondition(ops *OpsRequest, timeoutSeconds int32) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeApproved,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonApprovalExpired,
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("the OpsRequest: %s is not approved within %d seconds", ops.Name, timeoutSeconds),
+	}
+}
+
+// NewApprovalRejectedCondition creates a condition that the approval of the OpsRequest is rejected,
+// since its creator is not recorded by the webhook or is the same as the approver.
+func NewApprovalRejectedCondition(ops *OpsRequest, approver string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeApproved,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonApprovalRejected,
+		LastTransitionTime: metav1.Now(),
+		Message: fmt.Sprintf(`the approval of the OpsRequest: %s by "%s" is rejected, it should be approved by a user other than the creator recorded by the webhook`,
+			ops.Name, approver),
+	}
+}
+
+// NewApprovalWithoutWebhookCondition creates a condition that the approval of the OpsRequest is rejected,
+// since the webhook recording the creators and approvers is not enabled, and the annotations may be written by anyone.
+func NewApprovalWithoutWebhookCondition(ops *OpsRequest, approver string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeApproved,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonApprovalRejected,
+		LastTransitionTime: metav1.Now(),
+		Message: fmt.Sprintf(`the approval of the OpsRequest: %s by "%s" is rejected, the webhook recording the creators and approvers is not enabled`,
+			ops.Name, approver),
+	}
+}
+
 // NewValidatePassedCondition creates a condition for operation validation to pass.
 func NewValidatePassedCondition(opsRequestName string) *metav1.Condition {
 	return &metav1.Condition{
@@ -262,6 +376,18 @@ func NewUpgradingCondition(ops *OpsRequest) *metav1.Condition {
 	}
 }
 
+// NewUpgradeRolledBackCondition creates a condition that the OpsRequest rolls back the Components regressed by the upgrade.
+func NewUpgradeRolledBackCondition(ops *OpsRequest, compNames []string) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeRolledBack,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonUpgradeRolledBack,
+		LastTransitionTime: metav1.Now(),
+		Message: fmt.Sprintf("roll back the Components %s in Cluster: %s, which are unavailable after the upgrade, more detailed informations in status.components",
+			strings.Join(compNames, ","), ops.Spec.GetClusterName()),
+	}
+}
+
 // NewStopCondition creates a condition that the OpsRequest starts to stop the cluster.
 func NewStopCondition(ops *OpsRequest) *metav1.Condition {
 	return &metav1.Condition{
@@ -64,6 +64,17 @@ type OpsRequestSpec struct {
 	// +optional
 	EnqueueOnForce bool `json:"enqueueOnForce,omitempty"`
 
+	// Indicates whether the opsRequest is a dry run.
+	//
+	// A dry run validates the opsRequest and computes its impact into `status.dryRunResult` without mutating the Cluster,
+	// including the changes to the Cluster, the Pods to be restarted or recreated in the order they are updated,
+	// and whether the changed parameters are static or dynamic for a "Reconfiguring" opsRequest.
+	// The opsRequest turns to "Succeed" once the impact is computed.
+	//
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.dryRun"
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
 	// Specifies the type of this operation. Supported types include "Start", "Stop", "Restart", "Switchover",
 	// "VerticalScaling", "HorizontalScaling", "VolumeExpansion", "Reconfiguring", "Upgrade", "Backup", "Restore",
 	// "Expose", "RebuildInstance", "Custom".
@@ -328,6 +339,23 @@ type Upgrade struct {
 	// +kubebuilder:validation:MaxItems=1024
 	// +optional
 	Components []UpgradeComponent `json:"components,omitempty" patchStrategy:"merge,retainKeys" patchMergeKey:"componentName"`
+
+	// Specifies whether to roll back the Components that do not become available after the upgrade.
+	// The previous ComponentDefinition and ServiceVersion of these Components are restored, and the OpsRequest fails.
+	// +optional
+	AutoRollback *UpgradeAutoRollback `json:"autoRollback,omitempty"`
+}
+
+// UpgradeAutoRollback defines how an "Upgrade" OpsRequest rolls back the Components that regress.
+type UpgradeAutoRollback struct {
+	// Specifies the deadline in seconds, since the OpsRequest starts, for the upgraded Components to become available.
+	// The Components that are still unavailable after the deadline are rolled back.
+	// The Components that fail during the upgrade are rolled back immediately.
+	//
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AvailableTimeoutSeconds int32 `json:"availableTimeoutSeconds,omitempty"`
 }
 
 // +kubebuilder:validation:XValidation:rule="has(self.componentDefinitionName) || has(self.serviceVersion)",message="at least one componentDefinitionName or serviceVersion"
@@ -991,6 +1019,14 @@ type OpsRequestStatus struct {
 	// +optional
 	ReconfiguringStatusAsComponent map[string]*ReconfiguringStatus `json:"reconfiguringStatusAsComponent,omitempty"`
 
+	// Records the impact of the OpsRequest computed by a dry run if `opsRequest.spec.dryRun` is true.
+	// +optional
+	DryRunResult *OpsDryRunResult `json:"dryRunResult,omitempty"`
+
+	// Records the approval of the OpsRequest if it is selected by an OpsApprovalPolicy.
+	// +optional
+	Approval *OpsApproval `json:"approval,omitempty"`
+
 	// Describes the detailed status of the OpsRequest.
 	// Possible condition types include "Cancelled", "WaitForProgressing", "Validated", "Succeed", "Failed", "Restarting",
 	// "VerticalScaling", "HorizontalScaling", "VolumeExpanding", "Reconfigure", "Switchover", "Stopping", "Starting",
@@ -1239,6 +1275,88 @@ type UpdatedParameters struct {
 	UpdatedKeys map[string]string `json:"updatedKeys,omitempty"`
 }
 
+// OpsDryRunResult describes the impact of an OpsRequest computed by a dry run.
+type OpsDryRunResult struct {
+	// Represents the changes to the metadata and the spec of the Cluster, in the form of a JSON merge patch.
+	// It is empty if the Cluster is not changed.
+	//
+	// +optional
+	ClusterPatch string `json:"clusterPatch,omitempty"`
+
+	// Lists the Pods to be restarted or recreated, in the order they are updated.
+	//
+	// +optional
+	Pods []DryRunPod `json:"pods,omitempty"`
+
+	// Lists the changed parameters of a "Reconfiguring" OpsRequest.
+	//
+	// +optional
+	Parameters []DryRunParameter `json:"parameters,omitempty"`
+
+	// Provides additional information about the dry run, such as the impact that is not computed.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PodUpdateAction defines how a Pod is updated by an OpsRequest.
+//
+// +enum
+// +kubebuilder:validation:Enum={Restart,Recreate}
+type PodUpdateAction string
+
+const (
+	// PodRestartAction restarts the Pod with the same spec.
+	PodRestartAction PodUpdateAction = "Restart"
+
+	// PodRecreateAction recreates the Pod with the updated spec.
+	PodRecreateAction PodUpdateAction = "Recreate"
+)
+
+// DryRunPod describes a Pod to be updated by an OpsRequest.
+type DryRunPod struct {
+	// Specifies the name of the Component.
+	ComponentName string `json:"componentName"`
+
+	// Specifies the name of the Pod.
+	PodName string `json:"podName"`
+
+	// Specifies how the Pod is updated.
+	Action PodUpdateAction `json:"action"`
+}
+
+// DryRunParameter describes a parameter changed by a "Reconfiguring" OpsRequest.
+type DryRunParameter struct {
+	// Specifies the name of the Component.
+	ComponentName string `json:"componentName"`
+
+	// Specifies the name of the configuration template.
+	ConfigurationName string `json:"configurationName"`
+
+	// Specifies the name of the configuration file.
+	FileName string `json:"fileName"`
+
+	// Specifies the name of the parameter. It is empty if the whole file is replaced.
+	//
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Indicates whether the parameter takes effect without restarting the Pods.
+	Dynamic bool `json:"dynamic"`
+}
+
+// OpsApproval records the approval of an OpsRequest.
+type OpsApproval struct {
+	// Specifies the name of the OpsApprovalPolicy that requires the approval.
+	PolicyName string `json:"policyName"`
+
+	// Specifies the user who approved the OpsRequest.
+	Approver string `json:"approver"`
+
+	// Records the time when the approval was observed.
+	ApprovalTimestamp metav1.Time `json:"approvalTimestamp"`
+}
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
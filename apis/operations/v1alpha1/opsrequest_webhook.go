@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+)
+
+func (r *OpsRequest) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&opsRequestApprovalDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-operations-kubeblocks-io-v1alpha1-opsrequest,mutating=true,failurePolicy=fail,sideEffects=None,groups=operations.kubeblocks.io,resources=opsrequests,verbs=create;update,versions=v1alpha1,name=mopsrequest.kb.io,admissionReviewVersions=v1
+
+// opsRequestApprovalDefaulter records the creator and the approver of the OpsRequest from the identity of the requests,
+// which is required by the OpsApprovalPolicy to make sure that the OpsRequest is approved by a user other than the creator.
+type opsRequestApprovalDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &opsRequestApprovalDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (d *opsRequestApprovalDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	ops, ok := obj.(*OpsRequest)
+	if !ok {
+		return fmt.Errorf("expected an OpsRequest but got a %T", obj)
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if req.Operation == admissionv1.Create {
+		// the annotations set by users are overwritten.
+		setOpsAnnotation(ops, constant.OpsCreatedByAnnotationKey, req.UserInfo.Username)
+		setOpsAnnotation(ops, constant.OpsApprovedByAnnotationKey, "")
+		if ops.Annotations[constant.OpsApproveAnnotationKey] == "true" {
+			return fmt.Errorf(`the OpsRequest can not be approved by its creator "%s"`, req.UserInfo.Username)
+		}
+		return nil
+	}
+	oldOps := &OpsRequest{}
+	if err = json.Unmarshal(req.OldObject.Raw, oldOps); err != nil {
+		return err
+	}
+	// the recorded users are immutable.
+	creator := oldOps.Annotations[constant.OpsCreatedByAnnotationKey]
+	approver := oldOps.Annotations[constant.OpsApprovedByAnnotationKey]
+	setOpsAnnotation(ops, constant.OpsCreatedByAnnotationKey, creator)
+	setOpsAnnotation(ops, constant.OpsApprovedByAnnotationKey, approver)
+	if len(approver) > 0 || ops.Annotations[constant.OpsApproveAnnotationKey] != "true" ||
+		oldOps.Annotations[constant.OpsApproveAnnotationKey] == "true" {
+		return nil
+	}
+	if req.UserInfo.Username == creator {
+		return fmt.Errorf(`the OpsRequest can not be approved by its creator "%s"`, creator)
+	}
+	setOpsAnnotation(ops, constant.OpsApprovedByAnnotationKey, req.UserInfo.Username)
+	return nil
+}
+
+// setOpsAnnotation sets the annotation of the OpsRequest, or removes it if the value is empty.
+func setOpsAnnotation(ops *OpsRequest, key, value string) {
+	if len(value) == 0 {
+		delete(ops.Annotations, key)
+		return
+	}
+	if ops.Annotations == nil {
+		ops.Annotations = map[string]string{}
+	}
+	ops.Annotations[key] = value
+}
@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+)
+
+func defaultOpsRequest(t *testing.T, operation admissionv1.Operation, user string, oldOps, ops *OpsRequest) error {
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: operation,
+		UserInfo:  authenticationv1.UserInfo{Username: user},
+	}}
+	if oldOps != nil {
+		raw, err := json.Marshal(oldOps)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return (&opsRequestApprovalDefaulter{}).Default(admission.NewContextWithRequest(context.Background(), req), ops)
+}
+
+func TestOpsRequestApprovalDefaulter(t *testing.T) {
+	ops := &OpsRequest{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{constant.OpsApprovedByAnnotationKey: "alice"},
+	}}
+	if err := defaultOpsRequest(t, admissionv1.Create, "alice", nil, ops); err != nil {
+		t.Fatal(err)
+	}
+	if ops.Annotations[constant.OpsCreatedByAnnotationKey] != "alice" {
+		t.Errorf("expected the creator is recorded, got %v", ops.Annotations)
+	}
+	if _, ok := ops.Annotations[constant.OpsApprovedByAnnotationKey]; ok {
+		t.Errorf("expected the approver set by the creator is removed, got %v", ops.Annotations)
+	}
+
+	createdOps := ops.DeepCopy()
+	ops.Annotations[constant.OpsApproveAnnotationKey] = "true"
+	if err := defaultOpsRequest(t, admissionv1.Update, "alice", createdOps, ops.DeepCopy()); err == nil {
+		t.Error("expected the creator can not approve the OpsRequest")
+	}
+
+	ops.Annotations[constant.OpsCreatedByAnnotationKey] = "bob"
+	if err := defaultOpsRequest(t, admissionv1.Update, "bob", createdOps, ops); err != nil {
+		t.Fatal(err)
+	}
+	if ops.Annotations[constant.OpsCreatedByAnnotationKey] != "alice" {
+		t.Errorf("expected the creator is immutable, got %v", ops.Annotations)
+	}
+	if ops.Annotations[constant.OpsApprovedByAnnotationKey] != "bob" {
+		t.Errorf("expected the approver is recorded, got %v", ops.Annotations)
+	}
+
+	approvedOps := ops.DeepCopy()
+	ops.Annotations[constant.OpsApprovedByAnnotationKey] = "carol"
+	if err := defaultOpsRequest(t, admissionv1.Update, "carol", approvedOps, ops); err != nil {
+		t.Fatal(err)
+	}
+	if ops.Annotations[constant.OpsApprovedByAnnotationKey] != "bob" {
+		t.Errorf("expected the approver is immutable, got %v", ops.Annotations)
+	}
+}
+
+func TestOpsApprovalPolicyMatches(t *testing.T) {
+	policy := &OpsApprovalPolicy{Spec: OpsApprovalPolicySpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "production"}},
+		OpsTypes:          []OpsType{RestartType},
+	}}
+	for _, c := range []struct {
+		opsType         OpsType
+		namespaceLabels map[string]string
+		clusterLabels   map[string]string
+		expected        bool
+	}{
+		{RestartType, map[string]string{"env": "production"}, nil, true},
+		{RestartType, map[string]string{"env": "test"}, nil, false},
+		{StopType, map[string]string{"env": "production"}, nil, false},
+	} {
+		matched, err := policy.Matches(c.opsType, c.namespaceLabels, c.clusterLabels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matched != c.expected {
+			t.Errorf("expected %v for the %s OpsRequest in namespace %v, got %v", c.expected, c.opsType, c.namespaceLabels, matched)
+		}
+	}
+
+	policy.Spec.ClusterSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "critical"}}
+	if matched, _ := policy.Matches(RestartType, map[string]string{"env": "production"}, nil); matched {
+		t.Error("expected the Cluster is not selected")
+	}
+	if matched, _ := policy.Matches(RestartType, map[string]string{"env": "production"}, map[string]string{"tier": "critical"}); !matched {
+		t.Error("expected the Cluster is selected")
+	}
+}
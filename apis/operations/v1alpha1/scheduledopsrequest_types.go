@@ -0,0 +1,205 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy describes how a ScheduledOpsRequest handles a new run while the OpsRequests
+// created by the previous runs are still in progress.
+//
+// +enum
+// +kubebuilder:validation:Enum={Allow,Forbid,Replace}
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows the OpsRequests to run concurrently.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+
+	// ForbidConcurrent skips the new run if the previous OpsRequest has not completed yet.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+
+	// ReplaceConcurrent cancels the OpsRequests that are still in progress and creates a new one.
+	// OpsRequests that do not support cancellation are left running, and the new run is skipped.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// ScheduledOpsRequestSpec defines the desired state of ScheduledOpsRequest.
+type ScheduledOpsRequestSpec struct {
+	// Specifies the schedule in the standard cron format, e.g. "0 3 * * 0" for 03:00 every Sunday.
+	// Predefined schedules such as "@daily" and "@weekly" are also supported.
+	// The time zone can be specified by the prefix "CRON_TZ=" as well, e.g. "CRON_TZ=Asia/Shanghai 0 3 * * 0",
+	// which takes precedence over the `timeZone`.
+	//
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// Specifies the IANA name of the time zone in which the schedule is interpreted, e.g. "Asia/Shanghai".
+	// Defaults to UTC.
+	//
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Specifies the deadline in seconds for starting a run if it misses its scheduled time for any reason.
+	// Missed runs beyond the deadline are counted as skipped.
+	// If not specified, the most recent missed run is started no matter how late it is.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// Specifies how to treat concurrent runs. Valid values are:
+	//
+	// - Allow: allows the OpsRequests to run concurrently.
+	// - Forbid: skips the new run if the previous OpsRequest has not completed yet.
+	// - Replace: cancels the OpsRequests in progress and creates a new one.
+	//   Only the "VerticalScaling" and "HorizontalScaling" OpsRequests can be canceled,
+	//   the new run is skipped if any other OpsRequest is still in progress.
+	//
+	// +kubebuilder:default=Forbid
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspends the subsequent runs if set to true. The OpsRequests that have been created are not affected.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Specifies the number of succeeded OpsRequests to retain.
+	//
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulHistoryLimit *int32 `json:"successfulHistoryLimit,omitempty"`
+
+	// Specifies the number of failed, canceled or aborted OpsRequests to retain.
+	//
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedHistoryLimit *int32 `json:"failedHistoryLimit,omitempty"`
+
+	// Specifies the template of the OpsRequests that are created on schedule.
+	//
+	// +kubebuilder:validation:Required
+	OpsRequestTemplate OpsRequestTemplate `json:"opsRequestTemplate"`
+}
+
+// OpsRequestTemplate describes the OpsRequest that will be created on schedule.
+type OpsRequestTemplate struct {
+	// Specifies the labels of the OpsRequest.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Specifies the annotations of the OpsRequest.
+	//
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Specifies the spec of the OpsRequest.
+	//
+	// The schema is not validated here, as the immutable fields of the OpsRequest would otherwise
+	// prevent the template from being updated. It is validated when the OpsRequest is created.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec OpsRequestSpec `json:"spec"`
+}
+
+// ScheduledOpsRequestStatus defines the observed state of ScheduledOpsRequest.
+type ScheduledOpsRequestStatus struct {
+	// Represents the most recent generation observed for this ScheduledOpsRequest.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Represents the current phase of the ScheduledOpsRequest.
+	// It is "Unavailable" if the schedule or the time zone is invalid.
+	//
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Provides additional information about the current phase.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Lists the names of the OpsRequests that are still in progress.
+	//
+	// +optional
+	Active []string `json:"active,omitempty"`
+
+	// Records the last time an OpsRequest was successfully scheduled.
+	//
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Records the last time an OpsRequest successfully completed.
+	//
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// Records the scheduled time of the last run skipped, as the OpsRequests of the previous runs are still in progress.
+	//
+	// +optional
+	LastSkippedTime *metav1.Time `json:"lastSkippedTime,omitempty"`
+
+	// Records the next time an OpsRequest will be scheduled.
+	//
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories={kubeblocks},shortName=sops
+// +kubebuilder:printcolumn:name="SCHEDULE",type="string",JSONPath=".spec.schedule",description="The cron schedule."
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.opsRequestTemplate.spec.type",description="Operation request type."
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.opsRequestTemplate.spec.clusterName",description="Operand cluster."
+// +kubebuilder:printcolumn:name="SUSPEND",type="boolean",JSONPath=".spec.suspend",description="Whether the schedule is suspended."
+// +kubebuilder:printcolumn:name="LAST-SCHEDULE",type="date",JSONPath=".status.lastScheduleTime",description="The last time an OpsRequest was scheduled."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ScheduledOpsRequest is the Schema for the scheduledopsrequests API.
+// It creates OpsRequests from the template on a cron schedule, e.g. weekly restarts or nightly switchovers.
+type ScheduledOpsRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScheduledOpsRequestSpec   `json:"spec,omitempty"`
+	Status ScheduledOpsRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScheduledOpsRequestList contains a list of ScheduledOpsRequest
+type ScheduledOpsRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScheduledOpsRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScheduledOpsRequest{}, &ScheduledOpsRequestList{})
+}
@@ -42,18 +42,19 @@ const (
 
 // OpsPhase defines opsRequest phase.
 // +enum
-// +kubebuilder:validation:Enum={Pending,Creating,Running,Cancelling,Cancelled,Aborted,Failed,Succeed}
+// +kubebuilder:validation:Enum={Pending,PendingApproval,Creating,Running,Cancelling,Cancelled,Aborted,Failed,Succeed}
 type OpsPhase string
 
 const (
-	OpsPendingPhase    OpsPhase = "Pending"
-	OpsCreatingPhase   OpsPhase = "Creating"
-	OpsRunningPhase    OpsPhase = "Running"
-	OpsCancellingPhase OpsPhase = "Cancelling"
-	OpsSucceedPhase    OpsPhase = "Succeed"
-	OpsCancelledPhase  OpsPhase = "Cancelled"
-	OpsFailedPhase     OpsPhase = "Failed"
-	OpsAbortedPhase    OpsPhase = "Aborted"
+	OpsPendingPhase         OpsPhase = "Pending"
+	OpsPendingApprovalPhase OpsPhase = "PendingApproval"
+	OpsCreatingPhase        OpsPhase = "Creating"
+	OpsRunningPhase         OpsPhase = "Running"
+	OpsCancellingPhase      OpsPhase = "Cancelling"
+	OpsSucceedPhase         OpsPhase = "Succeed"
+	OpsCancelledPhase       OpsPhase = "Cancelled"
+	OpsFailedPhase          OpsPhase = "Failed"
+	OpsAbortedPhase         OpsPhase = "Aborted"
 )
 
 // Phase represents the current status of the ClusterDefinition CR.
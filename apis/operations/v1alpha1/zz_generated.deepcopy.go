@@ -26,9 +26,9 @@ package v1alpha1
 import (
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
-	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -224,6 +224,36 @@ func (in *CustomOpsComponent) DeepCopy() *CustomOpsComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunParameter) DeepCopyInto(out *DryRunParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunParameter.
+func (in *DryRunParameter) DeepCopy() *DryRunParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunPod) DeepCopyInto(out *DryRunPod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunPod.
+func (in *DryRunPod) DeepCopy() *DryRunPod {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunPod)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvVarRef) DeepCopyInto(out *EnvVarRef) {
 	*out = *in
@@ -489,6 +519,115 @@ func (in *OpsAction) DeepCopy() *OpsAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsApproval) DeepCopyInto(out *OpsApproval) {
+	*out = *in
+	in.ApprovalTimestamp.DeepCopyInto(&out.ApprovalTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsApproval.
+func (in *OpsApproval) DeepCopy() *OpsApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsApprovalPolicy) DeepCopyInto(out *OpsApprovalPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsApprovalPolicy.
+func (in *OpsApprovalPolicy) DeepCopy() *OpsApprovalPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsApprovalPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpsApprovalPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsApprovalPolicyList) DeepCopyInto(out *OpsApprovalPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpsApprovalPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsApprovalPolicyList.
+func (in *OpsApprovalPolicyList) DeepCopy() *OpsApprovalPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsApprovalPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpsApprovalPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsApprovalPolicySpec) DeepCopyInto(out *OpsApprovalPolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpsTypes != nil {
+		in, out := &in.OpsTypes, &out.OpsTypes
+		*out = make([]OpsType, len(*in))
+		copy(*out, *in)
+	}
+	if in.ApprovalTimeoutSeconds != nil {
+		in, out := &in.ApprovalTimeoutSeconds, &out.ApprovalTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsApprovalPolicySpec.
+func (in *OpsApprovalPolicySpec) DeepCopy() *OpsApprovalPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsApprovalPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsDefinition) DeepCopyInto(out *OpsDefinition) {
 	*out = *in
@@ -609,6 +748,31 @@ func (in *OpsDefinitionStatus) DeepCopy() *OpsDefinitionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsDryRunResult) DeepCopyInto(out *OpsDryRunResult) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]DryRunPod, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]DryRunParameter, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsDryRunResult.
+func (in *OpsDryRunResult) DeepCopy() *OpsDryRunResult {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsDryRunResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsEnvVar) DeepCopyInto(out *OpsEnvVar) {
 	*out = *in
@@ -649,6 +813,161 @@ func (in *OpsExecAction) DeepCopy() *OpsExecAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsPipeline) DeepCopyInto(out *OpsPipeline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsPipeline.
+func (in *OpsPipeline) DeepCopy() *OpsPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpsPipeline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsPipelineList) DeepCopyInto(out *OpsPipelineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpsPipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsPipelineList.
+func (in *OpsPipelineList) DeepCopy() *OpsPipelineList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsPipelineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpsPipelineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsPipelineSpec) DeepCopyInto(out *OpsPipelineSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]OpsPipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsPipelineSpec.
+func (in *OpsPipelineSpec) DeepCopy() *OpsPipelineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsPipelineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsPipelineStatus) DeepCopyInto(out *OpsPipelineStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]OpsPipelineStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Compensations != nil {
+		in, out := &in.Compensations, &out.Compensations
+		*out = make([]OpsPipelineStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.StartTimestamp.DeepCopyInto(&out.StartTimestamp)
+	in.CompletionTimestamp.DeepCopyInto(&out.CompletionTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsPipelineStatus.
+func (in *OpsPipelineStatus) DeepCopy() *OpsPipelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsPipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsPipelineStep) DeepCopyInto(out *OpsPipelineStep) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Compensation != nil {
+		in, out := &in.Compensation, &out.Compensation
+		*out = new(OpsRequestSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsPipelineStep.
+func (in *OpsPipelineStep) DeepCopy() *OpsPipelineStep {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsPipelineStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsPipelineStepStatus) DeepCopyInto(out *OpsPipelineStepStatus) {
+	*out = *in
+	in.StartTimestamp.DeepCopyInto(&out.StartTimestamp)
+	in.CompletionTimestamp.DeepCopyInto(&out.CompletionTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsPipelineStepStatus.
+func (in *OpsPipelineStepStatus) DeepCopy() *OpsPipelineStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsPipelineStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsRecorder) DeepCopyInto(out *OpsRecorder) {
 	*out = *in
@@ -840,9 +1159,19 @@ func (in *OpsRequestStatus) DeepCopyInto(out *OpsRequestStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.DryRunResult != nil {
+		in, out := &in.DryRunResult, &out.DryRunResult
+		*out = new(OpsDryRunResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(OpsApproval)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -859,6 +1188,36 @@ func (in *OpsRequestStatus) DeepCopy() *OpsRequestStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsRequestTemplate) DeepCopyInto(out *OpsRequestTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsRequestTemplate.
+func (in *OpsRequestTemplate) DeepCopy() *OpsRequestTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsRequestTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpsRequestVolumeClaimTemplate) DeepCopyInto(out *OpsRequestVolumeClaimTemplate) {
 	*out = *in
@@ -909,7 +1268,7 @@ func (in *OpsService) DeepCopyInto(out *OpsService) {
 	}
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
-		*out = make([]v1.ServicePort, len(*in))
+		*out = make([]corev1.ServicePort, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -923,12 +1282,12 @@ func (in *OpsService) DeepCopyInto(out *OpsService) {
 	}
 	if in.IPFamilies != nil {
 		in, out := &in.IPFamilies, &out.IPFamilies
-		*out = make([]v1.IPFamily, len(*in))
+		*out = make([]corev1.IPFamily, len(*in))
 		copy(*out, *in)
 	}
 	if in.IPFamilyPolicy != nil {
 		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
-		*out = new(v1.IPFamilyPolicy)
+		*out = new(corev1.IPFamilyPolicy)
 		**out = **in
 	}
 }
@@ -953,7 +1312,7 @@ func (in *OpsVarSource) DeepCopyInto(out *OpsVarSource) {
 	}
 	if in.FieldRef != nil {
 		in, out := &in.FieldRef, &out.FieldRef
-		*out = new(v1.ObjectFieldSelector)
+		*out = new(corev1.ObjectFieldSelector)
 		**out = **in
 	}
 }
@@ -1051,12 +1410,12 @@ func (in *ParameterSource) DeepCopyInto(out *ParameterSource) {
 	*out = *in
 	if in.ConfigMapKeyRef != nil {
 		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
-		*out = new(v1.ConfigMapKeySelector)
+		*out = new(corev1.ConfigMapKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.SecretKeyRef != nil {
 		in, out := &in.SecretKeyRef, &out.SecretKeyRef
-		*out = new(v1.SecretKeySelector)
+		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -1103,7 +1462,7 @@ func (in *PodInfoExtractor) DeepCopyInto(out *PodInfoExtractor) {
 	out.PodSelector = in.PodSelector
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
+		*out = make([]corev1.VolumeMount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1223,7 +1582,7 @@ func (in *RebuildInstance) DeepCopyInto(out *RebuildInstance) {
 	}
 	if in.RestoreEnv != nil {
 		in, out := &in.RestoreEnv, &out.RestoreEnv
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1268,7 +1627,7 @@ func (in *ReconfiguringStatus) DeepCopyInto(out *ReconfiguringStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1337,7 +1696,7 @@ func (in *Restore) DeepCopyInto(out *Restore) {
 	*out = *in
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1418,6 +1777,132 @@ func (in *ScaleOut) DeepCopy() *ScaleOut {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledOpsRequest) DeepCopyInto(out *ScheduledOpsRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledOpsRequest.
+func (in *ScheduledOpsRequest) DeepCopy() *ScheduledOpsRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledOpsRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledOpsRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledOpsRequestList) DeepCopyInto(out *ScheduledOpsRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledOpsRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledOpsRequestList.
+func (in *ScheduledOpsRequestList) DeepCopy() *ScheduledOpsRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledOpsRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledOpsRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledOpsRequestSpec) DeepCopyInto(out *ScheduledOpsRequestSpec) {
+	*out = *in
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuccessfulHistoryLimit != nil {
+		in, out := &in.SuccessfulHistoryLimit, &out.SuccessfulHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedHistoryLimit != nil {
+		in, out := &in.FailedHistoryLimit, &out.FailedHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	in.OpsRequestTemplate.DeepCopyInto(&out.OpsRequestTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledOpsRequestSpec.
+func (in *ScheduledOpsRequestSpec) DeepCopy() *ScheduledOpsRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledOpsRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledOpsRequestStatus) DeepCopyInto(out *ScheduledOpsRequestStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSkippedTime != nil {
+		in, out := &in.LastSkippedTime, &out.LastSkippedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledOpsRequestStatus.
+func (in *ScheduledOpsRequestStatus) DeepCopy() *ScheduledOpsRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledOpsRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpecificOpsRequest) DeepCopyInto(out *SpecificOpsRequest) {
 	*out = *in
@@ -1597,6 +2082,11 @@ func (in *Upgrade) DeepCopyInto(out *Upgrade) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(UpgradeAutoRollback)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
@@ -1609,6 +2099,21 @@ func (in *Upgrade) DeepCopy() *Upgrade {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeAutoRollback) DeepCopyInto(out *UpgradeAutoRollback) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeAutoRollback.
+func (in *UpgradeAutoRollback) DeepCopy() *UpgradeAutoRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeAutoRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpgradeComponent) DeepCopyInto(out *UpgradeComponent) {
 	*out = *in
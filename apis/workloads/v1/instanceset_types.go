@@ -215,6 +215,20 @@ type InstanceSetSpec struct {
 	// +optional
 	MemberUpdateStrategy *MemberUpdateStrategy `json:"memberUpdateStrategy,omitempty"`
 
+	// Specifies a canary rollout for the updates of instances.
+	//
+	// The instances are updated step by step, and the rollout pauses after each step so that the updated instances
+	// can be verified before it continues. Within a step, instances are still picked in the order of role priority
+	// and limited by the `updateStrategy` and `memberUpdateStrategy`.
+	//
+	// A paused rollout can be promoted to the next step by annotating the InstanceSet with
+	// `workloads.kubeblocks.io/canary-promote=true`, or aborted with `workloads.kubeblocks.io/canary-abort=true`.
+	// Once aborted, no more instances are updated until the instance templates are changed again,
+	// and rolling back to the previous revision is not gated by the canary steps.
+	//
+	// +optional
+	CanaryStrategy *CanaryStrategy `json:"canaryStrategy,omitempty"`
+
 	// Indicates that the InstanceSet is paused, meaning the reconciliation of this InstanceSet object will be paused.
 	// +optional
 	Paused bool `json:"paused,omitempty"`
@@ -309,6 +323,49 @@ type InstanceSetStatus struct {
 	// TemplatesStatus represents status of each instance generated by InstanceTemplates
 	// +optional
 	TemplatesStatus []InstanceTemplateStatus `json:"templatesStatus,omitempty"`
+
+	// Represents the progress of the canary rollout, it's set only when spec.canaryStrategy is specified.
+	//
+	// +optional
+	CanaryStatus *CanaryStatus `json:"canaryStatus,omitempty"`
+
+	// Provides the status of the instances on each data-plane k8s cluster, it's set only in the multi-cluster mode.
+	//
+	// +listType=map
+	// +listMapKey=context
+	// +optional
+	DataClusters []DataClusterStatus `json:"dataClusters,omitempty"`
+}
+
+// DataClusterStatus represents the status of the instances on a data-plane k8s cluster.
+type DataClusterStatus struct {
+	// The context of the data-plane k8s cluster.
+	//
+	// +kubebuilder:validation:Required
+	Context string `json:"context"`
+
+	// The number of instances on the data-plane k8s cluster.
+	Replicas int32 `json:"replicas"`
+
+	// The number of instances on the data-plane k8s cluster with a Ready Condition.
+	//
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// The number of available instances (ready for at least minReadySeconds) on the data-plane k8s cluster.
+	//
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// The number of instances of each role on the data-plane k8s cluster, keyed by the role name.
+	//
+	// +optional
+	Roles map[string]int32 `json:"roles,omitempty"`
+
+	// The last error met when accessing the data-plane k8s cluster, it's empty if the data-plane k8s cluster is healthy.
+	//
+	// +optional
+	LastError string `json:"lastError,omitempty"`
 }
 
 // Range represents a range with a start and an end value.
@@ -600,6 +657,9 @@ type MembershipReconfiguration struct {
 	// Defines the action to perform a switchover.
 	// If the Image is not configured, the latest [BusyBox](https://busybox.net/) image will be used.
 	//
+	// It's also used to move the leadership off the leader running on a node being cordoned or drained.
+	// That switchover is best-effort, the eviction of the leader is not gated on it.
+	//
 	// +optional
 	SwitchoverAction *Action `json:"switchoverAction,omitempty"`
 
@@ -638,6 +698,92 @@ const (
 	ParallelUpdateStrategy           MemberUpdateStrategy = "Parallel"
 )
 
+// CanaryStrategy defines the steps of a canary rollout.
+type CanaryStrategy struct {
+	// Defines the steps of the rollout, the instances left after the last step are updated without pausing.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	Steps []CanaryStep `json:"steps"`
+}
+
+// CanaryStep defines a step of a canary rollout.
+type CanaryStep struct {
+	// Specifies the total number of instances that should be updated once the step is done.
+	// The value can be an absolute number (ex: 1) or a percentage of the replicas (ex: 20%), which is rounded up.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Required
+	Replicas intstr.IntOrString `json:"replicas"`
+
+	// Specifies how long the rollout pauses after the updated instances are available.
+	// The rollout continues with the next step only if all the updated instances are still available when the pause ends.
+	// If it's not set, the rollout pauses until it's promoted.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PauseSeconds *int32 `json:"pauseSeconds,omitempty"`
+}
+
+// CanaryPhase defines the phase of a canary rollout.
+//
+// +enum
+// +kubebuilder:validation:Enum={Progressing,Paused,Completed,Aborted}
+type CanaryPhase string
+
+const (
+	// CanaryProgressingPhase indicates that the instances of the current step are being updated.
+	CanaryProgressingPhase CanaryPhase = "Progressing"
+
+	// CanaryPausedPhase indicates that the instances of the current step have been updated, and the rollout is
+	// waiting for the pause to end or to be promoted.
+	CanaryPausedPhase CanaryPhase = "Paused"
+
+	// CanaryCompletedPhase indicates that all the instances have been updated.
+	CanaryCompletedPhase CanaryPhase = "Completed"
+
+	// CanaryAbortedPhase indicates that the rollout has been aborted.
+	CanaryAbortedPhase CanaryPhase = "Aborted"
+)
+
+// CanaryStatus represents the progress of a canary rollout.
+type CanaryStatus struct {
+	// The revision of the instance templates being rolled out.
+	//
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// The revision of the instance templates that was completely rolled out before.
+	// Rolling back to this revision is not gated by the canary steps.
+	//
+	// +optional
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// The index of the current step. It equals the number of steps when all the steps are done,
+	// and the remaining instances are being updated.
+	CurrentStepIndex int32 `json:"currentStepIndex"`
+
+	// The total number of instances that should be updated in the current step.
+	//
+	// +optional
+	CurrentStepReplicas int32 `json:"currentStepReplicas,omitempty"`
+
+	// The phase of the rollout.
+	//
+	// +optional
+	Phase CanaryPhase `json:"phase,omitempty"`
+
+	// The time when the current step was paused.
+	//
+	// +optional
+	PauseStartTime *metav1.Time `json:"pauseStartTime,omitempty"`
+
+	// A human-readable message indicating details about the rollout.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 type Credential struct {
 	// Defines the user's name for the credential.
 	// The corresponding environment variable will be KB_ITS_USERNAME.
@@ -55,6 +55,68 @@ func (in *Action) DeepCopy() *Action {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	if in.PauseStartTime != nil {
+		in, out := &in.PauseStartTime, &out.PauseStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStep) DeepCopyInto(out *CanaryStep) {
+	*out = *in
+	out.Replicas = in.Replicas
+	if in.PauseSeconds != nil {
+		in, out := &in.PauseSeconds, &out.PauseSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStep.
+func (in *CanaryStep) DeepCopy() *CanaryStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CanaryStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStrategy.
+func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Credential) DeepCopyInto(out *Credential) {
 	*out = *in
@@ -92,6 +154,28 @@ func (in *CredentialVar) DeepCopy() *CredentialVar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataClusterStatus) DeepCopyInto(out *DataClusterStatus) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataClusterStatus.
+func (in *DataClusterStatus) DeepCopy() *DataClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceSet) DeepCopyInto(out *InstanceSet) {
 	*out = *in
@@ -211,6 +295,11 @@ func (in *InstanceSetSpec) DeepCopyInto(out *InstanceSetSpec) {
 		*out = new(MemberUpdateStrategy)
 		**out = **in
 	}
+	if in.CanaryStrategy != nil {
+		in, out := &in.CanaryStrategy, &out.CanaryStrategy
+		*out = new(CanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Credential != nil {
 		in, out := &in.Credential, &out.Credential
 		*out = new(Credential)
@@ -264,6 +353,18 @@ func (in *InstanceSetStatus) DeepCopyInto(out *InstanceSetStatus) {
 		*out = make([]InstanceTemplateStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.CanaryStatus != nil {
+		in, out := &in.CanaryStatus, &out.CanaryStatus
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataClusters != nil {
+		in, out := &in.DataClusters, &out.DataClusters
+		*out = make([]DataClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceSetStatus.
@@ -330,6 +330,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&dpcontrollers.BackupVerificationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("backup-verification-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BackupVerification")
+		os.Exit(1)
+	}
+
 	if err = (&dpcontrollers.BackupPolicyTemplateReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
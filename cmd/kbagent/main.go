@@ -54,9 +54,13 @@ func init() {
 	pflag.StringVar(&serverConfig.UnixDomainSocket, "unix-socket", "", "The path of the Unix Domain Socket for kb-agent service.")
 	pflag.IntVar(&serverConfig.Port, "port", kbagent.DefaultHTTPPort, "The HTTP Server listen port for kb-agent service.")
 	pflag.IntVar(&serverConfig.StreamingPort, "streaming-port", kbagent.DefaultStreamingPort, "The listen port used by kb-agent to stream data.")
+	pflag.IntVar(&serverConfig.GRPCPort, "grpc-port", 0, "The gRPC Server listen port for kb-agent service, disable the gRPC server if <=0.")
 	pflag.IntVar(&serverConfig.Concurrency, "max-concurrency", defaultMaxConcurrency,
 		fmt.Sprintf("The maximum number of concurrent connections the Server may serve, use the default value %d if <=0.", defaultMaxConcurrency))
 	pflag.BoolVar(&serverConfig.Logging, "api-logging", true, "Enable api logging for kb-agent request.")
+	pflag.StringVar(&serverConfig.TLSCertFile, "tls-cert-file", "", "The certificate file to serve with mutual TLS, which is also presented to the remote kb-agents.")
+	pflag.StringVar(&serverConfig.TLSKeyFile, "tls-key-file", "", "The private key file of the certificate.")
+	pflag.StringVar(&serverConfig.TLSCAFile, "tls-ca-file", "", "The CA file to verify the certificates of the clients and remote kb-agents.")
 }
 
 func main() {
@@ -39,6 +39,7 @@ import (
 	discoverycli "k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -115,6 +116,7 @@ func init() {
 	utilruntime.Must(workloadsv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(workloadsv1.AddToScheme(scheme))
 	utilruntime.Must(experimentalv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(metricsv1beta1.AddToScheme(scheme))
 
 	// +kubebuilder:scaffold:scheme
 
@@ -437,6 +439,15 @@ func main() {
 			os.Exit(1)
 		}
 
+		if err = (&appscontrollers.ClusterDisasterRecoveryReconciler{
+			Client:   client,
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("cluster-disaster-recovery-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterDisasterRecovery")
+			os.Exit(1)
+		}
+
 		if err = (&appscontrollers.ServiceDescriptorReconciler{
 			Client:   mgr.GetClient(),
 			Scheme:   mgr.GetScheme(),
@@ -512,6 +523,24 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "OpsRequest")
 			os.Exit(1)
 		}
+
+		if err = (&opscontrollers.ScheduledOpsRequestReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("scheduled-ops-request-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ScheduledOpsRequest")
+			os.Exit(1)
+		}
+
+		if err = (&opscontrollers.OpsPipelineReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("ops-pipeline-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OpsPipeline")
+			os.Exit(1)
+		}
 	}
 
 	if viper.GetBool(extensionsFlagKey.viperName()) {
@@ -535,6 +564,24 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "NodeCountScaler")
 			os.Exit(1)
 		}
+		if err = (&experimentalcontrollers.MetricsScalerReconciler{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Scheme:    mgr.GetScheme(),
+			Recorder:  mgr.GetEventRecorderFor("metrics-scaler-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "MetricsScaler")
+			os.Exit(1)
+		}
+		if err = (&experimentalcontrollers.VerticalScalerReconciler{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Scheme:    mgr.GetScheme(),
+			Recorder:  mgr.GetEventRecorderFor("vertical-scaler-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "VerticalScaler")
+			os.Exit(1)
+		}
 	}
 
 	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
@@ -567,6 +614,14 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	// the OpsRequest webhook records the creators and approvers required by the OpsApprovalPolicy,
+	// it can be enabled alone since the approval is an opt-in feature.
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" || viper.GetBool(constant.CfgEnableOpsApprovalWebhook) {
+		if err = (&opsv1alpha1.OpsRequest{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OpsRequest")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -22,6 +22,8 @@ package apps
 import (
 	"context"
 	"math"
+	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,7 +32,9 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
@@ -171,7 +175,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
+	b := intctrlutil.NewNamespacedControllerManagedBy(mgr).
 		For(&appsv1.Cluster{}).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: int(math.Ceil(viper.GetFloat64(constant.CfgKBReconcileWorkers) / 4)),
@@ -180,6 +184,27 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Service{}). // cluster services
 		Owns(&corev1.Secret{}).  // sharding account secret
 		Owns(&dpv1alpha1.BackupPolicy{}).
-		Owns(&dpv1alpha1.BackupSchedule{}).
-		Complete(r)
+		Owns(&dpv1alpha1.BackupSchedule{})
+
+	if r.MultiClusterMgr != nil {
+		// re-evaluate the placement when the health of the data-plane k8s clusters changes
+		r.MultiClusterMgr.WatchHealth(b, handler.EnqueueRequestsFromMapFunc(r.filterClustersByDataCluster))
+	}
+
+	return b.Complete(r)
+}
+
+// filterClustersByDataCluster returns the clusters placed on the data-plane k8s cluster named by the object.
+func (r *ClusterReconciler) filterClustersByDataCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &appsv1.ClusterList{}
+	if err := r.Client.List(ctx, clusters); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0)
+	for _, cluster := range clusters.Items {
+		if slices.Contains(strings.Split(placement(&cluster), ","), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cluster)})
+		}
+	}
+	return requests
 }
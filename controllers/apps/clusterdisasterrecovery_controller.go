@@ -0,0 +1,638 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/restore"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/operations"
+)
+
+const (
+	drTransitionRequeueInterval = 5 * time.Second
+)
+
+// ClusterDisasterRecoveryReconciler reconciles a ClusterDisasterRecovery object
+type ClusterDisasterRecoveryReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=clusterdisasterrecoveries,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=clusterdisasterrecoveries/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=clusterdisasterrecoveries/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *ClusterDisasterRecoveryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      ctx,
+		Req:      req,
+		Log:      log.FromContext(ctx).WithValues("clusterDisasterRecovery", req.NamespacedName),
+		Recorder: r.Recorder,
+	}
+
+	cdr := &appsv1.ClusterDisasterRecovery{}
+	if err := r.Client.Get(reqCtx.Ctx, reqCtx.Req.NamespacedName, cdr); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	// the clusters are not owned by the ClusterDisasterRecovery, and they are left as they are.
+	if !cdr.DeletionTimestamp.IsZero() {
+		return intctrlutil.Reconciled()
+	}
+
+	oldCDR := cdr.DeepCopy()
+	requeueAfter, reconcileErr := r.reconcile(reqCtx, cdr)
+	if !apiequality.Semantic.DeepEqual(oldCDR.Status, cdr.Status) {
+		if err := r.Client.Status().Patch(reqCtx.Ctx, cdr, client.MergeFrom(oldCDR)); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		if oldCDR.Status.Phase != cdr.Status.Phase {
+			r.Recorder.Eventf(cdr, corev1.EventTypeNormal, string(cdr.Status.Phase), "the phase is changed from %s to %s", oldCDR.Status.Phase, cdr.Status.Phase)
+		}
+	}
+	// consume the transition requests after they have been accepted in the status.
+	if !apiequality.Semantic.DeepEqual(oldCDR.Annotations, cdr.Annotations) {
+		patch := client.MergeFrom(oldCDR.DeepCopy())
+		oldCDR.Annotations = cdr.Annotations
+		if err := r.Client.Patch(reqCtx.Ctx, oldCDR, patch); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+	if reconcileErr != nil {
+		return intctrlutil.CheckedRequeueWithError(reconcileErr, reqCtx.Log, "")
+	}
+	return intctrlutil.RequeueAfter(requeueAfter, reqCtx.Log, "")
+}
+
+func (r *ClusterDisasterRecoveryReconciler) reconcile(reqCtx intctrlutil.RequestCtx, cdr *appsv1.ClusterDisasterRecovery) (time.Duration, error) {
+	probePeriod := time.Duration(max(cdr.Spec.LagProbePeriodSeconds, 1)) * time.Second
+
+	cdr.Status.ObservedGeneration = cdr.Generation
+	if cdr.Status.PrimaryCluster == "" {
+		cdr.Status.PrimaryCluster = cdr.Spec.PrimaryCluster
+		cdr.Status.StandbyCluster = cdr.Spec.Standby.ClusterName
+		cdr.Status.Phase = appsv1.DRProvisioningPhase
+	}
+	if cdr.Status.Phase == appsv1.DRFailedPhase {
+		return 0, nil
+	}
+	if cdr.Spec.PrimaryCluster == cdr.Spec.Standby.ClusterName {
+		setDRPhase(cdr, appsv1.DRFailedPhase, "the primary and standby clusters must be different")
+		return 0, nil
+	}
+	syncComponentReplicationStatus(cdr)
+
+	primary, err := r.getCluster(reqCtx.Ctx, cdr.Namespace, cdr.Status.PrimaryCluster)
+	if err != nil {
+		return 0, err
+	}
+	standby, err := r.getCluster(reqCtx.Ctx, cdr.Namespace, cdr.Status.StandbyCluster)
+	if err != nil {
+		return 0, err
+	}
+
+	r.acceptTransitionRequest(cdr, standby)
+	if cdr.Status.Transition != nil {
+		return r.transit(reqCtx, cdr, primary, standby)
+	}
+
+	switch {
+	case primary == nil:
+		setDRPhase(cdr, appsv1.DRProvisioningPhase, fmt.Sprintf("the primary cluster %s is not found", cdr.Status.PrimaryCluster))
+		return probePeriod, nil
+	case standby == nil && cdr.Status.StandbyCluster != cdr.Spec.Standby.ClusterName:
+		// the former primary cluster has gone after the failover.
+		setDRPhase(cdr, appsv1.DRProvisioningPhase, fmt.Sprintf("the standby cluster %s is not found", cdr.Status.StandbyCluster))
+		return probePeriod, nil
+	case standby == nil:
+		return probePeriod, r.provisionStandby(reqCtx, cdr, primary)
+	}
+	if standby.Status.Phase != appsv1.RunningClusterPhase {
+		setDRPhase(cdr, appsv1.DRProvisioningPhase, fmt.Sprintf("waiting for the standby cluster %s to be running", standby.Name))
+		return probePeriod, nil
+	}
+	if !allComponentsReplicating(cdr) {
+		if primary.Status.Phase != appsv1.RunningClusterPhase {
+			setDRPhase(cdr, appsv1.DRProvisioningPhase, fmt.Sprintf("waiting for the primary cluster %s to be running", primary.Name))
+			return probePeriod, nil
+		}
+		r.replicate(reqCtx, cdr, primary, standby)
+		if !allComponentsReplicating(cdr) {
+			setDRPhase(cdr, appsv1.DRProvisioningPhase, "failed to establish the replication of the standby cluster")
+			return drTransitionRequeueInterval, nil
+		}
+	}
+	cdr.Status.StandbyDiverged = false
+	setDRPhase(cdr, appsv1.DRReplicatingPhase, "")
+
+	r.probeReplicationLag(reqCtx, cdr, standby, probePeriod)
+	return probePeriod, nil
+}
+
+// acceptTransitionRequest accepts the switchover or failover requested by the annotations, and consumes them.
+func (r *ClusterDisasterRecoveryReconciler) acceptTransitionRequest(cdr *appsv1.ClusterDisasterRecovery, standby *appsv1.Cluster) {
+	switchover := cdr.Annotations[constant.DRSwitchoverAnnotationKey] == trueVal
+	failover := cdr.Annotations[constant.DRFailoverAnnotationKey] == trueVal
+	if !switchover && !failover {
+		return
+	}
+	delete(cdr.Annotations, constant.DRSwitchoverAnnotationKey)
+	delete(cdr.Annotations, constant.DRFailoverAnnotationKey)
+
+	transition := &appsv1.DRTransitionStatus{
+		Type:      appsv1.DRSwitchoverTransition,
+		Step:      appsv1.DRFencingStep,
+		StartTime: &metav1.Time{Time: time.Now()},
+	}
+	if failover {
+		transition.Type = appsv1.DRFailoverTransition
+		transition.Step = appsv1.DRPromotingStep
+	}
+	switch {
+	case standby == nil || standby.Status.Phase != appsv1.RunningClusterPhase:
+		r.Recorder.Eventf(cdr, corev1.EventTypeWarning, "TransitionRejected",
+			"the %s is rejected, the standby cluster %s is not running", strings.ToLower(string(transition.Type)), cdr.Status.StandbyCluster)
+		return
+	case cdr.Status.StandbyDiverged:
+		r.Recorder.Eventf(cdr, corev1.EventTypeWarning, "TransitionRejected",
+			"the %s is rejected, the standby cluster %s was demoted by a failover and has not replicated from the primary cluster yet",
+			strings.ToLower(string(transition.Type)), cdr.Status.StandbyCluster)
+		return
+	case cdr.Status.Transition != nil && (!failover || cdr.Status.Transition.Type == appsv1.DRFailoverTransition):
+		r.Recorder.Eventf(cdr, corev1.EventTypeWarning, "TransitionRejected",
+			"the %s is rejected, a %s is in progress", strings.ToLower(string(transition.Type)), strings.ToLower(string(cdr.Status.Transition.Type)))
+		return
+	case switchover && !failover && cdr.Status.Phase != appsv1.DRReplicatingPhase:
+		r.Recorder.Eventf(cdr, corev1.EventTypeWarning, "TransitionRejected",
+			"the switchover is rejected, the standby cluster %s is not replicating", cdr.Status.StandbyCluster)
+		return
+	}
+	// an emergency failover takes over the in-progress switchover.
+	cdr.Status.Transition = transition
+	if failover {
+		setDRPhase(cdr, appsv1.DRFailingOverPhase, "")
+	} else {
+		setDRPhase(cdr, appsv1.DRSwitchingOverPhase, "")
+	}
+}
+
+// transit performs the planned switchover or emergency failover step by step.
+func (r *ClusterDisasterRecoveryReconciler) transit(reqCtx intctrlutil.RequestCtx,
+	cdr *appsv1.ClusterDisasterRecovery, primary, standby *appsv1.Cluster) (time.Duration, error) {
+	transition := cdr.Status.Transition
+	if standby == nil {
+		setDRPhase(cdr, cdr.Status.Phase, fmt.Sprintf("the standby cluster %s is not found", cdr.Status.StandbyCluster))
+		return drTransitionRequeueInterval, nil
+	}
+
+	if transition.Type == appsv1.DRSwitchoverTransition && transition.Step != appsv1.DRPromotingStep && switchoverTimedOut(cdr) {
+		return r.rollbackSwitchover(reqCtx, cdr, primary)
+	}
+
+	if transition.Step == appsv1.DRFencingStep {
+		if primary == nil {
+			setDRPhase(cdr, cdr.Status.Phase, fmt.Sprintf("the primary cluster %s is not found", cdr.Status.PrimaryCluster))
+			return drTransitionRequeueInterval, nil
+		}
+		for _, compName := range cdr.Spec.Components {
+			err := r.callAction(reqCtx.Ctx, primary, compName, func(lfa lifecycle.Lifecycle) error {
+				return lfa.Readonly(reqCtx.Ctx, r.Client, nil)
+			})
+			if err != nil && !errors.Is(err, lifecycle.ErrActionNotDefined) {
+				setDRPhase(cdr, cdr.Status.Phase, fmt.Sprintf("failed to fence the component %s of the primary cluster: %s", compName, err.Error()))
+				return drTransitionRequeueInterval, nil
+			}
+		}
+		transition.Step = appsv1.DRCatchingUpStep
+	}
+
+	if transition.Step == appsv1.DRCatchingUpStep {
+		r.probeReplicationLag(reqCtx, cdr, standby, 0)
+		for _, status := range cdr.Status.Components {
+			if status.ReplicationLagSeconds == nil || *status.ReplicationLagSeconds > cdr.Spec.SwitchoverMaxLagSeconds {
+				setDRPhase(cdr, cdr.Status.Phase, fmt.Sprintf("waiting for the component %s of the standby cluster to catch up", status.Name))
+				return drTransitionRequeueInterval, nil
+			}
+		}
+		transition.Step = appsv1.DRPromotingStep
+	}
+
+	emergency := transition.Type == appsv1.DRFailoverTransition
+	if emergency {
+		r.fenceBestEffort(reqCtx, cdr, primary)
+	}
+	for _, compName := range cdr.Spec.Components {
+		err := r.callAction(reqCtx.Ctx, standby, compName, func(lfa lifecycle.Lifecycle) error {
+			return lfa.StandbyPromote(reqCtx.Ctx, r.Client, nil, emergency)
+		})
+		if err != nil {
+			setDRPhase(cdr, cdr.Status.Phase, fmt.Sprintf("failed to promote the component %s of the standby cluster: %s", compName, err.Error()))
+			return drTransitionRequeueInterval, nil
+		}
+	}
+
+	r.Recorder.Eventf(cdr, corev1.EventTypeNormal, string(transition.Type),
+		"the cluster %s has been promoted to be the primary, and the cluster %s is demoted to be the standby",
+		cdr.Status.StandbyCluster, cdr.Status.PrimaryCluster)
+	reason := appsv1.ReasonDRSwitchoverSucceeded
+	if emergency {
+		reason = appsv1.ReasonDRFailoverSucceeded
+	}
+	setDRTransitionCondition(cdr, metav1.ConditionTrue, reason,
+		fmt.Sprintf("the cluster %s has been promoted to be the primary", cdr.Status.StandbyCluster))
+	cdr.Status.PrimaryCluster, cdr.Status.StandbyCluster = cdr.Status.StandbyCluster, cdr.Status.PrimaryCluster
+	cdr.Status.Transition = nil
+	cdr.Status.LastTransitionTime = &metav1.Time{Time: time.Now()}
+	cdr.Status.ReplicationLagSeconds = nil
+	// the former primary may have accepted the writes that were not replicated before the failover.
+	cdr.Status.StandbyDiverged = emergency
+	for i := range cdr.Status.Components {
+		// the former primary has to replicate from the new primary.
+		cdr.Status.Components[i] = appsv1.ComponentReplicationStatus{Name: cdr.Status.Components[i].Name}
+	}
+	setDRPhase(cdr, appsv1.DRProvisioningPhase, "")
+	return drTransitionRequeueInterval, nil
+}
+
+func switchoverTimedOut(cdr *appsv1.ClusterDisasterRecovery) bool {
+	transition := cdr.Status.Transition
+	if transition.StartTime == nil || cdr.Spec.SwitchoverTimeoutSeconds <= 0 {
+		return false
+	}
+	timeout := time.Duration(cdr.Spec.SwitchoverTimeoutSeconds) * time.Second
+	return time.Since(transition.StartTime.Time) > timeout
+}
+
+// rollbackSwitchover aborts the planned switchover that can't promote the standby cluster in time,
+// and switches the primary cluster back to read-write.
+func (r *ClusterDisasterRecoveryReconciler) rollbackSwitchover(reqCtx intctrlutil.RequestCtx,
+	cdr *appsv1.ClusterDisasterRecovery, primary *appsv1.Cluster) (time.Duration, error) {
+	if primary != nil {
+		for _, compName := range cdr.Spec.Components {
+			err := r.callAction(reqCtx.Ctx, primary, compName, func(lfa lifecycle.Lifecycle) error {
+				return lfa.Readwrite(reqCtx.Ctx, r.Client, nil)
+			})
+			if err != nil && !errors.Is(err, lifecycle.ErrActionNotDefined) {
+				setDRPhase(cdr, cdr.Status.Phase, fmt.Sprintf("the switchover timed out, failed to unfence the component %s of the primary cluster: %s", compName, err.Error()))
+				return drTransitionRequeueInterval, nil
+			}
+		}
+	}
+
+	message := fmt.Sprintf("the switchover timed out after %ds, the primary cluster %s has been switched back to read-write",
+		cdr.Spec.SwitchoverTimeoutSeconds, cdr.Status.PrimaryCluster)
+	r.Recorder.Event(cdr, corev1.EventTypeWarning, appsv1.ReasonDRSwitchoverTimeout, message)
+	setDRTransitionCondition(cdr, metav1.ConditionFalse, appsv1.ReasonDRSwitchoverTimeout, message)
+	cdr.Status.Transition = nil
+	setDRPhase(cdr, appsv1.DRReplicatingPhase, message)
+	return drTransitionRequeueInterval, nil
+}
+
+// fenceBestEffort switches the primary cluster into read-only before the emergency failover if it's reachable,
+// the failover goes on regardless of the result.
+func (r *ClusterDisasterRecoveryReconciler) fenceBestEffort(reqCtx intctrlutil.RequestCtx,
+	cdr *appsv1.ClusterDisasterRecovery, primary *appsv1.Cluster) {
+	if primary == nil {
+		return
+	}
+	for _, compName := range cdr.Spec.Components {
+		err := r.callAction(reqCtx.Ctx, primary, compName, func(lfa lifecycle.Lifecycle) error {
+			return lfa.Readonly(reqCtx.Ctx, r.Client, nil)
+		})
+		if err != nil && !errors.Is(err, lifecycle.ErrActionNotDefined) {
+			r.Recorder.Eventf(cdr, corev1.EventTypeWarning, "FenceFailed",
+				"failed to fence the component %s of the primary cluster %s before the failover: %s", compName, primary.Name, err.Error())
+		}
+	}
+}
+
+// provisionStandby creates the standby cluster from the backup of the primary cluster through the restore.
+func (r *ClusterDisasterRecoveryReconciler) provisionStandby(reqCtx intctrlutil.RequestCtx,
+	cdr *appsv1.ClusterDisasterRecovery, primary *appsv1.Cluster) error {
+	backup, err := r.getStandbyBackup(reqCtx.Ctx, cdr, primary)
+	if err != nil {
+		return err
+	}
+	if backup == nil {
+		setDRPhase(cdr, appsv1.DRProvisioningPhase, fmt.Sprintf("waiting for a completed backup of the primary cluster %s", primary.Name))
+		return nil
+	}
+
+	restoreAnnotation, err := restore.GetRestoreFromBackupAnnotation(backup, string(dpv1alpha1.VolumeClaimRestorePolicyParallel), "", nil, false)
+	if err != nil {
+		return err
+	}
+	cluster, err := operations.BuildClusterFromBackup(backup, cdr.Spec.Standby.ClusterName, restoreAnnotation)
+	if err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			setDRPhase(cdr, appsv1.DRFailedPhase, err.Error())
+			return nil
+		}
+		return err
+	}
+	cluster.Namespace = cdr.Namespace
+	if cluster.Labels == nil {
+		cluster.Labels = map[string]string{}
+	}
+	cluster.Labels[constant.DisasterRecoveryLabelKey] = cdr.Name
+	if len(cdr.Spec.Standby.Placement) > 0 {
+		cluster.Annotations[constant.KBAppMultiClusterPlacementKey] = strings.Join(cdr.Spec.Standby.Placement, ",")
+	} else {
+		delete(cluster.Annotations, constant.KBAppMultiClusterPlacementKey)
+	}
+	if err = r.Client.Create(reqCtx.Ctx, cluster); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	cdr.Status.BackupName = backup.Name
+	setDRPhase(cdr, appsv1.DRProvisioningPhase, fmt.Sprintf("the standby cluster %s is being restored from the backup %s", cluster.Name, backup.Name))
+	return nil
+}
+
+// getStandbyBackup returns the backup specified, or the latest completed backup of the primary cluster.
+func (r *ClusterDisasterRecoveryReconciler) getStandbyBackup(ctx context.Context,
+	cdr *appsv1.ClusterDisasterRecovery, primary *appsv1.Cluster) (*dpv1alpha1.Backup, error) {
+	if cdr.Spec.Standby.BackupName != "" {
+		backup := &dpv1alpha1.Backup{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: cdr.Namespace, Name: cdr.Spec.Standby.BackupName}, backup); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		if backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+			return nil, nil
+		}
+		return backup, nil
+	}
+
+	backups := &dpv1alpha1.BackupList{}
+	if err := r.Client.List(ctx, backups, client.InNamespace(primary.Namespace),
+		client.MatchingLabels{constant.AppInstanceLabelKey: primary.Name}); err != nil {
+		return nil, err
+	}
+	return latestCompletedBackup(backups.Items), nil
+}
+
+func latestCompletedBackup(backups []dpv1alpha1.Backup) *dpv1alpha1.Backup {
+	var latest *dpv1alpha1.Backup
+	for i, backup := range backups {
+		if backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted ||
+			backup.Labels[dptypes.BackupTypeLabelKey] == string(dpv1alpha1.BackupTypeContinuous) ||
+			backup.Status.CompletionTimestamp == nil {
+			continue
+		}
+		if latest == nil || latest.Status.CompletionTimestamp.Before(backup.Status.CompletionTimestamp) {
+			latest = &backups[i]
+		}
+	}
+	return latest
+}
+
+// replicate establishes the replication for the components of the standby cluster that are not replicating.
+func (r *ClusterDisasterRecoveryReconciler) replicate(reqCtx intctrlutil.RequestCtx,
+	cdr *appsv1.ClusterDisasterRecovery, primary, standby *appsv1.Cluster) {
+	for i := range cdr.Status.Components {
+		status := &cdr.Status.Components[i]
+		if status.Replicating {
+			continue
+		}
+		host, err := r.replicationHost(reqCtx.Ctx, cdr, primary, status.Name)
+		if err == nil {
+			err = r.callAction(reqCtx.Ctx, standby, status.Name, func(lfa lifecycle.Lifecycle) error {
+				return lfa.StandbyReplicate(reqCtx.Ctx, r.Client, nil, primary.Name, host)
+			})
+		}
+		if err != nil {
+			status.Message = err.Error()
+			continue
+		}
+		status.Replicating = true
+		status.Message = ""
+	}
+}
+
+// replicationHost resolves the host of the replication service of the component in the primary cluster,
+// it's the ingress of a LoadBalancer service, or the FQDN of the service otherwise.
+func (r *ClusterDisasterRecoveryReconciler) replicationHost(ctx context.Context,
+	cdr *appsv1.ClusterDisasterRecovery, primary *appsv1.Cluster, compName string) (string, error) {
+	svcName := constant.GenerateComponentServiceName(primary.Name, compName, cdr.Spec.ReplicationService)
+	services, err := component.ListOwnedServices(ctx, r.Client, primary.Namespace, primary.Name, compName)
+	if err != nil {
+		return "", err
+	}
+	for _, svc := range services {
+		if svc.Name != svcName {
+			continue
+		}
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			return component.ServiceFQDN(svc.Namespace, svc.Name), nil
+		}
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if len(ingress.Hostname) > 0 {
+				return ingress.Hostname, nil
+			}
+			if len(ingress.IP) > 0 {
+				return ingress.IP, nil
+			}
+		}
+		return "", fmt.Errorf("the load balancer of the service %s is not ready", svcName)
+	}
+	return "", fmt.Errorf("the replication service %s of the primary cluster is not found", svcName)
+}
+
+// probeReplicationLag probes the replication lag of the standby cluster if the last probe is older than the period.
+func (r *ClusterDisasterRecoveryReconciler) probeReplicationLag(reqCtx intctrlutil.RequestCtx,
+	cdr *appsv1.ClusterDisasterRecovery, standby *appsv1.Cluster, period time.Duration) {
+	now := time.Now()
+	for i := range cdr.Status.Components {
+		status := &cdr.Status.Components[i]
+		if period > 0 && status.LastProbeTime != nil && now.Sub(status.LastProbeTime.Time) < period {
+			continue
+		}
+		var output []byte
+		err := r.callAction(reqCtx.Ctx, standby, status.Name, func(lfa lifecycle.Lifecycle) error {
+			var err error
+			output, err = lfa.StandbyReplicationLag(reqCtx.Ctx, r.Client, nil, cdr.Status.PrimaryCluster)
+			return err
+		})
+		status.LastProbeTime = &metav1.Time{Time: now}
+		if err == nil {
+			var lag int64
+			lag, err = parseReplicationLag(output)
+			if err == nil {
+				status.ReplicationLagSeconds = &lag
+				status.Message = ""
+				continue
+			}
+		}
+		status.ReplicationLagSeconds = nil
+		status.Message = fmt.Sprintf("failed to probe the replication lag: %s", err.Error())
+	}
+	cdr.Status.ReplicationLagSeconds = maxReplicationLag(cdr.Status.Components)
+}
+
+func (r *ClusterDisasterRecoveryReconciler) callAction(ctx context.Context,
+	cluster *appsv1.Cluster, compName string, call func(lifecycle.Lifecycle) error) error {
+	comp, compDef, err := component.GetCompNCompDefByName(ctx, r.Client, cluster.Namespace,
+		constant.GenerateClusterComponentName(cluster.Name, compName))
+	if err != nil {
+		return err
+	}
+	synthesizedComp, err := component.BuildSynthesizedComponent(ctx, r.Client, compDef, comp, cluster)
+	if err != nil {
+		return err
+	}
+	synthesizedComp.TemplateVars, _, err = component.ResolveTemplateNEnvVars(ctx, r.Client, synthesizedComp, compDef.Spec.Vars)
+	if err != nil {
+		return err
+	}
+	pods, err := component.ListOwnedPods(ctx, r.Client, cluster.Namespace, cluster.Name, compName)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pod of the component %s is available", compName)
+	}
+	lfa, err := lifecycle.New(synthesizedComp, nil, pods...)
+	if err != nil {
+		return err
+	}
+	return call(lfa)
+}
+
+func (r *ClusterDisasterRecoveryReconciler) getCluster(ctx context.Context, namespace, name string) (*appsv1.Cluster, error) {
+	cluster := &appsv1.Cluster{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cluster, nil
+}
+
+func setDRPhase(cdr *appsv1.ClusterDisasterRecovery, phase appsv1.ClusterDisasterRecoveryPhase, message string) {
+	cdr.Status.Phase = phase
+	cdr.Status.Message = message
+}
+
+func setDRTransitionCondition(cdr *appsv1.ClusterDisasterRecovery, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&cdr.Status.Conditions, metav1.Condition{
+		Type:               appsv1.ConditionTypeDRTransition,
+		Status:             status,
+		ObservedGeneration: cdr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// syncComponentReplicationStatus aligns the replication status with the components to replicate.
+func syncComponentReplicationStatus(cdr *appsv1.ClusterDisasterRecovery) {
+	statuses := make([]appsv1.ComponentReplicationStatus, 0, len(cdr.Spec.Components))
+	for _, compName := range cdr.Spec.Components {
+		status := appsv1.ComponentReplicationStatus{Name: compName}
+		for _, s := range cdr.Status.Components {
+			if s.Name == compName {
+				status = s
+				break
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	cdr.Status.Components = statuses
+}
+
+func allComponentsReplicating(cdr *appsv1.ClusterDisasterRecovery) bool {
+	for _, status := range cdr.Status.Components {
+		if !status.Replicating {
+			return false
+		}
+	}
+	return len(cdr.Status.Components) > 0
+}
+
+func parseReplicationLag(output []byte) (int64, error) {
+	lag, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid replication lag %q", string(output))
+	}
+	return max(lag, 0), nil
+}
+
+func maxReplicationLag(statuses []appsv1.ComponentReplicationStatus) *int64 {
+	var lag *int64
+	for _, status := range statuses {
+		if status.ReplicationLagSeconds == nil {
+			return nil
+		}
+		if lag == nil || *status.ReplicationLagSeconds > *lag {
+			lag = status.ReplicationLagSeconds
+		}
+	}
+	return lag
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterDisasterRecoveryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
+		For(&appsv1.ClusterDisasterRecovery{}).
+		Watches(&appsv1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.matchedCluster)).
+		Complete(r)
+}
+
+func (r *ClusterDisasterRecoveryReconciler) matchedCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	cdrs := &appsv1.ClusterDisasterRecoveryList{}
+	if err := r.Client.List(ctx, cdrs, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0)
+	for _, cdr := range cdrs.Items {
+		if cdr.Spec.PrimaryCluster == obj.GetName() || cdr.Spec.Standby.ClusterName == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cdr)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,502 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	kbagentproto "github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
+)
+
+var _ = Describe("ClusterDisasterRecovery Controller", func() {
+	const (
+		compDefName        = "test-compdef"
+		compName           = "mysql"
+		primaryClusterName = "test-primary"
+		standbyClusterName = "test-standby"
+		cdrName            = "test-cdr"
+	)
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+
+		// delete cluster(and all dependent sub-resources), cluster definition
+		testapps.ClearClusterResourcesWithRemoveFinalizerOption(&testCtx)
+
+		// namespaced
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.ClusterDisasterRecoverySignature, true, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupSignature, true, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+	})
+
+	AfterEach(func() {
+		cleanEnv()
+	})
+
+	newCDR := func() *appsv1.ClusterDisasterRecovery {
+		return &appsv1.ClusterDisasterRecovery{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      cdrName,
+				Labels:    map[string]string{testCtx.TestObjLabelKey: "true"},
+			},
+			Spec: appsv1.ClusterDisasterRecoverySpec{
+				PrimaryCluster: primaryClusterName,
+				Standby: appsv1.StandbyClusterSpec{
+					ClusterName: standbyClusterName,
+					Placement:   []string{"dr-region"},
+				},
+				Components:            []string{compName},
+				LagProbePeriodSeconds: 30,
+			},
+		}
+	}
+
+	Context("provision", func() {
+		It("provisions the standby cluster from the latest backup", func() {
+			By("create the primary cluster")
+			primary := testapps.NewClusterFactory(testCtx.DefaultNamespace, primaryClusterName, "").
+				AddComponent(compName, compDefName).
+				Create(&testCtx).
+				GetObject()
+
+			By("create the backups of the primary cluster")
+			primary.ResourceVersion = ""
+			snapshot, err := json.Marshal(primary)
+			Expect(err).ShouldNot(HaveOccurred())
+			now := time.Now()
+			for i, name := range []string{"backup-old", "backup-latest", "backup-running"} {
+				backup := testdp.NewBackupFactory(testCtx.DefaultNamespace, name).
+					SetBackupPolicyName("test-policy").
+					SetBackupMethod("test-method").
+					AddLabelsInMap(map[string]string{
+						constant.AppInstanceLabelKey:    primaryClusterName,
+						constant.KBAppComponentLabelKey: compName,
+						dptypes.BackupTypeLabelKey:      string(dpv1alpha1.BackupTypeFull),
+					}).
+					AddAnnotations(constant.ClusterSnapshotAnnotationKey, string(snapshot)).
+					Create(&testCtx).
+					GetObject()
+				Expect(testapps.ChangeObjStatus(&testCtx, backup, func() {
+					if name == "backup-running" {
+						backup.Status.Phase = dpv1alpha1.BackupPhaseRunning
+						return
+					}
+					backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+					backup.Status.CompletionTimestamp = &metav1.Time{Time: now.Add(time.Duration(i) * time.Minute)}
+				})).Should(Succeed())
+			}
+
+			By("create the ClusterDisasterRecovery")
+			cdr := newCDR()
+			Expect(testCtx.Create(testCtx.Ctx, cdr)).Should(Succeed())
+
+			By("check the standby cluster")
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKey{Namespace: testCtx.DefaultNamespace, Name: standbyClusterName},
+				func(g Gomega, standby *appsv1.Cluster) {
+					g.Expect(standby.Labels).Should(HaveKeyWithValue(constant.DisasterRecoveryLabelKey, cdrName))
+					g.Expect(standby.Annotations).Should(HaveKeyWithValue(constant.KBAppMultiClusterPlacementKey, "dr-region"))
+					g.Expect(standby.Annotations[constant.RestoreFromBackupAnnotationKey]).Should(ContainSubstring("backup-latest"))
+					g.Expect(standby.Spec.ComponentSpecs).Should(HaveLen(1))
+				})).Should(Succeed())
+
+			By("check the status")
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(cdr),
+				func(g Gomega, cdr *appsv1.ClusterDisasterRecovery) {
+					g.Expect(cdr.Status.Phase).Should(Equal(appsv1.DRProvisioningPhase))
+					g.Expect(cdr.Status.PrimaryCluster).Should(Equal(primaryClusterName))
+					g.Expect(cdr.Status.StandbyCluster).Should(Equal(standbyClusterName))
+					g.Expect(cdr.Status.BackupName).Should(Equal("backup-latest"))
+					g.Expect(cdr.Status.Components).Should(HaveLen(1))
+				})).Should(Succeed())
+		})
+
+		It("fails if the primary and standby clusters are the same", func() {
+			cdr := newCDR()
+			cdr.Spec.Standby.ClusterName = primaryClusterName
+			Expect(testCtx.Create(testCtx.Ctx, cdr)).Should(Succeed())
+
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(cdr),
+				func(g Gomega, cdr *appsv1.ClusterDisasterRecovery) {
+					g.Expect(cdr.Status.Phase).Should(Equal(appsv1.DRFailedPhase))
+				})).Should(Succeed())
+		})
+	})
+
+	Context("transition", func() {
+		var (
+			reconciler *ClusterDisasterRecoveryReconciler
+			standby    *appsv1.Cluster
+		)
+
+		BeforeEach(func() {
+			reconciler = &ClusterDisasterRecoveryReconciler{Recorder: record.NewFakeRecorder(16)}
+			standby = &appsv1.Cluster{}
+			standby.Status.Phase = appsv1.RunningClusterPhase
+		})
+
+		requestedCDR := func(phase appsv1.ClusterDisasterRecoveryPhase, annotations ...string) *appsv1.ClusterDisasterRecovery {
+			cdr := newCDR()
+			cdr.Annotations = map[string]string{}
+			for _, key := range annotations {
+				cdr.Annotations[key] = trueVal
+			}
+			cdr.Status.Phase = phase
+			return cdr
+		}
+
+		It("rejects the switchover if the standby is not replicating", func() {
+			cdr := requestedCDR(appsv1.DRProvisioningPhase, constant.DRSwitchoverAnnotationKey)
+			reconciler.acceptTransitionRequest(cdr, standby)
+			Expect(cdr.Status.Transition).Should(BeNil())
+			Expect(cdr.Annotations).ShouldNot(HaveKey(constant.DRSwitchoverAnnotationKey))
+		})
+
+		It("accepts the switchover and the failover takes it over", func() {
+			cdr := requestedCDR(appsv1.DRReplicatingPhase, constant.DRSwitchoverAnnotationKey)
+			reconciler.acceptTransitionRequest(cdr, standby)
+			Expect(cdr.Status.Phase).Should(Equal(appsv1.DRSwitchingOverPhase))
+			Expect(cdr.Status.Transition).ShouldNot(BeNil())
+			Expect(cdr.Status.Transition.Type).Should(Equal(appsv1.DRSwitchoverTransition))
+			Expect(cdr.Status.Transition.Step).Should(Equal(appsv1.DRFencingStep))
+
+			By("another switchover is rejected")
+			cdr.Annotations[constant.DRSwitchoverAnnotationKey] = trueVal
+			reconciler.acceptTransitionRequest(cdr, standby)
+			Expect(cdr.Status.Transition.Type).Should(Equal(appsv1.DRSwitchoverTransition))
+
+			By("the failover takes over the switchover")
+			cdr.Annotations[constant.DRFailoverAnnotationKey] = trueVal
+			reconciler.acceptTransitionRequest(cdr, standby)
+			Expect(cdr.Status.Phase).Should(Equal(appsv1.DRFailingOverPhase))
+			Expect(cdr.Status.Transition.Type).Should(Equal(appsv1.DRFailoverTransition))
+			Expect(cdr.Status.Transition.Step).Should(Equal(appsv1.DRPromotingStep))
+			Expect(cdr.Annotations).Should(BeEmpty())
+		})
+
+		It("rejects the failover if the standby is not running", func() {
+			cdr := requestedCDR(appsv1.DRReplicatingPhase, constant.DRFailoverAnnotationKey)
+			standby.Status.Phase = appsv1.FailedClusterPhase
+			reconciler.acceptTransitionRequest(cdr, standby)
+			Expect(cdr.Status.Transition).Should(BeNil())
+			Expect(cdr.Status.Phase).Should(Equal(appsv1.DRReplicatingPhase))
+		})
+
+		It("rejects the transitions if the standby has diverged", func() {
+			cdr := requestedCDR(appsv1.DRProvisioningPhase, constant.DRFailoverAnnotationKey)
+			cdr.Status.StandbyDiverged = true
+			reconciler.acceptTransitionRequest(cdr, standby)
+			Expect(cdr.Status.Transition).Should(BeNil())
+			Expect(cdr.Annotations).Should(BeEmpty())
+		})
+	})
+
+	Context("transit", func() {
+		var (
+			cli        client.Client
+			reconciler *ClusterDisasterRecoveryReconciler
+			recorder   *record.FakeRecorder
+			primary    *appsv1.Cluster
+			standby    *appsv1.Cluster
+			actions    []string
+			lag        string
+			fenceErr   string
+		)
+
+		newCluster := func(name string) *appsv1.Cluster {
+			cluster := &appsv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: name, UID: "uid-" + types.UID(name)},
+				Spec: appsv1.ClusterSpec{
+					ComponentSpecs: []appsv1.ClusterComponentSpec{{Name: compName, ComponentDef: compDefName}},
+				},
+			}
+			cluster.Status.Phase = appsv1.RunningClusterPhase
+			return cluster
+		}
+
+		compObjs := func(cluster *appsv1.Cluster) []client.Object {
+			comp := builder.NewComponentBuilder(cluster.Namespace, constant.GenerateClusterComponentName(cluster.Name, compName), compDefName).
+				AddLabelsInMap(constant.GetCompLabels(cluster.Name, compName)).
+				AddAnnotations(constant.KBAppClusterUIDKey, string(cluster.UID)).
+				SetReplicas(1).
+				GetObject()
+			pod := builder.NewPodBuilder(cluster.Namespace, constant.GenerateClusterComponentName(cluster.Name, compName)+"-0").
+				AddLabelsInMap(constant.GetCompLabels(cluster.Name, compName)).
+				GetObject()
+			svc := builder.NewServiceBuilder(cluster.Namespace, constant.GenerateDefaultComponentServiceName(cluster.Name, compName)).
+				AddLabelsInMap(constant.GetCompLabels(cluster.Name, compName)).
+				GetObject()
+			return []client.Object{cluster, comp, pod, svc}
+		}
+
+		newTransitCDR := func(transitionType appsv1.DRTransitionType, step appsv1.DRTransitionStep) *appsv1.ClusterDisasterRecovery {
+			cdr := newCDR()
+			cdr.Spec.SwitchoverTimeoutSeconds = 600
+			cdr.Status.PrimaryCluster = primaryClusterName
+			cdr.Status.StandbyCluster = standbyClusterName
+			cdr.Status.Phase = appsv1.DRSwitchingOverPhase
+			if transitionType == appsv1.DRFailoverTransition {
+				cdr.Status.Phase = appsv1.DRFailingOverPhase
+			}
+			cdr.Status.Components = []appsv1.ComponentReplicationStatus{{Name: compName, Replicating: true}}
+			cdr.Status.Transition = &appsv1.DRTransitionStatus{
+				Type:      transitionType,
+				Step:      step,
+				StartTime: &metav1.Time{Time: time.Now()},
+			}
+			return cdr
+		}
+
+		transit := func(cdr *appsv1.ClusterDisasterRecovery) {
+			reqCtx := intctrlutil.RequestCtx{Ctx: context.Background(), Log: logf.Log, Recorder: recorder}
+			requeueAfter, err := reconciler.transit(reqCtx, cdr, primary, standby)
+			Expect(err).Should(Succeed())
+			Expect(requeueAfter).Should(Equal(drTransitionRequeueInterval))
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+			Expect(appsv1.AddToScheme(scheme)).Should(Succeed())
+
+			action := func() *appsv1.Action {
+				return &appsv1.Action{Exec: &appsv1.ExecAction{Command: []string{"action"}}}
+			}
+			compDef := &appsv1.ComponentDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: compDefName},
+				Spec: appsv1.ComponentDefinitionSpec{
+					LifecycleActions: &appsv1.ComponentLifecycleActions{
+						Readonly:              action(),
+						Readwrite:             action(),
+						StandbyReplicate:      action(),
+						StandbyReplicationLag: action(),
+						StandbyPromote:        action(),
+					},
+				},
+			}
+			primary = newCluster(primaryClusterName)
+			standby = newCluster(standbyClusterName)
+			objs := append(compObjs(primary), compObjs(standby)...)
+			cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, compDef)...).Build()
+			recorder = record.NewFakeRecorder(100)
+			reconciler = &ClusterDisasterRecoveryReconciler{Client: cli, Recorder: recorder}
+
+			actions, lag, fenceErr = nil, "0", ""
+			testapps.MockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req kbagentproto.ActionRequest) (kbagentproto.ActionResponse, error) {
+					// the actions called on the primary cluster are identified by the FQDN of the pod
+					target := standbyClusterName
+					if strings.Contains(req.Parameters["KB_POD_FQDN"], primaryClusterName) {
+						target = primaryClusterName
+					}
+					actions = append(actions, fmt.Sprintf("%s@%s", req.Action, target))
+					switch req.Action {
+					case "readonly":
+						if len(fenceErr) > 0 {
+							return kbagentproto.ActionResponse{}, fmt.Errorf("%s", fenceErr)
+						}
+					case "standbyReplicationLag":
+						return kbagentproto.ActionResponse{Output: []byte(lag)}, nil
+					case "standbyPromote":
+						Expect(req.Parameters).Should(HaveKey("KB_DR_EMERGENCY"))
+						return kbagentproto.ActionResponse{Output: []byte(req.Parameters["KB_DR_EMERGENCY"])}, nil
+					}
+					return kbagentproto.ActionResponse{}, nil
+				}).AnyTimes()
+			})
+		})
+
+		AfterEach(func() {
+			kbacli.UnsetMockClient()
+		})
+
+		It("switches over through fencing, catching up and promoting", func() {
+			cdr := newTransitCDR(appsv1.DRSwitchoverTransition, appsv1.DRFencingStep)
+
+			By("fence the primary and wait for the standby to catch up")
+			lag = "5"
+			transit(cdr)
+			Expect(actions).Should(Equal([]string{"readonly@" + primaryClusterName, "standbyReplicationLag@" + standbyClusterName}))
+			Expect(cdr.Status.Transition.Step).Should(Equal(appsv1.DRCatchingUpStep))
+			Expect(cdr.Status.Message).Should(ContainSubstring("catch up"))
+
+			By("promote the standby once it has caught up")
+			lag, actions = "0", nil
+			transit(cdr)
+			Expect(actions).Should(Equal([]string{"standbyReplicationLag@" + standbyClusterName, "standbyPromote@" + standbyClusterName}))
+			Expect(cdr.Status.Transition).Should(BeNil())
+			Expect(cdr.Status.PrimaryCluster).Should(Equal(standbyClusterName))
+			Expect(cdr.Status.StandbyCluster).Should(Equal(primaryClusterName))
+			Expect(cdr.Status.StandbyDiverged).Should(BeFalse())
+			Expect(cdr.Status.Phase).Should(Equal(appsv1.DRProvisioningPhase))
+			Expect(cdr.Status.Components[0].Replicating).Should(BeFalse())
+			cond := meta.FindStatusCondition(cdr.Status.Conditions, appsv1.ConditionTypeDRTransition)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).Should(Equal(appsv1.ReasonDRSwitchoverSucceeded))
+		})
+
+		It("rolls back the switchover after the timeout", func() {
+			cdr := newTransitCDR(appsv1.DRSwitchoverTransition, appsv1.DRCatchingUpStep)
+			cdr.Status.Transition.StartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+			transit(cdr)
+			Expect(actions).Should(Equal([]string{"readwrite@" + primaryClusterName}))
+			Expect(cdr.Status.Transition).Should(BeNil())
+			Expect(cdr.Status.PrimaryCluster).Should(Equal(primaryClusterName))
+			Expect(cdr.Status.Phase).Should(Equal(appsv1.DRReplicatingPhase))
+			Expect(cdr.Status.Components[0].Replicating).Should(BeTrue())
+			cond := meta.FindStatusCondition(cdr.Status.Conditions, appsv1.ConditionTypeDRTransition)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).Should(Equal(appsv1.ReasonDRSwitchoverTimeout))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(appsv1.ReasonDRSwitchoverTimeout)))
+
+			By("the promoting step is not rolled back")
+			cdr = newTransitCDR(appsv1.DRSwitchoverTransition, appsv1.DRPromotingStep)
+			cdr.Status.Transition.StartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			actions = nil
+			transit(cdr)
+			Expect(actions).Should(Equal([]string{"standbyPromote@" + standbyClusterName}))
+			Expect(cdr.Status.PrimaryCluster).Should(Equal(standbyClusterName))
+		})
+
+		It("fails over with the primary fenced best-effort", func() {
+			cdr := newTransitCDR(appsv1.DRFailoverTransition, appsv1.DRPromotingStep)
+			fenceErr = "the primary is unreachable"
+
+			transit(cdr)
+			Expect(actions).Should(Equal([]string{"readonly@" + primaryClusterName, "standbyPromote@" + standbyClusterName}))
+			Expect(recorder.Events).Should(Receive(ContainSubstring("FenceFailed")))
+			Expect(cdr.Status.Transition).Should(BeNil())
+			Expect(cdr.Status.PrimaryCluster).Should(Equal(standbyClusterName))
+			Expect(cdr.Status.StandbyDiverged).Should(BeTrue())
+			cond := meta.FindStatusCondition(cdr.Status.Conditions, appsv1.ConditionTypeDRTransition)
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Reason).Should(Equal(appsv1.ReasonDRFailoverSucceeded))
+
+			By("the former primary can't be failed over to")
+			cdr.Annotations = map[string]string{constant.DRFailoverAnnotationKey: trueVal}
+			reconciler.acceptTransitionRequest(cdr, primary)
+			Expect(cdr.Status.Transition).Should(BeNil())
+		})
+
+		It("fails over without the primary", func() {
+			cdr := newTransitCDR(appsv1.DRFailoverTransition, appsv1.DRPromotingStep)
+			primary = nil
+
+			transit(cdr)
+			Expect(actions).Should(Equal([]string{"standbyPromote@" + standbyClusterName}))
+			Expect(cdr.Status.PrimaryCluster).Should(Equal(standbyClusterName))
+			Expect(cdr.Status.StandbyDiverged).Should(BeTrue())
+		})
+
+		It("resolves the host to replicate from", func() {
+			cdr := newTransitCDR(appsv1.DRSwitchoverTransition, appsv1.DRFencingStep)
+			host, err := reconciler.replicationHost(context.Background(), cdr, primary, compName)
+			Expect(err).Should(Succeed())
+			Expect(host).Should(Equal(component.ServiceFQDN(testCtx.DefaultNamespace,
+				constant.GenerateDefaultComponentServiceName(primaryClusterName, compName))))
+
+			By("the replication service is not found")
+			cdr.Spec.ReplicationService = "lb"
+			_, err = reconciler.replicationHost(context.Background(), cdr, primary, compName)
+			Expect(err).Should(HaveOccurred())
+
+			By("the load balancer is not ready")
+			svc := builder.NewServiceBuilder(testCtx.DefaultNamespace, constant.GenerateComponentServiceName(primaryClusterName, compName, "lb")).
+				AddLabelsInMap(constant.GetCompLabels(primaryClusterName, compName)).
+				SetType(corev1.ServiceTypeLoadBalancer).
+				GetObject()
+			Expect(cli.Create(context.Background(), svc)).Should(Succeed())
+			_, err = reconciler.replicationHost(context.Background(), cdr, primary, compName)
+			Expect(err).Should(HaveOccurred())
+
+			By("the ingress of the load balancer")
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+			Expect(cli.Status().Update(context.Background(), svc)).Should(Succeed())
+			host, err = reconciler.replicationHost(context.Background(), cdr, primary, compName)
+			Expect(err).Should(Succeed())
+			Expect(host).Should(Equal("10.0.0.1"))
+		})
+	})
+
+	Context("replication lag", func() {
+		It("parses the replication lag", func() {
+			lag, err := parseReplicationLag([]byte(" 12\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(lag).Should(BeEquivalentTo(12))
+
+			_, err = parseReplicationLag([]byte("unknown"))
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("reports the maximum replication lag", func() {
+			statuses := []appsv1.ComponentReplicationStatus{
+				{Name: "a", ReplicationLagSeconds: ptr.To[int64](3)},
+				{Name: "b", ReplicationLagSeconds: ptr.To[int64](7)},
+			}
+			Expect(*maxReplicationLag(statuses)).Should(BeEquivalentTo(7))
+
+			statuses = append(statuses, appsv1.ComponentReplicationStatus{Name: "c"})
+			Expect(maxReplicationLag(statuses)).Should(BeNil())
+		})
+	})
+})
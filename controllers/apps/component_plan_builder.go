@@ -22,6 +22,7 @@ package apps
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -51,6 +52,9 @@ type componentTransformContext struct {
 	SynthesizeComponent *component.SynthesizedComponent
 	RunningWorkload     client.Object
 	ProtoWorkload       client.Object
+	// TLSCertRotatedAt is the time when the TLS certificates issued by KubeBlocks were re-issued last time,
+	// the workload will be restarted to load them if the reconfigure action is not defined.
+	TLSCertRotatedAt string
 }
 
 func (c *componentTransformContext) GetContext() context.Context {
@@ -202,10 +206,22 @@ func (c *componentPlanBuilder) reconcileCreateObject(ctx context.Context, vertex
 }
 
 func (c *componentPlanBuilder) reconcileUpdateObject(ctx context.Context, vertex *model.ObjectVertex) error {
+	// the status of the component is overwritten by the update, write it after the object
+	var status *appsv1.ComponentStatus
+	if comp, ok := vertex.Obj.(*appsv1.Component); ok {
+		status = comp.Status.DeepCopy()
+	}
 	err := c.cli.Update(ctx, vertex.Obj, clientOption(vertex))
-	if err != nil && !apierrors.IsNotFound(err) {
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
 		return err
 	}
+	if comp, ok := vertex.Obj.(*appsv1.Component); ok && !reflect.DeepEqual(*status, comp.Status) {
+		comp.Status = *status
+		return c.cli.Status().Update(ctx, comp, clientOption(vertex))
+	}
 	return nil
 }
 
@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configuration
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/configuration/core"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+)
+
+// reconfigureActionPolicy reloads the updated parameters by calling the reconfigure lifecycle action
+// of the component through kb-agent, rather than the config-manager sidecar.
+type reconfigureActionPolicy struct {
+}
+
+func init() {
+	RegisterPolicy(appsv1alpha1.ReconfigureActionPolicy, &reconfigureActionPolicy{})
+}
+
+func (o *reconfigureActionPolicy) GetPolicyName() string {
+	return string(appsv1alpha1.ReconfigureActionPolicy)
+}
+
+func (o *reconfigureActionPolicy) Upgrade(params reconfigureParams) (ReturnedStatus, error) {
+	configPatch := params.ConfigPatch
+	if !configPatch.IsModify {
+		return makeReturnedStatus(ESNone), nil
+	}
+	if !hasReconfigureAction(params) {
+		return makeReturnedStatus(ESFailed), core.MakeError("the reconfigure action is not defined for component: %s", params.SynthesizedComponent.Name)
+	}
+
+	updatedParameters := getOnlineUpdateParams(configPatch, params.ConfigConstraint)
+	if len(updatedParameters) == 0 {
+		return makeReturnedStatus(ESNone), nil
+	}
+
+	funcs := GetInstanceSetRollingUpgradeFuncs()
+	funcs.OnlineUpdatePodFunc = reconfigureActionWithPod(params)
+	pods, err := funcs.GetPodsFunc(params)
+	if err != nil {
+		return makeReturnedStatus(ESFailedAndRetry), err
+	}
+	return sync(params, updatedParameters, pods, funcs)
+}
+
+func reconfigureActionWithPod(params reconfigureParams) OnlineUpdatePodFunc {
+	return func(pod *corev1.Pod, ctx context.Context, _ createReconfigureClient, configSpec string, updatedParams map[string]string) error {
+		lfa, err := lifecycle.New(params.SynthesizedComponent, pod)
+		if err != nil {
+			return err
+		}
+		return lfa.Reconfigure(ctx, params.Client, nil, configSpec, updatedParams)
+	}
+}
+
+func hasReconfigureAction(params reconfigureParams) bool {
+	synthesizedComp := params.SynthesizedComponent
+	return synthesizedComp != nil && synthesizedComp.LifecycleActions != nil &&
+		synthesizedComp.LifecycleActions.Reconfigure != nil && synthesizedComp.LifecycleActions.Reconfigure.Exec != nil
+}
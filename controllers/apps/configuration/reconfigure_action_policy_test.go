@@ -0,0 +1,145 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configuration
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	testutil "github.com/apecloud/kubeblocks/pkg/testutil/k8s"
+)
+
+var reconfigureActionPolicyInstance = &reconfigureActionPolicy{}
+
+var _ = Describe("Reconfigure ReconfigureActionPolicy", func() {
+
+	var (
+		k8sMockClient *testutil.K8sClientMockHelper
+	)
+
+	BeforeEach(func() {
+		k8sMockClient = testutil.NewK8sMockClient()
+	})
+
+	AfterEach(func() {
+		k8sMockClient.Finish()
+		kbacli.UnsetMockClient()
+	})
+
+	withReconfigureAction := func(params *reconfigureParams) {
+		params.SynthesizedComponent.LifecycleActions = &appsv1.ComponentLifecycleActions{
+			Reconfigure: &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n reconfigure"},
+				},
+			},
+		}
+	}
+
+	Context("reconfigure action policy test", func() {
+		It("Should success without error", func() {
+			By("check policy name")
+			Expect(reconfigureActionPolicyInstance.GetPolicyName()).Should(BeEquivalentTo("reconfigureAction"))
+
+			By("prepare reconfigure policy params")
+			mockParam := newMockReconfigureParams("reconfigureActionPolicy", k8sMockClient.Client(),
+				withMockInstanceSet(3, nil),
+				withConfigSpec("for_test", map[string]string{"a": "c b e f"}),
+				withConfigConstraintSpec(&appsv1beta1.FileFormatConfig{Format: appsv1beta1.RedisCfg}),
+				withConfigPatch(map[string]string{
+					"a": "c b e f",
+				}),
+				withClusterComponent(3),
+				withReconfigureAction)
+
+			By("mock client get pod caller")
+			k8sMockClient.MockListMethod(testutil.WithListReturned(
+				testutil.WithConstructListReturnedResult(
+					fromPodObjectList(newMockPodsWithInstanceSet(&mockParam.InstanceSetUnits[0], 3,
+						withReadyPod(0, 3)))),
+				testutil.WithAnyTimes()))
+
+			By("mock client patch caller")
+			k8sMockClient.MockPatchMethod(testutil.WithSucceed(testutil.WithTimes(3)))
+
+			By("mock kb-agent reconfigure action caller")
+			cli := kbacli.NewMockClient(gomock.NewController(GinkgoT()))
+			cli.EXPECT().Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+				Expect(req.Action).Should(Equal("reconfigure"))
+				Expect(req.Parameters["KB_CONFIG_NAME"]).Should(Equal("for_test"))
+				Expect(req.Parameters["KB_CONFIG_PARAMETERS"]).Should(MatchJSON(`{"a":"c b e f"}`))
+				return proto.ActionResponse{}, nil
+			}).Times(3)
+			kbacli.SetMockClient(cli, nil)
+
+			status, err := reconfigureActionPolicyInstance.Upgrade(mockParam)
+			Expect(err).Should(Succeed())
+			Expect(status.Status).Should(BeEquivalentTo(ESNone))
+			Expect(status.SucceedCount).Should(BeEquivalentTo(3))
+			Expect(status.ExpectedCount).Should(BeEquivalentTo(3))
+		})
+
+		It("Should fail if the reconfigure action is not defined", func() {
+			mockParam := newMockReconfigureParams("reconfigureActionPolicy", k8sMockClient.Client(),
+				withMockInstanceSet(3, nil),
+				withConfigSpec("for_test", map[string]string{"a": "c b e f"}),
+				withConfigConstraintSpec(&appsv1beta1.FileFormatConfig{Format: appsv1beta1.RedisCfg}),
+				withConfigPatch(map[string]string{
+					"a": "c b e f",
+				}),
+				withClusterComponent(3))
+
+			status, err := reconfigureActionPolicyInstance.Upgrade(mockParam)
+			Expect(err).ShouldNot(Succeed())
+			Expect(status.Status).Should(BeEquivalentTo(ESFailed))
+		})
+	})
+
+	Context("reconfigure policy decision test", func() {
+		It("Should prefer the reconfigure action for dynamic parameters", func() {
+			mockParam := newMockReconfigureParams("reconfigureActionPolicy", k8sMockClient.Client(),
+				withConfigSpec("for_test", map[string]string{"a": "c b e f"}),
+				withConfigConstraintSpec(&appsv1beta1.FileFormatConfig{Format: appsv1beta1.RedisCfg}),
+				withConfigPatch(map[string]string{
+					"a": "c b e f",
+				}))
+			mockParam.ConfigConstraint.DynamicParameters = []string{"a"}
+
+			policy, err := NewReconfigurePolicy(mockParam.ConfigConstraint, mockParam.ConfigPatch, appsv1alpha1.NonePolicy, false, true)
+			Expect(err).Should(Succeed())
+			Expect(policy.GetPolicyName()).Should(BeEquivalentTo(appsv1alpha1.ReconfigureActionPolicy))
+
+			mockParam.ConfigConstraint.DynamicParameters = nil
+			mockParam.ConfigConstraint.StaticParameters = []string{"a"}
+			policy, err = NewReconfigurePolicy(mockParam.ConfigConstraint, mockParam.ConfigPatch, appsv1alpha1.NonePolicy, false, true)
+			Expect(err).Should(Succeed())
+			Expect(policy.GetPolicyName()).ShouldNot(BeEquivalentTo(appsv1alpha1.ReconfigureActionPolicy))
+		})
+	})
+})
@@ -112,6 +112,8 @@ var _ = BeforeSuite(func() {
 			// resolved by ref: https://github.com/operator-framework/operator-sdk/issues/4434#issuecomment-786794418
 			filepath.Join(build.Default.GOPATH, "pkg", "mod", "github.com", "kubernetes-csi/external-snapshotter/",
 				"client/v6@v6.2.0", "config", "crd"),
+			// the cert-manager Certificate CRD.
+			filepath.Join("..", "..", "test", "testdata", "crd"),
 		},
 		ErrorIfCRDPathMissing: true,
 	}
@@ -229,6 +231,13 @@ var _ = BeforeSuite(func() {
 	}).SetupWithManager(k8sManager, nil)
 	Expect(err).ToNot(HaveOccurred())
 
+	err = (&ClusterDisasterRecoveryReconciler{
+		Client:   k8sManager.GetClient(),
+		Scheme:   k8sManager.GetScheme(),
+		Recorder: k8sManager.GetEventRecorderFor("cluster-disaster-recovery-controller"),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	err = (&ServiceDescriptorReconciler{
 		Client:   k8sManager.GetClient(),
 		Scheme:   k8sManager.GetScheme(),
@@ -20,9 +20,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
-	"math/rand"
+	"fmt"
 	"slices"
 	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
@@ -31,6 +35,12 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/controller/multicluster"
 )
 
+const (
+	defaultPlacementGracePeriod = 5 * time.Minute
+
+	dataClusterRePlaced = "DataClusterRePlaced"
+)
+
 // clusterPlacementTransformer handles replicas placement.
 type clusterPlacementTransformer struct {
 	multiClusterMgr multicluster.Manager
@@ -48,13 +58,13 @@ func (t *clusterPlacementTransformer) Transform(ctx graph.TransformContext, dag
 		return nil // do nothing
 	}
 
+	var p []string
 	if t.assigned(transCtx) {
-		transCtx.Context = intoContext(transCtx.Context, placement(transCtx.OrigCluster))
-		return nil
+		p = t.failover(transCtx)
+	} else {
+		p = t.assign(transCtx)
 	}
 
-	p := t.assign(transCtx)
-
 	cluster := transCtx.Cluster
 	if cluster.Annotations == nil {
 		cluster.Annotations = make(map[string]string)
@@ -62,6 +72,8 @@ func (t *clusterPlacementTransformer) Transform(ctx graph.TransformContext, dag
 	cluster.Annotations[constant.KBAppMultiClusterPlacementKey] = strings.Join(p, ",")
 	transCtx.Context = intoContext(transCtx.Context, placement(cluster))
 
+	t.buildPlacementStatus(transCtx, p)
+
 	return nil
 }
 
@@ -77,18 +89,101 @@ func (t *clusterPlacementTransformer) assigned(transCtx *clusterTransformContext
 
 func (t *clusterPlacementTransformer) assign(transCtx *clusterTransformContext) []string {
 	replicas := t.maxReplicas(transCtx)
-	contexts := t.multiClusterMgr.GetContexts()
-	if replicas >= len(contexts) {
-		return contexts
+	return multicluster.Select(t.multiClusterMgr.GetContexts(), t.multiClusterMgr.GetHealth(), replicas)
+}
+
+// failover re-places the unavailable data-plane k8s clusters in the placement if the policy allows.
+func (t *clusterPlacementTransformer) failover(transCtx *clusterTransformContext) []string {
+	cluster := transCtx.Cluster
+	p := strings.Split(placement(transCtx.OrigCluster), ",")
+	policy, gracePeriod := t.failoverPolicy(cluster)
+	if policy != appsv1.RePlacePlacementFailoverPolicy {
+		return p
+	}
+
+	p, replacements := multicluster.RePlace(p, t.multiClusterMgr.GetContexts(), t.multiClusterMgr.GetHealth(), gracePeriod, time.Now())
+	for from, to := range replacements {
+		transCtx.Logger.Info(fmt.Sprintf("re-place the data cluster %s with %s", from, to))
+		if transCtx.GetRecorder() != nil {
+			transCtx.GetRecorder().Eventf(cluster, corev1.EventTypeWarning, dataClusterRePlaced,
+				"data cluster %s has been unavailable for more than %s, re-place it with %s", from, gracePeriod, to)
+		}
 	}
+	t.recordReplacements(transCtx, replacements)
+	return p
+}
+
+func (t *clusterPlacementTransformer) failoverPolicy(cluster *appsv1.Cluster) (appsv1.PlacementFailoverPolicy, time.Duration) {
+	policy, gracePeriod := appsv1.WaitPlacementFailoverPolicy, defaultPlacementGracePeriod
+	if cluster.Spec.Placement != nil {
+		if len(cluster.Spec.Placement.FailoverPolicy) > 0 {
+			policy = cluster.Spec.Placement.FailoverPolicy
+		}
+		if cluster.Spec.Placement.GracePeriod != nil {
+			gracePeriod = cluster.Spec.Placement.GracePeriod.Duration
+		}
+	}
+	return policy, gracePeriod
+}
 
-	slices.Sort(contexts)
-	for k := 0; k < len(contexts); k++ {
-		rand.Shuffle(len(contexts), func(i, j int) {
-			contexts[i], contexts[j] = contexts[j], contexts[i]
+func (t *clusterPlacementTransformer) recordReplacements(transCtx *clusterTransformContext, replacements map[string]string) {
+	if len(replacements) == 0 {
+		return
+	}
+	status := &transCtx.Cluster.Status
+	if status.Placement == nil {
+		status.Placement = &appsv1.ClusterPlacementStatus{}
+	}
+	health := t.multiClusterMgr.GetHealth()
+	for from, to := range replacements {
+		status.Placement.Failovers = slices.DeleteFunc(status.Placement.Failovers, func(f appsv1.DataClusterFailoverStatus) bool {
+			return f.Context == from
+		})
+		status.Placement.Failovers = append(status.Placement.Failovers, appsv1.DataClusterFailoverStatus{
+			Context:          from,
+			Phase:            appsv1.RePlacedDataClusterFailoverPhase,
+			UnavailableSince: unavailableSince(health, from),
+			RePlacedBy:       to,
+			Message:          health[from].Message,
 		})
 	}
-	return contexts[:replicas]
+}
+
+// buildPlacementStatus records the placement and the failover state of the unavailable data-plane k8s clusters.
+// The re-placed ones are kept until they recover, and the ones in the placement are waiting.
+func (t *clusterPlacementTransformer) buildPlacementStatus(transCtx *clusterTransformContext, p []string) {
+	cluster := transCtx.Cluster
+	health := t.multiClusterMgr.GetHealth()
+
+	failovers := make([]appsv1.DataClusterFailoverStatus, 0)
+	if cluster.Status.Placement != nil {
+		for _, f := range cluster.Status.Placement.Failovers {
+			if f.Phase == appsv1.RePlacedDataClusterFailoverPhase && !multicluster.IsAvailable(health, f.Context) {
+				failovers = append(failovers, f)
+			}
+		}
+	}
+	for _, c := range p {
+		if multicluster.IsAvailable(health, c) {
+			continue
+		}
+		failovers = append(failovers, appsv1.DataClusterFailoverStatus{
+			Context:          c,
+			Phase:            appsv1.WaitingDataClusterFailoverPhase,
+			UnavailableSince: unavailableSince(health, c),
+			Message:          health[c].Message,
+		})
+	}
+	slices.SortFunc(failovers, func(a, b appsv1.DataClusterFailoverStatus) int {
+		return strings.Compare(a.Context, b.Context)
+	})
+
+	cluster.Status.Placement = &appsv1.ClusterPlacementStatus{
+		Contexts: p,
+	}
+	if len(failovers) > 0 {
+		cluster.Status.Placement.Failovers = failovers
+	}
 }
 
 func (t *clusterPlacementTransformer) maxReplicas(transCtx *clusterTransformContext) int {
@@ -98,3 +193,8 @@ func (t *clusterPlacementTransformer) maxReplicas(transCtx *clusterTransformCont
 	})
 	return replicas
 }
+
+func unavailableSince(health map[string]multicluster.Health, context string) *metav1.Time {
+	t := metav1.NewTime(health[context].LastTransitionTime.Truncate(time.Second))
+	return &t
+}
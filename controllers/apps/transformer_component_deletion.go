@@ -25,6 +25,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -172,6 +173,7 @@ func compOwnedKinds() []client.ObjectList {
 		&appsv1alpha1.ConfigurationList{},
 		&corev1.ServiceAccountList{},
 		&rbacv1.RoleBindingList{},
+		&policyv1.PodDisruptionBudgetList{},
 	}
 }
 
@@ -0,0 +1,147 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	"github.com/apecloud/kubeblocks/pkg/controller/plan"
+	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/kbagent"
+)
+
+const (
+	kbAgentTokenLength = 32
+)
+
+// componentKBAgentAuthTransformer provisions the token and certificates to authenticate the callers of kb-agent.
+type componentKBAgentAuthTransformer struct{}
+
+var _ graph.Transformer = &componentKBAgentAuthTransformer{}
+
+func (t *componentKBAgentAuthTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*componentTransformContext)
+	if model.IsObjectDeleting(transCtx.ComponentOrig) {
+		return nil
+	}
+
+	synthesizeComp := transCtx.SynthesizeComponent
+	if synthesizeComp.LifecycleActions == nil {
+		return nil // has no kb-agent
+	}
+	mode, err := component.KBAgentAuthMode(synthesizeComp)
+	if err != nil || len(mode) == 0 {
+		return err
+	}
+
+	existSecret, err := t.getAuthSecret(transCtx, synthesizeComp)
+	if err != nil {
+		return err
+	}
+	secret := t.buildAuthSecret(synthesizeComp)
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+
+	if existSecret == nil {
+		if err = t.provisionCredentials(synthesizeComp, secret, mode); err != nil {
+			return err
+		}
+		if err = setCompOwnershipNFinalizer(transCtx.Component, secret); err != nil {
+			return err
+		}
+		graphCli.Create(dag, secret, inUniversalContext4G())
+		return nil
+	}
+
+	// keep the credentials provisioned, and provision the certificates if the mode is changed to mTLS
+	existSecretCopy := existSecret.DeepCopy()
+	ctrlutil.MergeMetadataMapInplace(secret.Labels, &existSecretCopy.Labels)
+	ctrlutil.MergeMetadataMapInplace(secret.Annotations, &existSecretCopy.Annotations)
+	if err = t.provisionCredentials(synthesizeComp, existSecretCopy, mode); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(existSecret, existSecretCopy) {
+		graphCli.Update(dag, existSecret, existSecretCopy, inUniversalContext4G())
+	}
+	return nil
+}
+
+func (t *componentKBAgentAuthTransformer) getAuthSecret(transCtx *componentTransformContext,
+	synthesizeComp *component.SynthesizedComponent) (*corev1.Secret, error) {
+	secretKey := types.NamespacedName{
+		Namespace: synthesizeComp.Namespace,
+		Name:      constant.GenerateKBAgentAuthSecretName(synthesizeComp.ClusterName, synthesizeComp.Name),
+	}
+	secret := &corev1.Secret{}
+	err := transCtx.Client.Get(transCtx.Context, secretKey, secret)
+	switch {
+	case err == nil:
+		return secret, nil
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+func (t *componentKBAgentAuthTransformer) buildAuthSecret(synthesizeComp *component.SynthesizedComponent) *corev1.Secret {
+	secretName := constant.GenerateKBAgentAuthSecretName(synthesizeComp.ClusterName, synthesizeComp.Name)
+	return builder.NewSecretBuilder(synthesizeComp.Namespace, secretName).
+		AddLabelsInMap(constant.GetCompLabels(synthesizeComp.ClusterName, synthesizeComp.Name)).
+		AddLabelsInMap(synthesizeComp.DynamicLabels).
+		AddLabelsInMap(synthesizeComp.StaticLabels).
+		AddAnnotationsInMap(synthesizeComp.DynamicAnnotations).
+		AddAnnotationsInMap(synthesizeComp.StaticAnnotations).
+		GetObject()
+}
+
+func (t *componentKBAgentAuthTransformer) provisionCredentials(synthesizeComp *component.SynthesizedComponent,
+	secret *corev1.Secret, mode string) error {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if len(secret.Data[kbagent.TokenSecretKey]) == 0 {
+		token := make([]byte, kbAgentTokenLength)
+		if _, err := rand.Read(token); err != nil {
+			return err
+		}
+		secret.Data[kbagent.TokenSecretKey] = []byte(hex.EncodeToString(token))
+	}
+	if mode == component.KBAgentAuthMTLS && len(secret.Data[constant.CertName]) == 0 {
+		certs, err := plan.ComposeKBAgentCerts(*synthesizeComp)
+		if err != nil {
+			return err
+		}
+		for k, v := range certs {
+			secret.Data[k] = v
+		}
+	}
+	return nil
+}
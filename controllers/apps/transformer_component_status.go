@@ -21,6 +21,7 @@ package apps
 
 import (
 	"fmt"
+	"maps"
 	"strconv"
 	"strings"
 	"time"
@@ -49,6 +50,9 @@ const (
 
 	// defaultRoleProbeTimeoutAfterPodsReady the default role probe timeout for application when all pods of component are ready.
 	defaultRoleProbeTimeoutAfterPodsReady int32 = 60
+
+	// dataLoadProgressMessagePrefix the prefix of the status message for the progress of loading data into new replicas.
+	dataLoadProgressMessagePrefix = "loading data: "
 )
 
 // componentStatusTransformer computes the current status: read the underlying workload status and update the component status
@@ -114,6 +118,8 @@ func (t *componentStatusTransformer) init(transCtx *componentTransformContext, d
 // reconcileStatus reconciles component status.
 func (t *componentStatusTransformer) reconcileStatus(transCtx *componentTransformContext) error {
 	if t.runningITS == nil {
+		// the data clusters of the deleted workload are stale.
+		t.comp.Status.DataClusters = nil
 		return t.reconcileStatusCondition(transCtx)
 	}
 
@@ -184,9 +190,35 @@ func (t *componentStatusTransformer) reconcileStatus(transCtx *componentTransfor
 		t.setComponentStatusPhase(transCtx, appsv1.FailedComponentPhase, messages, "component is Failed")
 	}
 
+	if err = t.reconcileDataLoadProgress(); err != nil {
+		return err
+	}
+
+	t.reconcileDataClustersStatus()
+
 	return t.reconcileStatusCondition(transCtx)
 }
 
+// reconcileDataClustersStatus breaks the component status down to the data-plane k8s clusters in the multi-cluster mode.
+func (t *componentStatusTransformer) reconcileDataClustersStatus() {
+	if len(t.runningITS.Status.DataClusters) == 0 {
+		t.comp.Status.DataClusters = nil
+		return
+	}
+	dataClusters := make([]appsv1.ComponentDataClusterStatus, 0, len(t.runningITS.Status.DataClusters))
+	for _, status := range t.runningITS.Status.DataClusters {
+		dataClusters = append(dataClusters, appsv1.ComponentDataClusterStatus{
+			Context:           status.Context,
+			Replicas:          status.Replicas,
+			ReadyReplicas:     status.ReadyReplicas,
+			AvailableReplicas: status.AvailableReplicas,
+			Roles:             maps.Clone(status.Roles),
+			LastError:         status.LastError,
+		})
+	}
+	t.comp.Status.DataClusters = dataClusters
+}
+
 func (t *componentStatusTransformer) workloadGeneration() (*int64, error) {
 	if t.runningITS == nil {
 		return nil, nil
@@ -283,6 +315,31 @@ func (t *componentStatusTransformer) hasScaleOutRunning(transCtx *componentTrans
 	return true, false, nil
 }
 
+// reconcileDataLoadProgress surfaces the progress of loading data into the new replicas in the status message.
+func (t *componentStatusTransformer) reconcileDataLoadProgress() error {
+	progress, err := component.GetReplicasDataLoadProgress(t.protoITS)
+	if err != nil {
+		return err
+	}
+	messages := appsv1alpha1.ComponentMessageMap(t.comp.Status.Message)
+	for key, msg := range messages {
+		if strings.HasPrefix(msg, dataLoadProgressMessagePrefix) {
+			delete(messages, key)
+		}
+	}
+	if len(progress) == 0 {
+		return nil
+	}
+	if messages == nil {
+		messages = appsv1alpha1.ComponentMessageMap{}
+	}
+	for replica, p := range progress {
+		messages.SetObjectMessage(constant.PodKind, replica, dataLoadProgressMessagePrefix+p.String())
+	}
+	t.comp.Status.Message = messages
+	return nil
+}
+
 // hasVolumeExpansionRunning checks if the volume expansion is running.
 func (t *componentStatusTransformer) hasVolumeExpansionRunning(transCtx *componentTransformContext) (running bool, failed bool, err error) {
 	for _, vct := range t.runningITS.Spec.VolumeClaimTemplates {
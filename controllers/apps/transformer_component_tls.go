@@ -23,23 +23,40 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
 	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	"github.com/apecloud/kubeblocks/pkg/controller/plan"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
 
+const (
+	// tlsCertPropagationDelay is the time to wait for the kubelet to sync the re-issued secret to the pods
+	// before reloading the certificates.
+	tlsCertPropagationDelay = 90 * time.Second
+
+	// tlsReloadRetryInterval is the interval to call the TLS reload action again on the pods failed.
+	tlsReloadRetryInterval = 10 * time.Second
+)
+
 // componentTLSTransformer handles component configuration render
 type componentTLSTransformer struct {
 	client.Client
@@ -56,16 +73,17 @@ func (t *componentTLSTransformer) Transform(ctx graph.TransformContext, dag *gra
 		return err
 	}
 
-	// build tls cert
-	if err := buildTLSCert(transCtx.Context, transCtx.Client, *synthesizedComp, dag); err != nil {
-		return err
+	// build tls cert, the delayed requeue error to wait for the certificates to be reloaded is returned after the re-render
+	buildErr := buildTLSCert(transCtx, *synthesizedComp, dag)
+	if buildErr != nil && !intctrlutil.IsDelayedRequeueError(buildErr) {
+		return buildErr
 	}
 
 	if err := checkAndTriggerReRender(transCtx.Context, *synthesizedComp, t.Client); err != nil {
 		return err
 	}
 
-	return nil
+	return buildErr
 }
 
 // a hack way to notify the configuration controller to re-render config
@@ -103,7 +121,8 @@ func checkAndTriggerReRender(ctx context.Context, synthesizedComp component.Synt
 	return cli.Patch(ctx, confCopy, client.MergeFrom(conf.DeepCopy()))
 }
 
-func buildTLSCert(ctx context.Context, cli client.Reader, synthesizedComp component.SynthesizedComponent, dag *graph.DAG) error {
+func buildTLSCert(transCtx *componentTransformContext, synthesizedComp component.SynthesizedComponent, dag *graph.DAG) error {
+	ctx, cli := transCtx.Context, transCtx.Client
 	tls := synthesizedComp.TLSConfig
 	if tls == nil || !tls.Enable {
 		return nil
@@ -119,12 +138,16 @@ func buildTLSCert(ctx context.Context, cli client.Reader, synthesizedComp compon
 		}
 	case appsv1.IssuerKubeBlocks:
 		graphCli, _ := cli.(model.GraphClient)
+		caSecret, err := buildTLSCASecret(transCtx, synthesizedComp, graphCli, dag)
+		if err != nil {
+			return err
+		}
 		secretName := plan.GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name)
 		existSecret := &corev1.Secret{}
-		err := cli.Get(ctx, types.NamespacedName{Namespace: synthesizedComp.Namespace, Name: secretName}, existSecret)
+		err = cli.Get(ctx, types.NamespacedName{Namespace: synthesizedComp.Namespace, Name: secretName}, existSecret)
 		if err != nil {
 			if errors.IsNotFound(err) {
-				secret, err := plan.ComposeTLSSecret(synthesizedComp)
+				secret, err := plan.ComposeTLSSecret(synthesizedComp, caSecret)
 				if err != nil {
 					return err
 				}
@@ -133,20 +156,203 @@ func buildTLSCert(ctx context.Context, cli client.Reader, synthesizedComp compon
 			}
 			return err
 		} else {
-			updateTLSSecretMeta(existSecret, graphCli, dag, synthesizedComp)
+			return updateTLSSecret(transCtx, existSecret, caSecret, graphCli, dag, synthesizedComp)
+		}
+	case appsv1.IssuerCertManager:
+		return buildCertManagerCertificate(transCtx, synthesizedComp, dag)
+	}
+	return nil
+}
+
+// buildTLSCASecret returns the CA that signs the TLS certificates of the component, and creates it if not exists.
+// The TLS secrets issued before the CA is kept have no CA to reuse, they are signed by the new CA when re-issued.
+func buildTLSCASecret(transCtx *componentTransformContext, synthesizedComp component.SynthesizedComponent,
+	graphCli model.GraphClient, dag *graph.DAG) (*corev1.Secret, error) {
+	caSecret := &corev1.Secret{}
+	caSecretKey := types.NamespacedName{
+		Namespace: synthesizedComp.Namespace,
+		Name:      plan.GenerateTLSCASecretName(synthesizedComp.ClusterName, synthesizedComp.Name),
+	}
+	err := transCtx.Client.Get(transCtx.Context, caSecretKey, caSecret)
+	if err == nil || !errors.IsNotFound(err) {
+		return caSecret, err
+	}
+	if caSecret, err = plan.ComposeTLSCASecret(synthesizedComp); err != nil {
+		return nil, err
+	}
+	graphCli.Create(dag, caSecret)
+	return caSecret, nil
+}
+
+// buildCertManagerCertificate creates or updates the cert-manager Certificate of the component,
+// cert-manager will issue and renew the certificates into the TLS secret.
+func buildCertManagerCertificate(transCtx *componentTransformContext, synthesizedComp component.SynthesizedComponent, dag *graph.DAG) error {
+	ctx, cli := transCtx.Context, transCtx.Client
+	graphCli, _ := cli.(model.GraphClient)
+	certProto, err := plan.BuildCertManagerCertificate(synthesizedComp)
+	if err != nil {
+		return err
+	}
+
+	existCert := &unstructured.Unstructured{}
+	existCert.SetGroupVersionKind(plan.CertManagerCertificateGVK)
+	err = cli.Get(ctx, client.ObjectKeyFromObject(certProto), existCert)
+	switch {
+	case meta.IsNoMatchError(err):
+		return fmt.Errorf("the cert-manager Certificate CRD is not installed, can't issue certificates with cert-manager: %s", err.Error())
+	case errors.IsNotFound(err):
+		if err = intctrlutil.SetOwnership(transCtx.Component, certProto, rscheme, ""); err != nil {
+			return err
 		}
+		graphCli.Create(dag, certProto)
+		return nil
+	case err != nil:
+		return err
+	}
+
+	existCertCopy := existCert.DeepCopy()
+	existCertCopy.SetLabels(intctrlutil.MergeMetadataMaps(existCert.GetLabels(), certProto.GetLabels()))
+	existCertCopy.Object["spec"] = certProto.Object["spec"]
+	if !reflect.DeepEqual(existCert, existCertCopy) {
+		graphCli.Update(dag, existCert, existCertCopy)
 	}
 	return nil
 }
 
-func updateTLSSecretMeta(existSecret *corev1.Secret, graphCli model.GraphClient, dag *graph.DAG, synthesizedComp component.SynthesizedComponent) {
+// updateTLSSecret updates the meta of the TLS secret, re-issues the certificates if they are about to expire,
+// and drives the pods to load the re-issued certificates.
+func updateTLSSecret(transCtx *componentTransformContext, existSecret, caSecret *corev1.Secret,
+	graphCli model.GraphClient, dag *graph.DAG, synthesizedComp component.SynthesizedComponent) error {
 	secretProto := plan.BuildTLSSecret(synthesizedComp)
 	existSecretCopy := existSecret.DeepCopy()
 	existSecretCopy.Labels = secretProto.Labels
 	existSecretCopy.Annotations = secretProto.Annotations
+	for _, key := range []string{constant.TLSCertRotatedAtAnnotationKey, constant.TLSCertReloadedAtAnnotationKey, constant.TLSCertReloadedPodsAnnotationKey} {
+		if val, ok := existSecret.Annotations[key]; ok {
+			if existSecretCopy.Annotations == nil {
+				existSecretCopy.Annotations = map[string]string{}
+			}
+			existSecretCopy.Annotations[key] = val
+		}
+	}
+
+	var err error
+	if plan.IsTLSCertRenewalDue(synthesizedComp, existSecret, time.Now()) {
+		err = rotateTLSCert(transCtx, existSecretCopy, caSecret, synthesizedComp)
+	} else {
+		err = reloadTLSCert(transCtx, existSecretCopy, synthesizedComp)
+	}
+
 	if !reflect.DeepEqual(existSecret, existSecretCopy) {
 		graphCli.Update(dag, existSecret, existSecretCopy)
 	}
+	return err
+}
+
+// rotateTLSCert re-issues the certificate with the kept CA, the clients trusting the CA are not affected.
+func rotateTLSCert(transCtx *componentTransformContext, secret, caSecret *corev1.Secret, synthesizedComp component.SynthesizedComponent) error {
+	newSecret, err := plan.ComposeTLSSecret(synthesizedComp, caSecret)
+	if err != nil {
+		return err
+	}
+	secret.Data = map[string][]byte{}
+	for key, val := range newSecret.StringData {
+		secret.Data[key] = []byte(val)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	rotatedAt := time.Now().Format(time.RFC3339)
+	secret.Annotations[constant.TLSCertRotatedAtAnnotationKey] = rotatedAt
+	delete(secret.Annotations, constant.TLSCertReloadedPodsAnnotationKey)
+	transCtx.EventRecorder.Eventf(transCtx.Component, corev1.EventTypeNormal, "TLSCertRotated",
+		"the TLS certificates in secret %s have been re-issued", secret.Name)
+
+	if hasTLSCertReloadAction(synthesizedComp) {
+		return intctrlutil.NewDelayedRequeueError(tlsCertPropagationDelay, "wait for the re-issued TLS certificates to be synced to pods")
+	}
+	// the workload transformer will roll the pods to load the re-issued certificates
+	transCtx.TLSCertRotatedAt = rotatedAt
+	return nil
+}
+
+// reloadTLSCert calls the TLS reload action on all pods to reload the re-issued certificates if there is any pending.
+// The pods that have reloaded the certificates are recorded in the secret, so they are not called again if any
+// of the others fails, and the failed ones are retried later without blocking the reconciliation of the component.
+func reloadTLSCert(transCtx *componentTransformContext, secret *corev1.Secret, synthesizedComp component.SynthesizedComponent) error {
+	rotatedAt, ok := secret.Annotations[constant.TLSCertRotatedAtAnnotationKey]
+	if !ok {
+		return nil
+	}
+	if !hasTLSCertReloadAction(synthesizedComp) {
+		transCtx.TLSCertRotatedAt = rotatedAt
+		return nil
+	}
+	if secret.Annotations[constant.TLSCertReloadedAtAnnotationKey] == rotatedAt {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, rotatedAt); err == nil && time.Since(t) < tlsCertPropagationDelay {
+		return intctrlutil.NewDelayedRequeueError(tlsCertPropagationDelay-time.Since(t), "wait for the re-issued TLS certificates to be synced to pods")
+	}
+
+	pods, err := component.ListOwnedPods(transCtx.Context, transCtx.Client,
+		synthesizedComp.Namespace, synthesizedComp.ClusterName, synthesizedComp.Name)
+	if err != nil {
+		return err
+	}
+	reloaded := sets.New[string]()
+	if val := secret.Annotations[constant.TLSCertReloadedPodsAnnotationKey]; len(val) > 0 {
+		reloaded.Insert(strings.Split(val, ",")...)
+	}
+	var errs []string
+	for _, pod := range pods {
+		if reloaded.Has(pod.Name) {
+			continue
+		}
+		lfa, err := lifecycle.New(&synthesizedComp, pod)
+		if err != nil {
+			return err
+		}
+		if err = lfa.TLSReload(transCtx.Context, transCtx.Client, nil); err != nil {
+			transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, "TLSReloadFailed",
+				fmt.Sprintf("failed to reload the TLS certificates on replica %s: %s", pod.Name, err.Error()))
+			errs = append(errs, fmt.Sprintf("%s: %s", pod.Name, err.Error()))
+			continue
+		}
+		reloaded.Insert(pod.Name)
+		secret.Annotations[constant.TLSCertReloadedPodsAnnotationKey] = strings.Join(sets.List(reloaded), ",")
+	}
+	if len(errs) > 0 {
+		return intctrlutil.NewDelayedRequeueError(tlsReloadRetryInterval,
+			fmt.Sprintf("failed to reload the TLS certificates: %s", strings.Join(errs, "; ")))
+	}
+	secret.Annotations[constant.TLSCertReloadedAtAnnotationKey] = rotatedAt
+	delete(secret.Annotations, constant.TLSCertReloadedPodsAnnotationKey)
+	return nil
+}
+
+func hasTLSCertReloadAction(synthesizedComp component.SynthesizedComponent) bool {
+	return synthesizedComp.LifecycleActions != nil && synthesizedComp.LifecycleActions.TLSReload != nil
+}
+
+// buildTLSCertRotationRestartAnnotation triggers a rolling restart of the workload to load the re-issued
+// certificates if the TLS reload action is not defined.
+func buildTLSCertRotationRestartAnnotation(transCtx *componentTransformContext, runningITS, protoITS *workloads.InstanceSet) {
+	rotatedAt := transCtx.TLSCertRotatedAt
+	if len(rotatedAt) == 0 || runningITS == nil {
+		return
+	}
+	if restartAt, ok := runningITS.Spec.Template.Annotations[constant.RestartAnnotationKey]; ok {
+		t1, err1 := time.Parse(time.RFC3339, restartAt)
+		t2, err2 := time.Parse(time.RFC3339, rotatedAt)
+		if err1 == nil && err2 == nil && !t1.Before(t2) {
+			return
+		}
+	}
+	if protoITS.Spec.Template.Annotations == nil {
+		protoITS.Spec.Template.Annotations = map[string]string{}
+	}
+	protoITS.Spec.Template.Annotations[constant.RestartAnnotationKey] = rotatedAt
 }
 
 func updateTLSVolumeAndVolumeMount(podSpec *corev1.PodSpec, clusterName string, synthesizeComp component.SynthesizedComponent) error {
@@ -186,6 +392,14 @@ func composeTLSVolume(clusterName string, synthesizeComp component.SynthesizedCo
 	if tls.Issuer.Name == appsv1.IssuerUserProvided && tls.Issuer.SecretRef == nil {
 		return nil, fmt.Errorf("secret ref shouldn't be nil when issuer is UserProvided")
 	}
+	if tls.Issuer.Name == appsv1.IssuerCertManager && tls.Issuer.IssuerRef == nil {
+		return nil, fmt.Errorf("issuer ref shouldn't be nil when issuer is CertManager")
+	}
+
+	mode := int32(0600)
+	if tls.Issuer.Name == appsv1.IssuerCertManager {
+		return composeCertManagerTLSVolume(plan.GenerateTLSSecretName(clusterName, synthesizeComp.Name), mode), nil
+	}
 
 	var secretName, ca, cert, key string
 	switch tls.Issuer.Name {
@@ -200,7 +414,6 @@ func composeTLSVolume(clusterName string, synthesizeComp component.SynthesizedCo
 		cert = tls.Issuer.SecretRef.Cert
 		key = tls.Issuer.SecretRef.Key
 	}
-	mode := int32(0600)
 	volume := corev1.Volume{
 		Name: constant.VolumeName,
 		VolumeSource: corev1.VolumeSource{
@@ -220,6 +433,40 @@ func composeTLSVolume(clusterName string, synthesizeComp component.SynthesizedCo
 	return &volume, nil
 }
 
+// composeCertManagerTLSVolume projects the TLS secret issued by cert-manager, the CA is optional since some issuers,
+// e.g. ACME, don't put the CA into the secret, and the pods would never start if it is required.
+func composeCertManagerTLSVolume(secretName string, mode int32) *corev1.Volume {
+	return &corev1.Volume{
+		Name: constant.VolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+							Items: []corev1.KeyToPath{
+								{Key: constant.CertName, Path: constant.CertName},
+								{Key: constant.KeyName, Path: constant.KeyName},
+							},
+							Optional: ptr.To(false),
+						},
+					},
+					{
+						Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+							Items: []corev1.KeyToPath{
+								{Key: constant.CAName, Path: constant.CAName},
+							},
+							Optional: ptr.To(true),
+						},
+					},
+				},
+				DefaultMode: &mode,
+			},
+		},
+	}
+}
+
 func composeTLSVolumeMount() corev1.VolumeMount {
 	return corev1.VolumeMount{
 		Name:      constant.VolumeName,
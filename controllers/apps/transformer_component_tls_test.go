@@ -21,13 +21,21 @@ package apps
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/golang/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
@@ -37,8 +45,12 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	"github.com/apecloud/kubeblocks/pkg/controller/plan"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	"github.com/apecloud/kubeblocks/pkg/generics"
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	kbagentproto "github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 )
 
@@ -115,7 +127,9 @@ var _ = Describe("TLS self-signed cert function", func() {
 					ClusterName: "test",
 					Name:        "self-provided",
 				}
-				userProvidedTLSSecretObj, err = plan.ComposeTLSSecret(synthesizedComp)
+				caSecret, err := plan.ComposeTLSCASecret(synthesizedComp)
+				Expect(err).Should(BeNil())
+				userProvidedTLSSecretObj, err = plan.ComposeTLSSecret(synthesizedComp, caSecret)
 				Expect(err).Should(BeNil())
 				Expect(k8sClient.Create(ctx, userProvidedTLSSecretObj)).Should(Succeed())
 			})
@@ -221,10 +235,11 @@ var _ = Describe("TLS self-signed cert function", func() {
 
 		Context("when issuer is KubeBlocks check secret exists or not", func() {
 			var (
-				kbTLSSecretObj  *corev1.Secret
-				synthesizedComp component.SynthesizedComponent
-				dag             *graph.DAG
-				err             error
+				kbTLSSecretObj   *corev1.Secret
+				kbTLSCASecretObj *corev1.Secret
+				synthesizedComp  component.SynthesizedComponent
+				dag              *graph.DAG
+				err              error
 			)
 
 			BeforeEach(func() {
@@ -240,30 +255,327 @@ var _ = Describe("TLS self-signed cert function", func() {
 					},
 				}
 				dag = &graph.DAG{}
-				kbTLSSecretObj, err = plan.ComposeTLSSecret(synthesizedComp)
+				kbTLSCASecretObj, err = plan.ComposeTLSCASecret(synthesizedComp)
+				Expect(err).Should(BeNil())
+				Expect(k8sClient.Create(ctx, kbTLSCASecretObj)).Should(Succeed())
+				// issue the certificates valid for one day
+				issued := synthesizedComp
+				issued.TLSConfig = &appsv1.TLSConfig{
+					Enable: true,
+					Issuer: &appsv1.Issuer{
+						Name:     appsv1.IssuerKubeBlocks,
+						Duration: &metav1.Duration{Duration: 24 * time.Hour},
+					},
+				}
+				kbTLSSecretObj, err = plan.ComposeTLSSecret(issued, kbTLSCASecretObj)
 				Expect(err).Should(BeNil())
 				Expect(k8sClient.Create(ctx, kbTLSSecretObj)).Should(Succeed())
 			})
 
 			AfterEach(func() {
 				// delete self provided tls certs secret
-				Expect(k8sClient.Delete(ctx, kbTLSSecretObj)).Should(Succeed())
-				Eventually(func() bool {
-					err := k8sClient.Get(ctx,
-						client.ObjectKeyFromObject(kbTLSSecretObj),
-						kbTLSSecretObj)
-					return apierrors.IsNotFound(err)
-				}).Should(BeTrue())
+				for _, obj := range []*corev1.Secret{kbTLSSecretObj, kbTLSCASecretObj} {
+					Expect(k8sClient.Delete(ctx, obj)).Should(Succeed())
+					Eventually(func() bool {
+						err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), &corev1.Secret{})
+						return apierrors.IsNotFound(err)
+					}).Should(BeTrue())
+				}
 			})
 
 			It("should skip if the existence of the secret is confirmed", func() {
-				err := buildTLSCert(ctx, k8sClient, synthesizedComp, dag)
+				transCtx := &componentTransformContext{Context: ctx, Client: k8sClient}
+				err := buildTLSCert(transCtx, synthesizedComp, dag)
 				Expect(err).Should(BeNil())
 				createdSecret := &corev1.Secret{}
 				err = k8sClient.Get(ctx, types.NamespacedName{Namespace: testCtx.DefaultNamespace, Name: kbTLSSecretObj.Name}, createdSecret)
 				Expect(err).Should(BeNil())
 				Expect(createdSecret.Data).To(Equal(kbTLSSecretObj.Data))
 			})
+
+			It("should re-issue the certificates if they are about to expire", func() {
+				synthesizedComp.TLSConfig.Issuer.Duration = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+				synthesizedComp.TLSConfig.Issuer.RenewBefore = &metav1.Duration{Duration: 29 * 24 * time.Hour}
+				transCtx := &componentTransformContext{
+					Context:       ctx,
+					Client:        model.NewGraphClient(k8sClient),
+					EventRecorder: clusterRecorder,
+					Component: &appsv1.Component{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: testCtx.DefaultNamespace,
+							Name:      constant.GenerateClusterComponentName(synthesizedComp.ClusterName, synthesizedComp.Name),
+						},
+					},
+				}
+				err := buildTLSCert(transCtx, synthesizedComp, dag)
+				Expect(err).Should(BeNil())
+				Expect(transCtx.TLSCertRotatedAt).ShouldNot(BeEmpty())
+
+				secrets := transCtx.Client.(model.GraphClient).FindAll(dag, &corev1.Secret{})
+				Expect(secrets).Should(HaveLen(1))
+				secret := secrets[0].(*corev1.Secret)
+				Expect(secret.Annotations).Should(HaveKeyWithValue(constant.TLSCertRotatedAtAnnotationKey, transCtx.TLSCertRotatedAt))
+				Expect(secret.Data[constant.CertName]).ShouldNot(Equal([]byte(kbTLSSecretObj.StringData[constant.CertName])))
+
+				By("checking the certificate is re-signed by the kept CA")
+				Expect(secret.Data[constant.CAName]).Should(Equal([]byte(kbTLSCASecretObj.StringData[constant.CAName])))
+				Expect(secret.Data).ShouldNot(HaveKey(constant.CAKeyName))
+
+			})
+		})
+
+		Context("when issuer is CertManager", func() {
+			var (
+				synthesizedComp component.SynthesizedComponent
+				transCtx        *componentTransformContext
+			)
+
+			BeforeEach(func() {
+				synthesizedComp = component.SynthesizedComponent{
+					Namespace:   testCtx.DefaultNamespace,
+					ClusterName: "test-cm",
+					Name:        "test-cm-tls",
+					Replicas:    2,
+					TLSConfig: &appsv1.TLSConfig{
+						Enable: true,
+						Issuer: &appsv1.Issuer{
+							Name:      appsv1.IssuerCertManager,
+							IssuerRef: &appsv1.CertManagerIssuerRef{Name: "test-issuer"},
+							Duration:  &metav1.Duration{Duration: 30 * 24 * time.Hour},
+						},
+					},
+				}
+				transCtx = &componentTransformContext{
+					Context: ctx,
+					Client:  model.NewGraphClient(k8sClient),
+					Component: &appsv1.Component{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: testCtx.DefaultNamespace,
+							Name:      constant.GenerateClusterComponentName(synthesizedComp.ClusterName, synthesizedComp.Name),
+							UID:       "test-cm-uid",
+						},
+					},
+				}
+			})
+
+			AfterEach(func() {
+				cert := &unstructured.Unstructured{}
+				cert.SetGroupVersionKind(plan.CertManagerCertificateGVK)
+				cert.SetNamespace(testCtx.DefaultNamespace)
+				cert.SetName(plan.GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name))
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, cert))).Should(Succeed())
+			})
+
+			findCertificates := func(dag *graph.DAG) []*model.ObjectVertex {
+				var vertices []*model.ObjectVertex
+				for _, vertex := range dag.Vertices() {
+					v, ok := vertex.(*model.ObjectVertex)
+					if !ok {
+						continue
+					}
+					if u, ok := v.Obj.(*unstructured.Unstructured); ok && u.GroupVersionKind() == plan.CertManagerCertificateGVK {
+						vertices = append(vertices, v)
+					}
+				}
+				return vertices
+			}
+
+			It("should create and update the Certificate issuing into the TLS secret", func() {
+				secretName := plan.GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name)
+
+				By("creating the Certificate")
+				dag := &graph.DAG{}
+				Expect(buildTLSCert(transCtx, synthesizedComp, dag)).Should(Succeed())
+				vertices := findCertificates(dag)
+				Expect(vertices).Should(HaveLen(1))
+				Expect(*vertices[0].Action).Should(Equal(model.CREATE))
+				cert := vertices[0].Obj.(*unstructured.Unstructured)
+				Expect(cert.GetName()).Should(Equal(secretName))
+				Expect(cert.GetOwnerReferences()).Should(HaveLen(1))
+				Expect(unstructured.NestedString(cert.Object, "spec", "secretName")).Should(Equal(secretName))
+				Expect(unstructured.NestedString(cert.Object, "spec", "issuerRef", "name")).Should(Equal("test-issuer"))
+				Expect(unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")).Should(Equal("Issuer"))
+				Expect(unstructured.NestedString(cert.Object, "spec", "duration")).Should(Equal((30 * 24 * time.Hour).String()))
+				dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+				podFQDNs, err := component.PodFQDNs(&synthesizedComp)
+				Expect(err).Should(BeNil())
+				Expect(dnsNames).Should(ContainElements(podFQDNs))
+				Expect(k8sClient.Create(ctx, cert)).Should(Succeed())
+
+				By("updating the Certificate when the issuer is changed")
+				synthesizedComp.TLSConfig.Issuer.IssuerRef = &appsv1.CertManagerIssuerRef{Name: "test-cluster-issuer", Kind: "ClusterIssuer"}
+				dag = &graph.DAG{}
+				Expect(buildTLSCert(transCtx, synthesizedComp, dag)).Should(Succeed())
+				vertices = findCertificates(dag)
+				Expect(vertices).Should(HaveLen(1))
+				Expect(*vertices[0].Action).Should(Equal(model.UPDATE))
+				cert = vertices[0].Obj.(*unstructured.Unstructured)
+				Expect(unstructured.NestedString(cert.Object, "spec", "issuerRef", "name")).Should(Equal("test-cluster-issuer"))
+				Expect(unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")).Should(Equal("ClusterIssuer"))
+
+				By("no change if the Certificate is up to date")
+				Expect(k8sClient.Update(ctx, cert)).Should(Succeed())
+				dag = &graph.DAG{}
+				Expect(buildTLSCert(transCtx, synthesizedComp, dag)).Should(Succeed())
+				Expect(findCertificates(dag)).Should(BeEmpty())
+			})
+
+			It("should mount the TLS secret issued by cert-manager", func() {
+				volume, err := composeTLSVolume(synthesizedComp.ClusterName, synthesizedComp)
+				Expect(err).Should(BeNil())
+				Expect(volume.Projected).ShouldNot(BeNil())
+				var items []corev1.KeyToPath
+				for _, source := range volume.Projected.Sources {
+					Expect(source.Secret).ShouldNot(BeNil())
+					Expect(source.Secret.Name).Should(Equal(plan.GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name)))
+					items = append(items, source.Secret.Items...)
+				}
+				Expect(items).Should(ConsistOf(
+					corev1.KeyToPath{Key: constant.CAName, Path: constant.CAName},
+					corev1.KeyToPath{Key: constant.CertName, Path: constant.CertName},
+					corev1.KeyToPath{Key: constant.KeyName, Path: constant.KeyName},
+				))
+
+				By("failing without the issuer ref")
+				synthesizedComp.TLSConfig.Issuer.IssuerRef = nil
+				_, err = composeTLSVolume(synthesizedComp.ClusterName, synthesizedComp)
+				Expect(err).ShouldNot(BeNil())
+			})
+
+			It("should mount the TLS secret without the CA, e.g. issued by an ACME issuer", func() {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testCtx.DefaultNamespace,
+						Name:      plan.GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name),
+					},
+					Type: corev1.SecretTypeTLS,
+					Data: map[string][]byte{
+						constant.CertName: []byte("cert"),
+						constant.KeyName:  []byte("key"),
+					},
+				}
+				Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+				defer func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).Should(Succeed())
+				}()
+
+				volume, err := composeTLSVolume(synthesizedComp.ClusterName, synthesizedComp)
+				Expect(err).Should(BeNil())
+				Expect(volume.Projected).ShouldNot(BeNil())
+				// the kubelet fails to set up the volume if any required key is missing in the secret
+				for _, source := range volume.Projected.Sources {
+					for _, item := range source.Secret.Items {
+						if _, ok := secret.Data[item.Key]; !ok {
+							Expect(source.Secret.Optional).ShouldNot(BeNil(), item.Key)
+							Expect(*source.Secret.Optional).Should(BeTrue(), item.Key)
+						}
+					}
+				}
+			})
+		})
+	})
+
+	Context("reload the re-issued certificates", func() {
+		const (
+			clusterName = "test-reload"
+			compName    = "test-reload-tls"
+		)
+
+		var (
+			synthesizedComp component.SynthesizedComponent
+			transCtx        *componentTransformContext
+			secret          *corev1.Secret
+			calls           int
+			failedCalls     sets.Set[int]
+		)
+
+		newPod := func(ordinal int) *corev1.Pod {
+			return testapps.NewPodFactory(testCtx.DefaultNamespace, fmt.Sprintf("%s-%s-%d", clusterName, compName, ordinal)).
+				AddContainer(corev1.Container{Name: "test-container", Image: "test-image"}).
+				AddLabelsInMap(constant.GetCompLabels(clusterName, compName)).
+				GetObject()
+		}
+
+		BeforeEach(func() {
+			calls = 0
+			failedCalls = sets.New[int]()
+			testapps.MockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req kbagentproto.ActionRequest) (kbagentproto.ActionResponse, error) {
+					Expect(req.Action).Should(Equal("tlsReload"))
+					calls++
+					if failedCalls.Has(calls) {
+						return kbagentproto.ActionResponse{}, fmt.Errorf("mock error")
+					}
+					return kbagentproto.ActionResponse{}, nil
+				}).AnyTimes()
+			})
+
+			synthesizedComp = component.SynthesizedComponent{
+				Namespace:   testCtx.DefaultNamespace,
+				ClusterName: clusterName,
+				Name:        compName,
+				LifecycleActions: &appsv1.ComponentLifecycleActions{
+					TLSReload: &appsv1.Action{
+						Exec: &appsv1.ExecAction{Command: []string{"reload"}},
+					},
+				},
+			}
+			transCtx = &componentTransformContext{
+				Context:       ctx,
+				Client:        model.NewGraphClient(&mockReader{objs: []client.Object{newPod(0), newPod(1), newPod(2)}}),
+				EventRecorder: record.NewFakeRecorder(100),
+				Component:     &appsv1.Component{},
+			}
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testCtx.DefaultNamespace,
+					Name:      plan.GenerateTLSSecretName(clusterName, compName),
+					Annotations: map[string]string{
+						constant.TLSCertRotatedAtAnnotationKey: time.Now().Add(-time.Hour).Format(time.RFC3339),
+					},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			kbacli.UnsetMockClient()
+		})
+
+		It("should not call the pods reloaded again", func() {
+			rotatedAt := secret.Annotations[constant.TLSCertRotatedAtAnnotationKey]
+
+			By("the second pod fails to reload, the others are reloaded")
+			failedCalls.Insert(2)
+			err := reloadTLSCert(transCtx, secret, synthesizedComp)
+			Expect(err).ShouldNot(BeNil())
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(calls).Should(Equal(3))
+			Expect(secret.Annotations).Should(HaveKeyWithValue(constant.TLSCertReloadedPodsAnnotationKey,
+				strings.Join([]string{newPod(0).Name, newPod(2).Name}, ",")))
+			Expect(secret.Annotations).ShouldNot(HaveKey(constant.TLSCertReloadedAtAnnotationKey))
+			Expect(transCtx.EventRecorder.(*record.FakeRecorder).Events).Should(Receive(ContainSubstring("TLSReloadFailed")))
+
+			By("retry the failed pod only")
+			Expect(reloadTLSCert(transCtx, secret, synthesizedComp)).Should(Succeed())
+			Expect(calls).Should(Equal(4))
+			Expect(secret.Annotations).Should(HaveKeyWithValue(constant.TLSCertReloadedAtAnnotationKey, rotatedAt))
+			Expect(secret.Annotations).ShouldNot(HaveKey(constant.TLSCertReloadedPodsAnnotationKey))
+			Expect(transCtx.TLSCertRotatedAt).Should(BeEmpty())
+
+			By("no more calls once reloaded")
+			Expect(reloadTLSCert(transCtx, secret, synthesizedComp)).Should(Succeed())
+			Expect(calls).Should(Equal(4))
+		})
+
+		It("should restart the pods if the TLS reload action is not defined", func() {
+			synthesizedComp.LifecycleActions = &appsv1.ComponentLifecycleActions{
+				Reconfigure: &appsv1.Action{
+					Exec: &appsv1.ExecAction{Command: []string{"reconfigure"}},
+				},
+			}
+			Expect(reloadTLSCert(transCtx, secret, synthesizedComp)).Should(Succeed())
+			Expect(calls).Should(Equal(0))
+			Expect(transCtx.TLSCertRotatedAt).Should(Equal(secret.Annotations[constant.TLSCertRotatedAtAnnotationKey]))
 		})
 	})
 })
@@ -0,0 +1,310 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	opsutil "github.com/apecloud/kubeblocks/pkg/operations/util"
+)
+
+const (
+	volumeAutoscalingRetryInterval = 30 * time.Second
+)
+
+// componentVolumeAutoscalingTransformer creates the VolumeExpansion OpsRequest automatically when the usage of
+// volumes reported by the kb-agent crosses the threshold of their autoscaling policy.
+type componentVolumeAutoscalingTransformer struct{}
+
+var _ graph.Transformer = &componentVolumeAutoscalingTransformer{}
+
+func (t *componentVolumeAutoscalingTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*componentTransformContext)
+	if model.IsObjectDeleting(transCtx.ComponentOrig) {
+		return nil
+	}
+
+	comp := transCtx.Component
+	synthesizedComp := transCtx.SynthesizeComponent
+	if !component.HasVolumeAutoscaling(synthesizedComp) {
+		if err := component.SetVolumeExpansionTime(comp, nil); err != nil {
+			return err
+		}
+		t.updateComponent(transCtx, dag)
+		return nil
+	}
+
+	watermarks, err := component.GetReplicasVolumeWatermarks(comp)
+	if err != nil {
+		return err
+	}
+	expansions, err := component.GetVolumeExpansionTime(comp)
+	if err != nil {
+		return err
+	}
+
+	var (
+		now          = time.Now()
+		vcts         []opsv1alpha1.OpsRequestVolumeClaimTemplate
+		requeueAfter time.Duration
+	)
+	for _, name := range component.AutoscaledVolumes(synthesizedComp) {
+		policy := synthesizedComp.VolumeAutoscaling[name]
+		replicas := replicasCrossedThreshold(watermarks, name)
+		if len(replicas) == 0 {
+			continue
+		}
+		if last, ok := expansions[name]; ok {
+			if cooldown := last.Add(component.VolumeAutoscalingCooldown(*policy)).Sub(now); cooldown > 0 {
+				if requeueAfter == 0 || cooldown < requeueAfter {
+					requeueAfter = cooldown
+				}
+				continue
+			}
+		}
+		vct, err := t.expandVolume(transCtx, name, *policy, replicas[0])
+		if err != nil {
+			return err
+		}
+		if vct != nil {
+			vcts = append(vcts, *vct)
+		}
+	}
+
+	if len(vcts) > 0 {
+		inProgress, err := t.volumeExpansionInProgress(transCtx)
+		if err != nil {
+			return err
+		}
+		if inProgress {
+			// wait for the running or queued volume expansion to be completed
+			return intctrlutil.NewDelayedRequeueError(volumeAutoscalingRetryInterval, "volume expansion is in progress")
+		}
+		t.createVolumeExpansionOps(transCtx, dag, vcts)
+		for _, vct := range vcts {
+			expansions[vct.Name] = now
+		}
+		if err = component.SetVolumeExpansionTime(comp, expansions); err != nil {
+			return err
+		}
+		t.updateComponent(transCtx, dag)
+	}
+
+	if requeueAfter > 0 {
+		return intctrlutil.NewDelayedRequeueError(requeueAfter, "volume autoscaling is cooling down")
+	}
+	return nil
+}
+
+// updateComponent persists the time of expansions recorded in the annotations of the component.
+func (t *componentVolumeAutoscalingTransformer) updateComponent(transCtx *componentTransformContext, dag *graph.DAG) {
+	if !reflect.DeepEqual(transCtx.ComponentOrig.Annotations, transCtx.Component.Annotations) {
+		graphCli, _ := transCtx.Client.(model.GraphClient)
+		graphCli.Update(dag, transCtx.ComponentOrig, transCtx.Component, &model.ReplaceIfExistingOption{})
+	}
+}
+
+// expandVolume returns the desired size of the volume, it returns nil if the volume can't be expanded.
+func (t *componentVolumeAutoscalingTransformer) expandVolume(transCtx *componentTransformContext,
+	name string, policy appsv1.VolumeAutoscaling, podName string) (*opsv1alpha1.OpsRequestVolumeClaimTemplate, error) {
+	var current *resource.Quantity
+	for _, vct := range transCtx.Component.Spec.VolumeClaimTemplates {
+		if vct.Name == name {
+			current = vct.Spec.Resources.Requests.Storage()
+			break
+		}
+	}
+	if current == nil || current.IsZero() {
+		return nil, nil
+	}
+
+	size, ok, err := component.ExpandedVolumeSize(policy, *current)
+	if err != nil {
+		transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, "VolumeAutoscalingFailed",
+			fmt.Sprintf("failed to expand volume %s: %s", name, err.Error()))
+		return nil, nil
+	}
+	if !ok {
+		transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, "VolumeAutoscalingLimitReached",
+			fmt.Sprintf("volume %s has reached the max size %s", name, current.String()))
+		return nil, nil
+	}
+
+	allowed, err := t.allowVolumeExpansion(transCtx, name, podName)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, "VolumeAutoscalingNotSupported",
+			fmt.Sprintf("the storage class of volume %s does not allow volume expansion", name))
+		return nil, nil
+	}
+	return &opsv1alpha1.OpsRequestVolumeClaimTemplate{
+		Name:    name,
+		Storage: size,
+	}, nil
+}
+
+// allowVolumeExpansion checks whether the storage class of the volume claimed by the pod allows volume expansion.
+func (t *componentVolumeAutoscalingTransformer) allowVolumeExpansion(transCtx *componentTransformContext, name, podName string) (bool, error) {
+	pod := &corev1.Pod{}
+	podKey := types.NamespacedName{Namespace: transCtx.Component.Namespace, Name: podName}
+	if err := transCtx.Client.Get(transCtx.Context, podKey, pod, inDataContext4C()); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	claimName := ""
+	for _, vol := range pod.Spec.Volumes {
+		if vol.Name == name && vol.PersistentVolumeClaim != nil {
+			claimName = vol.PersistentVolumeClaim.ClaimName
+			break
+		}
+	}
+	if len(claimName) == 0 {
+		return false, nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvcKey := types.NamespacedName{Namespace: pod.Namespace, Name: claimName}
+	if err := transCtx.Client.Get(transCtx.Context, pvcKey, pvc, inDataContext4C()); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if pvc.Spec.StorageClassName == nil || len(*pvc.Spec.StorageClassName) == 0 {
+		return false, nil
+	}
+
+	sc := &storagev1.StorageClass{}
+	if err := transCtx.Client.Get(transCtx.Context, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, sc, inDataContext4C()); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion, nil
+}
+
+// volumeExpansionInProgress checks whether there is any volume expansion OpsRequest of the cluster running or queued,
+// or the OpsRequest queue of the cluster is full.
+func (t *componentVolumeAutoscalingTransformer) volumeExpansionInProgress(transCtx *componentTransformContext) (bool, error) {
+	cluster := transCtx.Cluster
+	opsRecorders, err := opsutil.GetOpsRequestSliceFromCluster(cluster)
+	if err != nil {
+		return false, err
+	}
+	if len(opsRecorders) >= opsutil.OpsRequestQueueLimitSize {
+		return true, nil
+	}
+	for _, recorder := range opsRecorders {
+		if recorder.Type == opsv1alpha1.VolumeExpansionType {
+			return true, nil
+		}
+	}
+
+	// the OpsRequests that have not been enqueued yet
+	opsList := &opsv1alpha1.OpsRequestList{}
+	labels := client.MatchingLabels{
+		constant.AppInstanceLabelKey:    cluster.Name,
+		constant.OpsRequestTypeLabelKey: string(opsv1alpha1.VolumeExpansionType),
+	}
+	if err = transCtx.Client.List(transCtx.Context, opsList, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return false, err
+	}
+	for _, ops := range opsList.Items {
+		if !ops.IsComplete() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (t *componentVolumeAutoscalingTransformer) createVolumeExpansionOps(transCtx *componentTransformContext,
+	dag *graph.DAG, vcts []opsv1alpha1.OpsRequestVolumeClaimTemplate) {
+	var (
+		synthesizedComp = transCtx.SynthesizeComponent
+		// the volumes of all shards are expanded together
+		compName = component.GetComponentNameFromObj(transCtx.Component)
+	)
+	if len(compName) == 0 {
+		compName = synthesizedComp.Name
+	}
+	ops := &opsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: synthesizedComp.Namespace,
+			Name:      fmt.Sprintf("%s-volume-autoscaling-%s", synthesizedComp.FullCompName, time.Now().Format("20060102150405")),
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:    synthesizedComp.ClusterName,
+				constant.KBAppComponentLabelKey: synthesizedComp.Name,
+				constant.OpsRequestTypeLabelKey: string(opsv1alpha1.VolumeExpansionType),
+			},
+		},
+		Spec: opsv1alpha1.OpsRequestSpec{
+			ClusterName: synthesizedComp.ClusterName,
+			Type:        opsv1alpha1.VolumeExpansionType,
+			SpecificOpsRequest: opsv1alpha1.SpecificOpsRequest{
+				VolumeExpansionList: []opsv1alpha1.VolumeExpansion{
+					{
+						ComponentOps:         opsv1alpha1.ComponentOps{ComponentName: compName},
+						VolumeClaimTemplates: vcts,
+					},
+				},
+			},
+		},
+	}
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	graphCli.Create(dag, ops)
+
+	msgs := make([]string, 0, len(vcts))
+	for _, vct := range vcts {
+		msgs = append(msgs, fmt.Sprintf("%s to %s", vct.Name, vct.Storage.String()))
+	}
+	transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeNormal, "VolumeAutoscaling",
+		fmt.Sprintf("OpsRequest %s is created to expand volumes: %s", ops.Name, strings.Join(msgs, ", ")))
+}
+
+// replicasCrossedThreshold returns the replicas whose usage of the volume crosses the threshold.
+func replicasCrossedThreshold(watermarks map[string]component.VolumeWatermarks, name string) []string {
+	replicas := make([]string, 0)
+	for replica, crossed := range watermarks {
+		if slices.Contains(crossed.Autoscaling, name) {
+			replicas = append(replicas, replica)
+		}
+	}
+	sort.Strings(replicas)
+	return replicas
+}
@@ -0,0 +1,274 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+var _ = Describe("Component Volume Autoscaling Test", func() {
+	const (
+		clusterName = "test-cluster"
+		compName    = "test-comp"
+		scName      = "test-sc"
+	)
+
+	var (
+		reader   *mockReader
+		transCtx *componentTransformContext
+		dag      *graph.DAG
+		policy   *appsv1.VolumeAutoscaling
+		podName  = constant.GenerateClusterComponentName(clusterName, compName) + "-0"
+	)
+
+	reportCrossed := func(volumes ...string) {
+		Expect(component.SetReplicasVolumeWatermarks(transCtx.Component, map[string]component.VolumeWatermarks{
+			podName: {Autoscaling: volumes},
+		})).Should(Succeed())
+	}
+
+	setVolumeSize := func(size string) {
+		transCtx.Component.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse(size),
+		}
+	}
+
+	setLastExpansion := func(t time.Time) {
+		Expect(component.SetVolumeExpansionTime(transCtx.Component, map[string]time.Time{"data": t})).Should(Succeed())
+	}
+
+	transform := func() ([]*opsv1alpha1.OpsRequest, error) {
+		transformer := &componentVolumeAutoscalingTransformer{}
+		err := transformer.Transform(transCtx, dag)
+		opsList := make([]*opsv1alpha1.OpsRequest, 0)
+		for _, obj := range transCtx.Client.(model.GraphClient).FindAll(dag, &opsv1alpha1.OpsRequest{}) {
+			opsList = append(opsList, obj.(*opsv1alpha1.OpsRequest))
+		}
+		return opsList, err
+	}
+
+	expandedSize := func(ops *opsv1alpha1.OpsRequest) resource.Quantity {
+		Expect(ops.Spec.Type).Should(Equal(opsv1alpha1.VolumeExpansionType))
+		Expect(ops.Spec.VolumeExpansionList).Should(HaveLen(1))
+		Expect(ops.Spec.VolumeExpansionList[0].ComponentName).Should(Equal(compName))
+		Expect(ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates).Should(HaveLen(1))
+		Expect(ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates[0].Name).Should(Equal("data"))
+		return ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates[0].Storage
+	}
+
+	BeforeEach(func() {
+		pod := testapps.NewPodFactory(testCtx.DefaultNamespace, podName).
+			AddContainer(corev1.Container{Name: "test-container", Image: "test-image"}).
+			AddVolume(corev1.Volume{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-" + podName},
+				},
+			}).
+			GetObject()
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testCtx.DefaultNamespace, Name: "data-" + podName},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: func() *string { s := scName; return &s }(),
+			},
+		}
+		sc := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: scName},
+			AllowVolumeExpansion: func() *bool { b := true; return &b }(),
+		}
+		reader = &mockReader{objs: []client.Object{pod, pvc, sc}}
+
+		comp := &appsv1.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      constant.GenerateClusterComponentName(clusterName, compName),
+			},
+			Spec: appsv1.ComponentSpec{
+				Replicas: 1,
+				VolumeClaimTemplates: []appsv1.ClusterComponentVolumeClaimTemplate{
+					{
+						Name: "data",
+						Spec: appsv1.PersistentVolumeClaimSpec{
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+							},
+						},
+					},
+				},
+			},
+		}
+		policy = &appsv1.VolumeAutoscaling{
+			Threshold:       80,
+			Step:            "5Gi",
+			CooldownSeconds: 3600,
+		}
+		transCtx = &componentTransformContext{
+			Context:       ctx,
+			Client:        model.NewGraphClient(reader),
+			EventRecorder: record.NewFakeRecorder(100),
+			Logger:        logger,
+			Cluster:       testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, "").GetObject(),
+			Component:     comp,
+			ComponentOrig: comp.DeepCopy(),
+			SynthesizeComponent: &component.SynthesizedComponent{
+				Namespace:    testCtx.DefaultNamespace,
+				ClusterName:  clusterName,
+				Name:         compName,
+				FullCompName: comp.Name,
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaimTemplate{
+					{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				},
+				VolumeAutoscaling: map[string]*appsv1.VolumeAutoscaling{
+					"data": policy,
+				},
+			},
+		}
+		dag = graph.NewDAG()
+		transCtx.Client.(model.GraphClient).Root(dag, comp, comp, model.ActionStatusPtr())
+	})
+
+	Context("threshold", func() {
+		It("below the threshold", func() {
+			reportCrossed()
+			opsList, err := transform()
+			Expect(err).Should(BeNil())
+			Expect(opsList).Should(BeEmpty())
+		})
+
+		It("cross the threshold", func() {
+			reportCrossed("data")
+			opsList, err := transform()
+			Expect(err).Should(BeNil())
+			Expect(opsList).Should(HaveLen(1))
+			size := expandedSize(opsList[0])
+			Expect(size.Cmp(resource.MustParse("15Gi"))).Should(Equal(0))
+			Expect(opsList[0].Labels).Should(HaveKeyWithValue(constant.AppInstanceLabelKey, clusterName))
+			Expect(opsList[0].Labels).Should(HaveKeyWithValue(constant.OpsRequestTypeLabelKey, string(opsv1alpha1.VolumeExpansionType)))
+
+			expansions, err := component.GetVolumeExpansionTime(transCtx.Component)
+			Expect(err).Should(BeNil())
+			Expect(expansions).Should(HaveKey("data"))
+			Expect(transCtx.Client.(model.GraphClient).IsAction(dag, transCtx.Component, model.ActionUpdatePtr())).Should(BeTrue())
+		})
+
+		It("storage class not allowing volume expansion", func() {
+			reader.objs[2].(*storagev1.StorageClass).AllowVolumeExpansion = nil
+			reportCrossed("data")
+			opsList, err := transform()
+			Expect(err).Should(BeNil())
+			Expect(opsList).Should(BeEmpty())
+		})
+
+		It("volume expansion in progress", func() {
+			reader.objs = append(reader.objs, &opsv1alpha1.OpsRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testCtx.DefaultNamespace,
+					Name:      "test-volume-expansion",
+					Labels: map[string]string{
+						constant.AppInstanceLabelKey:    clusterName,
+						constant.OpsRequestTypeLabelKey: string(opsv1alpha1.VolumeExpansionType),
+					},
+				},
+				Status: opsv1alpha1.OpsRequestStatus{Phase: opsv1alpha1.OpsRunningPhase},
+			})
+			reportCrossed("data")
+			opsList, err := transform()
+			Expect(err).ShouldNot(BeNil())
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(opsList).Should(BeEmpty())
+		})
+	})
+
+	Context("max size", func() {
+		It("cap at the max size", func() {
+			maxSize := resource.MustParse("12Gi")
+			policy.MaxSize = &maxSize
+			reportCrossed("data")
+			opsList, err := transform()
+			Expect(err).Should(BeNil())
+			Expect(opsList).Should(HaveLen(1))
+			size := expandedSize(opsList[0])
+			Expect(size.Cmp(maxSize)).Should(Equal(0))
+		})
+
+		It("reached the max size", func() {
+			maxSize := resource.MustParse("12Gi")
+			policy.MaxSize = &maxSize
+			setVolumeSize("12Gi")
+			reportCrossed("data")
+			opsList, err := transform()
+			Expect(err).Should(BeNil())
+			Expect(opsList).Should(BeEmpty())
+			Expect(transCtx.EventRecorder.(*record.FakeRecorder).Events).Should(Receive(ContainSubstring("VolumeAutoscalingLimitReached")))
+		})
+	})
+
+	Context("cooldown", func() {
+		It("cooling down", func() {
+			setLastExpansion(time.Now().Add(-10 * time.Minute))
+			reportCrossed("data")
+			opsList, err := transform()
+			Expect(err).ShouldNot(BeNil())
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(opsList).Should(BeEmpty())
+		})
+
+		It("cooled down", func() {
+			setLastExpansion(time.Now().Add(-2 * time.Hour))
+			reportCrossed("data")
+			opsList, err := transform()
+			Expect(err).Should(BeNil())
+			Expect(opsList).Should(HaveLen(1))
+
+			expansions, err := component.GetVolumeExpansionTime(transCtx.Component)
+			Expect(err).Should(BeNil())
+			Expect(expansions["data"].After(time.Now().Add(-time.Minute))).Should(BeTrue())
+		})
+
+		It("not less than the usage report period", func() {
+			policy.CooldownSeconds = 1
+			setLastExpansion(time.Now().Add(-10 * time.Second))
+			reportCrossed("data")
+			opsList, err := transform()
+			Expect(err).ShouldNot(BeNil())
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(opsList).Should(BeEmpty())
+		})
+	})
+})
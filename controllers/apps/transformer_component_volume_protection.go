@@ -0,0 +1,180 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	volumeProtectionRetryInterval = 10 * time.Second
+)
+
+// componentVolumeProtectionTransformer switches the replicas to the read-only state when the usage of their volumes
+// crosses the high watermark, and back to the read-write state once the usage drops below it, e.g., the volumes
+// have been expanded by the VolumeExpansion OpsRequest.
+type componentVolumeProtectionTransformer struct{}
+
+var _ graph.Transformer = &componentVolumeProtectionTransformer{}
+
+func (t *componentVolumeProtectionTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*componentTransformContext)
+	if model.IsObjectDeleting(transCtx.ComponentOrig) {
+		return nil
+	}
+
+	comp := transCtx.Component
+	watermarks, err := component.GetReplicasVolumeWatermarks(comp)
+	if err != nil {
+		return err
+	}
+	readonly, err := component.GetReadonlyReplicas(comp)
+	if err != nil {
+		return err
+	}
+	if len(watermarks) == 0 && readonly.Len() == 0 {
+		// the condition is kept until it's switched to false
+		t.setCondition(transCtx, readonly)
+		return nil
+	}
+
+	synthesizedComp := transCtx.SynthesizeComponent
+	if !component.HasVolumeProtection(synthesizedComp) {
+		// the protection is disabled, switch the read-only replicas back
+		if !component.HasVolumeAutoscaling(synthesizedComp) {
+			if err = component.SetReplicasVolumeWatermarks(comp, nil); err != nil {
+				return err
+			}
+		}
+		watermarks = nil
+	}
+
+	pods, err := component.ListOwnedPods(transCtx.Context, transCtx.Client,
+		synthesizedComp.Namespace, synthesizedComp.ClusterName, synthesizedComp.Name)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	existing := sets.New[string]()
+	for _, pod := range pods {
+		existing.Insert(pod.Name)
+		exceeded := watermarks[pod.Name].Exceeded
+		switch {
+		case len(exceeded) > 0 && !readonly.Has(pod.Name):
+			if err = t.readonly(transCtx, pod, exceeded); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			readonly.Insert(pod.Name)
+		case len(exceeded) == 0 && readonly.Has(pod.Name):
+			if err = t.readwrite(transCtx, pod); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			readonly.Delete(pod.Name)
+		}
+	}
+	// the replicas deleted are recreated in the read-write state
+	readonly = readonly.Intersection(existing)
+
+	if err = component.SetReadonlyReplicas(comp, readonly); err != nil {
+		return err
+	}
+	t.setCondition(transCtx, readonly)
+
+	// the state is persisted in the annotations, the status is written along with them by the plan
+	if !reflect.DeepEqual(transCtx.ComponentOrig.Annotations, comp.Annotations) {
+		graphCli, _ := transCtx.Client.(model.GraphClient)
+		graphCli.Update(dag, transCtx.ComponentOrig, comp, &model.ReplaceIfExistingOption{})
+	}
+
+	if len(errs) > 0 {
+		return intctrlutil.NewDelayedRequeueError(volumeProtectionRetryInterval, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (t *componentVolumeProtectionTransformer) readonly(transCtx *componentTransformContext, pod *corev1.Pod, exceeded []string) error {
+	lfa, err := lifecycle.New(transCtx.SynthesizeComponent, pod)
+	if err != nil {
+		return err
+	}
+	if err = lfa.Readonly(transCtx.Context, transCtx.Client, nil); err != nil {
+		transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, "ReadonlyFailed",
+			fmt.Sprintf("failed to switch replica %s to read-only: %s", pod.Name, err.Error()))
+		return err
+	}
+	transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, "VolumeHighWatermarkExceeded",
+		fmt.Sprintf("replica %s is switched to read-only, the usage of volumes exceeds the high watermark: %s",
+			pod.Name, strings.Join(exceeded, ",")))
+	return nil
+}
+
+func (t *componentVolumeProtectionTransformer) readwrite(transCtx *componentTransformContext, pod *corev1.Pod) error {
+	lfa, err := lifecycle.New(transCtx.SynthesizeComponent, pod)
+	if err != nil {
+		return err
+	}
+	// the replica is considered as read-write if the readwrite action is not defined
+	if err = lfa.Readwrite(transCtx.Context, transCtx.Client, nil); err != nil && !errors.Is(err, lifecycle.ErrActionNotDefined) {
+		transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, "ReadwriteFailed",
+			fmt.Sprintf("failed to switch replica %s back to read-write: %s", pod.Name, err.Error()))
+		return err
+	}
+	transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeNormal, "VolumeHighWatermarkRecovered",
+		fmt.Sprintf("replica %s is switched back to read-write", pod.Name))
+	return nil
+}
+
+func (t *componentVolumeProtectionTransformer) setCondition(transCtx *componentTransformContext, readonly sets.Set[string]) {
+	comp := transCtx.Component
+	cond := metav1.Condition{
+		Type:               appsv1.ConditionTypeVolumeProtection,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: comp.Generation,
+		Reason:             "BelowHighWatermark",
+		Message:            "the usage of volumes is below the high watermark",
+	}
+	if readonly.Len() > 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "HighWatermarkExceeded"
+		cond.Message = fmt.Sprintf("replicas are switched to read-only: %s", strings.Join(sets.List(readonly), ","))
+	} else if meta.FindStatusCondition(comp.Status.Conditions, appsv1.ConditionTypeVolumeProtection) == nil {
+		return
+	}
+	meta.SetStatusCondition(&comp.Status.Conditions, cond)
+}
@@ -0,0 +1,313 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	kbagentproto "github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+var _ = Describe("Component Volume Protection Test", func() {
+	const (
+		clusterName = "test-cluster"
+		compName    = "test-comp"
+	)
+
+	var (
+		reader   *mockReader
+		transCtx *componentTransformContext
+		dag      *graph.DAG
+		mutex    sync.Mutex
+		// the actions called, in the form of <action>/<pod>
+		actions []string
+		// the pods fail to call the actions
+		failedPods sets.Set[string]
+	)
+
+	podName := func(ordinal int) string {
+		return fmt.Sprintf("%s-%d", constant.GenerateClusterComponentName(clusterName, compName), ordinal)
+	}
+
+	newPod := func(ordinal int) *corev1.Pod {
+		return testapps.NewPodFactory(testCtx.DefaultNamespace, podName(ordinal)).
+			AddContainer(corev1.Container{Name: "test-container", Image: "test-image"}).
+			AddLabelsInMap(constant.GetCompLabels(clusterName, compName)).
+			GetObject()
+	}
+
+	// the replicas whose usage of the data volume exceeds the high watermark
+	reportExceeded := func(replicas ...string) {
+		watermarks := make(map[string]component.VolumeWatermarks)
+		for _, replica := range replicas {
+			watermarks[replica] = component.VolumeWatermarks{Exceeded: []string{"data"}}
+		}
+		Expect(component.SetReplicasVolumeWatermarks(transCtx.Component, watermarks)).Should(Succeed())
+	}
+
+	readonlyReplicas := func() []string {
+		replicas, err := component.GetReadonlyReplicas(transCtx.Component)
+		Expect(err).Should(BeNil())
+		return sets.List(replicas)
+	}
+
+	calledActions := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		called := actions
+		actions = nil
+		return called
+	}
+
+	transform := func() error {
+		dag = graph.NewDAG()
+		transCtx.Client.(model.GraphClient).Root(dag, transCtx.ComponentOrig, transCtx.Component, model.ActionStatusPtr())
+		transformer := &componentVolumeProtectionTransformer{}
+		return transformer.Transform(transCtx, dag)
+	}
+
+	// componentUpdated checks whether the component is updated, and takes the updated one as the original
+	componentUpdated := func() bool {
+		graphCli, _ := transCtx.Client.(model.GraphClient)
+		updated := graphCli.IsAction(dag, transCtx.Component, model.ActionUpdatePtr())
+		transCtx.ComponentOrig = transCtx.Component.DeepCopy()
+		return updated
+	}
+
+	BeforeEach(func() {
+		actions = nil
+		failedPods = sets.New[string]()
+		testapps.MockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+			recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req kbagentproto.ActionRequest) (kbagentproto.ActionResponse, error) {
+				pod := ""
+				for i := 0; i < 3; i++ {
+					if req.Parameters["KB_POD_FQDN"] == component.PodFQDN(testCtx.DefaultNamespace,
+						constant.GenerateClusterComponentName(clusterName, compName), podName(i)) {
+						pod = podName(i)
+					}
+				}
+				mutex.Lock()
+				defer mutex.Unlock()
+				actions = append(actions, fmt.Sprintf("%s/%s", req.Action, pod))
+				if failedPods.Has(pod) {
+					return kbagentproto.ActionResponse{}, fmt.Errorf("mock error")
+				}
+				return kbagentproto.ActionResponse{}, nil
+			}).AnyTimes()
+		})
+
+		reader = &mockReader{
+			objs: []client.Object{newPod(0), newPod(1), newPod(2)},
+		}
+		comp := &appsv1.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      constant.GenerateClusterComponentName(clusterName, compName),
+			},
+			Spec: appsv1.ComponentSpec{
+				Replicas: 3,
+			},
+		}
+		transCtx = &componentTransformContext{
+			Context:       ctx,
+			Client:        model.NewGraphClient(reader),
+			EventRecorder: record.NewFakeRecorder(100),
+			Logger:        logger,
+			Component:     comp,
+			ComponentOrig: comp.DeepCopy(),
+			SynthesizeComponent: &component.SynthesizedComponent{
+				Namespace:   testCtx.DefaultNamespace,
+				ClusterName: clusterName,
+				Name:        compName,
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaimTemplate{
+					{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				},
+				Volumes: []appsv1.ComponentVolume{
+					{Name: "data", HighWatermark: 90},
+				},
+				LifecycleActions: &appsv1.ComponentLifecycleActions{
+					Readonly: &appsv1.Action{
+						Exec: &appsv1.ExecAction{Command: []string{"readonly"}},
+					},
+					Readwrite: &appsv1.Action{
+						Exec: &appsv1.ExecAction{Command: []string{"readwrite"}},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		kbacli.UnsetMockClient()
+	})
+
+	It("nothing reported", func() {
+		Expect(transform()).Should(Succeed())
+		Expect(calledActions()).Should(BeEmpty())
+		Expect(readonlyReplicas()).Should(BeEmpty())
+		Expect(meta.FindStatusCondition(transCtx.Component.Status.Conditions, appsv1.ConditionTypeVolumeProtection)).Should(BeNil())
+	})
+
+	It("switch to read-only and back to read-write", func() {
+		By("the usage of a replica exceeds the high watermark")
+		reportExceeded(podName(0))
+		Expect(transform()).Should(Succeed())
+		Expect(calledActions()).Should(Equal([]string{"readonly/" + podName(0)}))
+		Expect(readonlyReplicas()).Should(Equal([]string{podName(0)}))
+		cond := meta.FindStatusCondition(transCtx.Component.Status.Conditions, appsv1.ConditionTypeVolumeProtection)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(cond.Message).Should(ContainSubstring(podName(0)))
+		Expect(componentUpdated()).Should(BeTrue())
+
+		By("the replica is kept read-only without calling the action again")
+		Expect(transform()).Should(Succeed())
+		Expect(calledActions()).Should(BeEmpty())
+		Expect(readonlyReplicas()).Should(Equal([]string{podName(0)}))
+		Expect(componentUpdated()).Should(BeFalse())
+
+		By("the usage drops below the high watermark")
+		reportExceeded()
+		Expect(transform()).Should(Succeed())
+		Expect(calledActions()).Should(Equal([]string{"readwrite/" + podName(0)}))
+		Expect(readonlyReplicas()).Should(BeEmpty())
+		cond = meta.FindStatusCondition(transCtx.Component.Status.Conditions, appsv1.ConditionTypeVolumeProtection)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+	})
+
+	It("retry if failed to switch to read-only", func() {
+		failedPods.Insert(podName(1))
+		reportExceeded(podName(0), podName(1))
+		err := transform()
+		Expect(err).ShouldNot(BeNil())
+		Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+		Expect(calledActions()).Should(ConsistOf("readonly/"+podName(0), "readonly/"+podName(1)))
+		Expect(readonlyReplicas()).Should(Equal([]string{podName(0)}))
+
+		By("retry the failed replica only")
+		failedPods.Delete(podName(1))
+		Expect(transform()).Should(Succeed())
+		Expect(calledActions()).Should(Equal([]string{"readonly/" + podName(1)}))
+		Expect(readonlyReplicas()).Should(Equal([]string{podName(0), podName(1)}))
+	})
+
+	It("switch back to read-write if the protection is disabled", func() {
+		reportExceeded(podName(0))
+		Expect(transform()).Should(Succeed())
+		Expect(readonlyReplicas()).Should(Equal([]string{podName(0)}))
+		calledActions()
+
+		transCtx.SynthesizeComponent.Volumes = nil
+		Expect(transform()).Should(Succeed())
+		Expect(calledActions()).Should(Equal([]string{"readwrite/" + podName(0)}))
+		Expect(readonlyReplicas()).Should(BeEmpty())
+		watermarks, err := component.GetReplicasVolumeWatermarks(transCtx.Component)
+		Expect(err).Should(BeNil())
+		Expect(watermarks).Should(BeEmpty())
+	})
+
+	It("persist the condition once the replicas are switched back to read-write", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).Should(Succeed())
+		cli := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(transCtx.Component, newPod(0), newPod(1), newPod(2)).
+			WithStatusSubresource(&appsv1.Component{}).
+			Build()
+		compKey := client.ObjectKeyFromObject(transCtx.Component)
+
+		// reconcile runs the transformer on the stored component and executes the plan
+		reconcile := func(report bool, exceeded ...string) *appsv1.Component {
+			comp := &appsv1.Component{}
+			Expect(cli.Get(ctx, compKey, comp)).Should(Succeed())
+			transCtx.Component, transCtx.ComponentOrig = comp, comp.DeepCopy()
+			transCtx.Client = model.NewGraphClient(cli)
+			if report {
+				reportExceeded(exceeded...)
+			}
+			dag = graph.NewDAG()
+			Expect((&componentInitTransformer{}).Transform(transCtx, dag)).Should(Succeed())
+			Expect((&componentVolumeProtectionTransformer{}).Transform(transCtx, dag)).Should(Succeed())
+			builder := &componentPlanBuilder{cli: cli, transCtx: transCtx}
+			plan := &componentPlan{dag: dag, walkFunc: builder.defaultWalkFunc, transCtx: transCtx}
+			Expect(plan.Execute()).Should(Succeed())
+			Expect(cli.Get(ctx, compKey, comp)).Should(Succeed())
+			return comp
+		}
+
+		By("the usage of a replica exceeds the high watermark")
+		comp := reconcile(true, podName(0))
+		Expect(comp.Annotations).ShouldNot(BeEmpty())
+		cond := meta.FindStatusCondition(comp.Status.Conditions, appsv1.ConditionTypeVolumeProtection)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+
+		By("the usage drops below the high watermark")
+		comp = reconcile(true)
+		Expect(calledActions()).Should(ContainElement("readwrite/" + podName(0)))
+		cond = meta.FindStatusCondition(comp.Status.Conditions, appsv1.ConditionTypeVolumeProtection)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+
+		By("the condition is kept as false by the next reconciliation")
+		comp = reconcile(false)
+		cond = meta.FindStatusCondition(comp.Status.Conditions, appsv1.ConditionTypeVolumeProtection)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+	})
+
+	It("forget the replicas deleted", func() {
+		reportExceeded(podName(2))
+		Expect(transform()).Should(Succeed())
+		Expect(readonlyReplicas()).Should(Equal([]string{podName(2)}))
+		calledActions()
+
+		By("the replica is deleted")
+		reader.objs = reader.objs[:2]
+		Expect(transform()).Should(Succeed())
+		Expect(calledActions()).Should(BeEmpty())
+		Expect(readonlyReplicas()).Should(BeEmpty())
+	})
+})
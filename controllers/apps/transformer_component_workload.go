@@ -31,6 +31,7 @@ import (
 	"github.com/spf13/viper"
 	"golang.org/x/exp/maps"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -107,11 +108,17 @@ func (t *componentWorkloadTransformer) Transform(ctx graph.TransformContext, dag
 	}
 	transCtx.ProtoWorkload = protoITS
 
+	buildTLSCertRotationRestartAnnotation(transCtx, runningITS, protoITS)
+
 	if err = t.reconcileWorkload(transCtx.Context, t.Client, synthesizeComp, comp, runningITS, protoITS); err != nil {
 		return err
 	}
 
 	graphCli, _ := transCtx.Client.(model.GraphClient)
+	if err = t.reconcilePodDisruptionBudget(transCtx, graphCli, dag, synthesizeComp, comp, protoITS); err != nil {
+		return err
+	}
+
 	if runningITS == nil {
 		if protoITS != nil {
 			if err := setCompOwnershipNFinalizer(comp, protoITS); err != nil {
@@ -130,6 +137,69 @@ func (t *componentWorkloadTransformer) Transform(ctx graph.TransformContext, dag
 	return err
 }
 
+// reconcilePodDisruptionBudget keeps the PodDisruptionBudget of the component aligned with the replicas and roles of the workload.
+// The budget is relaxed when the component is stopped, since its instances are deleted by the controller rather than evicted.
+// A warning event is recorded when the budget starts to block any disruption, e.g. with two voting members.
+func (t *componentWorkloadTransformer) reconcilePodDisruptionBudget(transCtx *componentTransformContext, graphCli model.GraphClient,
+	dag *graph.DAG, synthesizeComp *component.SynthesizedComponent, comp *appsv1.Component, protoITS *workloads.InstanceSet) error {
+	var pdb *policyv1.PodDisruptionBudget
+	if protoITS != nil && !isCompStopped(synthesizeComp) {
+		var members []workloads.MemberStatus
+		if runningITS, ok := transCtx.RunningWorkload.(*workloads.InstanceSet); ok && runningITS != nil {
+			members = runningITS.Status.MembersStatus
+		}
+		pdb = factory.BuildPodDisruptionBudget(synthesizeComp, protoITS, members)
+	}
+
+	key := types.NamespacedName{
+		Namespace: synthesizeComp.Namespace,
+		Name:      constant.GenerateWorkloadNamePattern(synthesizeComp.ClusterName, synthesizeComp.Name),
+	}
+	runningPDB := &policyv1.PodDisruptionBudget{}
+	if err := transCtx.Client.Get(transCtx.Context, key, runningPDB, inDataContext4C()); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		runningPDB = nil
+	}
+
+	switch {
+	case runningPDB == nil && pdb == nil:
+	case runningPDB == nil:
+		if err := setCompOwnershipNFinalizer(comp, pdb); err != nil {
+			return err
+		}
+		graphCli.Create(dag, pdb, inDataContext4G())
+		recordPodDisruptionBlocked(transCtx, comp, nil, pdb)
+	case !model.IsOwnerOf(comp, runningPDB):
+		// don't touch the PDB not owned by the component
+	case pdb == nil:
+		graphCli.Delete(dag, runningPDB, inDataContext4G())
+	default:
+		pdbCopy := runningPDB.DeepCopy()
+		pdbCopy.Labels = intctrlutil.MergeMetadataMaps(pdbCopy.Labels, pdb.Labels)
+		pdbCopy.Annotations = intctrlutil.MergeMetadataMaps(pdbCopy.Annotations, pdb.Annotations)
+		pdbCopy.Spec = pdb.Spec
+		if !reflect.DeepEqual(runningPDB, pdbCopy) {
+			graphCli.Update(dag, runningPDB, pdbCopy, inDataContext4G())
+			recordPodDisruptionBlocked(transCtx, comp, runningPDB, pdbCopy)
+		}
+	}
+	return nil
+}
+
+func recordPodDisruptionBlocked(transCtx *componentTransformContext, comp *appsv1.Component, oldPDB, newPDB *policyv1.PodDisruptionBudget) {
+	blocked := func(pdb *policyv1.PodDisruptionBudget) bool {
+		return pdb != nil && pdb.Spec.MaxUnavailable != nil && pdb.Spec.MaxUnavailable.IntValue() == 0
+	}
+	if transCtx.EventRecorder == nil || !blocked(newPDB) || blocked(oldPDB) {
+		return
+	}
+	transCtx.EventRecorder.Eventf(comp, corev1.EventTypeWarning, "PodDisruptionBlocked",
+		"the PodDisruptionBudget %s allows no disruption to keep the quorum of the voting members, "+
+			"the nodes of the instances can't be drained until the component is scaled out", newPDB.Name)
+}
+
 func (t *componentWorkloadTransformer) runningInstanceSetObject(ctx graph.TransformContext,
 	synthesizeComp *component.SynthesizedComponent) (*workloads.InstanceSet, error) {
 	objs, err := component.ListOwnedWorkloads(ctx.GetContext(), ctx.GetClient(),
@@ -722,7 +792,8 @@ func (r *componentWorkloadOps) scaleOut() error {
 		}
 
 		replicas := append(slices.Clone(newReplicas), provisioningReplicas...)
-		parameters, err := component.NewReplicaTask(r.synthesizeComp.FullCompName, r.synthesizeComp.Generation, source, replicas)
+		parameters, err := component.NewReplicaTask(r.synthesizeComp.FullCompName, r.synthesizeComp.Generation, source, replicas,
+			r.synthesizeComp.Annotations[constant.DataTransferCompressionAnnotationKey])
 		if err != nil {
 			return err
 		}
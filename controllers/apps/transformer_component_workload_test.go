@@ -20,8 +20,10 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
@@ -99,6 +101,129 @@ var _ = Describe("Component Workload Operations Test", func() {
 		dag = newDAG(graphCli, comp)
 	})
 
+	Context("PodDisruptionBudget", func() {
+		var (
+			transCtx *componentTransformContext
+			protoITS *workloads.InstanceSet
+		)
+
+		BeforeEach(func() {
+			transCtx = &componentTransformContext{
+				Context:             ctx,
+				Client:              model.NewGraphClient(reader),
+				Logger:              logger,
+				CompDef:             &appsv1.ComponentDefinition{},
+				Component:           comp,
+				ComponentOrig:       comp.DeepCopy(),
+				SynthesizeComponent: synthesizeComp,
+			}
+			protoITS = testapps.NewInstanceSetFactory(testCtx.DefaultNamespace,
+				constant.GenerateWorkloadNamePattern(clusterName, compName), clusterName, compName).
+				SetReplicas(3).
+				SetRoles([]workloads.ReplicaRole{
+					{Name: "leader", AccessMode: workloads.ReadWriteMode, CanVote: true, IsLeader: true},
+					{Name: "follower", AccessMode: workloads.ReadonlyMode, CanVote: true, IsLeader: false},
+				}).
+				GetObject()
+		})
+
+		reconcilePDB := func() *policyv1.PodDisruptionBudget {
+			graphCli := transCtx.Client.(model.GraphClient)
+			transformer := &componentWorkloadTransformer{}
+			Expect(transformer.reconcilePodDisruptionBudget(transCtx, graphCli, dag, synthesizeComp, comp, protoITS)).Should(Succeed())
+			objs := graphCli.FindAll(dag, &policyv1.PodDisruptionBudget{})
+			if len(objs) == 0 {
+				return nil
+			}
+			Expect(objs).Should(HaveLen(1))
+			return objs[0].(*policyv1.PodDisruptionBudget)
+		}
+
+		It("should create the PDB to keep the quorum", func() {
+			pdb := reconcilePDB()
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(transCtx.Client.(model.GraphClient).IsAction(dag, pdb, model.ActionCreatePtr())).Should(BeTrue())
+			Expect(pdb.Name).Should(Equal(protoITS.Name))
+			Expect(pdb.Spec.Selector.MatchLabels).Should(Equal(protoITS.Spec.Selector.MatchLabels))
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(1))
+			Expect(model.IsOwnerOf(comp, pdb)).Should(BeTrue())
+		})
+
+		It("should update the PDB when scaling", func() {
+			pdb := reconcilePDB()
+			Expect(pdb).ShouldNot(BeNil())
+			reader.objs = append(reader.objs, pdb.DeepCopy())
+			dag = newDAG(transCtx.Client.(model.GraphClient), comp)
+
+			replicas := int32(5)
+			protoITS.Spec.Replicas = &replicas
+			runningITS := protoITS.DeepCopy()
+			for _, role := range []string{"leader", "follower", "follower", "follower", "follower"} {
+				runningITS.Status.MembersStatus = append(runningITS.Status.MembersStatus, workloads.MemberStatus{
+					ReplicaRole: &workloads.ReplicaRole{Name: role},
+				})
+			}
+			transCtx.RunningWorkload = runningITS
+			pdb = reconcilePDB()
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(transCtx.Client.(model.GraphClient).IsAction(dag, pdb, model.ActionUpdatePtr())).Should(BeTrue())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(2))
+		})
+
+		It("should keep the quorum of the voting members only", func() {
+			replicas := int32(5)
+			protoITS.Spec.Replicas = &replicas
+			protoITS.Spec.Roles = append(protoITS.Spec.Roles, workloads.ReplicaRole{Name: "learner", AccessMode: workloads.NoneMode})
+			runningITS := protoITS.DeepCopy()
+			for _, role := range []string{"leader", "follower", "follower", "learner", "learner"} {
+				runningITS.Status.MembersStatus = append(runningITS.Status.MembersStatus, workloads.MemberStatus{
+					ReplicaRole: &workloads.ReplicaRole{Name: role},
+				})
+			}
+			transCtx.RunningWorkload = runningITS
+			pdb := reconcilePDB()
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(1))
+		})
+
+		It("should record the event when no disruption is allowed", func() {
+			recorder := record.NewFakeRecorder(16)
+			transCtx.EventRecorder = recorder
+			replicas := int32(2)
+			protoITS.Spec.Replicas = &replicas
+			runningITS := protoITS.DeepCopy()
+			for _, role := range []string{"leader", "follower"} {
+				runningITS.Status.MembersStatus = append(runningITS.Status.MembersStatus, workloads.MemberStatus{
+					ReplicaRole: &workloads.ReplicaRole{Name: role},
+				})
+			}
+			transCtx.RunningWorkload = runningITS
+			pdb := reconcilePDB()
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(0))
+			Expect(recorder.Events).Should(HaveLen(1))
+			Expect(<-recorder.Events).Should(ContainSubstring("PodDisruptionBlocked"))
+
+			By("not record the event again if the PDB is not changed")
+			reader.objs = append(reader.objs, pdb.DeepCopy())
+			dag = newDAG(transCtx.Client.(model.GraphClient), comp)
+			Expect(reconcilePDB()).Should(BeNil())
+			Expect(recorder.Events).Should(BeEmpty())
+		})
+
+		It("should delete the PDB when the component is stopped", func() {
+			pdb := reconcilePDB()
+			Expect(pdb).ShouldNot(BeNil())
+			reader.objs = append(reader.objs, pdb.DeepCopy())
+			dag = newDAG(transCtx.Client.(model.GraphClient), comp)
+
+			synthesizeComp.Stop = func() *bool { b := true; return &b }()
+			pdb = reconcilePDB()
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(transCtx.Client.(model.GraphClient).IsAction(dag, pdb, model.ActionDeletePtr())).Should(BeTrue())
+		})
+	})
+
 	Context("Member Leave Operations", func() {
 		var (
 			ops  *componentWorkloadOps
@@ -0,0 +1,520 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dataprotection
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+)
+
+const (
+	// dataProtectionBackupVerificationKey labels the scratch resources of a verification run with the run name.
+	dataProtectionBackupVerificationKey = "dataprotection.kubeblocks.io/backup-verification"
+
+	verificationCheckContainerName = "check"
+	verificationDefaultMountPath   = "/verification"
+	verificationDefaultTimeout     = 60 * time.Minute
+
+	reasonInvalidVerificationSchedule = "InvalidVerificationSchedule"
+	reasonNoBackupToVerify            = "NoBackupToVerify"
+	reasonVerificationStarted         = "VerificationStarted"
+	reasonVerificationSucceeded       = "VerificationSucceeded"
+	reasonVerificationFailed          = "VerificationFailed"
+)
+
+// BackupVerificationReconciler verifies the backups of a BackupSchedule by restoring them
+// into a scratch environment and running a check against the restored data.
+type BackupVerificationReconciler struct {
+	client.Client
+	Scheme   *k8sruntime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=restores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile starts a verification run when it is due, and drives the running one to the end.
+func (r *BackupVerificationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      ctx,
+		Req:      req,
+		Log:      log.FromContext(ctx).WithValues("backupSchedule", req.NamespacedName),
+		Recorder: r.Recorder,
+	}
+
+	backupSchedule := &dpv1alpha1.BackupSchedule{}
+	if err := r.Client.Get(reqCtx.Ctx, reqCtx.Req.NamespacedName, backupSchedule); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	// the scratch resources are owned by the backup schedule and will be garbage collected.
+	if !backupSchedule.DeletionTimestamp.IsZero() {
+		return intctrlutil.Reconciled()
+	}
+
+	original := backupSchedule.DeepCopy()
+	requeueAfter, err := r.reconcile(reqCtx, backupSchedule, time.Now())
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if !reflect.DeepEqual(original.Status.Verification, backupSchedule.Status.Verification) {
+		if err = r.Client.Status().Patch(reqCtx.Ctx, backupSchedule, client.MergeFrom(original)); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+	if requeueAfter > 0 {
+		return intctrlutil.RequeueAfter(requeueAfter, reqCtx.Log, "wait for the verification")
+	}
+	return intctrlutil.Reconciled()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupVerificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
+		Named("backupverification").
+		For(&dpv1alpha1.BackupSchedule{}).
+		Owns(&dpv1alpha1.Restore{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
+
+// reconcile syncs the running verification and starts a new one if it is due.
+// It returns the duration to wait for the next check.
+func (r *BackupVerificationReconciler) reconcile(reqCtx intctrlutil.RequestCtx,
+	backupSchedule *dpv1alpha1.BackupSchedule,
+	now time.Time) (time.Duration, error) {
+	status := backupSchedule.Status.Verification
+	if status != nil && status.Phase == dpv1alpha1.BackupVerificationRunning {
+		requeueAfter, err := r.syncRun(reqCtx, backupSchedule, now)
+		if err != nil || status.Phase == dpv1alpha1.BackupVerificationRunning {
+			return requeueAfter, err
+		}
+	}
+
+	policy := backupSchedule.Spec.Verification
+	if policy == nil || !policy.Enabled {
+		return 0, nil
+	}
+	schedule, err := common.ParseCronExpression(policy.CronExpression, "")
+	if err != nil {
+		r.Recorder.Event(backupSchedule, corev1.EventTypeWarning, reasonInvalidVerificationSchedule, err.Error())
+		return 0, nil
+	}
+	var requeueAfter time.Duration
+	if nextTime := schedule.Next(now.UTC()); !nextTime.IsZero() {
+		requeueAfter = nextTime.Sub(now)
+	}
+	scheduledTime := mostRecentVerificationTime(backupSchedule, schedule, now)
+	if scheduledTime.IsZero() {
+		return requeueAfter, nil
+	}
+	if err = r.startRun(reqCtx, backupSchedule, scheduledTime, now); err != nil {
+		return 0, err
+	}
+	return requeueAfter, nil
+}
+
+// startRun restores the latest completed backup into the scratch volume claims.
+func (r *BackupVerificationReconciler) startRun(reqCtx intctrlutil.RequestCtx,
+	backupSchedule *dpv1alpha1.BackupSchedule,
+	scheduledTime, now time.Time) error {
+	policy := backupSchedule.Spec.Verification
+	backup, err := r.getLatestCompletedBackup(reqCtx, backupSchedule)
+	if err != nil {
+		return err
+	}
+	if backupSchedule.Status.Verification == nil {
+		backupSchedule.Status.Verification = &dpv1alpha1.BackupVerificationStatus{}
+	}
+	status := backupSchedule.Status.Verification
+	status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	if backup == nil {
+		r.Recorder.Eventf(backupSchedule, corev1.EventTypeWarning, reasonNoBackupToVerify,
+			"the verification at %s is skipped as there is no completed backup of method %s",
+			scheduledTime.Format(time.RFC3339), policy.BackupMethod)
+		return nil
+	}
+
+	restore := buildVerificationRestore(backupSchedule, backup, generateVerificationName(backupSchedule, scheduledTime))
+	if err = controllerutil.SetControllerReference(backupSchedule, restore, r.Scheme); err != nil {
+		return err
+	}
+	if err = r.Client.Create(reqCtx.Ctx, restore); client.IgnoreAlreadyExists(err) != nil {
+		return err
+	}
+
+	status.Phase = dpv1alpha1.BackupVerificationRunning
+	status.BackupName = backup.Name
+	status.RestoreName = restore.Name
+	status.StartTime = &metav1.Time{Time: now}
+	status.CompletionTime = nil
+	status.Message = ""
+	if err = r.patchBackupVerification(reqCtx, backupSchedule); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(backupSchedule, corev1.EventTypeNormal, reasonVerificationStarted,
+		"start to verify backup %s by restore %s", backup.Name, restore.Name)
+	return nil
+}
+
+// syncRun checks the restore and the check job of the running verification,
+// and finishes the run if either of them ends or the run times out.
+func (r *BackupVerificationReconciler) syncRun(reqCtx intctrlutil.RequestCtx,
+	backupSchedule *dpv1alpha1.BackupSchedule,
+	now time.Time) (time.Duration, error) {
+	policy := backupSchedule.Spec.Verification
+	status := backupSchedule.Status.Verification
+	if policy == nil || !policy.Enabled {
+		return 0, r.finishRun(reqCtx, backupSchedule, dpv1alpha1.BackupVerificationFailed,
+			"the verification is disabled", now)
+	}
+	timeout := verificationTimeout(policy)
+	deadline := status.StartTime.Add(timeout)
+	if !now.Before(deadline) {
+		return 0, r.finishRun(reqCtx, backupSchedule, dpv1alpha1.BackupVerificationFailed,
+			fmt.Sprintf("the verification does not finish in %s", timeout), now)
+	}
+
+	restore := &dpv1alpha1.Restore{}
+	restoreKey := client.ObjectKey{Namespace: backupSchedule.Namespace, Name: status.RestoreName}
+	if err := r.Client.Get(reqCtx.Ctx, restoreKey, restore); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		return 0, r.finishRun(reqCtx, backupSchedule, dpv1alpha1.BackupVerificationFailed,
+			fmt.Sprintf("restore %s is not found", status.RestoreName), now)
+	}
+	switch restore.Status.Phase {
+	case dpv1alpha1.RestorePhaseFailed:
+		return 0, r.finishRun(reqCtx, backupSchedule, dpv1alpha1.BackupVerificationFailed,
+			fmt.Sprintf("failed to restore backup %s: %s", status.BackupName, restoreFailureMessage(restore)), now)
+	case dpv1alpha1.RestorePhaseCompleted:
+	default:
+		return deadline.Sub(now), nil
+	}
+
+	job := &batchv1.Job{}
+	jobKey := client.ObjectKey{Namespace: backupSchedule.Namespace, Name: status.RestoreName}
+	if err := r.Client.Get(reqCtx.Ctx, jobKey, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		job = buildVerificationCheckJob(backupSchedule, restore, deadline.Sub(now))
+		if err = controllerutil.SetControllerReference(backupSchedule, job, r.Scheme); err != nil {
+			return 0, err
+		}
+		return deadline.Sub(now), client.IgnoreAlreadyExists(r.Client.Create(reqCtx.Ctx, job))
+	}
+	finished, conditionType, message := dputils.IsJobFinished(job)
+	switch {
+	case !finished:
+		return deadline.Sub(now), nil
+	case conditionType == batchv1.JobComplete:
+		return 0, r.finishRun(reqCtx, backupSchedule, dpv1alpha1.BackupVerificationSucceeded,
+			fmt.Sprintf("backup %s is verified", status.BackupName), now)
+	default:
+		return 0, r.finishRun(reqCtx, backupSchedule, dpv1alpha1.BackupVerificationFailed,
+			fmt.Sprintf("the check of backup %s failed: %s", status.BackupName, message), now)
+	}
+}
+
+// finishRun deletes the scratch resources and records the result of the run.
+func (r *BackupVerificationReconciler) finishRun(reqCtx intctrlutil.RequestCtx,
+	backupSchedule *dpv1alpha1.BackupSchedule,
+	phase dpv1alpha1.BackupVerificationPhase,
+	message string,
+	now time.Time) error {
+	status := backupSchedule.Status.Verification
+	labels := map[string]string{dataProtectionBackupVerificationKey: status.RestoreName}
+	namespaces := map[string]sets.Empty{backupSchedule.Namespace: {}}
+	if err := deleteRelatedObjectList(reqCtx, r.Client, &batchv1.JobList{}, namespaces, labels); err != nil {
+		return err
+	}
+	// keep the finalizer of the restore to let the restore controller clean up its own jobs.
+	restore := &dpv1alpha1.Restore{ObjectMeta: metav1.ObjectMeta{Namespace: backupSchedule.Namespace, Name: status.RestoreName}}
+	if err := intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, restore); err != nil {
+		return err
+	}
+	if err := deleteRelatedObjectList(reqCtx, r.Client, &corev1.PersistentVolumeClaimList{}, namespaces, labels); err != nil {
+		return err
+	}
+
+	status.Phase = phase
+	status.Message = message
+	status.CompletionTime = &metav1.Time{Time: now}
+	if err := r.patchBackupVerification(reqCtx, backupSchedule); err != nil {
+		return err
+	}
+	if phase == dpv1alpha1.BackupVerificationSucceeded {
+		r.Recorder.Event(backupSchedule, corev1.EventTypeNormal, reasonVerificationSucceeded, message)
+	} else {
+		r.Recorder.Event(backupSchedule, corev1.EventTypeWarning, reasonVerificationFailed, message)
+	}
+	return nil
+}
+
+// patchBackupVerification records the verification of the running or finished run in the backup status.
+func (r *BackupVerificationReconciler) patchBackupVerification(reqCtx intctrlutil.RequestCtx,
+	backupSchedule *dpv1alpha1.BackupSchedule) error {
+	status := backupSchedule.Status.Verification
+	backup := &dpv1alpha1.Backup{}
+	backupKey := client.ObjectKey{Namespace: backupSchedule.Namespace, Name: status.BackupName}
+	if err := r.Client.Get(reqCtx.Ctx, backupKey, backup); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	patch := client.MergeFrom(backup.DeepCopy())
+	backup.Status.Verification = &dpv1alpha1.BackupVerificationResult{
+		Phase:              status.Phase,
+		BackupScheduleName: backupSchedule.Name,
+		StartTime:          status.StartTime,
+		CompletionTime:     status.CompletionTime,
+		Message:            status.Message,
+	}
+	return client.IgnoreNotFound(r.Client.Status().Patch(reqCtx.Ctx, backup, patch))
+}
+
+// getLatestCompletedBackup returns the latest completed backup of the verification method.
+func (r *BackupVerificationReconciler) getLatestCompletedBackup(reqCtx intctrlutil.RequestCtx,
+	backupSchedule *dpv1alpha1.BackupSchedule) (*dpv1alpha1.Backup, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.Client.List(reqCtx.Ctx, backupList, client.InNamespace(backupSchedule.Namespace),
+		client.MatchingLabels{dptypes.BackupPolicyLabelKey: backupSchedule.Spec.BackupPolicyName}); err != nil {
+		return nil, err
+	}
+	var latest *dpv1alpha1.Backup
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+		if backup.Spec.BackupMethod != backupSchedule.Spec.Verification.BackupMethod ||
+			backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted ||
+			!backup.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if latest == nil || backupCompletionTime(latest).Before(backupCompletionTime(backup)) {
+			latest = backup
+		}
+	}
+	return latest, nil
+}
+
+// mostRecentVerificationTime returns the most recent scheduled time that has not been run yet and is not later than now.
+// The scheduled times during the last run or earlier than the starting deadline are ignored.
+func mostRecentVerificationTime(backupSchedule *dpv1alpha1.BackupSchedule,
+	schedule cron.Schedule,
+	now time.Time) time.Time {
+	earliestTime := backupSchedule.CreationTimestamp.Time
+	if status := backupSchedule.Status.Verification; status != nil {
+		for _, t := range []*metav1.Time{status.LastScheduleTime, status.CompletionTime} {
+			if t != nil && t.After(earliestTime) {
+				earliestTime = t.Time
+			}
+		}
+	}
+	if backupSchedule.Spec.StartingDeadlineMinutes != nil {
+		deadline := now.Add(-time.Duration(*backupSchedule.Spec.StartingDeadlineMinutes) * time.Minute)
+		if deadline.After(earliestTime) {
+			earliestTime = deadline
+		}
+	}
+
+	var mostRecentTime time.Time
+	for t := schedule.Next(earliestTime.UTC()); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		mostRecentTime = t
+	}
+	return mostRecentTime
+}
+
+// generateVerificationName generates the name of the scratch resources of the run scheduled at the specified time.
+func generateVerificationName(backupSchedule *dpv1alpha1.BackupSchedule, scheduledTime time.Time) string {
+	name := fmt.Sprintf("%s-%s", backupSchedule.UID[:8], backupSchedule.Name)
+	if len(name) > 30 {
+		name = strings.TrimSuffix(name[:30], "-")
+	}
+	return fmt.Sprintf("%s-verify-%d", name, scheduledTime.Unix())
+}
+
+func buildVerificationRestore(backupSchedule *dpv1alpha1.BackupSchedule,
+	backup *dpv1alpha1.Backup,
+	name string) *dpv1alpha1.Restore {
+	policy := backupSchedule.Spec.Verification
+	labels := map[string]string{
+		constant.AppManagedByLabelKey:       dptypes.AppName,
+		dptypes.BackupScheduleLabelKey:      backupSchedule.Name,
+		dataProtectionBackupVerificationKey: name,
+	}
+	// the restore only creates the volume claims, set the backup schedule as their owner to garbage collect them.
+	ownerReference := metav1.OwnerReference{
+		APIVersion: dpv1alpha1.GroupVersion.String(),
+		Kind:       dptypes.BackupScheduleKind,
+		Name:       backupSchedule.Name,
+		UID:        backupSchedule.UID,
+	}
+	volumeClaims := make([]dpv1alpha1.RestoreVolumeClaim, 0, len(policy.VolumeClaims))
+	for _, claim := range policy.VolumeClaims {
+		claimLabels := map[string]string{}
+		for k, v := range claim.Labels {
+			claimLabels[k] = v
+		}
+		for k, v := range labels {
+			claimLabels[k] = v
+		}
+		claim.ObjectMeta = metav1.ObjectMeta{
+			Name:            verificationClaimName(name, claim.Name),
+			Namespace:       backupSchedule.Namespace,
+			Labels:          claimLabels,
+			Annotations:     claim.Annotations,
+			OwnerReferences: []metav1.OwnerReference{ownerReference},
+		}
+		volumeClaims = append(volumeClaims, claim)
+	}
+	restorePolicy := policy.VolumeClaimRestorePolicy
+	if restorePolicy == "" {
+		restorePolicy = dpv1alpha1.VolumeClaimRestorePolicyParallel
+	}
+	return &dpv1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: backupSchedule.Namespace,
+			Labels:    labels,
+		},
+		Spec: dpv1alpha1.RestoreSpec{
+			Backup: dpv1alpha1.BackupRef{
+				Name:      backup.Name,
+				Namespace: backup.Namespace,
+			},
+			PrepareDataConfig: &dpv1alpha1.PrepareDataConfig{
+				RestoreVolumeClaims:      volumeClaims,
+				VolumeClaimRestorePolicy: restorePolicy,
+			},
+		},
+	}
+}
+
+// buildVerificationCheckJob builds the job that runs the check against the restored volume claims.
+func buildVerificationCheckJob(backupSchedule *dpv1alpha1.BackupSchedule,
+	restore *dpv1alpha1.Restore,
+	activeDeadline time.Duration) *batchv1.Job {
+	check := backupSchedule.Spec.Verification.Check
+	var (
+		volumes      []corev1.Volume
+		volumeMounts []corev1.VolumeMount
+	)
+	for _, claim := range backupSchedule.Spec.Verification.VolumeClaims {
+		mountPath := claim.MountPath
+		if mountPath == "" {
+			mountPath = filepath.Join(verificationDefaultMountPath, claim.Name)
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: claim.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: verificationClaimName(restore.Name, claim.Name),
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: claim.Name, MountPath: mountPath})
+	}
+	env := append([]corev1.EnvVar{{Name: dptypes.DPBackupName, Value: restore.Spec.Backup.Name}}, check.Env...)
+	activeDeadlineSeconds := int64(activeDeadline.Seconds()) + 1
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      restore.Name,
+			Namespace: restore.Namespace,
+			Labels:    restore.Labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: restore.Labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes:       volumes,
+					Containers: []corev1.Container{{
+						Name:         verificationCheckContainerName,
+						Image:        check.Image,
+						Command:      check.Command,
+						Env:          env,
+						Resources:    check.Resources,
+						VolumeMounts: volumeMounts,
+					}},
+				},
+			},
+		},
+	}
+}
+
+func verificationClaimName(runName, claimName string) string {
+	return fmt.Sprintf("%s-%s", runName, claimName)
+}
+
+func verificationTimeout(policy *dpv1alpha1.BackupVerificationPolicy) time.Duration {
+	if policy.TimeoutMinutes <= 0 {
+		return verificationDefaultTimeout
+	}
+	return time.Duration(policy.TimeoutMinutes) * time.Minute
+}
+
+func backupCompletionTime(backup *dpv1alpha1.Backup) time.Time {
+	if backup.Status.CompletionTimestamp != nil {
+		return backup.Status.CompletionTimestamp.Time
+	}
+	return backup.CreationTimestamp.Time
+}
+
+// restoreFailureMessage returns the message of the failed condition of the restore.
+func restoreFailureMessage(restore *dpv1alpha1.Restore) string {
+	for i := len(restore.Status.Conditions) - 1; i >= 0; i-- {
+		if cond := restore.Status.Conditions[i]; cond.Status == metav1.ConditionFalse && cond.Message != "" {
+			return cond.Message
+		}
+	}
+	return "unknown error"
+}
@@ -0,0 +1,314 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dataprotection
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dprestore "github.com/apecloud/kubeblocks/pkg/dataprotection/restore"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
+)
+
+var _ = Describe("Backup Verification Controller", func() {
+	// verify once a year to avoid the verification being started by the controller during the test.
+	const verificationCron = "0 0 1 1 *"
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+
+		// namespaced
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupScheduleSignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupSignature, true, inNS)
+		Eventually(testapps.List(&testCtx, generics.BackupSignature, inNS)).Should(HaveLen(0))
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.JobSignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.RestoreSignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.PersistentVolumeClaimSignature, true, inNS)
+		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, ml)
+
+		// non-namespaced
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupRepoSignature, true, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.ActionSetSignature, true, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.StorageClassSignature, true, ml)
+		testapps.ClearResources(&testCtx, generics.StorageProviderSignature, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.PersistentVolumeSignature, true, ml)
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(cleanEnv)
+
+	When("verifying the backups of a backup schedule", func() {
+		var (
+			repo        *dpv1alpha1.BackupRepo
+			repoPVCName string
+			actionSet   *dpv1alpha1.ActionSet
+		)
+
+		BeforeEach(func() {
+			By("creating an actionSet")
+			actionSet = testdp.NewFakeActionSet(&testCtx)
+
+			By("creating storage provider")
+			_ = testdp.NewFakeStorageProvider(&testCtx, nil)
+
+			By("creating a backupRepo")
+			repo, repoPVCName = testdp.NewFakeBackupRepo(&testCtx, nil)
+		})
+
+		mockCompletedBackup := func() *dpv1alpha1.Backup {
+			backup := mockBackupForRestore(actionSet.Name, repo.Name, repoPVCName, true, false)
+			Expect(testapps.ChangeObj(&testCtx, backup, func(b *dpv1alpha1.Backup) {
+				if b.Labels == nil {
+					b.Labels = map[string]string{}
+				}
+				b.Labels[dptypes.BackupPolicyLabelKey] = testdp.BackupPolicyName
+			})).Should(Succeed())
+			return backup
+		}
+
+		newVerificationSchedule := func() *dpv1alpha1.BackupSchedule {
+			storageClass := testdp.StorageClassName
+			return testdp.NewFakeBackupSchedule(&testCtx, func(schedule *dpv1alpha1.BackupSchedule) {
+				schedule.Spec.Verification = &dpv1alpha1.BackupVerificationPolicy{
+					Enabled:        true,
+					BackupMethod:   testdp.BackupMethodName,
+					CronExpression: verificationCron,
+					VolumeClaims: []dpv1alpha1.RestoreVolumeClaim{{
+						ObjectMeta: metav1.ObjectMeta{Name: "data"},
+						VolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageClass,
+							AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+							},
+						},
+						VolumeConfig: dpv1alpha1.VolumeConfig{
+							VolumeSource: testdp.DataVolumeName,
+							MountPath:    "/data",
+						},
+					}},
+					Check: dpv1alpha1.BackupVerificationCheck{
+						Image:   testapps.ApeCloudMySQLImage,
+						Command: []string{"sh", "-c", "test -d /data"},
+					},
+				}
+			})
+		}
+
+		// reconcileVerification reconciles the backup schedule at the given time.
+		reconcileVerification := func(backupSchedule *dpv1alpha1.BackupSchedule, now time.Time) *dpv1alpha1.BackupVerificationStatus {
+			reconciler := &BackupVerificationReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sManager.GetScheme(),
+				Recorder: k8sManager.GetEventRecorderFor("backup-verification-controller"),
+			}
+			reqCtx := intctrlutil.RequestCtx{Ctx: ctx, Log: log.FromContext(ctx), Recorder: reconciler.Recorder}
+
+			fetched := &dpv1alpha1.BackupSchedule{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(backupSchedule), fetched)).Should(Succeed())
+			original := fetched.DeepCopy()
+			_, err := reconciler.reconcile(reqCtx, fetched, now)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(k8sClient.Status().Patch(ctx, fetched, client.MergeFrom(original))).Should(Succeed())
+			Expect(fetched.Status.Verification).ShouldNot(BeNil())
+			return fetched.Status.Verification
+		}
+
+		// runVerification reconciles the backup schedule at the next scheduled time of the verification.
+		runVerification := func(backupSchedule *dpv1alpha1.BackupSchedule) *dpv1alpha1.BackupVerificationStatus {
+			schedule, err := common.ParseCronExpression(verificationCron, "")
+			Expect(err).ShouldNot(HaveOccurred())
+			return reconcileVerification(backupSchedule, schedule.Next(time.Now().UTC()).Add(time.Minute))
+		}
+
+		finishRestoreJobs := func(restoreKey client.ObjectKey, jobCondition batchv1.JobConditionType) {
+			By("wait for the restore to be running")
+			Eventually(testapps.CheckObj(&testCtx, restoreKey, func(g Gomega, r *dpv1alpha1.Restore) {
+				g.Expect(r.Status.Phase).Should(Equal(dpv1alpha1.RestorePhaseRunning))
+			})).Should(Succeed())
+
+			By("wait for the scratch volume claims to be created")
+			Eventually(testapps.List(&testCtx, generics.PersistentVolumeClaimSignature,
+				client.MatchingLabels{dataProtectionBackupVerificationKey: restoreKey.Name},
+				client.InNamespace(restoreKey.Namespace))).Should(HaveLen(1))
+
+			By(fmt.Sprintf("mock the restore jobs are %s", jobCondition))
+			jobList := &batchv1.JobList{}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.List(ctx, jobList,
+					client.MatchingLabels{dprestore.DataProtectionRestoreLabelKey: restoreKey.Name},
+					client.InNamespace(restoreKey.Namespace))).Should(Succeed())
+				g.Expect(jobList.Items).ShouldNot(BeEmpty())
+			}).Should(Succeed())
+			for i := range jobList.Items {
+				testdp.PatchK8sJobStatus(&testCtx, client.ObjectKeyFromObject(&jobList.Items[i]), jobCondition)
+			}
+		}
+
+		completeRestore := func(restoreKey client.ObjectKey) {
+			finishRestoreJobs(restoreKey, batchv1.JobComplete)
+
+			By("wait for the restore to be completed")
+			Eventually(testapps.CheckObj(&testCtx, restoreKey, func(g Gomega, r *dpv1alpha1.Restore) {
+				g.Expect(r.Status.Phase).Should(Equal(dpv1alpha1.RestorePhaseCompleted))
+			})).Should(Succeed())
+		}
+
+		checkVerificationFinished := func(backup *dpv1alpha1.Backup,
+			backupSchedule *dpv1alpha1.BackupSchedule,
+			restoreKey client.ObjectKey,
+			phase dpv1alpha1.BackupVerificationPhase) {
+			By("checking the result is recorded in the backup and the backup schedule")
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, b *dpv1alpha1.Backup) {
+				g.Expect(b.Status.Verification).ShouldNot(BeNil())
+				g.Expect(b.Status.Verification.Phase).Should(Equal(phase))
+				g.Expect(b.Status.Verification.BackupScheduleName).Should(Equal(backupSchedule.Name))
+				g.Expect(b.Status.Verification.CompletionTime).ShouldNot(BeNil())
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backupSchedule), func(g Gomega, s *dpv1alpha1.BackupSchedule) {
+				g.Expect(s.Status.Verification.Phase).Should(Equal(phase))
+			})).Should(Succeed())
+
+			By("checking the scratch resources are deleted")
+			Eventually(testapps.CheckObjExists(&testCtx, restoreKey, &dpv1alpha1.Restore{}, false)).Should(Succeed())
+			Eventually(testapps.CheckObjExists(&testCtx, restoreKey, &batchv1.Job{}, false)).Should(Succeed())
+			Eventually(testapps.List(&testCtx, generics.PersistentVolumeClaimSignature,
+				client.MatchingLabels{dataProtectionBackupVerificationKey: restoreKey.Name},
+				client.InNamespace(restoreKey.Namespace))).Should(HaveLen(0))
+		}
+
+		It("should skip the verification if there is no completed backup", func() {
+			backupSchedule := newVerificationSchedule()
+			status := runVerification(backupSchedule)
+			Expect(status.Phase).Should(BeEmpty())
+			Expect(status.LastScheduleTime).ShouldNot(BeNil())
+			Consistently(testapps.List(&testCtx, generics.RestoreSignature,
+				client.InNamespace(testCtx.DefaultNamespace))).Should(HaveLen(0))
+		})
+
+		It("should restore the backup and record the result of the check", func() {
+			backup := mockCompletedBackup()
+			backupSchedule := newVerificationSchedule()
+
+			By("starting the verification")
+			status := runVerification(backupSchedule)
+			Expect(status.Phase).Should(Equal(dpv1alpha1.BackupVerificationRunning))
+			Expect(status.BackupName).Should(Equal(backup.Name))
+			restoreKey := client.ObjectKey{Namespace: backupSchedule.Namespace, Name: status.RestoreName}
+			Eventually(testapps.CheckObj(&testCtx, restoreKey, func(g Gomega, r *dpv1alpha1.Restore) {
+				g.Expect(r.Spec.Backup.Name).Should(Equal(backup.Name))
+				g.Expect(r.Spec.PrepareDataConfig.RestoreVolumeClaims).Should(HaveLen(1))
+				g.Expect(r.Spec.PrepareDataConfig.RestoreVolumeClaims[0].Name).Should(Equal(restoreKey.Name + "-data"))
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, b *dpv1alpha1.Backup) {
+				g.Expect(b.Status.Verification).ShouldNot(BeNil())
+				g.Expect(b.Status.Verification.Phase).Should(Equal(dpv1alpha1.BackupVerificationRunning))
+			})).Should(Succeed())
+
+			completeRestore(restoreKey)
+
+			By("checking the check job mounts the restored volume claims")
+			Eventually(testapps.CheckObj(&testCtx, restoreKey, func(g Gomega, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				g.Expect(podSpec.Volumes).Should(HaveLen(1))
+				g.Expect(podSpec.Volumes[0].PersistentVolumeClaim.ClaimName).Should(Equal(restoreKey.Name + "-data"))
+				g.Expect(podSpec.Containers[0].VolumeMounts[0].MountPath).Should(Equal("/data"))
+				g.Expect(podSpec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: dptypes.DPBackupName, Value: backup.Name}))
+			})).Should(Succeed())
+
+			By("mock the check job is completed")
+			testdp.PatchK8sJobStatus(&testCtx, restoreKey, batchv1.JobComplete)
+			checkVerificationFinished(backup, backupSchedule, restoreKey, dpv1alpha1.BackupVerificationSucceeded)
+		})
+
+		It("should fail the verification if the check fails", func() {
+			backup := mockCompletedBackup()
+			backupSchedule := newVerificationSchedule()
+
+			By("starting the verification")
+			status := runVerification(backupSchedule)
+			restoreKey := client.ObjectKey{Namespace: backupSchedule.Namespace, Name: status.RestoreName}
+			completeRestore(restoreKey)
+
+			By("mock the check job is failed")
+			Eventually(testapps.CheckObjExists(&testCtx, restoreKey, &batchv1.Job{}, true)).Should(Succeed())
+			testdp.PatchK8sJobStatus(&testCtx, restoreKey, batchv1.JobFailed)
+			checkVerificationFinished(backup, backupSchedule, restoreKey, dpv1alpha1.BackupVerificationFailed)
+		})
+
+		It("should fail the verification if the restore fails", func() {
+			backup := mockCompletedBackup()
+			backupSchedule := newVerificationSchedule()
+
+			By("starting the verification")
+			status := runVerification(backupSchedule)
+			restoreKey := client.ObjectKey{Namespace: backupSchedule.Namespace, Name: status.RestoreName}
+			finishRestoreJobs(restoreKey, batchv1.JobFailed)
+
+			checkVerificationFinished(backup, backupSchedule, restoreKey, dpv1alpha1.BackupVerificationFailed)
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backupSchedule), func(g Gomega, s *dpv1alpha1.BackupSchedule) {
+				g.Expect(s.Status.Verification.Message).Should(ContainSubstring("failed to restore backup"))
+			})).Should(Succeed())
+			Consistently(testapps.CheckObjExists(&testCtx, restoreKey, &batchv1.Job{}, false)).Should(Succeed())
+		})
+
+		It("should fail the verification if it times out", func() {
+			backup := mockCompletedBackup()
+			backupSchedule := newVerificationSchedule()
+
+			By("starting the verification")
+			status := runVerification(backupSchedule)
+			restoreKey := client.ObjectKey{Namespace: backupSchedule.Namespace, Name: status.RestoreName}
+			completeRestore(restoreKey)
+			Eventually(testapps.CheckObjExists(&testCtx, restoreKey, &batchv1.Job{}, true)).Should(Succeed())
+
+			By("syncing the verification after the deadline")
+			deadline := status.StartTime.Add(verificationTimeout(backupSchedule.Spec.Verification))
+			status = reconcileVerification(backupSchedule, deadline)
+			Expect(status.Phase).Should(Equal(dpv1alpha1.BackupVerificationFailed))
+			Expect(status.Message).Should(ContainSubstring("does not finish"))
+			checkVerificationFinished(backup, backupSchedule, restoreKey, dpv1alpha1.BackupVerificationFailed)
+		})
+	})
+})
@@ -64,3 +64,34 @@ func (h *clusterHandler) mapAndEnqueue(ctx context.Context, q workqueue.RateLimi
 }
 
 var _ handler.EventHandler = &clusterHandler{}
+
+type metricsScalerClusterHandler struct {
+	client.Client
+}
+
+func (h *metricsScalerClusterHandler) Create(ctx context.Context, event event.CreateEvent, limitingInterface workqueue.RateLimitingInterface) {
+	h.mapAndEnqueue(ctx, limitingInterface, event.Object)
+}
+
+func (h *metricsScalerClusterHandler) Update(ctx context.Context, event event.UpdateEvent, limitingInterface workqueue.RateLimitingInterface) {
+	h.mapAndEnqueue(ctx, limitingInterface, event.ObjectNew)
+}
+
+func (h *metricsScalerClusterHandler) Delete(ctx context.Context, event event.DeleteEvent, limitingInterface workqueue.RateLimitingInterface) {
+}
+
+func (h *metricsScalerClusterHandler) Generic(ctx context.Context, event event.GenericEvent, limitingInterface workqueue.RateLimitingInterface) {
+}
+
+func (h *metricsScalerClusterHandler) mapAndEnqueue(ctx context.Context, q workqueue.RateLimitingInterface, object client.Object) {
+	scalerList := &experimental.MetricsScalerList{}
+	if err := h.Client.List(ctx, scalerList, client.InNamespace(object.GetNamespace())); err == nil {
+		for _, item := range scalerList.Items {
+			if item.Spec.TargetClusterName == object.GetName() {
+				q.Add(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: item.Namespace, Name: item.Name}})
+			}
+		}
+	}
+}
+
+var _ handler.EventHandler = &metricsScalerClusterHandler{}
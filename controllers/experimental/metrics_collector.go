@@ -0,0 +1,197 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+// metricsCollector reads the current value of metrics of the pods of the target component.
+// The resource metrics and events are read from the API server directly, since they are not cached.
+type metricsCollector struct {
+	ctx    context.Context
+	reader client.Reader
+}
+
+// collect returns the current status of the metric, and the ratio of the current value to the target value.
+func (c *metricsCollector) collect(scaler *experimental.MetricsScaler, pods []*corev1.Pod, metric experimental.MetricSpec) (*experimental.MetricStatus, float64, error) {
+	switch {
+	case metric.Type == experimental.ResourceMetricSourceType && metric.Resource != nil:
+		return c.resourceMetric(scaler, pods, metric.Resource)
+	case metric.Type == experimental.ProbeMetricSourceType && metric.Probe != nil:
+		return c.probeMetric(scaler, pods, metric.Probe)
+	default:
+		return nil, 0, fmt.Errorf("invalid metric source of type %s", metric.Type)
+	}
+}
+
+func (c *metricsCollector) resourceMetric(scaler *experimental.MetricsScaler, pods []*corev1.Pod,
+	source *experimental.ResourceMetricSource) (*experimental.MetricStatus, float64, error) {
+	podMetricsList := &metricsv1beta1.PodMetricsList{}
+	labels := constant.GetCompLabels(scaler.Spec.TargetClusterName, scaler.Spec.TargetComponentName)
+	if err := c.reader.List(c.ctx, podMetricsList, client.InNamespace(scaler.Namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, 0, err
+	}
+	usages := make(map[string]int64)
+	for _, podMetrics := range podMetricsList.Items {
+		var usage int64
+		for _, container := range podMetrics.Containers {
+			if quantity, ok := container.Usage[source.Name]; ok {
+				usage += quantity.MilliValue()
+			}
+		}
+		usages[podMetrics.Name] = usage
+	}
+
+	var usage, request, count int64
+	for _, pod := range pods {
+		podUsage, ok := usages[pod.Name]
+		if !ok {
+			// the metrics of the pod is not available yet
+			continue
+		}
+		var podRequest int64
+		for _, container := range pod.Spec.Containers {
+			if quantity, ok := container.Resources.Requests[source.Name]; ok {
+				podRequest += quantity.MilliValue()
+			}
+		}
+		if podRequest == 0 {
+			return nil, 0, fmt.Errorf("missing request for %s of pod %s", source.Name, pod.Name)
+		}
+		usage += podUsage
+		request += podRequest
+		count++
+	}
+	if count == 0 {
+		return nil, 0, fmt.Errorf("no metrics of resource %s returned from the resource metrics API", source.Name)
+	}
+
+	utilization := float64(usage) * 100 / float64(request)
+	status := &experimental.MetricStatus{
+		Type:                      experimental.ResourceMetricSourceType,
+		Name:                      string(source.Name),
+		CurrentAverageValue:       resource.NewMilliQuantity(usage/count, resource.DecimalSI),
+		CurrentAverageUtilization: ptr.To(int32(utilization)),
+	}
+	return status, utilization / float64(source.TargetAverageUtilization), nil
+}
+
+func (c *metricsCollector) probeMetric(scaler *experimental.MetricsScaler, pods []*corev1.Pod,
+	source *experimental.ProbeMetricSource) (*experimental.MetricStatus, float64, error) {
+	if source.TargetAverageValue.Sign() <= 0 {
+		return nil, 0, fmt.Errorf("invalid target value %s of probe %s", source.TargetAverageValue.String(), source.Name)
+	}
+
+	eventList := &corev1.EventList{}
+	if err := c.reader.List(c.ctx, eventList, client.InNamespace(scaler.Namespace), client.MatchingFields{"reason": source.Name}); err != nil {
+		return nil, 0, err
+	}
+	podNames := make(map[string]bool)
+	for _, pod := range pods {
+		podNames[pod.Name] = true
+	}
+	latest := make(map[string]*corev1.Event)
+	for i, event := range eventList.Items {
+		if event.ReportingController != proto.ProbeEventReportingController ||
+			event.InvolvedObject.FieldPath != proto.ProbeEventFieldPath || !podNames[event.InvolvedObject.Name] {
+			continue
+		}
+		if last, ok := latest[event.InvolvedObject.Name]; !ok || eventTime(last).Before(eventTime(&event)) {
+			latest[event.InvolvedObject.Name] = &eventList.Items[i]
+		}
+	}
+
+	var (
+		sum   float64
+		count int
+	)
+	for _, event := range latest {
+		probeEvent := &proto.ProbeEvent{}
+		if err := json.Unmarshal([]byte(event.Message), probeEvent); err != nil || probeEvent.Code != 0 {
+			continue
+		}
+		value, err := resource.ParseQuantity(strings.TrimSpace(string(probeEvent.Output)))
+		if err != nil {
+			continue
+		}
+		sum += value.AsApproximateFloat64()
+		count++
+	}
+	if count == 0 {
+		return nil, 0, fmt.Errorf("no valid output of probe %s reported by the kb-agent", source.Name)
+	}
+
+	average := sum / float64(count)
+	status := &experimental.MetricStatus{
+		Type:                experimental.ProbeMetricSourceType,
+		Name:                source.Name,
+		CurrentAverageValue: resource.NewMilliQuantity(int64(average*1000), resource.DecimalSI),
+	}
+	return status, average / source.TargetAverageValue.AsApproximateFloat64(), nil
+}
+
+func eventTime(event *corev1.Event) time.Time {
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.LastTimestamp.Time
+}
+
+// mainContainerUsage returns the current resource usage of the main container of the pods, keyed by the pod name.
+// The pods without the metrics available are omitted.
+func (c *metricsCollector) mainContainerUsage(namespace string, labels map[string]string, pods []*corev1.Pod) (map[string]corev1.ResourceList, error) {
+	podMetricsList := &metricsv1beta1.PodMetricsList{}
+	if err := c.reader.List(c.ctx, podMetricsList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+	podMetrics := make(map[string]*metricsv1beta1.PodMetrics)
+	for i, item := range podMetricsList.Items {
+		podMetrics[item.Name] = &podMetricsList.Items[i]
+	}
+	usages := make(map[string]corev1.ResourceList)
+	for _, pod := range pods {
+		metrics, ok := podMetrics[pod.Name]
+		if !ok || len(pod.Spec.Containers) == 0 {
+			continue
+		}
+		for _, container := range metrics.Containers {
+			if container.Name == pod.Spec.Containers[0].Name {
+				usages[pod.Name] = container.Usage
+				break
+			}
+		}
+	}
+	return usages, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+)
+
+func init() {
+	model.AddScheme(opsv1alpha1.AddToScheme)
+}
+
+// MetricsScalerReconciler reconciles a MetricsScaler object
+type MetricsScalerReconciler struct {
+	client.Client
+	// APIReader reads the resource metrics and events from the API server directly.
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=experimental.kubeblocks.io,resources=metricsscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=experimental.kubeblocks.io,resources=metricsscalers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=experimental.kubeblocks.io,resources=metricsscalers/finalizers,verbs=update
+
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=components,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=componentdefinitions,verbs=get;list;watch
+
+// +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opsrequests,verbs=get;list;watch;create
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
+func (r *MetricsScalerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("MetricsScaler", req.NamespacedName)
+
+	return kubebuilderx.NewController(ctx, r.Client, req, r.Recorder, logger).
+		Prepare(metricsScalerTree()).
+		Do(scaleByMetrics(ctx, r.APIReader)).
+		Commit()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MetricsScalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// the status is refreshed periodically, only the spec changes need to be reconciled immediately
+		For(&experimental.MetricsScaler{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&appsv1.Cluster{}, &metricsScalerClusterHandler{r.Client}).
+		Complete(r)
+}
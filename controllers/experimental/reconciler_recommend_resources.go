@@ -0,0 +1,400 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	// the interval to sample the resource usage and re-calculate the recommendations
+	verticalScalerSyncPeriod = 5 * time.Minute
+
+	// the minimum number of samples of an instance template to provide the recommendation
+	verticalScalerMinSamples = 12
+
+	defaultRequestPercentile    = 90
+	defaultLimitPercentile      = 99
+	defaultHistoryWindowSeconds = 86400
+
+	cpuRoundingMilli   = 10
+	memoryRoundingByte = 1024 * 1024
+)
+
+type recommendResourcesReconciler struct {
+	collector *metricsCollector
+}
+
+func (r *recommendResourcesReconciler) PreCondition(tree *kubebuilderx.ObjectTree) *kubebuilderx.CheckResult {
+	if tree.GetRoot() == nil || model.IsObjectDeleting(tree.GetRoot()) {
+		return kubebuilderx.ConditionUnsatisfied
+	}
+	return kubebuilderx.ConditionSatisfied
+}
+
+func (r *recommendResourcesReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilderx.Result, error) {
+	scaler, _ := tree.GetRoot().(*experimental.VerticalScaler)
+	clusterKey := builder.NewClusterBuilder(scaler.Namespace, scaler.Spec.TargetClusterName).GetObject()
+	object, err := tree.Get(clusterKey)
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+	cluster, _ := object.(*appsv1.Cluster)
+	compSpec := cluster.Spec.GetComponentByName(scaler.Spec.TargetComponentName)
+	if compSpec == nil {
+		return kubebuilderx.Continue, fmt.Errorf("component %s not found in cluster %s", scaler.Spec.TargetComponentName, cluster.Name)
+	}
+
+	now := time.Now()
+	history, err := r.recordUsage(tree, scaler, now)
+	if err != nil {
+		setVerticalScalerCondition(scaler, experimental.RecommendationProvided, metav1.ConditionFalse,
+			experimental.ReasonFailedGetMetrics, fmt.Sprintf("failed to get metrics: %s", err.Error()))
+		return kubebuilderx.RetryAfter(verticalScalerSyncPeriod), nil
+	}
+
+	recommendations := computeRecommendations(scaler, compSpec, history, now)
+	scaler.Status.Recommendations = recommendations
+	if len(recommendations) == 0 {
+		setVerticalScalerCondition(scaler, experimental.RecommendationProvided, metav1.ConditionFalse, experimental.ReasonInsufficientHistory,
+			fmt.Sprintf("at least %d usage samples are required to provide the recommendations", verticalScalerMinSamples))
+		return kubebuilderx.RetryAfter(verticalScalerSyncPeriod), nil
+	}
+	setVerticalScalerCondition(scaler, experimental.RecommendationProvided, metav1.ConditionTrue, experimental.ReasonRecommendationUpdated,
+		"the recommendations are calculated from the usage history")
+
+	if scaler.Spec.UpdateMode != experimental.VerticalScalerUpdateModeAuto {
+		setVerticalScalerCondition(scaler, experimental.AbleToScale, metav1.ConditionFalse, experimental.ReasonUpdateModeOff,
+			"the recommendations are not applied since the update mode is Off")
+		return kubebuilderx.RetryAfter(verticalScalerSyncPeriod), nil
+	}
+	if window := scaler.Spec.MaintenanceWindow; window != nil {
+		open, _, err := intctrlutil.MaintenanceWindowOpen(window, now)
+		if err != nil || !open {
+			message := "the recommendations will be applied in the next maintenance window"
+			if err != nil {
+				message = fmt.Sprintf("invalid maintenance window: %s", err.Error())
+			}
+			setVerticalScalerCondition(scaler, experimental.AbleToScale, metav1.ConditionFalse, experimental.ReasonOutOfMaintenanceWindow, message)
+			return kubebuilderx.RetryAfter(verticalScalerSyncPeriod), nil
+		}
+	}
+	if !significantlyChanged(scaler, recommendations) {
+		setVerticalScalerCondition(scaler, experimental.AbleToScale, metav1.ConditionTrue, experimental.ReasonReadyForNewScale,
+			"the recommendations are close to the current resources")
+		return kubebuilderx.RetryAfter(verticalScalerSyncPeriod), nil
+	}
+
+	if msg := scaleInProgress(tree, cluster); len(msg) > 0 {
+		setVerticalScalerCondition(scaler, experimental.AbleToScale, metav1.ConditionFalse, experimental.ReasonScaleInProgress, msg)
+		return kubebuilderx.RetryAfter(verticalScalerSyncPeriod), nil
+	}
+
+	ops := buildVerticalScalingOps(scaler, recommendations)
+	if err = tree.Add(ops); err != nil {
+		return kubebuilderx.Continue, err
+	}
+	scaler.Status.OpsRequestName = ops.Name
+	scaler.Status.LastScaleTime = metav1.Time{Time: now}
+	setVerticalScalerCondition(scaler, experimental.AbleToScale, metav1.ConditionTrue, experimental.ReasonReadyForNewScale,
+		"the recommendations are applied to the component")
+	if tree.EventRecorder != nil {
+		tree.EventRecorder.Eventf(scaler, corev1.EventTypeNormal, "Scaling",
+			"OpsRequest %s is created to apply the recommended resources to the component %s", ops.Name, scaler.Spec.TargetComponentName)
+	}
+
+	return kubebuilderx.RetryAfter(verticalScalerSyncPeriod), nil
+}
+
+// recordUsage samples the current usage of the instances into the usage history, and prunes the expired samples.
+func (r *recommendResourcesReconciler) recordUsage(tree *kubebuilderx.ObjectTree, scaler *experimental.VerticalScaler, now time.Time) (usageHistory, error) {
+	cm := builder.NewConfigMapBuilder(scaler.Namespace, usageHistoryName(scaler)).
+		SetOwnerReferences(experimental.GroupVersion.String(), "VerticalScaler", scaler).
+		GetObject()
+	object, err := tree.Get(cm)
+	if err != nil {
+		return nil, err
+	}
+	exist := object != nil
+	if exist {
+		cm, _ = object.(*corev1.ConfigMap)
+	}
+	history := loadUsageHistory(cm)
+
+	var pods []*corev1.Pod
+	for _, obj := range tree.List(&corev1.Pod{}) {
+		pods = append(pods, obj.(*corev1.Pod))
+	}
+	labels := constant.GetCompLabels(scaler.Spec.TargetClusterName, scaler.Spec.TargetComponentName)
+	usages, err := r.collector.mainContainerUsage(scaler.Namespace, labels, pods)
+	if err != nil {
+		return nil, err
+	}
+	interval := sampleInterval(historyWindow(scaler), len(usages))
+	for name, usage := range usages {
+		history.record(name, now, usage, interval)
+	}
+	history.prune(now.Add(-historyWindow(scaler)))
+	history.downsample()
+
+	if cm.Data, err = history.encode(); err != nil {
+		return nil, err
+	}
+	if exist {
+		return history, tree.Update(cm)
+	}
+	return history, tree.Add(cm)
+}
+
+func historyWindow(scaler *experimental.VerticalScaler) time.Duration {
+	seconds := scaler.Spec.HistoryWindowSeconds
+	if seconds <= 0 {
+		seconds = defaultHistoryWindowSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func controlledResources(scaler *experimental.VerticalScaler) []corev1.ResourceName {
+	if len(scaler.Spec.ControlledResources) == 0 {
+		return []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+	}
+	return scaler.Spec.ControlledResources
+}
+
+// computeRecommendations calculates the recommended resources for each instance template with enough usage samples.
+func computeRecommendations(scaler *experimental.VerticalScaler, compSpec *appsv1.ClusterComponentSpec,
+	history usageHistory, now time.Time) []experimental.ResourceRecommendation {
+	requestPercentile, limitPercentile := scaler.Spec.RequestPercentile, scaler.Spec.LimitPercentile
+	if requestPercentile <= 0 {
+		requestPercentile = defaultRequestPercentile
+	}
+	if limitPercentile <= 0 {
+		limitPercentile = defaultLimitPercentile
+	}
+	limitPercentile = max(requestPercentile, limitPercentile)
+
+	compName := constant.GenerateClusterComponentName(scaler.Spec.TargetClusterName, scaler.Spec.TargetComponentName)
+	templateSamples := make(map[string][]usageSample)
+	for instName, samples := range history {
+		if templateName, ok := instanceTemplateOf(compName, compSpec.Instances, instName); ok {
+			templateSamples[templateName] = append(templateSamples[templateName], samples...)
+		}
+	}
+	templateNames := make([]string, 0, len(templateSamples))
+	for templateName := range templateSamples {
+		templateNames = append(templateNames, templateName)
+	}
+	sort.Strings(templateNames)
+
+	var recommendations []experimental.ResourceRecommendation
+	for _, templateName := range templateNames {
+		samples := templateSamples[templateName]
+		if len(samples) < verticalScalerMinSamples {
+			continue
+		}
+		current := templateResources(compSpec, templateName)
+		target := current.DeepCopy()
+		if target.Requests == nil {
+			target.Requests = corev1.ResourceList{}
+		}
+		if target.Limits == nil {
+			target.Limits = corev1.ResourceList{}
+		}
+		for _, name := range controlledResources(scaler) {
+			request := recommendedValue(scaler, name, samples, requestPercentile)
+			limit := recommendedValue(scaler, name, samples, limitPercentile)
+			target.Requests[name] = request
+			target.Limits[name] = limit
+		}
+		recommendations = append(recommendations, experimental.ResourceRecommendation{
+			TemplateName:   templateName,
+			Current:        current,
+			Target:         *target,
+			Samples:        int32(len(samples)),
+			LastUpdateTime: metav1.Time{Time: now},
+		})
+	}
+	return recommendations
+}
+
+// instanceTemplateOf returns the name of the instance template the instance is created from,
+// the instance names follow the pattern: $(cluster.name)-$(component.name)[-$(template.name)]-$(ordinal).
+func instanceTemplateOf(compName string, templates []appsv1.InstanceTemplate, instName string) (string, bool) {
+	rest, found := strings.CutPrefix(instName, compName+"-")
+	if !found {
+		return "", false
+	}
+	isOrdinal := func(s string) bool {
+		_, err := strconv.ParseUint(s, 10, 32)
+		return err == nil
+	}
+	idx := strings.LastIndex(rest, "-")
+	if idx < 0 {
+		return "", isOrdinal(rest)
+	}
+	if !isOrdinal(rest[idx+1:]) {
+		return "", false
+	}
+	templateName := rest[:idx]
+	for _, template := range templates {
+		if template.Name == templateName {
+			return templateName, true
+		}
+	}
+	return "", false
+}
+
+// templateResources returns the current resources of the instances of the template.
+func templateResources(compSpec *appsv1.ClusterComponentSpec, templateName string) corev1.ResourceRequirements {
+	for _, template := range compSpec.Instances {
+		if template.Name == templateName && template.Resources != nil {
+			return *template.Resources.DeepCopy()
+		}
+	}
+	return *compSpec.Resources.DeepCopy()
+}
+
+// recommendedValue returns the percentile of the usage samples plus the safety margin,
+// rounded up and restricted by the min and max allowed resources.
+func recommendedValue(scaler *experimental.VerticalScaler, name corev1.ResourceName, samples []usageSample, percentile int32) resource.Quantity {
+	values := make([]int64, 0, len(samples))
+	for _, sample := range samples {
+		values = append(values, sample.value(name))
+	}
+	slices.Sort(values)
+	idx := int(math.Ceil(float64(percentile)*float64(len(values))/100)) - 1
+	idx = max(0, min(len(values)-1, idx))
+	value := int64(math.Ceil(float64(values[idx]) * float64(100+scaler.Spec.SafetyMarginPercent) / 100))
+
+	var quantity resource.Quantity
+	if name == corev1.ResourceCPU {
+		quantity = *resource.NewMilliQuantity(roundUp(value, cpuRoundingMilli), resource.DecimalSI)
+	} else {
+		quantity = *resource.NewQuantity(roundUp(value, memoryRoundingByte), resource.BinarySI)
+	}
+	if lower, ok := scaler.Spec.MinAllowed[name]; ok && quantity.Cmp(lower) < 0 {
+		quantity = lower.DeepCopy()
+	}
+	if upper, ok := scaler.Spec.MaxAllowed[name]; ok && quantity.Cmp(upper) > 0 {
+		quantity = upper.DeepCopy()
+	}
+	return quantity
+}
+
+func roundUp(value, unit int64) int64 {
+	return max(unit, (value+unit-1)/unit*unit)
+}
+
+// significantlyChanged checks whether any recommended resource differs from the current one by more than the minimum change percent.
+func significantlyChanged(scaler *experimental.VerticalScaler, recommendations []experimental.ResourceRecommendation) bool {
+	changed := func(current, target corev1.ResourceList, name corev1.ResourceName) bool {
+		currentValue, targetValue := current[name], target[name]
+		if currentValue.IsZero() {
+			return !targetValue.IsZero()
+		}
+		diff := math.Abs(targetValue.AsApproximateFloat64() - currentValue.AsApproximateFloat64())
+		return diff > 0 && diff*100 >= currentValue.AsApproximateFloat64()*float64(scaler.Spec.MinChangePercent)
+	}
+	for _, rec := range recommendations {
+		for _, name := range controlledResources(scaler) {
+			if changed(rec.Current.Requests, rec.Target.Requests, name) || changed(rec.Current.Limits, rec.Target.Limits, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// buildVerticalScalingOps builds the OpsRequest to apply all recommendations, the instance templates are pinned to
+// their own recommendations, so they are not affected by the change of the component-level resources.
+func buildVerticalScalingOps(scaler *experimental.VerticalScaler, recommendations []experimental.ResourceRecommendation) *opsv1alpha1.OpsRequest {
+	verticalScaling := opsv1alpha1.VerticalScaling{
+		ComponentOps: opsv1alpha1.ComponentOps{ComponentName: scaler.Spec.TargetComponentName},
+	}
+	for _, rec := range recommendations {
+		if len(rec.TemplateName) == 0 {
+			verticalScaling.ResourceRequirements = rec.Target
+		} else {
+			verticalScaling.Instances = append(verticalScaling.Instances, opsv1alpha1.InstanceResourceTemplate{
+				Name:                 rec.TemplateName,
+				ResourceRequirements: rec.Target,
+			})
+		}
+	}
+	return &opsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: scaler.Namespace,
+			Name:      fmt.Sprintf("%s-%s", scaler.Name, time.Now().Format("20060102150405")),
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:    scaler.Spec.TargetClusterName,
+				constant.KBAppComponentLabelKey: scaler.Spec.TargetComponentName,
+				constant.OpsRequestTypeLabelKey: string(opsv1alpha1.VerticalScalingType),
+			},
+		},
+		Spec: opsv1alpha1.OpsRequestSpec{
+			ClusterName: scaler.Spec.TargetClusterName,
+			Type:        opsv1alpha1.VerticalScalingType,
+			SpecificOpsRequest: opsv1alpha1.SpecificOpsRequest{
+				VerticalScalingList: []opsv1alpha1.VerticalScaling{verticalScaling},
+			},
+		},
+	}
+}
+
+func setVerticalScalerCondition(scaler *experimental.VerticalScaler, conditionType experimental.ConditionType,
+	status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&scaler.Status.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             status,
+		ObservedGeneration: scaler.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func recommendResources(ctx context.Context, reader client.Reader) kubebuilderx.Reconciler {
+	return &recommendResourcesReconciler{
+		collector: &metricsCollector{ctx: ctx, reader: reader},
+	}
+}
+
+var _ kubebuilderx.Reconciler = &recommendResourcesReconciler{}
@@ -0,0 +1,400 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	experimentalv1alpha1 "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+)
+
+var _ = Describe("recommend resources reconciler test", func() {
+	const (
+		compName     = "bar"
+		templateName = "large"
+	)
+
+	var (
+		vts       *experimentalv1alpha1.VerticalScaler
+		pods      []*corev1.Pod
+		comp      *appsv1.Component
+		recommend kubebuilderx.Reconciler
+	)
+
+	newReader := func(objs ...client.Object) client.Reader {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+		Expect(metricsv1beta1.AddToScheme(scheme)).Should(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	podMetrics := func(pod *corev1.Pod, cpu, memory string) *metricsv1beta1.PodMetrics {
+		return &metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Labels:    pod.Labels,
+			},
+			Containers: []metricsv1beta1.ContainerMetrics{
+				{
+					Name: "main",
+					Usage: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+				{
+					Name:  "sidecar",
+					Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+			},
+		}
+	}
+
+	// mockHistory mocks 12 samples for each pod in the last 2 hours, the cpu usage grows from 100m to 1200m.
+	mockHistory := func() *corev1.ConfigMap {
+		now := time.Now()
+		history := usageHistory{}
+		for _, pod := range pods {
+			for i := 0; i < 12; i++ {
+				ts := now.Add(-time.Duration(12-i) * 10 * time.Minute).Unix()
+				history[pod.Name] = append(history[pod.Name], usageSample{ts, int64(i+1) * 100, 1024 * 1024 * 1024})
+			}
+		}
+		data, err := history.encode()
+		Expect(err).Should(BeNil())
+		return builder.NewConfigMapBuilder(namespace, usageHistoryName(vts)).SetData(data).GetObject()
+	}
+
+	mockTree := func(objs ...client.Object) *kubebuilderx.ObjectTree {
+		resources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		}
+		specs := []appsv1.ClusterComponentSpec{
+			{
+				Name:      compName,
+				Replicas:  2,
+				Resources: resources,
+				Instances: []appsv1.InstanceTemplate{
+					{
+						Name:     templateName,
+						Replicas: ptr.To(int32(1)),
+					},
+				},
+			},
+		}
+		cluster := builder.NewClusterBuilder(namespace, clusterName).SetComponentSpecs(specs).GetObject()
+
+		tree := kubebuilderx.NewObjectTree()
+		tree.SetRoot(vts)
+		Expect(tree.Add(cluster, comp)).Should(Succeed())
+		for _, pod := range pods {
+			Expect(tree.Add(pod)).Should(Succeed())
+		}
+		Expect(tree.Add(objs...)).Should(Succeed())
+		return tree
+	}
+
+	historyOf := func(tree *kubebuilderx.ObjectTree) usageHistory {
+		object, err := tree.Get(builder.NewConfigMapBuilder(namespace, usageHistoryName(vts)).GetObject())
+		Expect(err).Should(BeNil())
+		Expect(object).ShouldNot(BeNil())
+		return loadUsageHistory(object.(*corev1.ConfigMap))
+	}
+
+	scaleOps := func(tree *kubebuilderx.ObjectTree) *opsv1alpha1.OpsRequest {
+		objs := tree.List(&opsv1alpha1.OpsRequest{})
+		if len(objs) == 0 {
+			return nil
+		}
+		return objs[len(objs)-1].(*opsv1alpha1.OpsRequest)
+	}
+
+	BeforeEach(func() {
+		vts = builder.NewVerticalScalerBuilder(namespace, name).
+			SetTargetClusterName(clusterName).
+			SetTargetComponentName(compName).
+			SetPercentiles(90, 99).
+			SetSafetyMarginPercent(15).
+			SetHistoryWindowSeconds(86400).
+			SetMinChangePercent(10).
+			GetObject()
+		comp = &appsv1.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      constant.GenerateClusterComponentName(clusterName, compName),
+			},
+			Status: appsv1.ComponentStatus{
+				Phase: appsv1.RunningComponentPhase,
+			},
+		}
+		pods = nil
+		for _, podName := range []string{"foo-bar-0", fmt.Sprintf("foo-bar-%s-0", templateName)} {
+			pods = append(pods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      podName,
+					Labels:    constant.GetCompLabels(clusterName, compName),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main"}, {Name: "sidecar"}},
+				},
+			})
+		}
+	})
+
+	Context("PreCondition & Reconcile", func() {
+		It("insufficient history", func() {
+			recommend = recommendResources(context.Background(), newReader(podMetrics(pods[0], "200m", "512Mi")))
+			tree := mockTree()
+
+			By("PreCondition")
+			Expect(recommend.PreCondition(tree)).Should(Equal(kubebuilderx.ConditionSatisfied))
+
+			By("Reconcile")
+			res, err := recommend.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.RetryAfter(verticalScalerSyncPeriod)))
+			Expect(vts.Status.Recommendations).Should(BeEmpty())
+			cond := meta.FindStatusCondition(vts.Status.Conditions, string(experimentalv1alpha1.RecommendationProvided))
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Reason).Should(Equal(experimentalv1alpha1.ReasonInsufficientHistory))
+
+			By("the usage of the main container is recorded")
+			history := historyOf(tree)
+			Expect(history).Should(HaveLen(1))
+			Expect(history[pods[0].Name]).Should(HaveLen(1))
+			Expect(history[pods[0].Name][0].value(corev1.ResourceCPU)).Should(Equal(int64(200)))
+			Expect(history[pods[0].Name][0].value(corev1.ResourceMemory)).Should(Equal(int64(512 * 1024 * 1024)))
+		})
+
+		It("recommend per instance template", func() {
+			recommend = recommendResources(context.Background(), newReader())
+			tree := mockTree(mockHistory())
+
+			_, err := recommend.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(vts.Status.Recommendations).Should(HaveLen(2))
+			Expect(meta.IsStatusConditionTrue(vts.Status.Conditions, string(experimentalv1alpha1.RecommendationProvided))).Should(BeTrue())
+
+			// P90 of the cpu is 1100m, P99 is 1200m, plus 15% margin and rounded up to 10m.
+			// the memory is 1Gi, plus 15% margin and rounded up to 1Mi.
+			for i, templateName := range []string{"", templateName} {
+				rec := vts.Status.Recommendations[i]
+				Expect(rec.TemplateName).Should(Equal(templateName))
+				Expect(rec.Samples).Should(Equal(int32(12)))
+				Expect(rec.Current.Requests.Cpu().String()).Should(Equal("500m"))
+				Expect(rec.Target.Requests.Cpu().String()).Should(Equal("1270m"))
+				Expect(rec.Target.Limits.Cpu().String()).Should(Equal("1380m"))
+				Expect(rec.Target.Requests.Memory().String()).Should(Equal("1178Mi"))
+				Expect(rec.Target.Limits.Memory().String()).Should(Equal("1178Mi"))
+			}
+
+			By("the recommendations are not applied in Off mode")
+			Expect(scaleOps(tree)).Should(BeNil())
+			cond := meta.FindStatusCondition(vts.Status.Conditions, string(experimentalv1alpha1.AbleToScale))
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Reason).Should(Equal(experimentalv1alpha1.ReasonUpdateModeOff))
+		})
+
+		It("restricted by the max allowed", func() {
+			vts.Spec.ControlledResources = []corev1.ResourceName{corev1.ResourceCPU}
+			vts.Spec.MaxAllowed = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+			recommend = recommendResources(context.Background(), newReader())
+			tree := mockTree(mockHistory())
+
+			_, err := recommend.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			rec := vts.Status.Recommendations[0]
+			Expect(rec.Target.Requests.Cpu().String()).Should(Equal("1"))
+			Expect(rec.Target.Limits.Cpu().String()).Should(Equal("1"))
+			// the memory is not controlled
+			Expect(rec.Target.Requests.Memory().String()).Should(Equal("1Gi"))
+		})
+
+		It("apply the recommendations in Auto mode", func() {
+			vts.Spec.UpdateMode = experimentalv1alpha1.VerticalScalerUpdateModeAuto
+			recommend = recommendResources(context.Background(), newReader())
+			tree := mockTree(mockHistory())
+
+			_, err := recommend.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			ops := scaleOps(tree)
+			Expect(ops).ShouldNot(BeNil())
+			Expect(vts.Status.OpsRequestName).Should(Equal(ops.Name))
+			Expect(ops.Spec.Type).Should(Equal(opsv1alpha1.VerticalScalingType))
+			Expect(ops.Spec.VerticalScalingList).Should(HaveLen(1))
+			vs := ops.Spec.VerticalScalingList[0]
+			Expect(vs.ComponentName).Should(Equal(compName))
+			Expect(vs.Requests.Cpu().String()).Should(Equal("1270m"))
+			Expect(vs.Instances).Should(HaveLen(1))
+			Expect(vs.Instances[0].Name).Should(Equal(templateName))
+			Expect(vs.Instances[0].Limits.Cpu().String()).Should(Equal("1380m"))
+			Expect(meta.IsStatusConditionTrue(vts.Status.Conditions, string(experimentalv1alpha1.AbleToScale))).Should(BeTrue())
+		})
+
+		It("out of the maintenance window", func() {
+			vts.Spec.UpdateMode = experimentalv1alpha1.VerticalScalerUpdateModeAuto
+			opening := time.Now().UTC().Add(2 * time.Hour)
+			vts.Spec.MaintenanceWindow = &appsv1.MaintenanceWindow{
+				CronExpression: fmt.Sprintf("%d %d * * *", opening.Minute(), opening.Hour()),
+				Duration:       metav1.Duration{Duration: time.Hour},
+			}
+			recommend = recommendResources(context.Background(), newReader())
+			tree := mockTree(mockHistory())
+
+			_, err := recommend.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(scaleOps(tree)).Should(BeNil())
+			cond := meta.FindStatusCondition(vts.Status.Conditions, string(experimentalv1alpha1.AbleToScale))
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).Should(Equal(experimentalv1alpha1.ReasonOutOfMaintenanceWindow))
+		})
+
+		It("insignificant changes", func() {
+			vts.Spec.UpdateMode = experimentalv1alpha1.VerticalScalerUpdateModeAuto
+			vts.Spec.MinChangePercent = 200
+			recommend = recommendResources(context.Background(), newReader())
+			tree := mockTree(mockHistory())
+
+			_, err := recommend.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(scaleOps(tree)).Should(BeNil())
+		})
+	})
+
+	Context("helpers", func() {
+		It("instance template of the instance", func() {
+			templates := []appsv1.InstanceTemplate{{Name: "large"}, {Name: "x-1"}}
+			for instName, expected := range map[string]string{
+				"foo-bar-0":       "",
+				"foo-bar-12":      "",
+				"foo-bar-large-1": "large",
+				"foo-bar-x-1-0":   "x-1",
+			} {
+				templateName, ok := instanceTemplateOf("foo-bar", templates, instName)
+				Expect(ok).Should(BeTrue())
+				Expect(templateName).Should(Equal(expected))
+			}
+			for _, instName := range []string{"foo-baz-0", "foo-bar-small-0", "foo-bar-large"} {
+				_, ok := instanceTemplateOf("foo-bar", templates, instName)
+				Expect(ok).Should(BeFalse())
+			}
+		})
+
+		It("usage history", func() {
+			now := time.Now()
+			usage := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+			history := usageHistory{}
+			history.record("pod-0", now.Add(-2*time.Hour), usage, verticalScalerSyncPeriod)
+			history.record("pod-0", now.Add(-time.Minute), usage, verticalScalerSyncPeriod)
+			history.record("pod-0", now, usage, verticalScalerSyncPeriod)
+			history.record("pod-1", now.Add(-2*time.Hour), usage, verticalScalerSyncPeriod)
+			// the sample within the interval is skipped
+			Expect(history["pod-0"]).Should(HaveLen(2))
+
+			history.prune(now.Add(-time.Hour))
+			Expect(history).Should(HaveLen(1))
+			Expect(history["pod-0"]).Should(HaveLen(1))
+
+			data, err := history.encode()
+			Expect(err).Should(BeNil())
+			var samples []usageSample
+			Expect(json.Unmarshal([]byte(data["pod-0"]), &samples)).Should(Succeed())
+			Expect(samples).Should(Equal(history["pod-0"]))
+			Expect(loadUsageHistory(builder.NewConfigMapBuilder(namespace, name).SetData(data).GetObject())).Should(Equal(history))
+		})
+
+		It("usage history - sample interval", func() {
+			window := 14 * 24 * time.Hour
+			Expect(sampleInterval(time.Hour, 3)).Should(Equal(verticalScalerSyncPeriod))
+			Expect(sampleInterval(window, 0)).Should(Equal(verticalScalerSyncPeriod))
+			for _, instances := range []int{10, 100, 1000} {
+				interval := sampleInterval(window, instances)
+				Expect(int(window/interval) * instances).Should(BeNumerically("<=", maxUsageSamples))
+			}
+		})
+
+		It("usage history - bounded size", func() {
+			now := time.Now()
+			window := 14 * 24 * time.Hour
+			history := usageHistory{}
+			// the samples recorded densely before the instances are scaled out
+			for t := now.Add(-window); t.Before(now); t = t.Add(verticalScalerSyncPeriod) {
+				history.record("pod-0", t, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}, verticalScalerSyncPeriod)
+			}
+			history["pod-0"][10][1] = 5000
+			latest := history["pod-0"][len(history["pod-0"])-1]
+			for i := 1; i < 100; i++ {
+				history.record(fmt.Sprintf("pod-%d", i), now, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}, verticalScalerSyncPeriod)
+			}
+
+			history.downsample()
+			total := 0
+			for _, samples := range history {
+				total += len(samples)
+			}
+			Expect(total).Should(BeNumerically("<=", maxUsageSamples))
+			samples := history["pod-0"]
+			Expect(samples[len(samples)-1]).Should(Equal(latest))
+			// the peak usage is kept
+			Expect(slices.ContainsFunc(samples, func(s usageSample) bool { return s[1] == 5000 })).Should(BeTrue())
+
+			data, err := history.encode()
+			Expect(err).Should(BeNil())
+			size := 0
+			for k, v := range data {
+				size += len(k) + len(v)
+			}
+			Expect(size).Should(BeNumerically("<", 1024*1024))
+		})
+	})
+})
@@ -0,0 +1,297 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	opsutil "github.com/apecloud/kubeblocks/pkg/operations/util"
+)
+
+const (
+	// the interval to read the metrics and re-calculate the desired replicas
+	metricsScalerSyncPeriod = 15 * time.Second
+
+	// the ratio of the current metric value to the target value within the tolerance will not trigger scaling
+	metricsScalerTolerance = 0.1
+
+	defaultScaleUpStabilizationWindowSeconds   = 0
+	defaultScaleDownStabilizationWindowSeconds = 300
+)
+
+type scaleByMetricsReconciler struct {
+	collector *metricsCollector
+}
+
+func (r *scaleByMetricsReconciler) PreCondition(tree *kubebuilderx.ObjectTree) *kubebuilderx.CheckResult {
+	if tree.GetRoot() == nil || model.IsObjectDeleting(tree.GetRoot()) {
+		return kubebuilderx.ConditionUnsatisfied
+	}
+	return kubebuilderx.ConditionSatisfied
+}
+
+func (r *scaleByMetricsReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilderx.Result, error) {
+	scaler, _ := tree.GetRoot().(*experimental.MetricsScaler)
+	clusterKey := builder.NewClusterBuilder(scaler.Namespace, scaler.Spec.TargetClusterName).GetObject()
+	object, err := tree.Get(clusterKey)
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+	cluster, _ := object.(*appsv1.Cluster)
+	compSpec := cluster.Spec.GetComponentByName(scaler.Spec.TargetComponentName)
+	if compSpec == nil {
+		return kubebuilderx.Continue, fmt.Errorf("component %s not found in cluster %s", scaler.Spec.TargetComponentName, cluster.Name)
+	}
+	currentReplicas := compSpec.Replicas
+	scaler.Status.CurrentReplicas = currentReplicas
+	if currentReplicas == 0 {
+		setScalerCondition(scaler, experimental.ScalingActive, metav1.ConditionFalse,
+			experimental.ReasonScalingDisabled, "scaling is disabled since the replicas of the component is zero")
+		return kubebuilderx.RetryAfter(metricsScalerSyncPeriod), nil
+	}
+
+	var pods []*corev1.Pod
+	for _, obj := range tree.List(&corev1.Pod{}) {
+		pods = append(pods, obj.(*corev1.Pod))
+	}
+	desiredReplicas, err := r.computeReplicas(scaler, currentReplicas, pods)
+	if err != nil {
+		setScalerCondition(scaler, experimental.ScalingActive, metav1.ConditionFalse, experimental.ReasonFailedGetMetrics, err.Error())
+		return kubebuilderx.RetryAfter(metricsScalerSyncPeriod), nil
+	}
+	setScalerCondition(scaler, experimental.ScalingActive, metav1.ConditionTrue, experimental.ReasonValidMetricFound,
+		"the scaler is able to calculate the desired replicas from the metrics")
+
+	minReplicas, maxReplicas := replicasRange(scaler, compDefOf(tree))
+	desiredReplicas = max(minReplicas, min(maxReplicas, desiredReplicas))
+	desiredReplicas = stabilizeReplicas(scaler, currentReplicas, desiredReplicas, time.Now())
+	scaler.Status.DesiredReplicas = desiredReplicas
+	if desiredReplicas == currentReplicas {
+		setScalerCondition(scaler, experimental.AbleToScale, metav1.ConditionTrue, experimental.ReasonReadyForNewScale,
+			"the recommended replicas is the same as the current replicas")
+		return kubebuilderx.RetryAfter(metricsScalerSyncPeriod), nil
+	}
+
+	if msg := scaleInProgress(tree, cluster); len(msg) > 0 {
+		setScalerCondition(scaler, experimental.AbleToScale, metav1.ConditionFalse, experimental.ReasonScaleInProgress, msg)
+		return kubebuilderx.RetryAfter(metricsScalerSyncPeriod), nil
+	}
+
+	ops := buildHorizontalScalingOps(scaler, currentReplicas, desiredReplicas)
+	if err = tree.Add(ops); err != nil {
+		return kubebuilderx.Continue, err
+	}
+	scaler.Status.OpsRequestName = ops.Name
+	scaler.Status.LastScaleTime = metav1.Time{Time: time.Now()}
+	setScalerCondition(scaler, experimental.AbleToScale, metav1.ConditionTrue, experimental.ReasonReadyForNewScale,
+		fmt.Sprintf("the component is scaled from %d to %d replicas", currentReplicas, desiredReplicas))
+	if tree.EventRecorder != nil {
+		tree.EventRecorder.Eventf(scaler, corev1.EventTypeNormal, "Scaling",
+			"OpsRequest %s is created to scale the component %s from %d to %d replicas",
+			ops.Name, scaler.Spec.TargetComponentName, currentReplicas, desiredReplicas)
+	}
+
+	return kubebuilderx.RetryAfter(metricsScalerSyncPeriod), nil
+}
+
+// computeReplicas calculates the desired replicas from all metrics, the largest one will be taken.
+func (r *scaleByMetricsReconciler) computeReplicas(scaler *experimental.MetricsScaler, currentReplicas int32, pods []*corev1.Pod) (int32, error) {
+	var (
+		desiredReplicas int32
+		statuses        []experimental.MetricStatus
+		errs            []string
+	)
+	for _, metric := range scaler.Spec.Metrics {
+		status, ratio, err := r.collector.collect(scaler, pods, metric)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		statuses = append(statuses, *status)
+		replicas := currentReplicas
+		if math.Abs(ratio-1.0) > metricsScalerTolerance {
+			replicas = int32(math.Ceil(ratio * float64(currentReplicas)))
+		}
+		desiredReplicas = max(desiredReplicas, replicas)
+	}
+	scaler.Status.CurrentMetrics = statuses
+	if len(errs) > 0 {
+		// don't scale if any metric is unavailable, to avoid scaling down by the partial metrics
+		return 0, fmt.Errorf("failed to get metrics: %s", strings.Join(errs, "; "))
+	}
+	return desiredReplicas, nil
+}
+
+func compDefOf(tree *kubebuilderx.ObjectTree) *appsv1.ComponentDefinition {
+	for _, obj := range tree.List(&appsv1.ComponentDefinition{}) {
+		return obj.(*appsv1.ComponentDefinition)
+	}
+	return nil
+}
+
+// replicasRange returns the range of replicas the scaler can scale in, restricted by the replicas limit of the component definition.
+func replicasRange(scaler *experimental.MetricsScaler, compDef *appsv1.ComponentDefinition) (int32, int32) {
+	minReplicas, maxReplicas := int32(1), scaler.Spec.MaxReplicas
+	if scaler.Spec.MinReplicas != nil {
+		minReplicas = *scaler.Spec.MinReplicas
+	}
+	if compDef != nil && compDef.Spec.ReplicasLimit != nil {
+		minReplicas = max(minReplicas, compDef.Spec.ReplicasLimit.MinReplicas)
+		maxReplicas = min(maxReplicas, compDef.Spec.ReplicasLimit.MaxReplicas)
+	}
+	return minReplicas, max(minReplicas, maxReplicas)
+}
+
+// stabilizeReplicas records the recommendation and returns the stabilized replicas, it takes the smallest recommendation
+// in the scale-up window and the largest recommendation in the scale-down window to avoid flapping.
+func stabilizeReplicas(scaler *experimental.MetricsScaler, currentReplicas, desiredReplicas int32, now time.Time) int32 {
+	upWindow := time.Duration(defaultScaleUpStabilizationWindowSeconds) * time.Second
+	downWindow := time.Duration(defaultScaleDownStabilizationWindowSeconds) * time.Second
+	if behavior := scaler.Spec.Behavior; behavior != nil {
+		if behavior.ScaleUp != nil && behavior.ScaleUp.StabilizationWindowSeconds != nil {
+			upWindow = time.Duration(*behavior.ScaleUp.StabilizationWindowSeconds) * time.Second
+		}
+		if behavior.ScaleDown != nil && behavior.ScaleDown.StabilizationWindowSeconds != nil {
+			downWindow = time.Duration(*behavior.ScaleDown.StabilizationWindowSeconds) * time.Second
+		}
+	}
+
+	recommendations := []experimental.ScaleRecommendation{
+		{Timestamp: metav1.Time{Time: now}, Replicas: desiredReplicas},
+	}
+	upRecommendation, downRecommendation := desiredReplicas, desiredReplicas
+	for _, rec := range scaler.Status.Recommendations {
+		inUpWindow := rec.Timestamp.Time.After(now.Add(-upWindow))
+		inDownWindow := rec.Timestamp.Time.After(now.Add(-downWindow))
+		if inUpWindow {
+			upRecommendation = min(upRecommendation, rec.Replicas)
+		}
+		if inDownWindow {
+			downRecommendation = max(downRecommendation, rec.Replicas)
+		}
+		if inUpWindow || inDownWindow {
+			recommendations = append(recommendations, rec)
+		}
+	}
+	scaler.Status.Recommendations = recommendations
+
+	stabilizedReplicas := currentReplicas
+	if stabilizedReplicas < upRecommendation {
+		stabilizedReplicas = upRecommendation
+	}
+	if stabilizedReplicas > downRecommendation {
+		stabilizedReplicas = downRecommendation
+	}
+	return stabilizedReplicas
+}
+
+// scaleInProgress checks whether the component is being scaled or updated, it returns the reason if so.
+func scaleInProgress(tree *kubebuilderx.ObjectTree, cluster *appsv1.Cluster) string {
+	for _, obj := range tree.List(&opsv1alpha1.OpsRequest{}) {
+		ops, _ := obj.(*opsv1alpha1.OpsRequest)
+		if !ops.IsComplete() {
+			return fmt.Sprintf("the %s OpsRequest %s is in progress", ops.Spec.Type, ops.Name)
+		}
+	}
+	opsRecorders, err := opsutil.GetOpsRequestSliceFromCluster(cluster)
+	if err != nil {
+		return err.Error()
+	}
+	if len(opsRecorders) >= opsutil.OpsRequestQueueLimitSize {
+		return "the OpsRequest queue of the cluster is full"
+	}
+	for _, obj := range tree.List(&appsv1.Component{}) {
+		comp, _ := obj.(*appsv1.Component)
+		if comp.Status.Phase != appsv1.RunningComponentPhase {
+			return fmt.Sprintf("the component is %s", comp.Status.Phase)
+		}
+	}
+	return ""
+}
+
+func buildHorizontalScalingOps(scaler *experimental.MetricsScaler, currentReplicas, desiredReplicas int32) *opsv1alpha1.OpsRequest {
+	horizontalScaling := opsv1alpha1.HorizontalScaling{
+		ComponentOps: opsv1alpha1.ComponentOps{ComponentName: scaler.Spec.TargetComponentName},
+	}
+	if desiredReplicas > currentReplicas {
+		changes := desiredReplicas - currentReplicas
+		horizontalScaling.ScaleOut = &opsv1alpha1.ScaleOut{
+			ReplicaChanger: opsv1alpha1.ReplicaChanger{ReplicaChanges: &changes},
+		}
+	} else {
+		changes := currentReplicas - desiredReplicas
+		horizontalScaling.ScaleIn = &opsv1alpha1.ScaleIn{
+			ReplicaChanger: opsv1alpha1.ReplicaChanger{ReplicaChanges: &changes},
+		}
+	}
+	return &opsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: scaler.Namespace,
+			Name:      fmt.Sprintf("%s-%s", scaler.Name, time.Now().Format("20060102150405")),
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:    scaler.Spec.TargetClusterName,
+				constant.KBAppComponentLabelKey: scaler.Spec.TargetComponentName,
+				constant.OpsRequestTypeLabelKey: string(opsv1alpha1.HorizontalScalingType),
+			},
+		},
+		Spec: opsv1alpha1.OpsRequestSpec{
+			ClusterName: scaler.Spec.TargetClusterName,
+			Type:        opsv1alpha1.HorizontalScalingType,
+			SpecificOpsRequest: opsv1alpha1.SpecificOpsRequest{
+				HorizontalScalingList: []opsv1alpha1.HorizontalScaling{horizontalScaling},
+			},
+		},
+	}
+}
+
+func setScalerCondition(scaler *experimental.MetricsScaler, conditionType experimental.ConditionType,
+	status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&scaler.Status.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             status,
+		ObservedGeneration: scaler.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func scaleByMetrics(ctx context.Context, reader client.Reader) kubebuilderx.Reconciler {
+	return &scaleByMetricsReconciler{
+		collector: &metricsCollector{ctx: ctx, reader: reader},
+	}
+}
+
+var _ kubebuilderx.Reconciler = &scaleByMetricsReconciler{}
@@ -0,0 +1,396 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	experimentalv1alpha1 "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	kbagentservice "github.com/apecloud/kubeblocks/pkg/kbagent/service"
+)
+
+var _ = Describe("scale by metrics reconciler test", func() {
+	const (
+		compName = "bar"
+		probe    = "connections"
+	)
+
+	var (
+		mts   *experimentalv1alpha1.MetricsScaler
+		pods  []*corev1.Pod
+		comp  *appsv1.Component
+		scale kubebuilderx.Reconciler
+	)
+
+	newReader := func(objs ...client.Object) client.Reader {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+		Expect(metricsv1beta1.AddToScheme(scheme)).Should(Succeed())
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithIndex(&corev1.Event{}, "reason", func(obj client.Object) []string {
+				return []string{obj.(*corev1.Event).Reason}
+			}).
+			Build()
+	}
+
+	podMetrics := func(pod *corev1.Pod, cpu string) *metricsv1beta1.PodMetrics {
+		return &metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Labels:    pod.Labels,
+			},
+			Containers: []metricsv1beta1.ContainerMetrics{
+				{
+					Name:  "main",
+					Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+				},
+			},
+		}
+	}
+
+	// kbagentEvent builds the event as the kb-agent reports the probe event
+	kbagentEvent := func(pod *corev1.Pod, event *proto.ProbeEvent) *corev1.Event {
+		message, err := json.Marshal(event)
+		Expect(err).Should(BeNil())
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: pod.Namespace,
+				Name:      pod.Name + "." + event.Probe,
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Pod",
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				FieldPath: proto.ProbeEventFieldPath,
+			},
+			Reason:              event.Probe,
+			Message:             string(message),
+			EventTime:           metav1.NowMicro(),
+			ReportingController: proto.ProbeEventReportingController,
+		}
+	}
+
+	probeEvent := func(pod *corev1.Pod, output string) *corev1.Event {
+		return kbagentEvent(pod, &proto.ProbeEvent{Instance: comp.Name, Probe: probe, Output: []byte(output)})
+	}
+
+	mockTree := func(objs ...client.Object) *kubebuilderx.ObjectTree {
+		specs := []appsv1.ClusterComponentSpec{
+			{
+				Name:     compName,
+				Replicas: 2,
+			},
+		}
+		cluster := builder.NewClusterBuilder(namespace, clusterName).SetComponentSpecs(specs).GetObject()
+		compDef := &appsv1.ComponentDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-compdef",
+			},
+			Spec: appsv1.ComponentDefinitionSpec{
+				ReplicasLimit: &appsv1.ReplicasLimit{MinReplicas: 1, MaxReplicas: 4},
+			},
+		}
+
+		tree := kubebuilderx.NewObjectTree()
+		tree.SetRoot(mts)
+		Expect(tree.Add(cluster, comp, compDef)).Should(Succeed())
+		for _, pod := range pods {
+			Expect(tree.Add(pod)).Should(Succeed())
+		}
+		Expect(tree.Add(objs...)).Should(Succeed())
+		return tree
+	}
+
+	scaleOps := func(tree *kubebuilderx.ObjectTree) *opsv1alpha1.OpsRequest {
+		objs := tree.List(&opsv1alpha1.OpsRequest{})
+		if len(objs) == 0 {
+			return nil
+		}
+		return objs[len(objs)-1].(*opsv1alpha1.OpsRequest)
+	}
+
+	BeforeEach(func() {
+		mts = builder.NewMetricsScalerBuilder(namespace, name).
+			SetTargetClusterName(clusterName).
+			SetTargetComponentName(compName).
+			SetMaxReplicas(5).
+			AddMetrics(experimentalv1alpha1.MetricSpec{
+				Type: experimentalv1alpha1.ResourceMetricSourceType,
+				Resource: &experimentalv1alpha1.ResourceMetricSource{
+					Name:                     corev1.ResourceCPU,
+					TargetAverageUtilization: 80,
+				},
+			}).
+			GetObject()
+		comp = &appsv1.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      constant.GenerateClusterComponentName(clusterName, compName),
+			},
+			Status: appsv1.ComponentStatus{
+				Phase: appsv1.RunningComponentPhase,
+			},
+		}
+		pods = nil
+		for _, podName := range []string{"foo-bar-0", "foo-bar-1"} {
+			pods = append(pods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      podName,
+					Labels:    constant.GetCompLabels(clusterName, compName),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "main",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+							},
+						},
+					},
+				},
+			})
+		}
+	})
+
+	Context("PreCondition & Reconcile", func() {
+		It("scale out by resource metrics", func() {
+			scale = scaleByMetrics(context.Background(), newReader(podMetrics(pods[0], "1600m"), podMetrics(pods[1], "1600m")))
+			tree := mockTree()
+
+			By("PreCondition")
+			Expect(scale.PreCondition(tree)).Should(Equal(kubebuilderx.ConditionSatisfied))
+
+			By("Reconcile")
+			res, err := scale.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.RetryAfter(metricsScalerSyncPeriod)))
+			// 2 * 160 / 80 = 4, restricted by the replicas limit
+			Expect(mts.Status.CurrentReplicas).Should(Equal(int32(2)))
+			Expect(mts.Status.DesiredReplicas).Should(Equal(int32(4)))
+			Expect(mts.Status.CurrentMetrics).Should(HaveLen(1))
+			Expect(*mts.Status.CurrentMetrics[0].CurrentAverageUtilization).Should(Equal(int32(160)))
+			Expect(meta.IsStatusConditionTrue(mts.Status.Conditions, string(experimentalv1alpha1.ScalingActive))).Should(BeTrue())
+
+			ops := scaleOps(tree)
+			Expect(ops).ShouldNot(BeNil())
+			Expect(mts.Status.OpsRequestName).Should(Equal(ops.Name))
+			Expect(ops.Spec.Type).Should(Equal(opsv1alpha1.HorizontalScalingType))
+			Expect(ops.Spec.HorizontalScalingList).Should(HaveLen(1))
+			Expect(ops.Spec.HorizontalScalingList[0].ComponentName).Should(Equal(compName))
+			Expect(ops.Spec.HorizontalScalingList[0].ScaleOut).ShouldNot(BeNil())
+			Expect(*ops.Spec.HorizontalScalingList[0].ScaleOut.ReplicaChanges).Should(Equal(int32(2)))
+		})
+
+		It("scale in by probe metrics", func() {
+			mts.Spec.Metrics = []experimentalv1alpha1.MetricSpec{
+				{
+					Type: experimentalv1alpha1.ProbeMetricSourceType,
+					Probe: &experimentalv1alpha1.ProbeMetricSource{
+						Name:               probe,
+						TargetAverageValue: resource.MustParse("100"),
+					},
+				},
+			}
+			mts.Spec.Behavior = &experimentalv1alpha1.ScalingBehavior{
+				ScaleDown: &experimentalv1alpha1.ScalingRules{StabilizationWindowSeconds: ptr.To(int32(0))},
+			}
+			scale = scaleByMetrics(context.Background(), newReader(probeEvent(pods[0], "20"), probeEvent(pods[1], "40\n")))
+			tree := mockTree()
+
+			_, err := scale.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(mts.Status.DesiredReplicas).Should(Equal(int32(1)))
+			Expect(mts.Status.CurrentMetrics[0].CurrentAverageValue.Cmp(resource.MustParse("30"))).Should(Equal(0))
+
+			ops := scaleOps(tree)
+			Expect(ops).ShouldNot(BeNil())
+			Expect(ops.Spec.HorizontalScalingList[0].ScaleIn).ShouldNot(BeNil())
+			Expect(*ops.Spec.HorizontalScalingList[0].ScaleIn.ReplicaChanges).Should(Equal(int32(1)))
+		})
+
+		It("scale out by the custom probe of kb-agent", func() {
+			mts.Spec.Metrics = []experimentalv1alpha1.MetricSpec{
+				{
+					Type: experimentalv1alpha1.ProbeMetricSourceType,
+					Probe: &experimentalv1alpha1.ProbeMetricSource{
+						Name:               probe,
+						TargetAverageValue: resource.MustParse("100"),
+					},
+				},
+			}
+
+			By("run the custom probe by the kb-agent")
+			services, err := kbagentservice.New(logr.Discard(), []proto.Action{
+				{
+					Name: probe,
+					Exec: &proto.ExecAction{Commands: []string{"/bin/sh", "-c", "echo 300"}},
+				},
+			}, []proto.Probe{
+				{
+					Instance:      comp.Name,
+					Action:        probe,
+					PeriodSeconds: 1,
+				},
+			}, nil, nil)
+			Expect(err).Should(BeNil())
+			var reported *proto.ProbeEvent
+			for _, svc := range services {
+				if svc.Kind() != proto.ServiceProbe.Kind {
+					continue
+				}
+				Expect(svc.Start()).Should(Succeed())
+				Eventually(func(g Gomega) {
+					reported, err = svc.(kbagentservice.ProbeHandler).LatestProbeEvent(probe)
+					g.Expect(err).Should(BeNil())
+					g.Expect(reported.Code).Should(BeEquivalentTo(0))
+				}).WithTimeout(5 * time.Second).Should(Succeed())
+			}
+			Expect(reported).ShouldNot(BeNil())
+
+			By("scale by the probe events reported")
+			scale = scaleByMetrics(context.Background(), newReader(kbagentEvent(pods[0], reported), kbagentEvent(pods[1], reported)))
+			tree := mockTree()
+
+			_, err = scale.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(mts.Status.CurrentMetrics).Should(HaveLen(1))
+			Expect(mts.Status.CurrentMetrics[0].CurrentAverageValue.Cmp(resource.MustParse("300"))).Should(Equal(0))
+			// 2 * 300 / 100 = 6, restricted by the replicas limit
+			Expect(mts.Status.DesiredReplicas).Should(Equal(int32(4)))
+
+			ops := scaleOps(tree)
+			Expect(ops).ShouldNot(BeNil())
+			Expect(ops.Spec.HorizontalScalingList[0].ScaleOut).ShouldNot(BeNil())
+			Expect(*ops.Spec.HorizontalScalingList[0].ScaleOut.ReplicaChanges).Should(Equal(int32(2)))
+		})
+
+		It("within tolerance", func() {
+			scale = scaleByMetrics(context.Background(), newReader(podMetrics(pods[0], "820m"), podMetrics(pods[1], "800m")))
+			tree := mockTree()
+
+			_, err := scale.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(mts.Status.DesiredReplicas).Should(Equal(int32(2)))
+			Expect(scaleOps(tree)).Should(BeNil())
+			Expect(meta.IsStatusConditionTrue(mts.Status.Conditions, string(experimentalv1alpha1.AbleToScale))).Should(BeTrue())
+		})
+
+		It("metrics unavailable", func() {
+			scale = scaleByMetrics(context.Background(), newReader())
+			tree := mockTree()
+
+			_, err := scale.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(scaleOps(tree)).Should(BeNil())
+			cond := meta.FindStatusCondition(mts.Status.Conditions, string(experimentalv1alpha1.ScalingActive))
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).Should(Equal(experimentalv1alpha1.ReasonFailedGetMetrics))
+		})
+
+		It("scale in progress", func() {
+			running := &opsv1alpha1.OpsRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      "running",
+				},
+				Status: opsv1alpha1.OpsRequestStatus{
+					Phase: opsv1alpha1.OpsRunningPhase,
+				},
+			}
+			scale = scaleByMetrics(context.Background(), newReader(podMetrics(pods[0], "1600m"), podMetrics(pods[1], "1600m")))
+			tree := mockTree(running)
+
+			_, err := scale.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(scaleOps(tree).Name).Should(Equal(running.Name))
+			cond := meta.FindStatusCondition(mts.Status.Conditions, string(experimentalv1alpha1.AbleToScale))
+			Expect(cond).ShouldNot(BeNil())
+			Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).Should(Equal(experimentalv1alpha1.ReasonScaleInProgress))
+		})
+	})
+
+	Context("stabilization", func() {
+		It("should avoid flapping", func() {
+			now := time.Now()
+			mts.Spec.Behavior = &experimentalv1alpha1.ScalingBehavior{
+				ScaleUp:   &experimentalv1alpha1.ScalingRules{StabilizationWindowSeconds: ptr.To(int32(60))},
+				ScaleDown: &experimentalv1alpha1.ScalingRules{StabilizationWindowSeconds: ptr.To(int32(300))},
+			}
+			mts.Status.Recommendations = []experimentalv1alpha1.ScaleRecommendation{
+				{Timestamp: metav1.Time{Time: now.Add(-30 * time.Second)}, Replicas: 3},
+				{Timestamp: metav1.Time{Time: now.Add(-120 * time.Second)}, Replicas: 5},
+				{Timestamp: metav1.Time{Time: now.Add(-600 * time.Second)}, Replicas: 1},
+			}
+
+			By("scale down is held by the largest recommendation in the window")
+			Expect(stabilizeReplicas(mts, 5, 2, now)).Should(Equal(int32(5)))
+			// the expired recommendation is pruned
+			Expect(mts.Status.Recommendations).Should(HaveLen(3))
+
+			By("scale up is held by the smallest recommendation in the window")
+			Expect(stabilizeReplicas(mts, 2, 6, now)).Should(Equal(int32(2)))
+		})
+
+		It("replicas range", func() {
+			compDef := &appsv1.ComponentDefinition{
+				Spec: appsv1.ComponentDefinitionSpec{
+					ReplicasLimit: &appsv1.ReplicasLimit{MinReplicas: 2, MaxReplicas: 4},
+				},
+			}
+			minReplicas, maxReplicas := replicasRange(mts, compDef)
+			Expect(minReplicas).Should(Equal(int32(2)))
+			Expect(maxReplicas).Should(Equal(int32(4)))
+
+			mts.Spec.MinReplicas = ptr.To(int32(3))
+			minReplicas, maxReplicas = replicasRange(mts, nil)
+			Expect(minReplicas).Should(Equal(int32(3)))
+			Expect(maxReplicas).Should(Equal(int32(5)))
+		})
+	})
+})
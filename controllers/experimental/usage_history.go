@@ -0,0 +1,143 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+)
+
+// usageSample is a sample of the resource usage of the main container of an instance,
+// encoded as [unix timestamp in seconds, cpu in millicores, memory in bytes].
+type usageSample [3]int64
+
+func (s usageSample) time() time.Time {
+	return time.Unix(s[0], 0)
+}
+
+func (s usageSample) value(name corev1.ResourceName) int64 {
+	if name == corev1.ResourceCPU {
+		return s[1]
+	}
+	return s[2]
+}
+
+// usageHistory holds the usage samples of the instances, keyed by the instance name.
+// It is persisted in a ConfigMap owned by the scaler, since it is too large to be kept in the status.
+// The total number of samples is bounded by maxUsageSamples to keep the ConfigMap far below its size limit.
+type usageHistory map[string][]usageSample
+
+// maxUsageSamples is the max number of samples of all instances, each sample takes about 32 bytes when encoded.
+const maxUsageSamples = 16384
+
+// sampleInterval returns the interval to sample the usage of each instance, it is lengthened to cover
+// the whole history window with the samples shared by all the instances.
+func sampleInterval(window time.Duration, instances int) time.Duration {
+	samples := maxUsageSamples / max(1, instances)
+	return max(verticalScalerSyncPeriod, window/time.Duration(max(1, samples)))
+}
+
+func usageHistoryName(scaler *experimental.VerticalScaler) string {
+	return scaler.Name + "-usage-history"
+}
+
+// loadUsageHistory decodes the usage history from the ConfigMap, malformed entries are dropped.
+func loadUsageHistory(cm *corev1.ConfigMap) usageHistory {
+	history := usageHistory{}
+	if cm == nil {
+		return history
+	}
+	for name, data := range cm.Data {
+		var samples []usageSample
+		if err := json.Unmarshal([]byte(data), &samples); err == nil {
+			history[name] = samples
+		}
+	}
+	return history
+}
+
+func (h usageHistory) encode() (map[string]string, error) {
+	data := make(map[string]string, len(h))
+	for name, samples := range h {
+		out, err := json.Marshal(samples)
+		if err != nil {
+			return nil, err
+		}
+		data[name] = string(out)
+	}
+	return data, nil
+}
+
+// record appends a sample of the instance, unless the last sample was taken within the interval.
+func (h usageHistory) record(name string, now time.Time, usage corev1.ResourceList, interval time.Duration) {
+	samples := h[name]
+	if len(samples) > 0 && now.Sub(samples[len(samples)-1].time()) < interval {
+		return
+	}
+	h[name] = append(samples, usageSample{now.Unix(), usage.Cpu().MilliValue(), usage.Memory().Value()})
+}
+
+// prune removes the samples taken before the time, and the instances without any sample left.
+func (h usageHistory) prune(since time.Time) {
+	for name, samples := range h {
+		var kept []usageSample
+		for _, sample := range samples {
+			if !sample.time().Before(since) {
+				kept = append(kept, sample)
+			}
+		}
+		if len(kept) == 0 {
+			delete(h, name)
+		} else {
+			h[name] = kept
+		}
+	}
+}
+
+// downsample merges the adjacent samples of each instance, until the total number of samples fits maxUsageSamples.
+// The merged sample takes the peak usage, so the recommendations are not underestimated.
+func (h usageHistory) downsample() {
+	if len(h) == 0 {
+		return
+	}
+	limit := max(1, maxUsageSamples/len(h))
+	for name, samples := range h {
+		if len(samples) <= limit {
+			continue
+		}
+		size := (len(samples) + limit - 1) / limit
+		merged := make([]usageSample, 0, limit)
+		// merge from the latest sample, to keep the latest one intact as far as possible.
+		for end := len(samples); end > 0; end -= size {
+			peak := samples[end-1]
+			for _, sample := range samples[max(0, end-size) : end-1] {
+				peak[1], peak[2] = max(peak[1], sample[1]), max(peak[2], sample[2])
+			}
+			merged = append(merged, peak)
+		}
+		slices.Reverse(merged)
+		h[name] = merged
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+)
+
+type verticalScalerTreeLoader struct{}
+
+func (t *verticalScalerTreeLoader) Load(ctx context.Context, reader client.Reader, req ctrl.Request, recorder record.EventRecorder, logger logr.Logger) (*kubebuilderx.ObjectTree, error) {
+	tree, err := kubebuilderx.ReadObjectTree[*experimental.VerticalScaler](ctx, reader, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	root := tree.GetRoot()
+	if root == nil {
+		return tree, nil
+	}
+	scaler, _ := root.(*experimental.VerticalScaler)
+	key := types.NamespacedName{Namespace: scaler.Namespace, Name: scaler.Spec.TargetClusterName}
+	cluster := &appsv1.Cluster{}
+	if err = reader.Get(ctx, key, cluster); err != nil {
+		return nil, err
+	}
+	if err = tree.Add(cluster); err != nil {
+		return nil, err
+	}
+
+	key.Name = constant.GenerateClusterComponentName(scaler.Spec.TargetClusterName, scaler.Spec.TargetComponentName)
+	comp := &appsv1.Component{}
+	if err = reader.Get(ctx, key, comp); err != nil {
+		return nil, err
+	}
+	if err = tree.Add(comp); err != nil {
+		return nil, err
+	}
+
+	key.Name = usageHistoryName(scaler)
+	history := &corev1.ConfigMap{}
+	if err = reader.Get(ctx, key, history); err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err = tree.Add(history); err != nil {
+			return nil, err
+		}
+	}
+
+	inNS := client.InNamespace(scaler.Namespace)
+	podList := &corev1.PodList{}
+	labels := constant.GetCompLabels(scaler.Spec.TargetClusterName, scaler.Spec.TargetComponentName)
+	if err = reader.List(ctx, podList, inNS, client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+	for i := range podList.Items {
+		if err = tree.Add(&podList.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	opsList := &opsv1alpha1.OpsRequestList{}
+	labels = map[string]string{
+		constant.AppInstanceLabelKey:    scaler.Spec.TargetClusterName,
+		constant.OpsRequestTypeLabelKey: string(opsv1alpha1.VerticalScalingType),
+	}
+	if err = reader.List(ctx, opsList, inNS, client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+	for i := range opsList.Items {
+		if err = tree.Add(&opsList.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	tree.EventRecorder = recorder
+	tree.Logger = logger
+
+	return tree, nil
+}
+
+func verticalScalerTree() kubebuilderx.TreeLoader {
+	return &verticalScalerTreeLoader{}
+}
+
+var _ kubebuilderx.TreeLoader = &verticalScalerTreeLoader{}
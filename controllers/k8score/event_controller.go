@@ -83,6 +83,7 @@ func (r *EventReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		&instanceset.PodRoleEventHandler{},
 		&component.AvailableEventHandler{},
 		&component.KBAgentTaskEventHandler{},
+		&component.VolumeProtectionEventHandler{},
 	}
 	for _, handler := range handlers {
 		if err := handler.Handle(r.Client, reqCtx, r.Recorder, event); err != nil && !apierrors.IsNotFound(err) {
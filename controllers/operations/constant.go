@@ -30,3 +30,12 @@ const (
 	reasonOpsReconcileStatusFailed    = "ReconcileStatusFailed"
 	reasonOpsDoActionFailed           = "DoActionFailed"
 )
+
+const (
+	reasonInvalidSchedule      = "InvalidSchedule"
+	reasonOpsRequestCreated    = "OpsRequestCreated"
+	reasonOpsRequestCreateFail = "OpsRequestCreateFailed"
+	reasonOpsRequestCanceled   = "OpsRequestCanceled"
+	reasonRunSkipped           = "RunSkipped"
+	reasonTooManyMissedRuns    = "TooManyMissedRuns"
+)
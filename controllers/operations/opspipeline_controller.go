@@ -0,0 +1,393 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// OpsPipelineReconciler reconciles a OpsPipeline object
+type OpsPipelineReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opspipelines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opspipelines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opspipelines/finalizers,verbs=update
+
+func (r *OpsPipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      ctx,
+		Req:      req,
+		Log:      log.FromContext(ctx).WithValues("opsPipeline", req.NamespacedName),
+		Recorder: r.Recorder,
+	}
+
+	pipeline := &opsv1alpha1.OpsPipeline{}
+	if err := r.Client.Get(reqCtx.Ctx, reqCtx.Req.NamespacedName, pipeline); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	// the OpsRequests created are garbage collected along with the owner.
+	if !pipeline.DeletionTimestamp.IsZero() || pipeline.IsComplete() {
+		return intctrlutil.Reconciled()
+	}
+
+	opsList := &opsv1alpha1.OpsRequestList{}
+	if err := r.Client.List(reqCtx.Ctx, opsList, client.InNamespace(pipeline.Namespace),
+		client.MatchingLabels{constant.OpsPipelineLabelKey: pipeline.Name}); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+
+	oldPipeline := pipeline.DeepCopy()
+	syncOpsPipelineStepStatus(pipeline, opsList.Items)
+	reconcileErr := r.reconcile(reqCtx, pipeline)
+	if !apiequality.Semantic.DeepEqual(oldPipeline.Status, pipeline.Status) {
+		if err := r.Client.Status().Patch(reqCtx.Ctx, pipeline, client.MergeFrom(oldPipeline)); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		if oldPipeline.Status.Phase != pipeline.Status.Phase {
+			r.recordPhaseEvent(pipeline)
+		}
+	}
+	if reconcileErr != nil {
+		return intctrlutil.CheckedRequeueWithError(reconcileErr, reqCtx.Log, "")
+	}
+	return intctrlutil.Reconciled()
+}
+
+func (r *OpsPipelineReconciler) reconcile(reqCtx intctrlutil.RequestCtx, pipeline *opsv1alpha1.OpsPipeline) error {
+	if pipeline.Status.Phase == "" {
+		pipeline.Status.Phase = opsv1alpha1.OpsPipelinePendingPhase
+		pipeline.Status.StartTimestamp = metav1.Now()
+	}
+	pipeline.Status.Progress = fmt.Sprintf("%d/%d", len(completedOpsPipelineSteps(pipeline.Status.Steps)), len(pipeline.Spec.Steps))
+
+	if err := validateOpsPipeline(pipeline); err != nil {
+		completeOpsPipeline(pipeline, opsv1alpha1.OpsPipelineFailedPhase, err.Error())
+		return nil
+	}
+	if pipeline.Status.Phase == opsv1alpha1.OpsPipelineRollingBackPhase {
+		return r.rollback(reqCtx, pipeline)
+	}
+
+	plan := planOpsPipeline(pipeline)
+	switch {
+	case plan.failedStep != nil && plan.running > 0:
+		// wait for the running steps to complete.
+		return nil
+	case plan.failedStep != nil && plan.failedStep.FailurePolicy == opsv1alpha1.RollbackOnFailure:
+		pipeline.Status.Compensations = buildOpsPipelineCompensations(pipeline)
+		if len(pipeline.Status.Compensations) == 0 {
+			completeOpsPipeline(pipeline, opsv1alpha1.OpsPipelineFailedPhase,
+				fmt.Sprintf("the step %s failed, and no step has a compensation to roll back", plan.failedStep.Name))
+			return nil
+		}
+		pipeline.Status.Phase = opsv1alpha1.OpsPipelineRollingBackPhase
+		pipeline.Status.Message = fmt.Sprintf("the step %s failed", plan.failedStep.Name)
+		return r.rollback(reqCtx, pipeline)
+	case plan.failedStep != nil:
+		completeOpsPipeline(pipeline, opsv1alpha1.OpsPipelineFailedPhase, fmt.Sprintf("the step %s failed", plan.failedStep.Name))
+		return nil
+	case len(pipeline.Status.Steps) == len(pipeline.Spec.Steps) && plan.running == 0:
+		completeOpsPipeline(pipeline, opsv1alpha1.OpsPipelineSucceedPhase, "")
+		return nil
+	}
+
+	pipeline.Status.Phase = opsv1alpha1.OpsPipelineRunningPhase
+	for _, step := range plan.readySteps {
+		opsName := fmt.Sprintf("%s-%s", pipeline.Name, step.Name)
+		if err := r.createOpsRequest(reqCtx, pipeline, step.Name, opsName, &step.Spec); err != nil {
+			return err
+		}
+		pipeline.Status.Steps = append(pipeline.Status.Steps, opsv1alpha1.OpsPipelineStepStatus{
+			Name:           step.Name,
+			OpsRequestName: opsName,
+		})
+	}
+	return nil
+}
+
+// rollback runs the compensations one by one.
+func (r *OpsPipelineReconciler) rollback(reqCtx intctrlutil.RequestCtx, pipeline *opsv1alpha1.OpsPipeline) error {
+	steps := map[string]*opsv1alpha1.OpsPipelineStep{}
+	for i := range pipeline.Spec.Steps {
+		steps[pipeline.Spec.Steps[i].Name] = &pipeline.Spec.Steps[i]
+	}
+	for i := range pipeline.Status.Compensations {
+		compensation := &pipeline.Status.Compensations[i]
+		switch {
+		case compensation.Phase == opsv1alpha1.OpsSucceedPhase:
+			continue
+		case opsPhaseFailed(compensation.Phase):
+			completeOpsPipeline(pipeline, opsv1alpha1.OpsPipelineFailedPhase,
+				fmt.Sprintf("the compensation of the step %s failed", compensation.Name))
+			return nil
+		case compensation.Phase == "":
+			// the OpsRequest has not been created, or it is not observed yet.
+			return r.createOpsRequest(reqCtx, pipeline, compensation.Name, compensation.OpsRequestName, steps[compensation.Name].Compensation)
+		default:
+			return nil
+		}
+	}
+	completeOpsPipeline(pipeline, opsv1alpha1.OpsPipelineRolledBackPhase, pipeline.Status.Message)
+	return nil
+}
+
+func (r *OpsPipelineReconciler) createOpsRequest(reqCtx intctrlutil.RequestCtx,
+	pipeline *opsv1alpha1.OpsPipeline,
+	stepName, opsName string,
+	spec *opsv1alpha1.OpsRequestSpec) error {
+	ops := &opsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opsName,
+			Namespace: pipeline.Namespace,
+			Labels: map[string]string{
+				constant.OpsPipelineLabelKey:     pipeline.Name,
+				constant.OpsPipelineStepLabelKey: stepName,
+			},
+		},
+		Spec: *spec.DeepCopy(),
+	}
+	if ops.Spec.ClusterName == "" {
+		ops.Spec.ClusterName = pipeline.Spec.ClusterName
+	}
+	if err := controllerutil.SetControllerReference(pipeline, ops, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Client.Create(reqCtx.Ctx, ops); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+			completeOpsPipeline(pipeline, opsv1alpha1.OpsPipelineFailedPhase,
+				fmt.Sprintf("failed to create the OpsRequest %s: %s", opsName, err.Error()))
+			return nil
+		}
+		return err
+	}
+	r.Recorder.Eventf(pipeline, corev1.EventTypeNormal, reasonOpsRequestCreated, "created the OpsRequest %s for the step %s", opsName, stepName)
+	return nil
+}
+
+func (r *OpsPipelineReconciler) recordPhaseEvent(pipeline *opsv1alpha1.OpsPipeline) {
+	eventType := corev1.EventTypeNormal
+	if pipeline.Status.Phase == opsv1alpha1.OpsPipelineFailedPhase ||
+		pipeline.Status.Phase == opsv1alpha1.OpsPipelineRollingBackPhase ||
+		pipeline.Status.Phase == opsv1alpha1.OpsPipelineRolledBackPhase {
+		eventType = corev1.EventTypeWarning
+	}
+	message := fmt.Sprintf("OpsPipeline %s is %s", pipeline.Name, pipeline.Status.Phase)
+	if pipeline.Status.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, pipeline.Status.Message)
+	}
+	r.Recorder.Event(pipeline, eventType, string(pipeline.Status.Phase), message)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpsPipelineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
+		For(&opsv1alpha1.OpsPipeline{}).
+		Owns(&opsv1alpha1.OpsRequest{}).
+		Complete(r)
+}
+
+// opsPipelinePlan describes what to do next for the steps of an OpsPipeline.
+type opsPipelinePlan struct {
+	// the steps whose dependencies have been satisfied and are ready to start.
+	readySteps []*opsv1alpha1.OpsPipelineStep
+	// the number of the steps that are running.
+	running int
+	// the first step that failed with the "Abort" or "Rollback" failure policy.
+	failedStep *opsv1alpha1.OpsPipelineStep
+}
+
+func planOpsPipeline(pipeline *opsv1alpha1.OpsPipeline) opsPipelinePlan {
+	var plan opsPipelinePlan
+	stepStatus := map[string]opsv1alpha1.OpsPipelineStepStatus{}
+	for _, status := range pipeline.Status.Steps {
+		stepStatus[status.Name] = status
+	}
+	// satisfied checks whether the steps depending on the step can start.
+	satisfied := func(name string, failurePolicy opsv1alpha1.OpsStepFailurePolicy) bool {
+		phase := stepStatus[name].Phase
+		return phase == opsv1alpha1.OpsSucceedPhase || (opsPhaseFailed(phase) && failurePolicy == opsv1alpha1.ContinueOnFailure)
+	}
+	failurePolicies := map[string]opsv1alpha1.OpsStepFailurePolicy{}
+	for i, step := range pipeline.Spec.Steps {
+		failurePolicies[step.Name] = step.FailurePolicy
+		status, started := stepStatus[step.Name]
+		switch {
+		case !started:
+			ready := true
+			for _, dep := range pipeline.GetStepDependencies(i) {
+				if !satisfied(dep, failurePolicies[dep]) {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				plan.readySteps = append(plan.readySteps, &pipeline.Spec.Steps[i])
+			}
+		case opsPhaseFailed(status.Phase):
+			if step.FailurePolicy != opsv1alpha1.ContinueOnFailure && plan.failedStep == nil {
+				plan.failedStep = &pipeline.Spec.Steps[i]
+			}
+		case status.Phase != opsv1alpha1.OpsSucceedPhase:
+			plan.running++
+		}
+	}
+	return plan
+}
+
+// buildOpsPipelineCompensations builds the compensations of the completed steps in the reverse order of their completion.
+func buildOpsPipelineCompensations(pipeline *opsv1alpha1.OpsPipeline) []opsv1alpha1.OpsPipelineStepStatus {
+	hasCompensation := map[string]bool{}
+	for _, step := range pipeline.Spec.Steps {
+		hasCompensation[step.Name] = step.Compensation != nil
+	}
+	completed := completedOpsPipelineSteps(pipeline.Status.Steps)
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[j].CompletionTimestamp.Before(&completed[i].CompletionTimestamp)
+	})
+	var compensations []opsv1alpha1.OpsPipelineStepStatus
+	for _, status := range completed {
+		if !hasCompensation[status.Name] {
+			continue
+		}
+		compensations = append(compensations, opsv1alpha1.OpsPipelineStepStatus{
+			Name:           status.Name,
+			OpsRequestName: fmt.Sprintf("%s-%s-compensation", pipeline.Name, status.Name),
+		})
+	}
+	return compensations
+}
+
+// validateOpsPipeline checks that the dependencies of the steps exist and have no cycle.
+func validateOpsPipeline(pipeline *opsv1alpha1.OpsPipeline) error {
+	inDegrees := map[string]int{}
+	dependents := map[string][]string{}
+	for _, step := range pipeline.Spec.Steps {
+		if _, ok := inDegrees[step.Name]; ok {
+			return fmt.Errorf("the step %s is duplicated", step.Name)
+		}
+		inDegrees[step.Name] = 0
+	}
+	for i, step := range pipeline.Spec.Steps {
+		for _, dep := range pipeline.GetStepDependencies(i) {
+			if _, ok := inDegrees[dep]; !ok {
+				return fmt.Errorf("the step %s depends on the step %s that does not exist", step.Name, dep)
+			}
+			inDegrees[step.Name]++
+			dependents[dep] = append(dependents[dep], step.Name)
+		}
+	}
+	var queue []string
+	for name, inDegree := range inDegrees {
+		if inDegree == 0 {
+			queue = append(queue, name)
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, dependent := range dependents[name] {
+			inDegrees[dependent]--
+			if inDegrees[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	if visited != len(pipeline.Spec.Steps) {
+		var cyclic []string
+		for name, inDegree := range inDegrees {
+			if inDegree > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+		sort.Strings(cyclic)
+		return fmt.Errorf("the dependencies of the steps %s contain a cycle", strings.Join(cyclic, ","))
+	}
+	return nil
+}
+
+// syncOpsPipelineStepStatus syncs the status of the steps and the compensations from their OpsRequests.
+func syncOpsPipelineStepStatus(pipeline *opsv1alpha1.OpsPipeline, opsRequests []opsv1alpha1.OpsRequest) {
+	opsMap := map[string]*opsv1alpha1.OpsRequest{}
+	for i := range opsRequests {
+		opsMap[opsRequests[i].Name] = &opsRequests[i]
+	}
+	sync := func(statuses []opsv1alpha1.OpsPipelineStepStatus) {
+		for i := range statuses {
+			ops, ok := opsMap[statuses[i].OpsRequestName]
+			if !ok {
+				continue
+			}
+			statuses[i].Phase = ops.Status.Phase
+			statuses[i].StartTimestamp = ops.Status.StartTimestamp
+			statuses[i].CompletionTimestamp = ops.Status.CompletionTimestamp
+		}
+	}
+	sync(pipeline.Status.Steps)
+	sync(pipeline.Status.Compensations)
+}
+
+func completedOpsPipelineSteps(statuses []opsv1alpha1.OpsPipelineStepStatus) []opsv1alpha1.OpsPipelineStepStatus {
+	var completed []opsv1alpha1.OpsPipelineStepStatus
+	for _, status := range statuses {
+		if status.Phase == opsv1alpha1.OpsSucceedPhase || opsPhaseFailed(status.Phase) {
+			completed = append(completed, status)
+		}
+	}
+	return completed
+}
+
+func completeOpsPipeline(pipeline *opsv1alpha1.OpsPipeline, phase opsv1alpha1.OpsPipelinePhase, message string) {
+	pipeline.Status.Phase = phase
+	pipeline.Status.Message = message
+	pipeline.Status.CompletionTimestamp = metav1.Now()
+}
+
+// opsPhaseFailed checks if the OpsRequest has completed without success.
+func opsPhaseFailed(phase opsv1alpha1.OpsPhase) bool {
+	ops := &opsv1alpha1.OpsRequest{}
+	return ops.IsComplete(phase) && phase != opsv1alpha1.OpsSucceedPhase
+}
@@ -0,0 +1,199 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+var _ = Describe("OpsPipeline Controller", func() {
+
+	cleanEnv := func() {
+		By("clean resources")
+
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+
+		testapps.ClearResources(&testCtx, intctrlutil.OpsPipelineSignature, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+	})
+
+	AfterEach(func() {
+		cleanEnv()
+	})
+
+	newStep := func(name string, opsType opsv1alpha1.OpsType, dependsOn ...string) opsv1alpha1.OpsPipelineStep {
+		return opsv1alpha1.OpsPipelineStep{
+			Name:          name,
+			DependsOn:     dependsOn,
+			FailurePolicy: opsv1alpha1.AbortOnFailure,
+			Spec:          opsv1alpha1.OpsRequestSpec{Type: opsType},
+		}
+	}
+
+	newPipeline := func(steps ...opsv1alpha1.OpsPipelineStep) *opsv1alpha1.OpsPipeline {
+		return &opsv1alpha1.OpsPipeline{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "upgrade-" + testCtx.GetRandomStr(),
+				Namespace: testCtx.DefaultNamespace,
+				Labels:    map[string]string{testCtx.TestObjLabelKey: "true"},
+			},
+			Spec: opsv1alpha1.OpsPipelineSpec{
+				ClusterName: "mycluster",
+				Steps:       steps,
+			},
+		}
+	}
+
+	setStepPhase := func(pipeline *opsv1alpha1.OpsPipeline, name string, phase opsv1alpha1.OpsPhase, completionTime time.Time) {
+		for i := range pipeline.Status.Steps {
+			if pipeline.Status.Steps[i].Name == name {
+				pipeline.Status.Steps[i].Phase = phase
+				pipeline.Status.Steps[i].CompletionTimestamp = metav1.NewTime(completionTime)
+				return
+			}
+		}
+		pipeline.Status.Steps = append(pipeline.Status.Steps, opsv1alpha1.OpsPipelineStepStatus{
+			Name:                name,
+			OpsRequestName:      pipeline.Name + "-" + name,
+			Phase:               phase,
+			CompletionTimestamp: metav1.NewTime(completionTime),
+		})
+	}
+
+	stepNames := func(steps []*opsv1alpha1.OpsPipelineStep) []string {
+		var names []string
+		for _, step := range steps {
+			names = append(names, step.Name)
+		}
+		return names
+	}
+
+	Context("Test OpsPipeline", func() {
+		It("should fail if the dependencies of the steps form a cycle", func() {
+			pipeline := newPipeline(
+				newStep("backup", opsv1alpha1.BackupType, "switchover"),
+				newStep("upgrade", opsv1alpha1.UpgradeType),
+				newStep("switchover", opsv1alpha1.SwitchoverType))
+			Expect(testCtx.CreateObj(testCtx.Ctx, pipeline)).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(pipeline), func(g Gomega, obj *opsv1alpha1.OpsPipeline) {
+				g.Expect(obj.Status.Phase).Should(Equal(opsv1alpha1.OpsPipelineFailedPhase))
+				g.Expect(obj.Status.Message).Should(ContainSubstring("cycle"))
+				g.Expect(obj.Status.Steps).Should(BeEmpty())
+			})).Should(Succeed())
+		})
+	})
+
+	Context("Test pipeline plan", func() {
+		It("should run the steps in sequence by default", func() {
+			now := time.Now()
+			pipeline := newPipeline(
+				newStep("backup", opsv1alpha1.BackupType),
+				newStep("upgrade", opsv1alpha1.UpgradeType),
+				newStep("switchover", opsv1alpha1.SwitchoverType))
+			Expect(validateOpsPipeline(pipeline)).Should(Succeed())
+			Expect(stepNames(planOpsPipeline(pipeline).readySteps)).Should(Equal([]string{"backup"}))
+
+			setStepPhase(pipeline, "backup", opsv1alpha1.OpsRunningPhase, time.Time{})
+			plan := planOpsPipeline(pipeline)
+			Expect(plan.readySteps).Should(BeEmpty())
+			Expect(plan.running).Should(Equal(1))
+
+			setStepPhase(pipeline, "backup", opsv1alpha1.OpsSucceedPhase, now)
+			Expect(stepNames(planOpsPipeline(pipeline).readySteps)).Should(Equal([]string{"upgrade"}))
+		})
+
+		It("should run the steps as a DAG", func() {
+			pipeline := newPipeline(
+				newStep("backup", opsv1alpha1.BackupType),
+				newStep("reconfigure", opsv1alpha1.ReconfiguringType),
+				newStep("upgrade", opsv1alpha1.UpgradeType, "backup", "reconfigure"))
+			pipeline.Spec.Steps[1].DependsOn = []string{}
+			Expect(validateOpsPipeline(pipeline)).Should(Succeed())
+			Expect(stepNames(planOpsPipeline(pipeline).readySteps)).Should(Equal([]string{"backup", "reconfigure"}))
+
+			setStepPhase(pipeline, "backup", opsv1alpha1.OpsSucceedPhase, time.Now())
+			setStepPhase(pipeline, "reconfigure", opsv1alpha1.OpsRunningPhase, time.Time{})
+			Expect(planOpsPipeline(pipeline).readySteps).Should(BeEmpty())
+
+			setStepPhase(pipeline, "reconfigure", opsv1alpha1.OpsSucceedPhase, time.Now())
+			Expect(stepNames(planOpsPipeline(pipeline).readySteps)).Should(Equal([]string{"upgrade"}))
+		})
+
+		It("should check the dependencies", func() {
+			pipeline := newPipeline(newStep("backup", opsv1alpha1.BackupType, "restore"))
+			Expect(validateOpsPipeline(pipeline)).Should(HaveOccurred())
+		})
+
+		It("should handle the failure by the failure policy", func() {
+			pipeline := newPipeline(
+				newStep("backup", opsv1alpha1.BackupType),
+				newStep("upgrade", opsv1alpha1.UpgradeType),
+				newStep("switchover", opsv1alpha1.SwitchoverType))
+
+			By("continue on failure")
+			pipeline.Spec.Steps[0].FailurePolicy = opsv1alpha1.ContinueOnFailure
+			setStepPhase(pipeline, "backup", opsv1alpha1.OpsFailedPhase, time.Now())
+			plan := planOpsPipeline(pipeline)
+			Expect(plan.failedStep).Should(BeNil())
+			Expect(stepNames(plan.readySteps)).Should(Equal([]string{"upgrade"}))
+
+			By("abort on failure")
+			setStepPhase(pipeline, "upgrade", opsv1alpha1.OpsAbortedPhase, time.Now())
+			plan = planOpsPipeline(pipeline)
+			Expect(plan.failedStep).ShouldNot(BeNil())
+			Expect(plan.failedStep.Name).Should(Equal("upgrade"))
+			Expect(plan.readySteps).Should(BeEmpty())
+		})
+
+		It("should build the compensations in the reverse order of completion", func() {
+			now := time.Now()
+			pipeline := newPipeline(
+				newStep("backup", opsv1alpha1.BackupType),
+				newStep("reconfigure", opsv1alpha1.ReconfiguringType),
+				newStep("upgrade", opsv1alpha1.UpgradeType))
+			pipeline.Spec.Steps[1].Compensation = &opsv1alpha1.OpsRequestSpec{Type: opsv1alpha1.ReconfiguringType}
+			pipeline.Spec.Steps[2].Compensation = &opsv1alpha1.OpsRequestSpec{Type: opsv1alpha1.UpgradeType}
+			pipeline.Spec.Steps[2].FailurePolicy = opsv1alpha1.RollbackOnFailure
+			setStepPhase(pipeline, "backup", opsv1alpha1.OpsSucceedPhase, now)
+			setStepPhase(pipeline, "reconfigure", opsv1alpha1.OpsSucceedPhase, now.Add(time.Minute))
+			setStepPhase(pipeline, "upgrade", opsv1alpha1.OpsFailedPhase, now.Add(2*time.Minute))
+
+			compensations := buildOpsPipelineCompensations(pipeline)
+			Expect(compensations).Should(HaveLen(2))
+			Expect(compensations[0].Name).Should(Equal("upgrade"))
+			Expect(compensations[0].OpsRequestName).Should(Equal(pipeline.Name + "-upgrade-compensation"))
+			Expect(compensations[1].Name).Should(Equal("reconfigure"))
+		})
+	})
+})
@@ -64,6 +64,8 @@ type OpsRequestReconciler struct {
 // +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opsrequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opsrequests/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opsrequests/finalizers,verbs=update
+// +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=opsapprovalpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -186,7 +188,7 @@ func (r *OpsRequestReconciler) handleOpsRequestByPhase(reqCtx intctrlutil.Reques
 			return intctrlutil.ResultToP(intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, ""))
 		}
 		return intctrlutil.ResultToP(intctrlutil.Reconciled())
-	case opsv1alpha1.OpsPendingPhase, opsv1alpha1.OpsCreatingPhase:
+	case opsv1alpha1.OpsPendingPhase, opsv1alpha1.OpsPendingApprovalPhase, opsv1alpha1.OpsCreatingPhase:
 		return r.doOpsRequestAction(reqCtx, opsRes)
 	case opsv1alpha1.OpsRunningPhase, opsv1alpha1.OpsCancellingPhase:
 		return r.reconcileStatusDuringRunningOrCanceling(reqCtx, opsRes)
@@ -206,7 +208,7 @@ func (r *OpsRequestReconciler) handleCancelSignal(reqCtx intctrlutil.RequestCtx,
 	if opsRequest.IsComplete() || opsRequest.Status.Phase == opsv1alpha1.OpsCancellingPhase {
 		return nil, nil
 	}
-	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase {
+	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase || opsRequest.Status.Phase == opsv1alpha1.OpsPendingApprovalPhase {
 		return &ctrl.Result{}, operations.PatchOpsStatus(reqCtx.Ctx, r.Client, opsRes, opsv1alpha1.OpsCancelledPhase)
 	}
 	opsBehaviour := operations.GetOpsManager().OpsMap[opsRequest.Spec.Type]
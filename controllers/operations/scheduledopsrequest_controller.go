@@ -0,0 +1,383 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// tooManyMissedRuns is the number of missed runs after which a warning event is recorded.
+const tooManyMissedRuns = 100
+
+// ScheduledOpsRequestReconciler reconciles a ScheduledOpsRequest object
+type ScheduledOpsRequestReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=scheduledopsrequests,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=scheduledopsrequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operations.kubeblocks.io,resources=scheduledopsrequests/finalizers,verbs=update
+
+func (r *ScheduledOpsRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      ctx,
+		Req:      req,
+		Log:      log.FromContext(ctx).WithValues("scheduledOpsRequest", req.NamespacedName),
+		Recorder: r.Recorder,
+	}
+
+	sops := &opsv1alpha1.ScheduledOpsRequest{}
+	if err := r.Client.Get(reqCtx.Ctx, reqCtx.Req.NamespacedName, sops); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	// the OpsRequests created are garbage collected along with the owner.
+	if !sops.DeletionTimestamp.IsZero() {
+		return intctrlutil.Reconciled()
+	}
+
+	opsList := &opsv1alpha1.OpsRequestList{}
+	if err := r.Client.List(reqCtx.Ctx, opsList, client.InNamespace(sops.Namespace),
+		client.MatchingLabels{constant.ScheduledOpsRequestLabelKey: scheduledOpsRequestLabelValue(sops.Name)}); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+
+	oldSops := sops.DeepCopy()
+	requeueAfter, reconcileErr := r.reconcile(reqCtx, sops, opsList.Items, time.Now())
+	sops.Status.ObservedGeneration = sops.Generation
+	if !apiequality.Semantic.DeepEqual(oldSops.Status, sops.Status) {
+		if err := r.Client.Status().Patch(reqCtx.Ctx, sops, client.MergeFrom(oldSops)); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+	if reconcileErr != nil {
+		return intctrlutil.CheckedRequeueWithError(reconcileErr, reqCtx.Log, "")
+	}
+	if requeueAfter > 0 {
+		return intctrlutil.RequeueAfter(requeueAfter, reqCtx.Log, "wait for the next schedule")
+	}
+	return intctrlutil.Reconciled()
+}
+
+// reconcile syncs the history of the OpsRequests and creates a new OpsRequest if a run is due.
+// It returns the duration to wait for the next run.
+func (r *ScheduledOpsRequestReconciler) reconcile(reqCtx intctrlutil.RequestCtx,
+	sops *opsv1alpha1.ScheduledOpsRequest,
+	opsRequests []opsv1alpha1.OpsRequest,
+	now time.Time) (time.Duration, error) {
+	active, err := r.syncHistory(reqCtx, sops, opsRequests)
+	if err != nil {
+		return 0, err
+	}
+
+	schedule, err := common.ParseCronExpression(sops.Spec.Schedule, sops.Spec.TimeZone)
+	if err != nil {
+		sops.Status.Phase = opsv1alpha1.UnavailablePhase
+		sops.Status.Message = err.Error()
+		sops.Status.NextScheduleTime = nil
+		r.Recorder.Event(sops, corev1.EventTypeWarning, reasonInvalidSchedule, err.Error())
+		return 0, nil
+	}
+	sops.Status.Phase = opsv1alpha1.AvailablePhase
+	sops.Status.Message = ""
+	if sops.Spec.Suspend {
+		sops.Status.NextScheduleTime = nil
+		return 0, nil
+	}
+
+	nextTime := schedule.Next(now)
+	if nextTime.IsZero() {
+		sops.Status.NextScheduleTime = nil
+	} else {
+		sops.Status.NextScheduleTime = &metav1.Time{Time: nextTime}
+	}
+	requeueAfter := nextTime.Sub(now)
+	if nextTime.IsZero() {
+		requeueAfter = 0
+	}
+
+	scheduledTime, missed := mostRecentScheduleTime(sops, schedule, now)
+	if scheduledTime.IsZero() {
+		return requeueAfter, nil
+	}
+	// the events of a run are recorded once, though it is retried by every reconciliation until it's started.
+	skipped := sops.Status.LastSkippedTime != nil && sops.Status.LastSkippedTime.Time.Equal(scheduledTime)
+	if missed > tooManyMissedRuns && !skipped {
+		r.Recorder.Eventf(sops, corev1.EventTypeWarning, reasonTooManyMissedRuns,
+			"more than %d runs have been missed, only the most recent one at %s will be started", tooManyMissedRuns, scheduledTime.Format(time.RFC3339))
+	}
+
+	if len(active) > 0 {
+		switch sops.Spec.ConcurrencyPolicy {
+		case opsv1alpha1.ForbidConcurrent:
+			// retry when the active OpsRequests complete, as long as the run does not miss the starting deadline.
+			if !skipped {
+				r.Recorder.Eventf(sops, corev1.EventTypeNormal, reasonRunSkipped,
+					"the run at %s is skipped as the OpsRequests %s are still in progress", scheduledTime.Format(time.RFC3339), strings.Join(opsNames(active), ","))
+			}
+			sops.Status.LastSkippedTime = &metav1.Time{Time: scheduledTime}
+			return requeueAfter, nil
+		case opsv1alpha1.ReplaceConcurrent:
+			uncancelable, err := r.cancelActiveOpsRequests(reqCtx, sops, active)
+			if err != nil {
+				return requeueAfter, err
+			}
+			if uncancelable != nil {
+				if !skipped {
+					r.Recorder.Eventf(sops, corev1.EventTypeNormal, reasonRunSkipped,
+						"the run at %s is skipped as the %s OpsRequest %s in progress can not be canceled",
+						scheduledTime.Format(time.RFC3339), uncancelable.Spec.Type, uncancelable.Name)
+				}
+				sops.Status.LastSkippedTime = &metav1.Time{Time: scheduledTime}
+				return requeueAfter, nil
+			}
+		}
+	}
+
+	ops, err := r.createOpsRequest(reqCtx, sops, scheduledTime)
+	if err != nil {
+		return 0, err
+	}
+	if ops != nil && !slices.Contains(sops.Status.Active, ops.Name) {
+		sops.Status.Active = append(sops.Status.Active, ops.Name)
+	}
+	sops.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	return requeueAfter, nil
+}
+
+// syncHistory updates the active OpsRequests and the last successful time in the status,
+// and deletes the completed OpsRequests that exceed the history limits.
+func (r *ScheduledOpsRequestReconciler) syncHistory(reqCtx intctrlutil.RequestCtx,
+	sops *opsv1alpha1.ScheduledOpsRequest,
+	opsRequests []opsv1alpha1.OpsRequest) ([]*opsv1alpha1.OpsRequest, error) {
+	var active, succeeded, failed []*opsv1alpha1.OpsRequest
+	for i := range opsRequests {
+		ops := &opsRequests[i]
+		switch {
+		case !ops.DeletionTimestamp.IsZero():
+			continue
+		case !ops.IsComplete():
+			active = append(active, ops)
+		case ops.Status.Phase == opsv1alpha1.OpsSucceedPhase:
+			succeeded = append(succeeded, ops)
+			completionTime := ops.Status.CompletionTimestamp
+			if completionTime.IsZero() {
+				continue
+			}
+			if sops.Status.LastSuccessfulTime == nil || sops.Status.LastSuccessfulTime.Before(&completionTime) {
+				sops.Status.LastSuccessfulTime = completionTime.DeepCopy()
+			}
+		default:
+			failed = append(failed, ops)
+		}
+	}
+	sops.Status.Active = opsNames(active)
+
+	deleteHistory := func(history []*opsv1alpha1.OpsRequest, limit *int32) error {
+		if limit == nil || len(history) <= int(*limit) {
+			return nil
+		}
+		// keep the latest ones
+		sort.Slice(history, func(i, j int) bool {
+			return history[j].CreationTimestamp.Before(&history[i].CreationTimestamp)
+		})
+		for _, ops := range history[*limit:] {
+			if err := r.Client.Delete(reqCtx.Ctx, ops); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := deleteHistory(succeeded, sops.Spec.SuccessfulHistoryLimit); err != nil {
+		return nil, err
+	}
+	if err := deleteHistory(failed, sops.Spec.FailedHistoryLimit); err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+// cancelActiveOpsRequests cancels the active OpsRequests for the Replace concurrency policy.
+// It returns the OpsRequest that does not support cancellation if there is any, and none is canceled in that case.
+func (r *ScheduledOpsRequestReconciler) cancelActiveOpsRequests(reqCtx intctrlutil.RequestCtx,
+	sops *opsv1alpha1.ScheduledOpsRequest,
+	active []*opsv1alpha1.OpsRequest) (*opsv1alpha1.OpsRequest, error) {
+	for _, ops := range active {
+		if !slices.Contains([]opsv1alpha1.OpsType{opsv1alpha1.VerticalScalingType, opsv1alpha1.HorizontalScalingType}, ops.Spec.Type) {
+			return ops, nil
+		}
+	}
+	for _, ops := range active {
+		if ops.Spec.Cancel {
+			continue
+		}
+		patch := client.MergeFrom(ops.DeepCopy())
+		ops.Spec.Cancel = true
+		if err := r.Client.Patch(reqCtx.Ctx, ops, patch); err != nil {
+			return nil, err
+		}
+		r.Recorder.Eventf(sops, corev1.EventTypeNormal, reasonOpsRequestCanceled, "canceled the OpsRequest %s", ops.Name)
+	}
+	return nil, nil
+}
+
+// createOpsRequest creates the OpsRequest of the run at the scheduled time.
+// It returns nil if the template is rejected by the API server, the run is considered done in that case.
+func (r *ScheduledOpsRequestReconciler) createOpsRequest(reqCtx intctrlutil.RequestCtx,
+	sops *opsv1alpha1.ScheduledOpsRequest,
+	scheduledTime time.Time) (*opsv1alpha1.OpsRequest, error) {
+	ops := buildScheduledOpsRequest(sops, scheduledTime)
+	if err := controllerutil.SetControllerReference(sops, ops, r.Scheme); err != nil {
+		return nil, err
+	}
+	err := r.Client.Create(reqCtx.Ctx, ops)
+	switch {
+	case err == nil:
+		r.Recorder.Eventf(sops, corev1.EventTypeNormal, reasonOpsRequestCreated, "created the OpsRequest %s", ops.Name)
+		return ops, nil
+	case apierrors.IsAlreadyExists(err):
+		return ops, nil
+	case apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+		r.Recorder.Eventf(sops, corev1.EventTypeWarning, reasonOpsRequestCreateFail, "failed to create the OpsRequest %s: %s", ops.Name, err.Error())
+		sops.Status.Message = err.Error()
+		return nil, nil
+	default:
+		r.Recorder.Eventf(sops, corev1.EventTypeWarning, reasonOpsRequestCreateFail, "failed to create the OpsRequest %s: %s", ops.Name, err.Error())
+		return nil, err
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ScheduledOpsRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
+		For(&opsv1alpha1.ScheduledOpsRequest{}).
+		Owns(&opsv1alpha1.OpsRequest{}).
+		Complete(r)
+}
+
+// mostRecentScheduleTime returns the most recent scheduled time that has not been run yet and is not later than now,
+// and the number of runs missed since the last scheduled time, which is counted up to tooManyMissedRuns+1.
+// The scheduled times earlier than the starting deadline are ignored.
+func mostRecentScheduleTime(sops *opsv1alpha1.ScheduledOpsRequest, schedule cron.Schedule, now time.Time) (time.Time, int) {
+	earliestTime := sops.CreationTimestamp.Time
+	if sops.Status.LastScheduleTime != nil {
+		earliestTime = sops.Status.LastScheduleTime.Time
+	}
+	if sops.Spec.StartingDeadlineSeconds != nil {
+		deadline := now.Add(-time.Duration(*sops.Spec.StartingDeadlineSeconds) * time.Second)
+		if deadline.After(earliestTime) {
+			earliestTime = deadline
+		}
+	}
+
+	var (
+		mostRecentTime time.Time
+		missed         int
+	)
+	for t := schedule.Next(earliestTime); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		mostRecentTime = t
+		missed++
+		if missed > tooManyMissedRuns {
+			// stop counting, and look for the most recent one backwards from now instead of walking through all runs missed.
+			return lastScheduleTime(schedule, now), missed
+		}
+	}
+	return mostRecentTime, missed
+}
+
+// lastScheduleTime returns the last scheduled time not later than now, there must be one.
+// It looks back from now by a doubled step each time, until a scheduled time falls in the step.
+func lastScheduleTime(schedule cron.Schedule, now time.Time) time.Time {
+	for step := time.Minute; ; step *= 2 {
+		t := schedule.Next(now.Add(-step))
+		if t.IsZero() || t.After(now) {
+			continue
+		}
+		for next := schedule.Next(t); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+			t = next
+		}
+		return t
+	}
+}
+
+func buildScheduledOpsRequest(sops *opsv1alpha1.ScheduledOpsRequest, scheduledTime time.Time) *opsv1alpha1.OpsRequest {
+	template := sops.Spec.OpsRequestTemplate
+	labels := map[string]string{}
+	for k, v := range template.Labels {
+		labels[k] = v
+	}
+	labels[constant.ScheduledOpsRequestLabelKey] = scheduledOpsRequestLabelValue(sops.Name)
+	return &opsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			// the name is deterministic for the scheduled time, to avoid duplicated runs.
+			Name:        fmt.Sprintf("%s-%d", sops.Name, scheduledTime.Unix()/60),
+			Namespace:   sops.Namespace,
+			Labels:      labels,
+			Annotations: template.Annotations,
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+}
+
+// scheduledOpsRequestLabelValue returns the label value of the OpsRequests created by the ScheduledOpsRequest,
+// the names longer than the limit of label values are truncated and suffixed with their hash to keep them unique.
+func scheduledOpsRequestLabelValue(name string) string {
+	if len(name) <= validation.LabelValueMaxLength {
+		return name
+	}
+	hf := fnv.New32a()
+	_, _ = hf.Write([]byte(name))
+	suffix := fmt.Sprintf("-%08x", hf.Sum32())
+	return name[:validation.LabelValueMaxLength-len(suffix)] + suffix
+}
+
+func opsNames(opsRequests []*opsv1alpha1.OpsRequest) []string {
+	var names []string
+	for _, ops := range opsRequests {
+		names = append(names, ops.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,242 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+var _ = Describe("ScheduledOpsRequest Controller", func() {
+
+	cleanEnv := func() {
+		By("clean resources")
+
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+
+		testapps.ClearResources(&testCtx, generics.ScheduledOpsRequestSignature, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+	})
+
+	AfterEach(func() {
+		cleanEnv()
+	})
+
+	newScheduledOpsRequest := func(schedule string) *opsv1alpha1.ScheduledOpsRequest {
+		return &opsv1alpha1.ScheduledOpsRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "weekly-restart-" + testCtx.GetRandomStr(),
+				Namespace: testCtx.DefaultNamespace,
+				Labels:    map[string]string{testCtx.TestObjLabelKey: "true"},
+			},
+			Spec: opsv1alpha1.ScheduledOpsRequestSpec{
+				Schedule: schedule,
+				OpsRequestTemplate: opsv1alpha1.OpsRequestTemplate{
+					Labels: map[string]string{"app": "test"},
+					Spec: opsv1alpha1.OpsRequestSpec{
+						ClusterName: "mycluster",
+						Type:        opsv1alpha1.RestartType,
+						SpecificOpsRequest: opsv1alpha1.SpecificOpsRequest{
+							RestartList: []opsv1alpha1.ComponentOps{{ComponentName: "mysql"}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	Context("Test ScheduledOpsRequest", func() {
+		It("should be available with a valid schedule", func() {
+			sops := newScheduledOpsRequest("0 3 * * 0")
+			Expect(testCtx.CreateObj(testCtx.Ctx, sops)).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(sops), func(g Gomega, obj *opsv1alpha1.ScheduledOpsRequest) {
+				g.Expect(obj.Status.Phase).Should(Equal(opsv1alpha1.AvailablePhase))
+				g.Expect(obj.Status.NextScheduleTime).ShouldNot(BeNil())
+				g.Expect(obj.Status.NextScheduleTime.Weekday()).Should(Equal(time.Sunday))
+				g.Expect(obj.Status.LastScheduleTime).Should(BeNil())
+			})).Should(Succeed())
+		})
+
+		It("should be unavailable with an invalid schedule", func() {
+			sops := newScheduledOpsRequest("0 25 * * *")
+			Expect(testCtx.CreateObj(testCtx.Ctx, sops)).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(sops), func(g Gomega, obj *opsv1alpha1.ScheduledOpsRequest) {
+				g.Expect(obj.Status.Phase).Should(Equal(opsv1alpha1.UnavailablePhase))
+				g.Expect(obj.Status.Message).ShouldNot(BeEmpty())
+			})).Should(Succeed())
+		})
+	})
+
+	Context("Test schedule", func() {
+		var (
+			sops     *opsv1alpha1.ScheduledOpsRequest
+			schedule cron.Schedule
+		)
+
+		BeforeEach(func() {
+			var err error
+			sops = newScheduledOpsRequest("0 * * * *")
+			sops.CreationTimestamp = metav1.NewTime(time.Date(2024, 6, 1, 0, 30, 0, 0, time.UTC))
+			schedule, err = common.ParseCronExpression(sops.Spec.Schedule, sops.Spec.TimeZone)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should not run before the first scheduled time", func() {
+			scheduledTime, missed := mostRecentScheduleTime(sops, schedule, time.Date(2024, 6, 1, 0, 59, 0, 0, time.UTC))
+			Expect(scheduledTime.IsZero()).Should(BeTrue())
+			Expect(missed).Should(Equal(0))
+		})
+
+		It("should run the most recent missed time", func() {
+			scheduledTime, missed := mostRecentScheduleTime(sops, schedule, time.Date(2024, 6, 1, 3, 10, 0, 0, time.UTC))
+			Expect(scheduledTime).Should(BeTemporally("==", time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)))
+			Expect(missed).Should(Equal(3))
+		})
+
+		It("should not run the same scheduled time twice", func() {
+			sops.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)}
+			scheduledTime, _ := mostRecentScheduleTime(sops, schedule, time.Date(2024, 6, 1, 3, 10, 0, 0, time.UTC))
+			Expect(scheduledTime.IsZero()).Should(BeTrue())
+		})
+
+		It("should skip the scheduled times beyond the starting deadline", func() {
+			sops.Spec.StartingDeadlineSeconds = pointer.Int64(300)
+			scheduledTime, _ := mostRecentScheduleTime(sops, schedule, time.Date(2024, 6, 1, 3, 10, 0, 0, time.UTC))
+			Expect(scheduledTime.IsZero()).Should(BeTrue())
+
+			scheduledTime, _ = mostRecentScheduleTime(sops, schedule, time.Date(2024, 6, 1, 3, 4, 0, 0, time.UTC))
+			Expect(scheduledTime).Should(BeTemporally("==", time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)))
+		})
+
+		It("should interpret the schedule in the time zone", func() {
+			sops.Spec.Schedule = "0 3 * * *"
+			sops.Spec.TimeZone = "Asia/Shanghai"
+			schedule, err := common.ParseCronExpression(sops.Spec.Schedule, sops.Spec.TimeZone)
+			Expect(err).ShouldNot(HaveOccurred())
+			scheduledTime, _ := mostRecentScheduleTime(sops, schedule, time.Date(2024, 6, 1, 19, 30, 0, 0, time.UTC))
+			Expect(scheduledTime.UTC()).Should(BeTemporally("==", time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC)))
+
+			By("the time zone prefixed")
+			sops.Spec.Schedule = "CRON_TZ=Asia/Shanghai 0 3 * * *"
+			sops.Spec.TimeZone = ""
+			schedule, err = common.ParseCronExpression(sops.Spec.Schedule, sops.Spec.TimeZone)
+			Expect(err).ShouldNot(HaveOccurred())
+			scheduledTime, _ = mostRecentScheduleTime(sops, schedule, time.Date(2024, 6, 1, 19, 30, 0, 0, time.UTC))
+			Expect(scheduledTime.UTC()).Should(BeTemporally("==", time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC)))
+
+			sops.Spec.TimeZone = "Mars/Olympus"
+			_, err = common.ParseCronExpression("0 3 * * *", sops.Spec.TimeZone)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should stop counting too many missed runs", func() {
+			sops.Spec.Schedule = "* * * * *"
+			schedule, err := common.ParseCronExpression(sops.Spec.Schedule, sops.Spec.TimeZone)
+			Expect(err).ShouldNot(HaveOccurred())
+			scheduledTime, missed := mostRecentScheduleTime(sops, schedule, time.Date(2025, 6, 1, 3, 10, 30, 0, time.UTC))
+			Expect(scheduledTime).Should(BeTemporally("==", time.Date(2025, 6, 1, 3, 10, 0, 0, time.UTC)))
+			Expect(missed).Should(Equal(tooManyMissedRuns + 1))
+
+			By("the runs are not evenly distributed")
+			sops.Spec.Schedule = "* 1 * * *"
+			schedule, err = common.ParseCronExpression(sops.Spec.Schedule, sops.Spec.TimeZone)
+			Expect(err).ShouldNot(HaveOccurred())
+			scheduledTime, missed = mostRecentScheduleTime(sops, schedule, time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC))
+			Expect(scheduledTime).Should(BeTemporally("==", time.Date(2024, 6, 30, 1, 59, 0, 0, time.UTC)))
+			Expect(missed).Should(Equal(tooManyMissedRuns + 1))
+		})
+
+		It("should build the OpsRequest from the template", func() {
+			scheduledTime := time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)
+			ops := buildScheduledOpsRequest(sops, scheduledTime)
+			Expect(ops.Name).Should(Equal(buildScheduledOpsRequest(sops, scheduledTime.Add(30*time.Second)).Name))
+			Expect(ops.Namespace).Should(Equal(sops.Namespace))
+			Expect(ops.Labels).Should(HaveKeyWithValue("app", "test"))
+			Expect(ops.Labels).Should(HaveKeyWithValue(constant.ScheduledOpsRequestLabelKey, sops.Name))
+			Expect(ops.Spec.Type).Should(Equal(opsv1alpha1.RestartType))
+			Expect(sops.Spec.OpsRequestTemplate.Labels).ShouldNot(HaveKey(constant.ScheduledOpsRequestLabelKey))
+		})
+
+		It("should limit the length of the label value", func() {
+			name := strings.Repeat("a", 100)
+			value := scheduledOpsRequestLabelValue(name)
+			Expect(validation.IsValidLabelValue(value)).Should(BeEmpty())
+			Expect(value).Should(HavePrefix(name[:50]))
+			Expect(value).ShouldNot(Equal(scheduledOpsRequestLabelValue(name + "b")))
+			Expect(scheduledOpsRequestLabelValue(sops.Name)).Should(Equal(sops.Name))
+
+			sops.Name = name
+			ops := buildScheduledOpsRequest(sops, time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC))
+			Expect(ops.Labels).Should(HaveKeyWithValue(constant.ScheduledOpsRequestLabelKey, value))
+		})
+
+		It("should report the skipped run once", func() {
+			recorder := record.NewFakeRecorder(100)
+			r := &ScheduledOpsRequestReconciler{Recorder: recorder}
+			sops.Spec.ConcurrencyPolicy = opsv1alpha1.ForbidConcurrent
+			active := []opsv1alpha1.OpsRequest{{
+				ObjectMeta: metav1.ObjectMeta{Name: "active-ops", Namespace: sops.Namespace},
+				Status:     opsv1alpha1.OpsRequestStatus{Phase: opsv1alpha1.OpsRunningPhase},
+			}}
+			reconcile := func(now time.Time) {
+				_, err := r.reconcile(intctrlutil.RequestCtx{Ctx: testCtx.Ctx}, sops, active, now)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(sops.Status.Active).Should(Equal([]string{"active-ops"}))
+				Expect(sops.Status.LastScheduleTime).Should(BeNil())
+			}
+
+			By("the run is skipped as the previous one is still in progress")
+			reconcile(time.Date(2024, 6, 1, 3, 10, 0, 0, time.UTC))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(reasonRunSkipped)))
+			Expect(sops.Status.LastSkippedTime).ShouldNot(BeNil())
+			Expect(sops.Status.LastSkippedTime.Time).Should(BeTemporally("==", time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)))
+
+			By("the same run is retried without reporting again")
+			reconcile(time.Date(2024, 6, 1, 3, 20, 0, 0, time.UTC))
+			Expect(recorder.Events).ShouldNot(Receive())
+
+			By("the next run is reported")
+			reconcile(time.Date(2024, 6, 1, 4, 10, 0, 0, time.UTC))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(reasonRunSkipped)))
+			Expect(sops.Status.LastSkippedTime.Time).Should(BeTemporally("==", time.Date(2024, 6, 1, 4, 0, 0, 0, time.UTC)))
+		})
+	})
+})
@@ -46,6 +46,8 @@ type InstanceSetReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	drainSwitchovers drainSwitchoverTracker
 }
 
 // +kubebuilder:rbac:groups=workloads.kubeblocks.io,resources=instancesets,verbs=get;list;watch;create;update;patch;delete
@@ -67,6 +69,10 @@ type InstanceSetReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=services/status,verbs=get
 // +kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 // TODO(user): Modify the Reconcile function to compare the state specified by
@@ -91,8 +97,18 @@ func (r *InstanceSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		Commit()
 
 	// TODO(free6om): handle error based on ErrorCode (after defined)
+	if err != nil {
+		return res, err
+	}
 
-	return res, err
+	drainRes, err := r.switchoverOnDrainingNodes(ctx, req, logger)
+	if err != nil {
+		return res, err
+	}
+	if !res.Requeue && drainRes.RequeueAfter > 0 && (res.RequeueAfter == 0 || drainRes.RequeueAfter < res.RequeueAfter) {
+		res.RequeueAfter = drainRes.RequeueAfter
+	}
+	return res, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -110,6 +126,10 @@ func (r *InstanceSetReconciler) SetupWithManager(mgr ctrl.Manager, multiClusterM
 }
 
 func (r *InstanceSetReconciler) setupWithManager(mgr ctrl.Manager, ctx *handler.FinderContext) error {
+	// for the nodeDrainHandler to list the pods on the draining nodes
+	if err := mgr.GetFieldIndexer().IndexField(ctx.Context, &corev1.Pod{}, podNodeNameField, podNodeNameIndexer); err != nil {
+		return err
+	}
 	itsFinder := handler.NewLabelFinder(&workloads.InstanceSet{}, instanceset.WorkloadsManagedByLabelKey, workloads.Kind, instanceset.WorkloadsInstanceLabelKey)
 	podHandler := handler.NewBuilder(ctx).AddFinder(itsFinder).Build()
 	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
@@ -118,6 +138,7 @@ func (r *InstanceSetReconciler) setupWithManager(mgr ctrl.Manager, ctx *handler.
 			MaxConcurrentReconciles: viper.GetInt(constant.CfgKBReconcileWorkers),
 		}).
 		Watches(&corev1.Pod{}, podHandler).
+		Watches(&corev1.Node{}, &nodeDrainHandler{Client: r.Client}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&batchv1.Job{}).
 		Owns(&corev1.Service{}).
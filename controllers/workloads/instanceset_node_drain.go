@@ -0,0 +1,415 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package workloads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	"github.com/apecloud/kubeblocks/pkg/controller/instanceset"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	podutils "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	// drainSwitchoverInterval is the interval to wait for the role labels to be updated after a switchover is issued,
+	// before another switchover is issued for the same pod.
+	drainSwitchoverInterval = 30 * time.Second
+
+	// drainRequeueInterval is the interval to check the leadership of the pods on draining nodes again.
+	drainRequeueInterval = 5 * time.Second
+
+	// toBeDeletedTaint is added by the cluster autoscaler to the nodes being scaled down, before draining them.
+	toBeDeletedTaint = "ToBeDeletedByClusterAutoscaler"
+
+	// podNodeNameField is the field index of pods by the node they are running on.
+	podNodeNameField = "spec.nodeName"
+
+	// drainHoldLabelKey labels the PodDisruptionBudgets that hold the eviction of the leader pods on draining nodes,
+	// the value is the name of the leader pod.
+	drainHoldLabelKey = "workloads.kubeblocks.io/drain-hold"
+
+	eventReasonDrainSwitchover       = "DrainSwitchover"
+	eventReasonDrainSwitchoverFailed = "DrainSwitchoverFailed"
+)
+
+// drainSwitchoverTracker records the time when the last switchover was issued for the leader pods, grouped by the InstanceSet.
+// The pods of an InstanceSet are reconciled by one worker at a time, so the pods map of an InstanceSet is never shared.
+type drainSwitchoverTracker struct {
+	issued sync.Map // types.NamespacedName -> map[types.UID]time.Time
+}
+
+func (t *drainSwitchoverTracker) recentlyIssued(its types.NamespacedName, pod *corev1.Pod) bool {
+	val, ok := t.issued.Load(its)
+	if !ok {
+		return false
+	}
+	issuedAt, ok := val.(map[types.UID]time.Time)[pod.UID]
+	return ok && time.Since(issuedAt) < drainSwitchoverInterval
+}
+
+func (t *drainSwitchoverTracker) issue(its types.NamespacedName, pod *corev1.Pod) {
+	pods := map[types.UID]time.Time{}
+	if val, ok := t.issued.Load(its); ok {
+		pods = val.(map[types.UID]time.Time)
+	}
+	pods[pod.UID] = time.Now()
+	t.issued.Store(its, pods)
+}
+
+// retain forgets the pods of the InstanceSet other than the given leaders, e.g., the pods that have been deleted,
+// or are no longer leaders, or are not on draining nodes anymore.
+func (t *drainSwitchoverTracker) retain(its types.NamespacedName, leaders []*corev1.Pod) {
+	val, ok := t.issued.Load(its)
+	if !ok {
+		return
+	}
+	pods := val.(map[types.UID]time.Time)
+	kept := make(map[types.UID]bool, len(leaders))
+	for _, leader := range leaders {
+		kept[leader.UID] = true
+	}
+	for uid := range pods {
+		if !kept[uid] {
+			delete(pods, uid)
+		}
+	}
+	if len(pods) == 0 {
+		t.issued.Delete(its)
+	}
+}
+
+// forget forgets all the pods of the InstanceSet.
+func (t *drainSwitchoverTracker) forget(its types.NamespacedName) {
+	t.issued.Delete(its)
+}
+
+// isNodeDraining tells whether the node is cordoned or about to be drained.
+func isNodeDraining(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeUnschedulable || taint.Key == toBeDeletedTaint {
+			return true
+		}
+	}
+	return false
+}
+
+// podNodeNameIndexer indexes the pods by the node they are running on.
+func podNodeNameIndexer(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || len(pod.Spec.NodeName) == 0 {
+		return nil
+	}
+	return []string{pod.Spec.NodeName}
+}
+
+// nodeDrainHandler enqueues the InstanceSets whose pods are running on the nodes being cordoned or drained,
+// the pods are listed by the podNodeNameField index.
+type nodeDrainHandler struct {
+	client.Client
+}
+
+var _ handler.EventHandler = &nodeDrainHandler{}
+
+func (h *nodeDrainHandler) Create(ctx context.Context, evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (h *nodeDrainHandler) Update(ctx context.Context, evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldNode, ok1 := evt.ObjectOld.(*corev1.Node)
+	newNode, ok2 := evt.ObjectNew.(*corev1.Node)
+	if !ok1 || !ok2 || isNodeDraining(oldNode) || !isNodeDraining(newNode) {
+		return
+	}
+	h.mapAndEnqueue(ctx, q, newNode)
+}
+
+func (h *nodeDrainHandler) Delete(ctx context.Context, evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (h *nodeDrainHandler) Generic(ctx context.Context, evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (h *nodeDrainHandler) mapAndEnqueue(ctx context.Context, q workqueue.RateLimitingInterface, node *corev1.Node) {
+	podList := &corev1.PodList{}
+	if err := h.Client.List(ctx, podList, client.MatchingFields{podNodeNameField: node.Name},
+		client.MatchingLabels{instanceset.WorkloadsManagedByLabelKey: workloads.Kind}); err != nil {
+		return
+	}
+	for _, pod := range podList.Items {
+		itsName, ok := pod.Labels[instanceset.WorkloadsInstanceLabelKey]
+		if !ok {
+			continue
+		}
+		q.Add(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: itsName}})
+	}
+}
+
+// switchoverOnDrainingNodes moves the leadership off the leader pods running on the nodes being cordoned or drained,
+// so that the leaders are not evicted blindly. Once a pod is no longer the leader, it's left to the drain.
+//
+// It takes effect only if the switchover action is declared in spec.membershipReconfiguration, and the action is called
+// through the Switchover lifecycle action of the component that the InstanceSet belongs to.
+//
+// The eviction of a leader is held by a temporary PodDisruptionBudget while its switchover is in progress, see holdDrainingLeaders.
+// If the switchover action fails, or the switchover is not done within the drainSwitchoverInterval after it is issued,
+// the leader is released to the drain, and the disruption is bounded by the PodDisruptionBudget of the component.
+func (r *InstanceSetReconciler) switchoverOnDrainingNodes(ctx context.Context, req ctrl.Request, logger logr.Logger) (ctrl.Result, error) {
+	its := &workloads.InstanceSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, its); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.drainSwitchovers.forget(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if model.IsObjectDeleting(its) || model.IsReconciliationPaused(its) ||
+		its.Spec.MembershipReconfiguration == nil || its.Spec.MembershipReconfiguration.SwitchoverAction == nil {
+		r.drainSwitchovers.forget(req.NamespacedName)
+		return ctrl.Result{}, r.holdDrainingLeaders(ctx, its, nil)
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.InNamespace(its.Namespace), client.MatchingLabels(its.Spec.Selector.MatchLabels)); err != nil {
+		return ctrl.Result{}, err
+	}
+	pods := make([]*corev1.Pod, 0, len(podList.Items))
+	drainingPods := make(map[string]bool)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		pods = append(pods, pod)
+		draining, err := r.isPodOnDrainingNode(ctx, pod)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		drainingPods[pod.Name] = draining
+	}
+
+	var drainingLeaders []*corev1.Pod
+	for _, leader := range leaderPods(its, pods) {
+		if drainingPods[leader.Name] && leader.DeletionTimestamp == nil {
+			drainingLeaders = append(drainingLeaders, leader)
+		}
+	}
+	r.drainSwitchovers.retain(req.NamespacedName, drainingLeaders)
+
+	for _, leader := range drainingLeaders {
+		if r.drainSwitchovers.recentlyIssued(req.NamespacedName, leader) {
+			continue
+		}
+		candidate := pickSwitchoverCandidate(its, pods, drainingPods)
+		if candidate == nil {
+			r.Recorder.Eventf(its, corev1.EventTypeWarning, eventReasonDrainSwitchoverFailed,
+				"the node of leader pod %s is draining, but there is no available candidate to switchover to", leader.Name)
+			continue
+		}
+		if err := r.switchover(ctx, its, leader, candidate, pods); err != nil {
+			if errors.Is(err, lifecycle.ErrActionNotDefined) {
+				r.drainSwitchovers.forget(req.NamespacedName)
+				return ctrl.Result{}, r.holdDrainingLeaders(ctx, its, nil)
+			}
+			r.Recorder.Eventf(its, corev1.EventTypeWarning, eventReasonDrainSwitchoverFailed,
+				"failed to switchover from leader pod %s on the draining node %s: %s", leader.Name, leader.Spec.NodeName, err.Error())
+			continue
+		}
+		r.drainSwitchovers.issue(req.NamespacedName, leader)
+		logger.Info(fmt.Sprintf("switchover from leader pod %s to %s as the node %s is draining", leader.Name, candidate.Name, leader.Spec.NodeName))
+		r.Recorder.Eventf(its, corev1.EventTypeNormal, eventReasonDrainSwitchover,
+			"switchover from leader pod %s to %s as the node %s is draining", leader.Name, candidate.Name, leader.Spec.NodeName)
+	}
+
+	var switchingLeaders []*corev1.Pod
+	for _, leader := range drainingLeaders {
+		if r.drainSwitchovers.recentlyIssued(req.NamespacedName, leader) {
+			switchingLeaders = append(switchingLeaders, leader)
+		}
+	}
+	if err := r.holdDrainingLeaders(ctx, its, switchingLeaders); err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(drainingLeaders) > 0 {
+		return ctrl.Result{RequeueAfter: drainRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// holdDrainingLeaders holds the eviction of the given leaders by a PodDisruptionBudget with maxUnavailable 0 for each of them,
+// and removes the budgets of the other pods. The budget selects the pod by its role label as well, so the pod is released
+// as soon as the role label moves away, even before the budget is removed.
+//
+// Note that the eviction of a pod selected by more than one budget, e.g., with the budget of the component, is rejected
+// by the API server with an error rather than a retryable 429 status, the drain should be retried in that case.
+func (r *InstanceSetReconciler) holdDrainingLeaders(ctx context.Context, its *workloads.InstanceSet, leaders []*corev1.Pod) error {
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	if err := r.Client.List(ctx, pdbList, client.InNamespace(its.Namespace), client.HasLabels{drainHoldLabelKey},
+		client.MatchingLabels{instanceset.WorkloadsManagedByLabelKey: workloads.Kind, instanceset.WorkloadsInstanceLabelKey: its.Name}); err != nil {
+		return err
+	}
+	held := sets.New[string]()
+	for i := range pdbList.Items {
+		pdb := &pdbList.Items[i]
+		podName := pdb.Labels[drainHoldLabelKey]
+		if slices.ContainsFunc(leaders, func(leader *corev1.Pod) bool { return leader.Name == podName }) {
+			held.Insert(podName)
+			continue
+		}
+		if err := r.Client.Delete(ctx, pdb); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	for _, leader := range leaders {
+		if held.Has(leader.Name) || len(leader.Labels[constant.RoleLabelKey]) == 0 {
+			continue
+		}
+		pdb := buildDrainHoldPDB(its, leader)
+		if err := controllerutil.SetControllerReference(its, pdb, r.Client.Scheme()); err != nil {
+			return err
+		}
+		if err := r.Client.Create(ctx, pdb); client.IgnoreAlreadyExists(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildDrainHoldPDB(its *workloads.InstanceSet, leader *corev1.Pod) *policyv1.PodDisruptionBudget {
+	return builder.NewPDBBuilder(its.Namespace, fmt.Sprintf("%s-drain-hold", leader.Name)).
+		AddLabels(instanceset.WorkloadsManagedByLabelKey, workloads.Kind,
+			instanceset.WorkloadsInstanceLabelKey, its.Name,
+			drainHoldLabelKey, leader.Name).
+		AddSelectorsInMap(its.Spec.Selector.MatchLabels).
+		AddSelectorsInMap(map[string]string{
+			constant.KBAppPodNameLabelKey: leader.Name,
+			constant.RoleLabelKey:         leader.Labels[constant.RoleLabelKey],
+		}).
+		SetMaxUnavailable(intstr.FromInt32(0)).
+		GetObject()
+}
+
+func (r *InstanceSetReconciler) isPodOnDrainingNode(ctx context.Context, pod *corev1.Pod) (bool, error) {
+	if len(pod.Spec.NodeName) == 0 {
+		return false, nil
+	}
+	node := &corev1.Node{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isNodeDraining(node), nil
+}
+
+// switchover calls the Switchover lifecycle action of the component that the InstanceSet belongs to.
+func (r *InstanceSetReconciler) switchover(ctx context.Context, its *workloads.InstanceSet, leader, candidate *corev1.Pod, pods []*corev1.Pod) error {
+	clusterName, ok1 := its.Labels[constant.AppInstanceLabelKey]
+	compName, ok2 := its.Labels[constant.KBAppComponentLabelKey]
+	if !ok1 || !ok2 {
+		return lifecycle.ErrActionNotDefined
+	}
+	cluster := &appsv1.Cluster{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: its.Namespace, Name: clusterName}, cluster); err != nil {
+		return err
+	}
+	compObj, compDef, err := component.GetCompNCompDefByName(ctx, r.Client, its.Namespace, constant.GenerateClusterComponentName(clusterName, compName))
+	if err != nil {
+		return err
+	}
+	synthesizedComp, err := component.BuildSynthesizedComponent(ctx, r.Client, compDef, compObj, cluster)
+	if err != nil {
+		return err
+	}
+	synthesizedComp.TemplateVars, _, err = component.ResolveTemplateNEnvVars(ctx, r.Client, synthesizedComp, compDef.Spec.Vars)
+	if err != nil {
+		return err
+	}
+	lfa, err := lifecycle.New(synthesizedComp, leader, pods...)
+	if err != nil {
+		return err
+	}
+	return lfa.Switchover(ctx, r.Client, nil, candidate.Name)
+}
+
+// leaderPods returns the pods that are the leaders according to the status.membersStatus.
+func leaderPods(its *workloads.InstanceSet, pods []*corev1.Pod) []*corev1.Pod {
+	var leaders []*corev1.Pod
+	for _, member := range its.Status.MembersStatus {
+		if member.ReplicaRole == nil || !member.ReplicaRole.IsLeader {
+			continue
+		}
+		for _, pod := range pods {
+			if pod.Name == member.PodName {
+				leaders = append(leaders, pod)
+			}
+		}
+	}
+	return leaders
+}
+
+// pickSwitchoverCandidate picks a ready non-leader member that is not running on a draining node,
+// the members with the roles that can vote are preferred.
+func pickSwitchoverCandidate(its *workloads.InstanceSet, pods []*corev1.Pod, drainingPods map[string]bool) *corev1.Pod {
+	podMap := make(map[string]*corev1.Pod, len(pods))
+	for _, pod := range pods {
+		podMap[pod.Name] = pod
+	}
+	var candidate *corev1.Pod
+	for _, member := range its.Status.MembersStatus {
+		if member.ReplicaRole == nil || member.ReplicaRole.IsLeader {
+			continue
+		}
+		pod, ok := podMap[member.PodName]
+		if !ok || drainingPods[pod.Name] || pod.DeletionTimestamp != nil || !podutils.IsAvailable(pod, its.Spec.MinReadySeconds) {
+			continue
+		}
+		if member.ReplicaRole.CanVote {
+			return pod
+		}
+		if candidate == nil {
+			candidate = pod
+		}
+	}
+	return candidate
+}
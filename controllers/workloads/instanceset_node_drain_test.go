@@ -0,0 +1,405 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package workloads
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/instanceset"
+	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
+	kbagentproto "github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+var _ = Describe("InstanceSet node drain", func() {
+	Context("isNodeDraining", func() {
+		It("should detect the cordoned and draining nodes", func() {
+			node := &corev1.Node{}
+			Expect(isNodeDraining(node)).Should(BeFalse())
+
+			node.Spec.Unschedulable = true
+			Expect(isNodeDraining(node)).Should(BeTrue())
+
+			node = &corev1.Node{}
+			node.Spec.Taints = []corev1.Taint{{Key: toBeDeletedTaint, Effect: corev1.TaintEffectNoSchedule}}
+			Expect(isNodeDraining(node)).Should(BeTrue())
+		})
+	})
+
+	Context("switchover candidate", func() {
+		readyPod := func(name string) *corev1.Pod {
+			pod := builder.NewPodBuilder("default", name).GetObject()
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			return pod
+		}
+
+		It("should pick a ready voter that is not on a draining node", func() {
+			leader := workloads.ReplicaRole{Name: "leader", IsLeader: true, CanVote: true}
+			follower := workloads.ReplicaRole{Name: "follower", CanVote: true}
+			learner := workloads.ReplicaRole{Name: "learner"}
+			its := builder.NewInstanceSetBuilder("default", "foo").GetObject()
+			its.Status.MembersStatus = []workloads.MemberStatus{
+				{PodName: "foo-0", ReplicaRole: &leader},
+				{PodName: "foo-1", ReplicaRole: &learner},
+				{PodName: "foo-2", ReplicaRole: &follower},
+				{PodName: "foo-3", ReplicaRole: &follower},
+			}
+			pods := []*corev1.Pod{readyPod("foo-0"), readyPod("foo-1"), readyPod("foo-2"), readyPod("foo-3")}
+
+			leaders := leaderPods(its, pods)
+			Expect(leaders).Should(HaveLen(1))
+			Expect(leaders[0].Name).Should(Equal("foo-0"))
+
+			By("prefer the voter")
+			draining := map[string]bool{"foo-0": true}
+			Expect(pickSwitchoverCandidate(its, pods, draining).Name).Should(Equal("foo-2"))
+
+			By("skip the voter on the draining node and the terminating one")
+			draining["foo-2"] = true
+			pods[3].DeletionTimestamp = &metav1.Time{}
+			Expect(pickSwitchoverCandidate(its, pods, draining).Name).Should(Equal("foo-1"))
+
+			By("no candidate available")
+			pods[1].Status.Conditions = nil
+			Expect(pickSwitchoverCandidate(its, pods, draining)).Should(BeNil())
+		})
+	})
+
+	Context("switchover on draining nodes", func() {
+		const (
+			namespace   = "default"
+			clusterName = "test-cluster"
+			compName    = "comp"
+			compDefName = "test-compdef"
+		)
+
+		var (
+			itsKey     = types.NamespacedName{Namespace: namespace, Name: constant.GenerateWorkloadNamePattern(clusterName, compName)}
+			req        = ctrl.Request{NamespacedName: itsKey}
+			cli        client.Client
+			reconciler *InstanceSetReconciler
+			recorder   *record.FakeRecorder
+			candidates []string
+		)
+
+		newNode := func(name string, draining bool) *corev1.Node {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			node.Spec.Unschedulable = draining
+			return node
+		}
+
+		newPod := func(name, nodeName, role string) *corev1.Pod {
+			pod := builder.NewPodBuilder(namespace, name).
+				AddLabelsInMap(constant.GetCompLabels(clusterName, compName)).
+				AddLabels(instanceset.WorkloadsManagedByLabelKey, workloads.Kind,
+					instanceset.WorkloadsInstanceLabelKey, itsKey.Name,
+					constant.KBAppPodNameLabelKey, name,
+					constant.RoleLabelKey, role).
+				SetUID(types.UID(name)).
+				SetNodeName(types.NodeName(nodeName)).
+				GetObject()
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			return pod
+		}
+
+		newITS := func(switchover bool) *workloads.InstanceSet {
+			leader := workloads.ReplicaRole{Name: "leader", IsLeader: true, CanVote: true}
+			follower := workloads.ReplicaRole{Name: "follower", CanVote: true}
+			its := builder.NewInstanceSetBuilder(namespace, itsKey.Name).
+				AddLabels(constant.AppInstanceLabelKey, clusterName, constant.KBAppComponentLabelKey, compName).
+				AddMatchLabelsInMap(constant.GetCompLabels(clusterName, compName)).
+				SetRoles([]workloads.ReplicaRole{leader, follower}).
+				GetObject()
+			if switchover {
+				its.Spec.MembershipReconfiguration = &workloads.MembershipReconfiguration{
+					SwitchoverAction: &workloads.Action{Command: []string{"switchover"}},
+				}
+			}
+			its.Status.MembersStatus = []workloads.MemberStatus{
+				{PodName: itsKey.Name + "-0", ReplicaRole: &leader},
+				{PodName: itsKey.Name + "-1", ReplicaRole: &follower},
+			}
+			return its
+		}
+
+		newClient := func(objs ...client.Object) client.Client {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+			Expect(appsv1.AddToScheme(scheme)).Should(Succeed())
+			Expect(workloads.AddToScheme(scheme)).Should(Succeed())
+
+			cluster := &appsv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: clusterName, UID: "cluster-uid"},
+				Spec: appsv1.ClusterSpec{
+					ComponentSpecs: []appsv1.ClusterComponentSpec{{Name: compName, ComponentDef: compDefName}},
+				},
+			}
+			comp := builder.NewComponentBuilder(namespace, constant.GenerateClusterComponentName(clusterName, compName), compDefName).
+				AddLabelsInMap(constant.GetCompLabels(clusterName, compName)).
+				AddAnnotations(constant.KBAppClusterUIDKey, string(cluster.UID)).
+				SetReplicas(2).
+				GetObject()
+			compDef := &appsv1.ComponentDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: compDefName},
+				Spec: appsv1.ComponentDefinitionSpec{
+					Roles: []appsv1.ReplicaRole{
+						{Name: "leader", Serviceable: true, Writable: true, Votable: true},
+						{Name: "follower", Serviceable: true, Votable: true},
+					},
+					LifecycleActions: &appsv1.ComponentLifecycleActions{
+						Switchover: &appsv1.Action{Exec: &appsv1.ExecAction{Command: []string{"switchover"}}},
+					},
+				},
+			}
+			objs = append(objs, cluster, comp, compDef)
+			return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(&workloads.InstanceSet{}).
+				WithIndex(&corev1.Pod{}, podNodeNameField, podNodeNameIndexer).Build()
+		}
+
+		reconcile := func() ctrl.Result {
+			result, err := reconciler.switchoverOnDrainingNodes(context.Background(), req, logf.Log)
+			Expect(err).Should(Succeed())
+			return result
+		}
+
+		issued := func() bool {
+			_, ok := reconciler.drainSwitchovers.issued.Load(itsKey)
+			return ok
+		}
+
+		// evictable tells whether the eviction of the pod is allowed by the PodDisruptionBudgets selecting it.
+		evictable := func(name string) bool {
+			pod := &corev1.Pod{}
+			Expect(cli.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, pod)).Should(Succeed())
+			pdbList := &policyv1.PodDisruptionBudgetList{}
+			Expect(cli.List(context.Background(), pdbList, client.InNamespace(namespace))).Should(Succeed())
+			for _, pdb := range pdbList.Items {
+				selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+				Expect(err).Should(Succeed())
+				if selector.Matches(labels.Set(pod.Labels)) && pdb.Spec.MaxUnavailable.IntValue() == 0 {
+					return false
+				}
+			}
+			return true
+		}
+
+		held := func() []string {
+			pdbList := &policyv1.PodDisruptionBudgetList{}
+			Expect(cli.List(context.Background(), pdbList, client.InNamespace(namespace), client.HasLabels{drainHoldLabelKey})).Should(Succeed())
+			var pods []string
+			for _, pdb := range pdbList.Items {
+				pods = append(pods, pdb.Labels[drainHoldLabelKey])
+			}
+			return pods
+		}
+
+		BeforeEach(func() {
+			candidates = nil
+			testapps.MockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req kbagentproto.ActionRequest) (kbagentproto.ActionResponse, error) {
+					if req.Action == "switchover" {
+						candidates = append(candidates, req.Parameters["KB_SWITCHOVER_CANDIDATE_NAME"])
+					}
+					return kbagentproto.ActionResponse{}, nil
+				}).AnyTimes()
+			})
+		})
+
+		AfterEach(func() {
+			kbacli.UnsetMockClient()
+		})
+
+		setup := func(its *workloads.InstanceSet, drainingNodes ...bool) {
+			cli = newClient(its,
+				newNode("node-0", drainingNodes[0]), newNode("node-1", drainingNodes[1]),
+				newPod(itsKey.Name+"-0", "node-0", "leader"), newPod(itsKey.Name+"-1", "node-1", "follower"))
+			recorder = record.NewFakeRecorder(100)
+			reconciler = &InstanceSetReconciler{Client: cli, Recorder: recorder}
+		}
+
+		It("should switchover the leader on the draining node once", func() {
+			setup(newITS(true), true, false)
+
+			By("issue the switchover to the follower")
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(candidates).Should(Equal([]string{itsKey.Name + "-1"}))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(eventReasonDrainSwitchover)))
+			Expect(issued()).Should(BeTrue())
+
+			By("not issue it again within the interval")
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(candidates).Should(HaveLen(1))
+
+			By("issue it again after the interval")
+			val, _ := reconciler.drainSwitchovers.issued.Load(itsKey)
+			val.(map[types.UID]time.Time)[types.UID(itsKey.Name+"-0")] = time.Now().Add(-drainSwitchoverInterval)
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(candidates).Should(HaveLen(2))
+		})
+
+		It("should hold the eviction of the leader until the switchover is done", func() {
+			its := newITS(true)
+			setup(its, true, false)
+			leader, follower := itsKey.Name+"-0", itsKey.Name+"-1"
+			Expect(evictable(leader)).Should(BeTrue())
+
+			By("the eviction of the leader is refused once the switchover is issued")
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(candidates).Should(Equal([]string{follower}))
+			Expect(held()).Should(Equal([]string{leader}))
+			Expect(evictable(leader)).Should(BeFalse())
+			Expect(evictable(follower)).Should(BeTrue())
+
+			By("still refused while the switchover is in progress")
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(held()).Should(Equal([]string{leader}))
+			Expect(evictable(leader)).Should(BeFalse())
+
+			By("allowed once the role label moves")
+			setRole := func(name, role string) {
+				pod := &corev1.Pod{}
+				Expect(cli.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, pod)).Should(Succeed())
+				pod.Labels[constant.RoleLabelKey] = role
+				Expect(cli.Update(context.Background(), pod)).Should(Succeed())
+			}
+			setRole(leader, "follower")
+			setRole(follower, "leader")
+			Expect(evictable(leader)).Should(BeTrue())
+			Expect(evictable(follower)).Should(BeTrue())
+
+			By("the hold is removed once the leadership moves")
+			Expect(cli.Get(context.Background(), itsKey, its)).Should(Succeed())
+			its.Status.MembersStatus[0].ReplicaRole, its.Status.MembersStatus[1].ReplicaRole =
+				its.Status.MembersStatus[1].ReplicaRole, its.Status.MembersStatus[0].ReplicaRole
+			Expect(cli.Status().Update(context.Background(), its)).Should(Succeed())
+			Expect(reconcile()).Should(Equal(ctrl.Result{}))
+			Expect(held()).Should(BeEmpty())
+		})
+
+		It("should release the leader if the switchover is not done in time", func() {
+			setup(newITS(true), true, false)
+			leader := itsKey.Name + "-0"
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(evictable(leader)).Should(BeFalse())
+
+			By("the switchover can not be issued again")
+			val, _ := reconciler.drainSwitchovers.issued.Load(itsKey)
+			val.(map[types.UID]time.Time)[types.UID(leader)] = time.Now().Add(-drainSwitchoverInterval)
+			Expect(cli.Delete(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})).Should(Succeed())
+			Expect(cli.Create(context.Background(), newNode("node-1", true))).Should(Succeed())
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(eventReasonDrainSwitchover)))
+			Expect(recorder.Events).Should(Receive(ContainSubstring(eventReasonDrainSwitchoverFailed)))
+			Expect(held()).Should(BeEmpty())
+			Expect(evictable(leader)).Should(BeTrue())
+		})
+
+		It("should forget the switchover once the leader moves or the InstanceSet is gone", func() {
+			its := newITS(true)
+			setup(its, true, false)
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(issued()).Should(BeTrue())
+
+			By("the leadership moved to the follower")
+			Expect(cli.Get(context.Background(), itsKey, its)).Should(Succeed())
+			its.Status.MembersStatus[0].ReplicaRole, its.Status.MembersStatus[1].ReplicaRole =
+				its.Status.MembersStatus[1].ReplicaRole, its.Status.MembersStatus[0].ReplicaRole
+			Expect(cli.Status().Update(context.Background(), its)).Should(Succeed())
+			Expect(reconcile()).Should(Equal(ctrl.Result{}))
+			Expect(issued()).Should(BeFalse())
+
+			By("the InstanceSet is deleted")
+			reconciler.drainSwitchovers.issue(itsKey, newPod(itsKey.Name+"-1", "node-1", "leader"))
+			Expect(cli.Delete(context.Background(), its)).Should(Succeed())
+			Expect(reconcile()).Should(Equal(ctrl.Result{}))
+			Expect(issued()).Should(BeFalse())
+		})
+
+		It("should warn if there is no candidate to switchover to", func() {
+			setup(newITS(true), true, true)
+
+			Expect(reconcile().RequeueAfter).Should(Equal(drainRequeueInterval))
+			Expect(candidates).Should(BeEmpty())
+			Expect(recorder.Events).Should(Receive(ContainSubstring(eventReasonDrainSwitchoverFailed)))
+			Expect(issued()).Should(BeFalse())
+			Expect(held()).Should(BeEmpty())
+		})
+
+		It("should do nothing without the switchover action", func() {
+			setup(newITS(false), true, false)
+
+			Expect(reconcile()).Should(Equal(ctrl.Result{}))
+			Expect(candidates).Should(BeEmpty())
+			Expect(recorder.Events).ShouldNot(Receive())
+			Expect(held()).Should(BeEmpty())
+		})
+
+		It("should enqueue the InstanceSets of the pods on the node being cordoned", func() {
+			setup(newITS(true), false, false)
+			h := &nodeDrainHandler{Client: cli}
+			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer q.ShutDown()
+
+			By("the node is not draining")
+			h.Update(context.Background(), event.UpdateEvent{ObjectOld: newNode("node-0", false), ObjectNew: newNode("node-0", false)}, q)
+			Expect(q.Len()).Should(Equal(0))
+
+			By("the node has been draining already")
+			h.Update(context.Background(), event.UpdateEvent{ObjectOld: newNode("node-0", true), ObjectNew: newNode("node-0", true)}, q)
+			Expect(q.Len()).Should(Equal(0))
+
+			By("the node without pods is cordoned")
+			h.Update(context.Background(), event.UpdateEvent{ObjectOld: newNode("node-2", false), ObjectNew: newNode("node-2", true)}, q)
+			Expect(q.Len()).Should(Equal(0))
+
+			By("the node is cordoned")
+			h.Update(context.Background(), event.UpdateEvent{ObjectOld: newNode("node-0", false), ObjectNew: newNode("node-0", true)}, q)
+			Expect(q.Len()).Should(Equal(1))
+			item, _ := q.Get()
+			Expect(item).Should(Equal(req))
+		})
+	})
+})
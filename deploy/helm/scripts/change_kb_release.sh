@@ -25,6 +25,8 @@ clusterRoles=(
     "dataprotection-exec-worker-role"
     "restore-editor-role"
     "nodecountscaler-editor-role"
+    "metricsscaler-editor-role"
+    "verticalscaler-editor-role"
     "editor-role"
     "leader-election-role"
     "rbac-manager-role"
@@ -0,0 +1,45 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ParseCronExpression parses a standard cron expression by robfig/cron, the descriptors such as "@daily" are supported
+// as well. The expression is evaluated in the time zone given, or UTC if empty, unless it is prefixed with its own
+// time zone by "CRON_TZ=" or "TZ=", e.g. "CRON_TZ=Asia/Shanghai 0 2 * * *", the same as the BackupSchedule.
+func ParseCronExpression(expr string, timeZone string) (cron.Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "CRON_TZ=") && !strings.HasPrefix(expr, "TZ=") {
+		if len(timeZone) == 0 {
+			timeZone = "UTC"
+		}
+		expr = fmt.Sprintf("CRON_TZ=%s %s", timeZone, expr)
+	}
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %s", expr, err.Error())
+	}
+	return schedule, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronExpression(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "*/15 0-6 1,15 jan-jun MON-FRI", "30 2 * * 0", "@daily", "CRON_TZ=Asia/Shanghai 0 2 * * *", "TZ=UTC @hourly"} {
+		if _, err := ParseCronExpression(expr, ""); err != nil {
+			t.Errorf("expected %q to be valid, got error: %s", expr, err.Error())
+		}
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"5-1 * * * *", "a * * * *", "CRON_TZ=Invalid/Zone * * * * *"} {
+		if _, err := ParseCronExpression(expr, ""); err == nil {
+			t.Errorf("expected %q to be invalid", expr)
+		}
+	}
+	if _, err := ParseCronExpression("* * * * *", "Invalid/Zone"); err == nil {
+		t.Errorf("expected the time zone to be invalid")
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-06-01 is a Saturday
+	from := time.Date(2024, 6, 1, 10, 20, 30, 0, time.UTC)
+	for _, c := range []struct {
+		expr     string
+		timeZone string
+		expected time.Time
+	}{
+		{"* * * * *", "", time.Date(2024, 6, 1, 10, 21, 0, 0, time.UTC)},
+		{"*/15 * * * *", "", time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * *", "", time.Date(2024, 6, 2, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * mon-fri", "", time.Date(2024, 6, 3, 2, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", "", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", "", time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)},
+		{"0 2 * * *", "Asia/Shanghai", time.Date(2024, 6, 2, 2, 0, 0, 0, shanghai)},
+		// the time zone of the expression takes precedence
+		{"CRON_TZ=Asia/Shanghai 0 2 * * *", "UTC", time.Date(2024, 6, 2, 2, 0, 0, 0, shanghai)},
+		{"TZ=Asia/Shanghai 0 20 * * *", "", time.Date(2024, 6, 1, 20, 0, 0, 0, shanghai)},
+		{"0 0 30 2 *", "", time.Time{}},
+	} {
+		schedule, err := ParseCronExpression(c.expr, c.timeZone)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %s", c.expr, err.Error())
+		}
+		if next := schedule.Next(from); !next.Equal(c.expected) {
+			t.Errorf("expected the next time of %q in %q from %s to be %s, got %s", c.expr, c.timeZone, from, c.expected, next)
+		}
+	}
+}
@@ -42,6 +42,33 @@ const (
 
 	// NodeSelectorOnceAnnotationKey adds nodeSelector in podSpec for one pod exactly once
 	NodeSelectorOnceAnnotationKey = "workloads.kubeblocks.io/node-selector-once"
+
+	// CanaryPromoteAnnotationKey promotes the paused canary rollout of an InstanceSet to the next step, it is kept until
+	// the current step is paused, and ignored if the rollout is completed or aborted
+	CanaryPromoteAnnotationKey = "workloads.kubeblocks.io/canary-promote"
+
+	// CanaryAbortAnnotationKey aborts the canary rollout of an InstanceSet
+	CanaryAbortAnnotationKey = "workloads.kubeblocks.io/canary-abort"
+
+	// KBAgentTransportAnnotationKey specifies the transport used to call the kb-agent of the component,
+	// the supported values are "http" (default) and "grpc".
+	KBAgentTransportAnnotationKey = "apps.kubeblocks.io/kbagent-transport"
+	// KBAgentTransportGRPC is the value of KBAgentTransportAnnotationKey to call the kb-agent through gRPC.
+	KBAgentTransportGRPC = "grpc"
+
+	// KBAgentAuthAnnotationKey specifies how the kb-agent of the component authenticates the callers,
+	// the supported values are "token" (bearer token) and "mtls" (mutual TLS and bearer token).
+	KBAgentAuthAnnotationKey = "apps.kubeblocks.io/kbagent-auth"
+
+	// DataTransferCompressionAnnotationKey specifies the compression of the data transferred when seeding new replicas
+	// of the component, the supported value is "zstd", and the data is not compressed if it is not set.
+	DataTransferCompressionAnnotationKey = "apps.kubeblocks.io/data-transfer-compression"
+
+	// DRSwitchoverAnnotationKey requests a planned switchover between the primary and standby Clusters of a ClusterDisasterRecovery
+	DRSwitchoverAnnotationKey = "apps.kubeblocks.io/dr-switchover"
+
+	// DRFailoverAnnotationKey requests an emergency failover to the standby Cluster of a ClusterDisasterRecovery
+	DRFailoverAnnotationKey = "apps.kubeblocks.io/dr-failover"
 )
 
 // annotations for multi-cluster
@@ -39,6 +39,7 @@ const (
 	ComponentVersionLabelKey      = "componentversion.kubeblocks.io/name"
 	SidecarDefLabelKey            = "sidecardefinition.kubeblocks.io/name"
 	ServiceDescriptorNameLabelKey = "servicedescriptor.kubeblocks.io/name"
+	DisasterRecoveryLabelKey      = "apps.kubeblocks.io/disaster-recovery"
 	AddonNameLabelKey             = "extensions.kubeblocks.io/addon-name"
 
 	KBAppComponentLabelKey    = "apps.kubeblocks.io/component-name"
@@ -24,6 +24,9 @@ const (
 	OpsRequestTypeLabelKey      = "operations.kubeblocks.io/ops-type"
 	OpsRequestNameLabelKey      = "operations.kubeblocks.io/ops-name"
 	OpsRequestNamespaceLabelKey = "operations.kubeblocks.io/ops-namespace"
+	ScheduledOpsRequestLabelKey = "operations.kubeblocks.io/scheduled-ops-request"
+	OpsPipelineLabelKey         = "operations.kubeblocks.io/ops-pipeline"
+	OpsPipelineStepLabelKey     = "operations.kubeblocks.io/ops-pipeline-step"
 )
 
 // annotations
@@ -33,4 +36,7 @@ const (
 	RelatedOpsAnnotationKey            = "operations.kubeblocks.io/related-ops"
 	OpsDependentOnSuccessfulOpsAnnoKey = "operations.kubeblocks.io/dependent-on-successful-ops" // OpsDependentOnSuccessfulOpsAnnoKey wait for the dependent ops to succeed before executing the current ops. If it fails, this ops will also fail.
 	IgnoreHscaleValidateAnnoKey        = "apps.kubeblocks.io/ignore-strict-horizontal-scale-validation"
+	OpsCreatedByAnnotationKey          = "operations.kubeblocks.io/created-by"  // OpsCreatedByAnnotationKey records the user who created the OpsRequest, set by the webhook.
+	OpsApproveAnnotationKey            = "operations.kubeblocks.io/approve"     // OpsApproveAnnotationKey approves the OpsRequest if set to "true" by a user other than the creator.
+	OpsApprovedByAnnotationKey         = "operations.kubeblocks.io/approved-by" // OpsApprovedByAnnotationKey records the user who approved the OpsRequest, set by the webhook.
 )
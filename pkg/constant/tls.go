@@ -24,5 +24,13 @@ const (
 	CAName     = "ca.crt"
 	CertName   = "tls.crt"
 	KeyName    = "tls.key"
+	CAKeyName  = "ca.key"
 	MountPath  = "/etc/pki/tls"
 )
+
+// annotations for the TLS secret issued by KubeBlocks
+const (
+	TLSCertRotatedAtAnnotationKey    = "kubeblocks.io/tls-cert-rotated-at"    // the time when the certificates were re-issued
+	TLSCertReloadedAtAnnotationKey   = "kubeblocks.io/tls-cert-reloaded-at"   // the rotation time of the certificates that have been reloaded
+	TLSCertReloadedPodsAnnotationKey = "kubeblocks.io/tls-cert-reloaded-pods" // the pods that have reloaded the pending certificates
+)
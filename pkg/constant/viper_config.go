@@ -32,6 +32,7 @@ const (
 	CfgHostPortConfigMapName            = "HOST_PORT_CM_NAME"
 	CfgHostPortIncludeRanges            = "HOST_PORT_INCLUDE_RANGES"
 	CfgHostPortExcludeRanges            = "HOST_PORT_EXCLUDE_RANGES"
+	CfgEnableOpsApprovalWebhook         = "ENABLE_OPS_APPROVAL_WEBHOOK" // the webhook recording the creators and approvers of OpsRequests is installed.
 
 	// addon config keys
 	CfgKeyAddonJobTTL        = "ADDON_JOB_TTL"
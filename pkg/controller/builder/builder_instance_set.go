@@ -168,6 +168,11 @@ func (builder *InstanceSetBuilder) SetMemberUpdateStrategy(strategy *workloads.M
 	return builder
 }
 
+func (builder *InstanceSetBuilder) SetCanaryStrategy(strategy *workloads.CanaryStrategy) *InstanceSetBuilder {
+	builder.get().Spec.CanaryStrategy = strategy
+	return builder
+}
+
 func (builder *InstanceSetBuilder) SetPaused(paused bool) *InstanceSetBuilder {
 	builder.get().Spec.Paused = paused
 	return builder
@@ -129,6 +129,12 @@ var _ = Describe("instance_set builder", func() {
 			Command: []string{"bar-2"},
 		}
 		memberUpdateStrategy := workloads.BestEffortParallelUpdateStrategy
+		canaryStrategy := workloads.CanaryStrategy{
+			Steps: []workloads.CanaryStep{
+				{Replicas: intstr.FromInt32(1), PauseSeconds: func() *int32 { p := int32(60); return &p }()},
+				{Replicas: intstr.FromString("50%")},
+			},
+		}
 		paused := true
 		credential := workloads.Credential{
 			Username: workloads.CredentialVar{Value: "foo"},
@@ -164,6 +170,7 @@ var _ = Describe("instance_set builder", func() {
 			SetCustomHandler(actions).
 			AddCustomHandler(action).
 			SetMemberUpdateStrategy(&memberUpdateStrategy).
+			SetCanaryStrategy(&canaryStrategy).
 			SetPaused(paused).
 			SetCredential(credential).
 			SetInstances(instances).
@@ -203,6 +210,8 @@ var _ = Describe("instance_set builder", func() {
 		Expect(its.Spec.RoleProbe.CustomHandler[1]).Should(Equal(action))
 		Expect(its.Spec.MemberUpdateStrategy).ShouldNot(BeNil())
 		Expect(*its.Spec.MemberUpdateStrategy).Should(Equal(memberUpdateStrategy))
+		Expect(its.Spec.CanaryStrategy).ShouldNot(BeNil())
+		Expect(*its.Spec.CanaryStrategy).Should(Equal(canaryStrategy))
 		Expect(its.Spec.Paused).Should(Equal(paused))
 		Expect(its.Spec.Credential).ShouldNot(BeNil())
 		Expect(*its.Spec.Credential).Should(Equal(credential))
@@ -0,0 +1,64 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package builder
+
+import (
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+)
+
+type MetricsScalerBuilder struct {
+	BaseBuilder[experimental.MetricsScaler, *experimental.MetricsScaler, MetricsScalerBuilder]
+}
+
+func NewMetricsScalerBuilder(namespace, name string) *MetricsScalerBuilder {
+	builder := &MetricsScalerBuilder{}
+	builder.init(namespace, name, &experimental.MetricsScaler{}, builder)
+	return builder
+}
+
+func (builder *MetricsScalerBuilder) SetTargetClusterName(clusterName string) *MetricsScalerBuilder {
+	builder.get().Spec.TargetClusterName = clusterName
+	return builder
+}
+
+func (builder *MetricsScalerBuilder) SetTargetComponentName(componentName string) *MetricsScalerBuilder {
+	builder.get().Spec.TargetComponentName = componentName
+	return builder
+}
+
+func (builder *MetricsScalerBuilder) SetMinReplicas(replicas int32) *MetricsScalerBuilder {
+	builder.get().Spec.MinReplicas = &replicas
+	return builder
+}
+
+func (builder *MetricsScalerBuilder) SetMaxReplicas(replicas int32) *MetricsScalerBuilder {
+	builder.get().Spec.MaxReplicas = replicas
+	return builder
+}
+
+func (builder *MetricsScalerBuilder) AddMetrics(metrics ...experimental.MetricSpec) *MetricsScalerBuilder {
+	builder.get().Spec.Metrics = append(builder.get().Spec.Metrics, metrics...)
+	return builder
+}
+
+func (builder *MetricsScalerBuilder) SetBehavior(behavior *experimental.ScalingBehavior) *MetricsScalerBuilder {
+	builder.get().Spec.Behavior = behavior
+	return builder
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package builder
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+)
+
+var _ = Describe("metrics_scaler builder", func() {
+	It("should work well", func() {
+		const (
+			name = "foo"
+			ns   = "default"
+		)
+		clusterName := "target-cluster-name"
+		componentName := "comp-1"
+		metric := experimental.MetricSpec{
+			Type: experimental.ResourceMetricSourceType,
+			Resource: &experimental.ResourceMetricSource{
+				Name:                     corev1.ResourceCPU,
+				TargetAverageUtilization: 80,
+			},
+		}
+		behavior := &experimental.ScalingBehavior{
+			ScaleDown: &experimental.ScalingRules{},
+		}
+
+		mts := NewMetricsScalerBuilder(ns, name).
+			SetTargetClusterName(clusterName).
+			SetTargetComponentName(componentName).
+			SetMinReplicas(1).
+			SetMaxReplicas(5).
+			AddMetrics(metric).
+			SetBehavior(behavior).
+			GetObject()
+
+		Expect(mts.Name).Should(Equal(name))
+		Expect(mts.Namespace).Should(Equal(ns))
+		Expect(mts.Spec.TargetClusterName).Should(Equal(clusterName))
+		Expect(mts.Spec.TargetComponentName).Should(Equal(componentName))
+		Expect(*mts.Spec.MinReplicas).Should(Equal(int32(1)))
+		Expect(mts.Spec.MaxReplicas).Should(Equal(int32(5)))
+		Expect(mts.Spec.Metrics).Should(Equal([]experimental.MetricSpec{metric}))
+		Expect(mts.Spec.Behavior).Should(Equal(behavior))
+	})
+})
@@ -0,0 +1,63 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package builder
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("pdb builder", func() {
+	It("should work well", func() {
+		const (
+			name = "foo"
+			ns   = "default"
+		)
+		selectors := map[string]string{
+			"foo": "bar",
+		}
+		minAvailable := intstr.FromInt32(2)
+		maxUnavailable := intstr.FromString("50%")
+		policy := policyv1.AlwaysAllow
+		pdb := NewPDBBuilder(ns, name).
+			AddSelectorsInMap(selectors).
+			AddSelectorsInMap(map[string]string{"hello": "world"}).
+			SetMinAvailable(minAvailable).
+			SetMaxUnavailable(maxUnavailable).
+			SetUnhealthyPodEvictionPolicy(policy).
+			GetObject()
+
+		Expect(pdb.Name).Should(Equal(name))
+		Expect(pdb.Namespace).Should(Equal(ns))
+		Expect(pdb.Spec.Selector).ShouldNot(BeNil())
+		Expect(pdb.Spec.Selector.MatchLabels).Should(HaveLen(2))
+		Expect(pdb.Spec.Selector.MatchLabels["foo"]).Should(Equal("bar"))
+		Expect(pdb.Spec.Selector.MatchLabels["hello"]).Should(Equal("world"))
+		Expect(pdb.Spec.MinAvailable).ShouldNot(BeNil())
+		Expect(*pdb.Spec.MinAvailable).Should(Equal(minAvailable))
+		Expect(pdb.Spec.MaxUnavailable).ShouldNot(BeNil())
+		Expect(*pdb.Spec.MaxUnavailable).Should(Equal(maxUnavailable))
+		Expect(pdb.Spec.UnhealthyPodEvictionPolicy).ShouldNot(BeNil())
+		Expect(*pdb.Spec.UnhealthyPodEvictionPolicy).Should(Equal(policy))
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package builder
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+)
+
+type VerticalScalerBuilder struct {
+	BaseBuilder[experimental.VerticalScaler, *experimental.VerticalScaler, VerticalScalerBuilder]
+}
+
+func NewVerticalScalerBuilder(namespace, name string) *VerticalScalerBuilder {
+	builder := &VerticalScalerBuilder{}
+	builder.init(namespace, name, &experimental.VerticalScaler{}, builder)
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetTargetClusterName(clusterName string) *VerticalScalerBuilder {
+	builder.get().Spec.TargetClusterName = clusterName
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetTargetComponentName(componentName string) *VerticalScalerBuilder {
+	builder.get().Spec.TargetComponentName = componentName
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetControlledResources(resources ...corev1.ResourceName) *VerticalScalerBuilder {
+	builder.get().Spec.ControlledResources = resources
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetPercentiles(request, limit int32) *VerticalScalerBuilder {
+	builder.get().Spec.RequestPercentile = request
+	builder.get().Spec.LimitPercentile = limit
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetSafetyMarginPercent(margin int32) *VerticalScalerBuilder {
+	builder.get().Spec.SafetyMarginPercent = margin
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetHistoryWindowSeconds(seconds int32) *VerticalScalerBuilder {
+	builder.get().Spec.HistoryWindowSeconds = seconds
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetMinAllowed(resources corev1.ResourceList) *VerticalScalerBuilder {
+	builder.get().Spec.MinAllowed = resources
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetMaxAllowed(resources corev1.ResourceList) *VerticalScalerBuilder {
+	builder.get().Spec.MaxAllowed = resources
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetUpdateMode(mode experimental.VerticalScalerUpdateMode) *VerticalScalerBuilder {
+	builder.get().Spec.UpdateMode = mode
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetMaintenanceWindow(window *appsv1.MaintenanceWindow) *VerticalScalerBuilder {
+	builder.get().Spec.MaintenanceWindow = window
+	return builder
+}
+
+func (builder *VerticalScalerBuilder) SetMinChangePercent(percent int32) *VerticalScalerBuilder {
+	builder.get().Spec.MinChangePercent = percent
+	return builder
+}
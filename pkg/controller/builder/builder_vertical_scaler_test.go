@@ -0,0 +1,80 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package builder
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+)
+
+var _ = Describe("vertical_scaler builder", func() {
+	It("should work well", func() {
+		const (
+			name = "foo"
+			ns   = "default"
+		)
+		clusterName := "target-cluster-name"
+		componentName := "comp-1"
+		minAllowed := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+		maxAllowed := corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")}
+		window := &appsv1.MaintenanceWindow{
+			CronExpression: "0 2 * * 0",
+			Duration:       metav1.Duration{Duration: 2 * time.Hour},
+		}
+
+		vts := NewVerticalScalerBuilder(ns, name).
+			SetTargetClusterName(clusterName).
+			SetTargetComponentName(componentName).
+			SetControlledResources(corev1.ResourceCPU).
+			SetPercentiles(80, 95).
+			SetSafetyMarginPercent(20).
+			SetHistoryWindowSeconds(7200).
+			SetMinAllowed(minAllowed).
+			SetMaxAllowed(maxAllowed).
+			SetUpdateMode(experimental.VerticalScalerUpdateModeAuto).
+			SetMaintenanceWindow(window).
+			SetMinChangePercent(5).
+			GetObject()
+
+		Expect(vts.Name).Should(Equal(name))
+		Expect(vts.Namespace).Should(Equal(ns))
+		Expect(vts.Spec.TargetClusterName).Should(Equal(clusterName))
+		Expect(vts.Spec.TargetComponentName).Should(Equal(componentName))
+		Expect(vts.Spec.ControlledResources).Should(Equal([]corev1.ResourceName{corev1.ResourceCPU}))
+		Expect(vts.Spec.RequestPercentile).Should(Equal(int32(80)))
+		Expect(vts.Spec.LimitPercentile).Should(Equal(int32(95)))
+		Expect(vts.Spec.SafetyMarginPercent).Should(Equal(int32(20)))
+		Expect(vts.Spec.HistoryWindowSeconds).Should(Equal(int32(7200)))
+		Expect(vts.Spec.MinAllowed).Should(Equal(minAllowed))
+		Expect(vts.Spec.MaxAllowed).Should(Equal(maxAllowed))
+		Expect(vts.Spec.UpdateMode).Should(Equal(experimental.VerticalScalerUpdateModeAuto))
+		Expect(vts.Spec.MaintenanceWindow).Should(Equal(window))
+		Expect(vts.Spec.MinChangePercent).Should(Equal(int32(5)))
+	})
+})
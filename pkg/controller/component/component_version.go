@@ -201,17 +201,21 @@ func actionsToResolveImage(compDef *appsv1.ComponentDefinition) map[string]*apps
 
 	normalize := strings.ToLower
 	actions := map[string]*appsv1.Action{
-		normalize("postProvision"):    compDef.Spec.LifecycleActions.PostProvision,
-		normalize("preTerminate"):     compDef.Spec.LifecycleActions.PreTerminate,
-		normalize("switchover"):       compDef.Spec.LifecycleActions.Switchover,
-		normalize("memberJoin"):       compDef.Spec.LifecycleActions.MemberJoin,
-		normalize("memberLeave"):      compDef.Spec.LifecycleActions.MemberLeave,
-		normalize("readonly"):         compDef.Spec.LifecycleActions.Readonly,
-		normalize("readwrite"):        compDef.Spec.LifecycleActions.Readwrite,
-		normalize("dataDump"):         compDef.Spec.LifecycleActions.DataDump,
-		normalize("dataLoad"):         compDef.Spec.LifecycleActions.DataLoad,
-		normalize("reconfigure"):      compDef.Spec.LifecycleActions.Reconfigure,
-		normalize("accountProvision"): compDef.Spec.LifecycleActions.AccountProvision,
+		normalize("postProvision"):         compDef.Spec.LifecycleActions.PostProvision,
+		normalize("preTerminate"):          compDef.Spec.LifecycleActions.PreTerminate,
+		normalize("switchover"):            compDef.Spec.LifecycleActions.Switchover,
+		normalize("memberJoin"):            compDef.Spec.LifecycleActions.MemberJoin,
+		normalize("memberLeave"):           compDef.Spec.LifecycleActions.MemberLeave,
+		normalize("readonly"):              compDef.Spec.LifecycleActions.Readonly,
+		normalize("readwrite"):             compDef.Spec.LifecycleActions.Readwrite,
+		normalize("dataDump"):              compDef.Spec.LifecycleActions.DataDump,
+		normalize("dataLoad"):              compDef.Spec.LifecycleActions.DataLoad,
+		normalize("reconfigure"):           compDef.Spec.LifecycleActions.Reconfigure,
+		normalize("tlsReload"):             compDef.Spec.LifecycleActions.TLSReload,
+		normalize("accountProvision"):      compDef.Spec.LifecycleActions.AccountProvision,
+		normalize("standbyReplicate"):      compDef.Spec.LifecycleActions.StandbyReplicate,
+		normalize("standbyReplicationLag"): compDef.Spec.LifecycleActions.StandbyReplicationLag,
+		normalize("standbyPromote"):        compDef.Spec.LifecycleActions.StandbyPromote,
 	}
 	if compDef.Spec.LifecycleActions.RoleProbe != nil {
 		actions[normalize("roleProbe")] = &compDef.Spec.LifecycleActions.RoleProbe.Action
@@ -338,8 +338,22 @@ func (c *itsCredentialConvertor) convert(args ...any) (any, error) {
 }
 
 func (c *itsMembershipReconfigurationConvertor) convert(args ...any) (any, error) {
-	// synthesizeComp, err := parseITSConvertorArgs(args...)
-	return "", nil // TODO
+	synthesizeComp, err := parseITSConvertorArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+	if synthesizeComp.LifecycleActions == nil || synthesizeComp.LifecycleActions.Switchover == nil ||
+		synthesizeComp.LifecycleActions.Switchover.Exec == nil {
+		return nil, nil
+	}
+	exec := synthesizeComp.LifecycleActions.Switchover.Exec
+	return &workloads.MembershipReconfiguration{
+		SwitchoverAction: &workloads.Action{
+			Image:   exec.Image,
+			Command: exec.Command,
+			Args:    exec.Args,
+		},
+	}, nil
 }
 
 // ConvertSynthesizeCompRoleToInstanceSetRole converts the component.SynthesizedComponent.Roles to workloads.ReplicaRole.
@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+)
+
+var _ = Describe("ITS convertor", func() {
+	Context("membership reconfiguration", func() {
+		var synthesizedComp *SynthesizedComponent
+
+		BeforeEach(func() {
+			synthesizedComp = &SynthesizedComponent{
+				LifecycleActions: &appsv1.ComponentLifecycleActions{
+					Switchover: &appsv1.Action{
+						Exec: &appsv1.ExecAction{
+							Image:   "switchover-image",
+							Command: []string{"/bin/sh", "-c"},
+							Args:    []string{"switchover.sh"},
+						},
+					},
+				},
+			}
+		})
+
+		It("converts the switchover action", func() {
+			obj, err := (&itsMembershipReconfigurationConvertor{}).convert(synthesizedComp)
+			Expect(err).Should(BeNil())
+			Expect(obj).Should(Equal(&workloads.MembershipReconfiguration{
+				SwitchoverAction: &workloads.Action{
+					Image:   "switchover-image",
+					Command: []string{"/bin/sh", "-c"},
+					Args:    []string{"switchover.sh"},
+				},
+			}))
+		})
+
+		It("no switchover action", func() {
+			synthesizedComp.LifecycleActions.Switchover.Exec = nil
+			obj, err := (&itsMembershipReconfigurationConvertor{}).convert(synthesizedComp)
+			Expect(err).Should(BeNil())
+			Expect(obj).Should(BeNil())
+
+			synthesizedComp.LifecycleActions = nil
+			obj, err = (&itsMembershipReconfigurationConvertor{}).convert(synthesizedComp)
+			Expect(err).Should(BeNil())
+			Expect(obj).Should(BeNil())
+		})
+
+		It("builds the workload with the switchover action", func() {
+			its, err := BuildWorkloadFrom(synthesizedComp, nil)
+			Expect(err).Should(BeNil())
+			Expect(its.Spec.MembershipReconfiguration).ShouldNot(BeNil())
+			Expect(its.Spec.MembershipReconfiguration.SwitchoverAction).ShouldNot(BeNil())
+			Expect(its.Spec.MembershipReconfiguration.SwitchoverAction.Image).Should(Equal("switchover-image"))
+
+			synthesizedComp.LifecycleActions.Switchover = nil
+			its, err = BuildWorkloadFrom(synthesizedComp, nil)
+			Expect(err).Should(BeNil())
+			Expect(its.Spec.MembershipReconfiguration).Should(BeNil())
+		})
+	})
+})
@@ -22,6 +22,7 @@ package component
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,12 +42,20 @@ const (
 	kbAgentCommand              = "/bin/kbagent"
 	kbAgentSharedMountPath      = "/kubeblocks"
 	kbAgentCommandOnSharedMount = "/kubeblocks/kbagent"
+	kbAgentVolumesMountPath     = "/kbagent/volumes"
 
 	minAvailablePort = 1025
 	maxAvailablePort = 65535
 
 	defaultProbeReportPeriodSeconds = 60
 	minProbeReportPeriodSeconds     = 15
+
+	volumeProtectionProbePeriodSeconds = 30
+
+	// KBAgentAuthToken requires the callers of kb-agent to present the bearer token.
+	KBAgentAuthToken = "token"
+	// KBAgentAuthMTLS requires the callers of kb-agent to present both the client certificate and the bearer token.
+	KBAgentAuthMTLS = "mtls"
 )
 
 var (
@@ -86,6 +95,9 @@ func UpdateKBAgentContainer4HostNetwork(synthesizedComp *SynthesizedComponent) {
 	}
 	updatePortInArgs("--port", httpPort)
 	updatePortInArgs("--streaming-port", port(kbagent.DefaultStreamingPortName))
+	if grpcPort := port(kbagent.DefaultGRPCPortName); grpcPort > 0 {
+		updatePortInArgs("--grpc-port", grpcPort)
+	}
 
 	// update startup probe
 	if c.StartupProbe != nil && c.StartupProbe.TCPSocket != nil {
@@ -118,6 +130,12 @@ func updateKBAgentTaskEnv(envVars map[string]string, f func(proto.Task) *proto.T
 	}, nil
 }
 
+// kbAgentGRPCEnabled checks whether the component opts into calling the kb-agent through gRPC,
+// the gRPC server of kb-agent is started only if so.
+func kbAgentGRPCEnabled(synthesizedComp *SynthesizedComponent) bool {
+	return synthesizedComp.Annotations[constant.KBAgentTransportAnnotationKey] == constant.KBAgentTransportGRPC
+}
+
 func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 	if synthesizedComp.LifecycleActions == nil {
 		return nil
@@ -147,13 +165,17 @@ func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 	}
 
 	container, err := newContainer(kbagent.ContainerName, func(b *builder.ContainerBuilder) error {
-		ports, err1 := getAvailablePorts(synthesizedComp.PodSpec.Containers,
-			[]int32{int32(kbagent.DefaultHTTPPort), int32(kbagent.DefaultStreamingPort)})
+		candidatePorts := []int32{int32(kbagent.DefaultHTTPPort), int32(kbagent.DefaultStreamingPort)}
+		if kbAgentGRPCEnabled(synthesizedComp) {
+			candidatePorts = append(candidatePorts, int32(kbagent.DefaultGRPCPort))
+		}
+		ports, err1 := getAvailablePorts(synthesizedComp.PodSpec.Containers, candidatePorts)
 		if err1 != nil {
 			return err1
 		}
 		httpPort, streamingPort := int(ports[0]), int(ports[1])
-		b.AddArgs("--port", strconv.Itoa(httpPort)).
+		b.AddVolumeMounts(volumeProtectionMounts(synthesizedComp)...).
+			AddArgs("--port", strconv.Itoa(httpPort)).
 			AddArgs("--streaming-port", strconv.Itoa(streamingPort)).
 			AddPorts(
 				corev1.ContainerPort{
@@ -165,11 +187,20 @@ func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 					ContainerPort: int32(streamingPort),
 					Name:          kbagent.DefaultStreamingPortName,
 					Protocol:      corev1.ProtocolTCP,
-				}).
-			SetStartupProbe(corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(httpPort)},
-				}})
+				})
+		if len(ports) > 2 {
+			grpcPort := int(ports[2])
+			b.AddArgs("--grpc-port", strconv.Itoa(grpcPort)).
+				AddPorts(corev1.ContainerPort{
+					ContainerPort: int32(grpcPort),
+					Name:          kbagent.DefaultGRPCPortName,
+					Protocol:      corev1.ProtocolTCP,
+				})
+		}
+		b.SetStartupProbe(corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(httpPort)},
+			}})
 		return nil
 	})
 	if err != nil {
@@ -188,23 +219,32 @@ func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 		return err
 	}
 
+	if err = handleKBAgentAuth(synthesizedComp, container, workerContainer); err != nil {
+		return err
+	}
+
 	// set kb-agent container ports to host network
 	if synthesizedComp.HostNetwork != nil {
 		if synthesizedComp.HostNetwork.ContainerPorts == nil {
 			synthesizedComp.HostNetwork.ContainerPorts = make([]appsv1.HostNetworkContainerPort, 0)
 		}
-		synthesizedComp.HostNetwork.ContainerPorts = append(
-			synthesizedComp.HostNetwork.ContainerPorts,
-			[]appsv1.HostNetworkContainerPort{
-				{
-					Container: container.Name,
-					Ports:     []string{kbagent.DefaultHTTPPortName},
-				},
-				{
-					Container: container.Name,
-					Ports:     []string{kbagent.DefaultStreamingPortName},
-				},
-			}...)
+		containerPorts := []appsv1.HostNetworkContainerPort{
+			{
+				Container: container.Name,
+				Ports:     []string{kbagent.DefaultHTTPPortName},
+			},
+			{
+				Container: container.Name,
+				Ports:     []string{kbagent.DefaultStreamingPortName},
+			},
+		}
+		if kbAgentGRPCEnabled(synthesizedComp) {
+			containerPorts = append(containerPorts, appsv1.HostNetworkContainerPort{
+				Container: container.Name,
+				Ports:     []string{kbagent.DefaultGRPCPortName},
+			})
+		}
+		synthesizedComp.HostNetwork.ContainerPorts = append(synthesizedComp.HostNetwork.ContainerPorts, containerPorts...)
 	}
 
 	synthesizedComp.PodSpec.Containers = append(synthesizedComp.PodSpec.Containers, *container)
@@ -239,13 +279,20 @@ func mergedActionEnv4KBAgent(synthesizedComp *SynthesizedComponent) []corev1.Env
 		synthesizedComp.LifecycleActions.DataDump,
 		synthesizedComp.LifecycleActions.DataLoad,
 		synthesizedComp.LifecycleActions.Reconfigure,
+		synthesizedComp.LifecycleActions.TLSReload,
 		synthesizedComp.LifecycleActions.AccountProvision,
+		synthesizedComp.LifecycleActions.StandbyReplicate,
+		synthesizedComp.LifecycleActions.StandbyReplicationLag,
+		synthesizedComp.LifecycleActions.StandbyPromote,
 	} {
 		checkedAppend(action)
 	}
 	if synthesizedComp.LifecycleActions.RoleProbe != nil {
 		checkedAppend(&synthesizedComp.LifecycleActions.RoleProbe.Action)
 	}
+	for i := range synthesizedComp.LifecycleActions.CustomProbes {
+		checkedAppend(&synthesizedComp.LifecycleActions.CustomProbes[i].Action)
+	}
 
 	return env
 }
@@ -289,9 +336,21 @@ func buildKBAgentStartupEnvs(synthesizedComp *SynthesizedComponent) ([]corev1.En
 	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Reconfigure, "reconfigure"); a != nil {
 		actions = append(actions, *a)
 	}
+	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.TLSReload, "tlsReload"); a != nil {
+		actions = append(actions, *a)
+	}
 	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.AccountProvision, "accountProvision"); a != nil {
 		actions = append(actions, *a)
 	}
+	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.StandbyReplicate, "standbyReplicate"); a != nil {
+		actions = append(actions, *a)
+	}
+	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.StandbyReplicationLag, "standbyReplicationLag"); a != nil {
+		actions = append(actions, *a)
+	}
+	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.StandbyPromote, "standbyPromote"); a != nil {
+		actions = append(actions, *a)
+	}
 
 	if a, p := buildProbe4KBAgent(synthesizedComp.LifecycleActions.RoleProbe, "roleProbe", synthesizedComp.FullCompName); a != nil && p != nil {
 		actions = append(actions, *a)
@@ -303,10 +362,117 @@ func buildKBAgentStartupEnvs(synthesizedComp *SynthesizedComponent) ([]corev1.En
 		actions = append(actions, *a)
 		probes = append(probes, *p)
 	}
+	if p := buildVolumeProtectionProbe4KBAgent(synthesizedComp); p != nil {
+		probes = append(probes, *p)
+	}
+	customActions, customProbes, err := buildCustomProbes4KBAgent(synthesizedComp, actions)
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions, customActions...)
+	probes = append(probes, customProbes...)
 
 	return kbagent.BuildEnv4Server(actions, probes, streaming)
 }
 
+// buildCustomProbes4KBAgent builds the custom probes, whose outputs are reported periodically to be consumed
+// as custom metrics of the replicas.
+func buildCustomProbes4KBAgent(synthesizedComp *SynthesizedComponent, builtin []proto.Action) ([]proto.Action, []proto.Probe, error) {
+	names := sets.New[string](availableProbe, volumeProtectionProbe)
+	for _, a := range builtin {
+		names.Insert(a.Name)
+	}
+	var (
+		actions []proto.Action
+		probes  []proto.Probe
+	)
+	for i := range synthesizedComp.LifecycleActions.CustomProbes {
+		probe := &synthesizedComp.LifecycleActions.CustomProbes[i]
+		if names.Has(probe.Name) {
+			return nil, nil, fmt.Errorf("the name of custom probe %s conflicts with other lifecycle actions", probe.Name)
+		}
+		names.Insert(probe.Name)
+		if a, p := buildProbe4KBAgent(&probe.Probe, probe.Name, synthesizedComp.FullCompName); a != nil && p != nil {
+			p.ReportPeriodSeconds = probeReportPeriodSeconds(p.PeriodSeconds)
+			actions = append(actions, *a)
+			probes = append(probes, *p)
+		}
+	}
+	return actions, probes, nil
+}
+
+// protectedVolumes returns the volumes that have the high watermark set, they are protected only if the readonly
+// action is defined.
+func protectedVolumes(synthesizedComp *SynthesizedComponent) []appsv1.ComponentVolume {
+	if synthesizedComp.LifecycleActions == nil ||
+		synthesizedComp.LifecycleActions.Readonly == nil || synthesizedComp.LifecycleActions.Readonly.Exec == nil {
+		return nil
+	}
+	claims := sets.New[string]()
+	for _, vct := range synthesizedComp.VolumeClaimTemplates {
+		claims.Insert(vct.Name)
+	}
+	volumes := make([]appsv1.ComponentVolume, 0)
+	for _, vol := range synthesizedComp.Volumes {
+		if vol.HighWatermark > 0 && claims.Has(vol.Name) {
+			volumes = append(volumes, vol)
+		}
+	}
+	return volumes
+}
+
+// monitoredVolumes returns the volumes whose usage should be measured by the kb-agent, including both the protected
+// volumes and the autoscaled volumes.
+func monitoredVolumes(synthesizedComp *SynthesizedComponent) []proto.VolumeWatermark {
+	volumes := make([]proto.VolumeWatermark, 0)
+	monitored := sets.New[string]()
+	for _, vol := range protectedVolumes(synthesizedComp) {
+		volumes = append(volumes, proto.VolumeWatermark{
+			Name:          vol.Name,
+			MountPath:     filepath.Join(kbAgentVolumesMountPath, vol.Name),
+			HighWatermark: vol.HighWatermark,
+		})
+		monitored.Insert(vol.Name)
+	}
+	for _, name := range AutoscaledVolumes(synthesizedComp) {
+		if !monitored.Has(name) {
+			volumes = append(volumes, proto.VolumeWatermark{
+				Name:      name,
+				MountPath: filepath.Join(kbAgentVolumesMountPath, name),
+			})
+		}
+	}
+	return volumes
+}
+
+// buildVolumeProtectionProbe4KBAgent builds the built-in probe to measure the usage of the protected and autoscaled
+// volumes, which are mounted into the kb-agent container.
+func buildVolumeProtectionProbe4KBAgent(synthesizedComp *SynthesizedComponent) *proto.Probe {
+	volumes := monitoredVolumes(synthesizedComp)
+	if len(volumes) == 0 {
+		return nil
+	}
+	return &proto.Probe{
+		Instance:            synthesizedComp.FullCompName,
+		Action:              volumeProtectionProbe,
+		PeriodSeconds:       volumeProtectionProbePeriodSeconds,
+		ReportPeriodSeconds: probeReportPeriodSeconds(defaultProbeReportPeriodSeconds),
+		Volumes:             volumes,
+	}
+}
+
+func volumeProtectionMounts(synthesizedComp *SynthesizedComponent) []corev1.VolumeMount {
+	mounts := make([]corev1.VolumeMount, 0)
+	for _, vol := range monitoredVolumes(synthesizedComp) {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      vol.Name,
+			MountPath: vol.MountPath,
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
 func probeReportPeriodSeconds(periodSeconds int32) int32 {
 	if periodSeconds <= 0 {
 		return defaultProbeReportPeriodSeconds
@@ -387,6 +553,69 @@ func handleCustomImageNContainerDefined(synthesizedComp *SynthesizedComponent, c
 	return nil
 }
 
+// KBAgentAuthMode returns how the kb-agent of the component authenticates the callers, it's empty if not required.
+func KBAgentAuthMode(synthesizedComp *SynthesizedComponent) (string, error) {
+	mode := synthesizedComp.Annotations[constant.KBAgentAuthAnnotationKey]
+	switch mode {
+	case "", KBAgentAuthToken, KBAgentAuthMTLS:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown kb-agent auth mode: %s", mode)
+	}
+}
+
+// handleKBAgentAuth provides the token and certificates in the auth secret of the component to the kb-agent containers,
+// the worker presents them to the remote kb-agent when loading data.
+func handleKBAgentAuth(synthesizedComp *SynthesizedComponent, containers ...*corev1.Container) error {
+	mode, err := KBAgentAuthMode(synthesizedComp)
+	if err != nil || len(mode) == 0 {
+		return err
+	}
+
+	secretName := constant.GenerateKBAgentAuthSecretName(synthesizedComp.ClusterName, synthesizedComp.Name)
+	tokenEnv := corev1.EnvVar{
+		Name: kbagent.TokenEnvName,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  kbagent.TokenSecretKey,
+			},
+		},
+	}
+	for _, container := range containers {
+		container.Env = append(container.Env, tokenEnv)
+	}
+	if mode != KBAgentAuthMTLS {
+		return nil
+	}
+
+	synthesizedComp.PodSpec.Volumes = append(synthesizedComp.PodSpec.Volumes, corev1.Volume{
+		Name: kbagent.TLSVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+				Items: []corev1.KeyToPath{
+					{Key: constant.CAName, Path: constant.CAName},
+					{Key: constant.CertName, Path: constant.CertName},
+					{Key: constant.KeyName, Path: constant.KeyName},
+				},
+			},
+		},
+	})
+	for _, container := range containers {
+		container.Args = append(container.Args,
+			"--tls-cert-file", filepath.Join(kbagent.TLSMountPath, constant.CertName),
+			"--tls-key-file", filepath.Join(kbagent.TLSMountPath, constant.KeyName),
+			"--tls-ca-file", filepath.Join(kbagent.TLSMountPath, constant.CAName))
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      kbagent.TLSVolumeName,
+			MountPath: kbagent.TLSMountPath,
+			ReadOnly:  true,
+		})
+	}
+	return nil
+}
+
 func customExecActionImageNContainer(synthesizedComp *SynthesizedComponent) (string, *corev1.Container, error) {
 	if synthesizedComp.LifecycleActions == nil {
 		return "", nil, nil
@@ -403,11 +632,18 @@ func customExecActionImageNContainer(synthesizedComp *SynthesizedComponent) (str
 		synthesizedComp.LifecycleActions.DataDump,
 		synthesizedComp.LifecycleActions.DataLoad,
 		synthesizedComp.LifecycleActions.Reconfigure,
+		synthesizedComp.LifecycleActions.TLSReload,
 		synthesizedComp.LifecycleActions.AccountProvision,
+		synthesizedComp.LifecycleActions.StandbyReplicate,
+		synthesizedComp.LifecycleActions.StandbyReplicationLag,
+		synthesizedComp.LifecycleActions.StandbyPromote,
 	}
 	if synthesizedComp.LifecycleActions.RoleProbe != nil && synthesizedComp.LifecycleActions.RoleProbe.Exec != nil {
 		actions = append(actions, &synthesizedComp.LifecycleActions.RoleProbe.Action)
 	}
+	for i := range synthesizedComp.LifecycleActions.CustomProbes {
+		actions = append(actions, &synthesizedComp.LifecycleActions.CustomProbes[i].Action)
+	}
 
 	var image, container string
 	for _, action := range actions {
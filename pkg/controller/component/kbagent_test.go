@@ -22,16 +22,19 @@ package component
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	kbagent "github.com/apecloud/kubeblocks/pkg/kbagent"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	"github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
@@ -130,6 +133,22 @@ var _ = Describe("kb-agent", func() {
 			Expect(c.Ports).Should(HaveLen(2))
 			Expect(c.Ports[0].ContainerPort).Should(Equal(int32(kbagent.DefaultHTTPPort)))
 			Expect(c.Ports[1].ContainerPort).Should(Equal(int32(kbagent.DefaultStreamingPort)))
+			Expect(c.Args).ShouldNot(ContainElement("--grpc-port"))
+		})
+
+		It("port - grpc", func() {
+			synthesizedComp.Annotations = map[string]string{
+				constant.KBAgentTransportAnnotationKey: constant.KBAgentTransportGRPC,
+			}
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.Ports).Should(HaveLen(3))
+			Expect(c.Ports[2].Name).Should(Equal(kbagent.DefaultGRPCPortName))
+			Expect(c.Ports[2].ContainerPort).Should(Equal(int32(kbagent.DefaultGRPCPort)))
+			Expect(c.Args).Should(ContainElements("--grpc-port", strconv.Itoa(kbagent.DefaultGRPCPort)))
 		})
 
 		It("port - in use", func() {
@@ -184,6 +203,185 @@ var _ = Describe("kb-agent", func() {
 			Expect(reflect.DeepEqual(c.Env[1], env[1])).Should(BeTrue())
 		})
 
+		It("volume protection", func() {
+			synthesizedComp.FullCompName = "test-cluster-comp"
+			synthesizedComp.LifecycleActions.Readonly = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"echo", "readonly"},
+				},
+			}
+			synthesizedComp.Volumes = []appsv1.ComponentVolume{
+				{Name: "data", HighWatermark: 90},
+				{Name: "log"},
+				{Name: "not-claimed", HighWatermark: 90},
+			}
+			synthesizedComp.VolumeClaimTemplates = []corev1.PersistentVolumeClaimTemplate{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "log"}},
+			}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.VolumeMounts).Should(ContainElement(corev1.VolumeMount{
+				Name:      "data",
+				MountPath: "/kbagent/volumes/data",
+				ReadOnly:  true,
+			}))
+			Expect(c.VolumeMounts).Should(HaveLen(1))
+
+			probe := buildVolumeProtectionProbe4KBAgent(synthesizedComp)
+			Expect(probe).ShouldNot(BeNil())
+			Expect(probe.Action).Should(Equal(volumeProtectionProbe))
+			Expect(probe.Instance).Should(Equal("test-cluster-comp"))
+			Expect(probe.Volumes).Should(Equal([]proto.VolumeWatermark{
+				{Name: "data", MountPath: "/kbagent/volumes/data", HighWatermark: 90},
+			}))
+		})
+
+		It("volume protection - no readonly action", func() {
+			synthesizedComp.Volumes = []appsv1.ComponentVolume{
+				{Name: "data", HighWatermark: 90},
+			}
+			synthesizedComp.VolumeClaimTemplates = []corev1.PersistentVolumeClaimTemplate{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+			}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.VolumeMounts).Should(BeEmpty())
+			Expect(buildVolumeProtectionProbe4KBAgent(synthesizedComp)).Should(BeNil())
+		})
+
+		It("volume autoscaling", func() {
+			synthesizedComp.VolumeClaimTemplates = []corev1.PersistentVolumeClaimTemplate{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "log"}},
+			}
+			synthesizedComp.VolumeAutoscaling = map[string]*appsv1.VolumeAutoscaling{
+				"data": {Threshold: 80},
+			}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.VolumeMounts).Should(Equal([]corev1.VolumeMount{
+				{Name: "data", MountPath: "/kbagent/volumes/data", ReadOnly: true},
+			}))
+
+			probe := buildVolumeProtectionProbe4KBAgent(synthesizedComp)
+			Expect(probe).ShouldNot(BeNil())
+			Expect(probe.Volumes).Should(Equal([]proto.VolumeWatermark{
+				{Name: "data", MountPath: "/kbagent/volumes/data"},
+			}))
+		})
+
+		It("custom probes", func() {
+			synthesizedComp.FullCompName = "test-cluster-comp"
+			synthesizedComp.LifecycleActions.CustomProbes = []appsv1.CustomProbe{
+				{
+					Name: "connections",
+					Probe: appsv1.Probe{
+						Action: appsv1.Action{
+							Exec: &appsv1.ExecAction{
+								Command: []string{"echo", "100"},
+							},
+						},
+						PeriodSeconds: 10,
+					},
+				},
+				{
+					Name: "qps",
+					Probe: appsv1.Probe{
+						Action: appsv1.Action{
+							Exec: &appsv1.ExecAction{
+								Command: []string{"echo", "1.5k"},
+							},
+						},
+					},
+				},
+			}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			actions, probes, err := buildCustomProbes4KBAgent(synthesizedComp, nil)
+			Expect(err).Should(BeNil())
+			Expect(actions).Should(HaveLen(2))
+			Expect(actions[0].Name).Should(Equal("connections"))
+			Expect(actions[1].Name).Should(Equal("qps"))
+			Expect(probes).Should(HaveLen(2))
+			Expect(probes[0].Action).Should(Equal("connections"))
+			Expect(probes[0].Instance).Should(Equal("test-cluster-comp"))
+			Expect(probes[0].ReportPeriodSeconds).Should(Equal(probeReportPeriodSeconds(10)))
+			Expect(probes[1].Action).Should(Equal("qps"))
+			Expect(probes[1].ReportPeriodSeconds).Should(Equal(int32(defaultProbeReportPeriodSeconds)))
+		})
+
+		It("custom probes - name conflicts", func() {
+			synthesizedComp.LifecycleActions.CustomProbes = []appsv1.CustomProbe{
+				{
+					Name: "roleProbe",
+					Probe: appsv1.Probe{
+						Action: appsv1.Action{
+							Exec: &appsv1.ExecAction{
+								Command: []string{"echo", "100"},
+							},
+						},
+					},
+				},
+			}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("conflicts"))
+		})
+
+		It("auth - token", func() {
+			synthesizedComp.ClusterName = "test-cluster"
+			synthesizedComp.Name = "test-comp"
+			synthesizedComp.Annotations = map[string]string{constant.KBAgentAuthAnnotationKey: KBAgentAuthToken}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			c := kbAgentContainer()
+			Expect(c).ShouldNot(BeNil())
+			Expect(c.Env).Should(HaveLen(7)) // 4 + 2 + 1
+			Expect(c.Env[6].Name).Should(Equal(kbagent.TokenEnvName))
+			Expect(c.Env[6].ValueFrom.SecretKeyRef.Name).Should(Equal("test-cluster-test-comp-kbagent-auth"))
+			Expect(c.Args).ShouldNot(ContainElement("--tls-cert-file"))
+		})
+
+		It("auth - mtls", func() {
+			synthesizedComp.Annotations = map[string]string{constant.KBAgentAuthAnnotationKey: KBAgentAuthMTLS}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).Should(BeNil())
+
+			for _, c := range []*corev1.Container{kbAgentContainer(), &synthesizedComp.PodSpec.InitContainers[0]} {
+				Expect(c).ShouldNot(BeNil())
+				Expect(c.Env[len(c.Env)-1].Name).Should(Equal(kbagent.TokenEnvName))
+				Expect(c.Args).Should(ContainElements("--tls-cert-file", "--tls-key-file", "--tls-ca-file"))
+				Expect(c.VolumeMounts).Should(ContainElement(HaveField("Name", kbagent.TLSVolumeName)))
+			}
+			Expect(synthesizedComp.PodSpec.Volumes).Should(ContainElement(HaveField("Name", kbagent.TLSVolumeName)))
+		})
+
+		It("auth - unknown", func() {
+			synthesizedComp.Annotations = map[string]string{constant.KBAgentAuthAnnotationKey: "unknown"}
+
+			err := buildKBAgentContainer(synthesizedComp)
+			Expect(err).ShouldNot(BeNil())
+		})
+
 		It("custom image", func() {
 			image := "custom-image"
 			synthesizedComp.LifecycleActions.PostProvision.Exec.Image = image
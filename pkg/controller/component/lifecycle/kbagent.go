@@ -38,6 +38,7 @@ import (
 	kbagt "github.com/apecloud/kubeblocks/pkg/kbagent"
 	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	kbautil "github.com/apecloud/kubeblocks/pkg/kbagent/util"
 )
 
 type lifecycleAction interface {
@@ -108,6 +109,39 @@ func (a *kbagent) MemberLeave(ctx context.Context, cli client.Reader, opts *Opti
 	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.MemberLeave, lfa, opts))
 }
 
+func (a *kbagent) Readonly(ctx context.Context, cli client.Reader, opts *Options) error {
+	lfa := &readonly{
+		namespace:   a.synthesizedComp.Namespace,
+		clusterName: a.synthesizedComp.ClusterName,
+		compName:    a.synthesizedComp.Name,
+		pod:         a.pod,
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.Readonly, lfa, opts))
+}
+
+func (a *kbagent) Readwrite(ctx context.Context, cli client.Reader, opts *Options) error {
+	lfa := &readwrite{
+		namespace:   a.synthesizedComp.Namespace,
+		clusterName: a.synthesizedComp.ClusterName,
+		compName:    a.synthesizedComp.Name,
+		pod:         a.pod,
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.Readwrite, lfa, opts))
+}
+
+func (a *kbagent) Reconfigure(ctx context.Context, cli client.Reader, opts *Options, configName string, changes map[string]string) error {
+	lfa := &reconfigure{
+		configName: configName,
+		changes:    changes,
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.Reconfigure, lfa, opts))
+}
+
+func (a *kbagent) TLSReload(ctx context.Context, cli client.Reader, opts *Options) error {
+	lfa := &tlsReload{}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.TLSReload, lfa, opts))
+}
+
 func (a *kbagent) AccountProvision(ctx context.Context, cli client.Reader, opts *Options, statement, user, password string) error {
 	lfa := &accountProvision{
 		statement: statement,
@@ -117,6 +151,30 @@ func (a *kbagent) AccountProvision(ctx context.Context, cli client.Reader, opts
 	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.AccountProvision, lfa, opts))
 }
 
+func (a *kbagent) StandbyReplicate(ctx context.Context, cli client.Reader, opts *Options, primaryClusterName, primaryHost string) error {
+	lfa := &standbyReplicate{
+		primaryClusterName: primaryClusterName,
+		primaryHost:        primaryHost,
+		compName:           a.synthesizedComp.Name,
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.StandbyReplicate, lfa, opts))
+}
+
+func (a *kbagent) StandbyReplicationLag(ctx context.Context, cli client.Reader, opts *Options, primaryClusterName string) ([]byte, error) {
+	lfa := &standbyReplicationLag{
+		primaryClusterName: primaryClusterName,
+		compName:           a.synthesizedComp.Name,
+	}
+	return a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.StandbyReplicationLag, lfa, opts)
+}
+
+func (a *kbagent) StandbyPromote(ctx context.Context, cli client.Reader, opts *Options, emergency bool) error {
+	lfa := &standbyPromote{
+		emergency: emergency,
+	}
+	return a.ignoreOutput(a.checkedCallAction(ctx, cli, a.synthesizedComp.LifecycleActions.StandbyPromote, lfa, opts))
+}
+
 func (a *kbagent) ignoreOutput(_ []byte, err error) error {
 	return err
 }
@@ -202,7 +260,7 @@ func (a *kbagent) callAction(ctx context.Context, cli client.Reader, spec *appsv
 	if err1 != nil {
 		return nil, err1
 	}
-	return a.callActionWithSelector(ctx, spec, lfa, req)
+	return a.callActionWithSelector(ctx, cli, spec, lfa, req)
 }
 
 func (a *kbagent) buildActionRequest(ctx context.Context, cli client.Reader, lfa lifecycleAction, opts *Options) (*proto.ActionRequest, error) {
@@ -258,7 +316,7 @@ func (a *kbagent) templateVarsParameters() (map[string]string, error) {
 	return m, nil
 }
 
-func (a *kbagent) callActionWithSelector(ctx context.Context, spec *appsv1.Action, lfa lifecycleAction, req *proto.ActionRequest) ([]byte, error) {
+func (a *kbagent) callActionWithSelector(ctx context.Context, cli client.Reader, spec *appsv1.Action, lfa lifecycleAction, req *proto.ActionRequest) ([]byte, error) {
 	pods, err := a.selectTargetPods(spec)
 	if err != nil {
 		return nil, err
@@ -272,23 +330,16 @@ func (a *kbagent) callActionWithSelector(ctx context.Context, spec *appsv1.Actio
 	//  - timeout
 	var output []byte
 	for _, pod := range pods {
-		endpoint := func() (string, int32, error) {
-			host, port, err := a.serverEndpoint(pod)
-			if err != nil {
-				return "", 0, errors.Wrapf(err, "pod %s is unavailable to execute action %s", pod.Name, lfa.name())
-			}
-			return host, port, nil
-		}
-		cli, err := kbacli.NewClient(endpoint)
+		agent, err := a.newClient(ctx, cli, pod, lfa)
 		if err != nil {
 			return nil, err // mock client error
 		}
-		if cli == nil {
+		if agent == nil {
 			continue // not kb-agent container and port defined, for test only
 		}
-		rsp, err := cli.Action(ctx, *req)
+		rsp, err := agent.Action(ctx, *req)
 		if err != nil {
-			return nil, errors.Wrapf(err, "http error occurred when executing action %s at pod %s", lfa.name(), pod.Name)
+			return nil, errors.Wrapf(err, "transport error occurred when executing action %s at pod %s", lfa.name(), pod.Name)
 		}
 		if len(rsp.Error) > 0 {
 			return nil, a.formatError(lfa, rsp)
@@ -305,8 +356,67 @@ func (a *kbagent) selectTargetPods(spec *appsv1.Action) ([]*corev1.Pod, error) {
 	return SelectTargetPods(a.pods, a.pod, spec)
 }
 
-func (a *kbagent) serverEndpoint(pod *corev1.Pod) (string, int32, error) {
-	port, err := intctrlutil.GetPortByName(*pod, kbagt.ContainerName, kbagt.DefaultHTTPPortName)
+func (a *kbagent) newClient(ctx context.Context, cli client.Reader, pod *corev1.Pod, lfa lifecycleAction) (kbacli.Client, error) {
+	endpoint := func(portName string) func() (string, int32, error) {
+		return func() (string, int32, error) {
+			host, port, err := a.serverEndpoint(pod, portName)
+			if err != nil {
+				return "", 0, errors.Wrapf(err, "pod %s is unavailable to execute action %s", pod.Name, lfa.name())
+			}
+			return host, port, nil
+		}
+	}
+	credentials, err := a.credentials(ctx, cli)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the credentials to execute action %s at pod %s", lfa.name(), pod.Name)
+	}
+	if a.grpcTransport(pod) {
+		return kbacli.NewGRPCClient(endpoint(kbagt.DefaultGRPCPortName), credentials)
+	}
+	return kbacli.NewClient(endpoint(kbagt.DefaultHTTPPortName), credentials)
+}
+
+// credentials loads the credentials required by the kb-agent, it follows the kb-agent auth mode of the component,
+// which generates the auth settings of the kb-agent containers.
+func (a *kbagent) credentials(ctx context.Context, cli client.Reader) (*kbacli.Credentials, error) {
+	mode, err := component.KBAgentAuthMode(a.synthesizedComp)
+	if err != nil || len(mode) == 0 {
+		return nil, err // the kb-agent doesn't require authentication
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{
+		Namespace: a.synthesizedComp.Namespace,
+		Name:      constant.GenerateKBAgentAuthSecretName(a.synthesizedComp.ClusterName, a.synthesizedComp.Name),
+	}
+	if err = cli.Get(ctx, secretKey, secret); err != nil {
+		return nil, err
+	}
+	credentials := &kbacli.Credentials{
+		Token: string(secret.Data[kbagt.TokenSecretKey]),
+	}
+	if mode == component.KBAgentAuthMTLS {
+		tlsConfig, err := kbautil.ClientTLSConfig(secret.Data[constant.CAName], secret.Data[constant.CertName], secret.Data[constant.KeyName])
+		if err != nil {
+			return nil, err
+		}
+		credentials.TLSConfig = tlsConfig
+	}
+	return credentials, nil
+}
+
+// grpcTransport checks whether to call the kb-agent through gRPC, the pods created by the kb-agent
+// without the gRPC server will fall back to HTTP.
+func (a *kbagent) grpcTransport(pod *corev1.Pod) bool {
+	if a.synthesizedComp.Annotations[constant.KBAgentTransportAnnotationKey] != constant.KBAgentTransportGRPC {
+		return false
+	}
+	_, err := intctrlutil.GetPortByName(*pod, kbagt.ContainerName, kbagt.DefaultGRPCPortName)
+	return err == nil
+}
+
+func (a *kbagent) serverEndpoint(pod *corev1.Pod, portName string) (string, int32, error) {
+	port, err := intctrlutil.GetPortByName(*pod, kbagt.ContainerName, portName)
 	if err != nil {
 		// has no kb-agent defined
 		return "", 0, nil
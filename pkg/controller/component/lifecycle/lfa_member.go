@@ -21,6 +21,7 @@ package lifecycle
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -38,6 +39,9 @@ const (
 	joinMemberPodNameVar    = "KB_JOIN_MEMBER_POD_NAME"
 	leaveMemberPodFQDNVar   = "KB_LEAVE_MEMBER_POD_FQDN"
 	leaveMemberPodNameVar   = "KB_LEAVE_MEMBER_POD_NAME"
+	podFQDNVar              = "KB_POD_FQDN"
+	reconfigureConfigVar    = "KB_CONFIG_NAME"
+	reconfigureParamsVar    = "KB_CONFIG_PARAMETERS"
 )
 
 type roleProbe struct{}
@@ -133,6 +137,90 @@ func (a *memberLeave) parameters(ctx context.Context, cli client.Reader) (map[st
 	}, nil
 }
 
+type readonly struct {
+	namespace   string
+	clusterName string
+	compName    string
+	pod         *corev1.Pod
+}
+
+var _ lifecycleAction = &readonly{}
+
+func (a *readonly) name() string {
+	return "readonly"
+}
+
+func (a *readonly) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_POD_FQDN: The FQDN of the replica pod to be switched into the read-only state.
+	compName := constant.GenerateClusterComponentName(a.clusterName, a.compName)
+	return map[string]string{
+		podFQDNVar: component.PodFQDN(a.namespace, compName, a.pod.Name),
+	}, nil
+}
+
+type readwrite struct {
+	namespace   string
+	clusterName string
+	compName    string
+	pod         *corev1.Pod
+}
+
+var _ lifecycleAction = &readwrite{}
+
+func (a *readwrite) name() string {
+	return "readwrite"
+}
+
+func (a *readwrite) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_POD_FQDN: The FQDN of the replica pod to be switched back to the read-write state.
+	compName := constant.GenerateClusterComponentName(a.clusterName, a.compName)
+	return map[string]string{
+		podFQDNVar: component.PodFQDN(a.namespace, compName, a.pod.Name),
+	}, nil
+}
+
+type reconfigure struct {
+	configName string
+	changes    map[string]string
+}
+
+var _ lifecycleAction = &reconfigure{}
+
+func (a *reconfigure) name() string {
+	return "reconfigure"
+}
+
+func (a *reconfigure) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_CONFIG_NAME: The name of the config whose parameters have been changed.
+	// - KB_CONFIG_PARAMETERS: The changed parameters and the new values, encoded as a JSON object.
+	changes, err := json.Marshal(a.changes)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		reconfigureConfigVar: a.configName,
+		reconfigureParamsVar: string(changes),
+	}, nil
+}
+
+type tlsReload struct{}
+
+var _ lifecycleAction = &tlsReload{}
+
+func (a *tlsReload) name() string {
+	return "tlsReload"
+}
+
+func (a *tlsReload) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	return nil, nil
+}
+
 ////////// hack for legacy Addons //////////
 // The container executing this action has access to following variables:
 //
@@ -0,0 +1,100 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	drPrimaryClusterNameVar   = "KB_DR_PRIMARY_CLUSTER_NAME"
+	drPrimaryComponentNameVar = "KB_DR_PRIMARY_COMPONENT_NAME"
+	drPrimaryHostVar          = "KB_DR_PRIMARY_HOST"
+	drEmergencyVar            = "KB_DR_EMERGENCY"
+)
+
+type standbyReplicate struct {
+	primaryClusterName string
+	primaryHost        string
+	compName           string
+}
+
+var _ lifecycleAction = &standbyReplicate{}
+
+func (a *standbyReplicate) name() string {
+	return "standbyReplicate"
+}
+
+func (a *standbyReplicate) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_DR_PRIMARY_CLUSTER_NAME: The name of the primary Cluster.
+	// - KB_DR_PRIMARY_COMPONENT_NAME: The name of the component to replicate from in the primary Cluster.
+	// - KB_DR_PRIMARY_HOST: The host of the replication service of the component in the primary Cluster.
+	return map[string]string{
+		drPrimaryClusterNameVar:   a.primaryClusterName,
+		drPrimaryComponentNameVar: a.compName,
+		drPrimaryHostVar:          a.primaryHost,
+	}, nil
+}
+
+type standbyReplicationLag struct {
+	primaryClusterName string
+	compName           string
+}
+
+var _ lifecycleAction = &standbyReplicationLag{}
+
+func (a *standbyReplicationLag) name() string {
+	return "standbyReplicationLag"
+}
+
+func (a *standbyReplicationLag) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_DR_PRIMARY_CLUSTER_NAME: The name of the primary Cluster.
+	// - KB_DR_PRIMARY_COMPONENT_NAME: The name of the component to replicate from in the primary Cluster.
+	return map[string]string{
+		drPrimaryClusterNameVar:   a.primaryClusterName,
+		drPrimaryComponentNameVar: a.compName,
+	}, nil
+}
+
+type standbyPromote struct {
+	emergency bool
+}
+
+var _ lifecycleAction = &standbyPromote{}
+
+func (a *standbyPromote) name() string {
+	return "standbyPromote"
+}
+
+func (a *standbyPromote) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following variables:
+	//
+	// - KB_DR_EMERGENCY: "true" if the promotion is caused by an emergency failover, otherwise "false".
+	return map[string]string{
+		drEmergencyVar: strconv.FormatBool(a.emergency),
+	}, nil
+}
@@ -49,13 +49,21 @@ type Lifecycle interface {
 
 	MemberLeave(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Readonly(ctx context.Context, cli client.Reader, opts *Options) error
+	Readonly(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Readwrite(ctx context.Context, cli client.Reader, opts *Options) error
+	Readwrite(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Reconfigure(ctx context.Context, cli client.Reader, opts *Options) error
+	Reconfigure(ctx context.Context, cli client.Reader, opts *Options, configName string, changes map[string]string) error
+
+	TLSReload(ctx context.Context, cli client.Reader, opts *Options) error
 
 	AccountProvision(ctx context.Context, cli client.Reader, opts *Options, statement, user, password string) error
+
+	StandbyReplicate(ctx context.Context, cli client.Reader, opts *Options, primaryClusterName, primaryHost string) error
+
+	StandbyReplicationLag(ctx context.Context, cli client.Reader, opts *Options, primaryClusterName string) ([]byte, error)
+
+	StandbyPromote(ctx context.Context, cli client.Reader, opts *Options, emergency bool) error
 }
 
 func New(synthesizedComp *component.SynthesizedComponent, pod *corev1.Pod, pods ...*corev1.Pod) (Lifecycle, error) {
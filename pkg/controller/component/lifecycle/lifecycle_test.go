@@ -38,6 +38,8 @@ import (
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/plan"
+	kbagt "github.com/apecloud/kubeblocks/pkg/kbagent"
 	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 )
@@ -626,6 +628,153 @@ var _ = Describe("lifecycle", func() {
 			Expect(err.Error()).Should(ContainSubstring("no available pod to execute action"))
 		})
 
+		It("readonly & readwrite", func() {
+			synthesizedComp.LifecycleActions.Readonly = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n readonly"},
+				},
+			}
+			synthesizedComp.LifecycleActions.Readwrite = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n readwrite"},
+				},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: synthesizedComp.Namespace,
+					Name:      "test-cluster-kbagent-0",
+				},
+			}
+			fqdn := component.PodFQDN(synthesizedComp.Namespace,
+				constant.GenerateClusterComponentName(synthesizedComp.ClusterName, synthesizedComp.Name), pod.Name)
+
+			lifecycle, err := New(synthesizedComp, pod)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			actions := make([]string, 0)
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					Expect(req.Parameters[podFQDNVar]).Should(Equal(fqdn))
+					actions = append(actions, req.Action)
+					return proto.ActionResponse{}, nil
+				}).AnyTimes()
+			})
+
+			Expect(lifecycle.Readonly(ctx, k8sClient, nil)).Should(Succeed())
+			Expect(lifecycle.Readwrite(ctx, k8sClient, nil)).Should(Succeed())
+			Expect(actions).Should(Equal([]string{"readonly", "readwrite"}))
+		})
+
+		It("reconfigure", func() {
+			synthesizedComp.LifecycleActions.Reconfigure = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n reconfigure"},
+				},
+			}
+
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					Expect(req.Action).Should(Equal("reconfigure"))
+					Expect(req.Parameters[reconfigureConfigVar]).Should(Equal("mysql-config"))
+					Expect(req.Parameters[reconfigureParamsVar]).Should(MatchJSON(`{"max_connections":"1000"}`))
+					return proto.ActionResponse{}, nil
+				}).Times(1)
+			})
+
+			err = lifecycle.Reconfigure(ctx, k8sClient, nil, "mysql-config", map[string]string{"max_connections": "1000"})
+			Expect(err).Should(BeNil())
+		})
+
+		It("reconfigure - not defined", func() {
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			err = lifecycle.Reconfigure(ctx, k8sClient, nil, "", nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, ErrActionNotDefined)).Should(BeTrue())
+		})
+
+		It("tls reload", func() {
+			synthesizedComp.LifecycleActions.TLSReload = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n tls reload"},
+				},
+			}
+
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					Expect(req.Action).Should(Equal("tlsReload"))
+					return proto.ActionResponse{}, nil
+				}).Times(1)
+			})
+
+			Expect(lifecycle.TLSReload(ctx, k8sClient, nil)).Should(Succeed())
+
+			By("not defined")
+			synthesizedComp.LifecycleActions.TLSReload = nil
+			lifecycle, err = New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			err = lifecycle.TLSReload(ctx, k8sClient, nil)
+			Expect(errors.Is(err, ErrActionNotDefined)).Should(BeTrue())
+		})
+
+		It("standby actions", func() {
+			synthesizedComp.LifecycleActions.StandbyReplicate = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n replicate"},
+				},
+			}
+			synthesizedComp.LifecycleActions.StandbyReplicationLag = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n 3"},
+				},
+			}
+			synthesizedComp.LifecycleActions.StandbyPromote = &appsv1.Action{
+				Exec: &appsv1.ExecAction{
+					Command: []string{"/bin/bash", "-c", "echo -n promote"},
+				},
+			}
+
+			lifecycle, err := New(synthesizedComp, nil, pods...)
+			Expect(err).Should(BeNil())
+			Expect(lifecycle).ShouldNot(BeNil())
+
+			mockKBAgentClient(func(recorder *kbacli.MockClientMockRecorder) {
+				recorder.Action(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+					switch req.Action {
+					case "standbyReplicate":
+						Expect(req.Parameters[drPrimaryClusterNameVar]).Should(Equal("primary"))
+						Expect(req.Parameters[drPrimaryComponentNameVar]).Should(Equal(synthesizedComp.Name))
+						Expect(req.Parameters[drPrimaryHostVar]).Should(Equal("primary.example.com"))
+						return proto.ActionResponse{}, nil
+					case "standbyReplicationLag":
+						Expect(req.Parameters[drPrimaryClusterNameVar]).Should(Equal("primary"))
+						return proto.ActionResponse{Output: []byte("3")}, nil
+					default:
+						Expect(req.Action).Should(Equal("standbyPromote"))
+						Expect(req.Parameters[drEmergencyVar]).Should(Equal("true"))
+						return proto.ActionResponse{}, nil
+					}
+				}).Times(3)
+			})
+
+			Expect(lifecycle.StandbyReplicate(ctx, k8sClient, nil, "primary", "primary.example.com")).Should(Succeed())
+			output, err := lifecycle.StandbyReplicationLag(ctx, k8sClient, nil, "primary")
+			Expect(err).Should(BeNil())
+			Expect(string(output)).Should(Equal("3"))
+			Expect(lifecycle.StandbyPromote(ctx, k8sClient, nil, true)).Should(Succeed())
+		})
+
 		It("non-blocking", func() {
 			// TODO: impl
 		})
@@ -634,4 +783,62 @@ var _ = Describe("lifecycle", func() {
 			// TODO: impl
 		})
 	})
+
+	Context("credentials", func() {
+		var (
+			secret *corev1.Secret
+			reader *mockReader
+		)
+
+		BeforeEach(func() {
+			certs, err := plan.ComposeKBAgentCerts(*synthesizedComp)
+			Expect(err).Should(BeNil())
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: synthesizedComp.Namespace,
+					Name:      constant.GenerateKBAgentAuthSecretName(synthesizedComp.ClusterName, synthesizedComp.Name),
+				},
+				Data: certs,
+			}
+			secret.Data[kbagt.TokenSecretKey] = []byte("token")
+			reader = &mockReader{cli: k8sClient, objs: []client.Object{secret}}
+		})
+
+		It("no auth", func() {
+			agent := &kbagent{synthesizedComp: synthesizedComp}
+			credentials, err := agent.credentials(ctx, reader)
+			Expect(err).Should(BeNil())
+			Expect(credentials).Should(BeNil())
+		})
+
+		It("token", func() {
+			synthesizedComp.Annotations = map[string]string{constant.KBAgentAuthAnnotationKey: component.KBAgentAuthToken}
+			agent := &kbagent{synthesizedComp: synthesizedComp}
+			credentials, err := agent.credentials(ctx, reader)
+			Expect(err).Should(BeNil())
+			Expect(credentials).ShouldNot(BeNil())
+			Expect(credentials.Token).Should(Equal("token"))
+			Expect(credentials.TLSConfig).Should(BeNil())
+		})
+
+		It("mtls", func() {
+			// the mutual TLS follows the auth mode of the component, regardless of how the args are rendered
+			synthesizedComp.Annotations = map[string]string{constant.KBAgentAuthAnnotationKey: component.KBAgentAuthMTLS}
+			synthesizedComp.PodSpec.Containers[0].Args = []string{"--tls-cert-file=/etc/kbagent/tls/tls.crt"}
+			agent := &kbagent{synthesizedComp: synthesizedComp}
+			credentials, err := agent.credentials(ctx, reader)
+			Expect(err).Should(BeNil())
+			Expect(credentials).ShouldNot(BeNil())
+			Expect(credentials.Token).Should(Equal("token"))
+			Expect(credentials.TLSConfig).ShouldNot(BeNil())
+			Expect(credentials.TLSConfig.Certificates).Should(HaveLen(1))
+		})
+
+		It("unknown mode", func() {
+			synthesizedComp.Annotations = map[string]string{constant.KBAgentAuthAnnotationKey: "unknown"}
+			agent := &kbagent{synthesizedComp: synthesizedComp}
+			_, err := agent.credentials(ctx, reader)
+			Expect(err).ShouldNot(BeNil())
+		})
+	})
 })
@@ -62,6 +62,8 @@ type ReplicaStatus struct {
 	Provisioned       bool       `json:"provisioned,omitempty"`
 	DataLoaded        *bool      `json:"dataLoaded,omitempty"`
 	MemberJoined      *bool      `json:"memberJoined,omitempty"`
+
+	DataLoadProgress *proto.NewReplicaProgress `json:"dataLoadProgress,omitempty"`
 }
 
 func BuildReplicasStatus(running, proto *workloads.InstanceSet) {
@@ -208,7 +210,22 @@ func GetReplicasStatusFunc(its *workloads.InstanceSet, f func(ReplicaStatus) boo
 	return replicas, nil
 }
 
-func NewReplicaTask(compName, uid string, source *corev1.Pod, replicas []string) (map[string]string, error) {
+// GetReplicasDataLoadProgress returns the progress of the replicas which are loading data.
+func GetReplicasDataLoadProgress(its *workloads.InstanceSet) (map[string]proto.NewReplicaProgress, error) {
+	status, err := getReplicasStatus(its)
+	if err != nil {
+		return nil, err
+	}
+	progress := make(map[string]proto.NewReplicaProgress)
+	for _, s := range status.Status {
+		if s.DataLoaded != nil && !*s.DataLoaded && s.DataLoadProgress != nil {
+			progress[s.Name] = *s.DataLoadProgress
+		}
+	}
+	return progress, nil
+}
+
+func NewReplicaTask(compName, uid string, source *corev1.Pod, replicas []string, compression string) (map[string]string, error) {
 	port, err := intctrlutil.GetPortByName(*source, kbagent.ContainerName, kbagent.DefaultStreamingPortName)
 	if err != nil {
 		return nil, err
@@ -221,9 +238,10 @@ func NewReplicaTask(compName, uid string, source *corev1.Pod, replicas []string)
 		NotifyAtFinish:      true,
 		ReportPeriodSeconds: defaultNewReplicaTaskReportPeriodSeconds,
 		NewReplica: &proto.NewReplicaTask{
-			Remote:   source.Status.PodIP,
-			Port:     port,
-			Replicas: strings.Join(replicas, ","),
+			Remote:      source.Status.PodIP,
+			Port:        port,
+			Replicas:    strings.Join(replicas, ","),
+			Compression: compression,
 		},
 	}
 	return buildKBAgentTaskEnv(task)
@@ -358,6 +376,7 @@ func handleNewReplicaTaskEvent4Finished(ctx context.Context, cli client.Client,
 		status.Message = ""
 		status.Provisioned = true
 		status.DataLoaded = ptr.To(true)
+		status.DataLoadProgress = nil
 		return nil
 	})
 }
@@ -367,6 +386,13 @@ func handleNewReplicaTaskEvent4Unfinished(ctx context.Context, cli client.Client
 		status.Message = event.Message
 		status.Provisioned = true
 		status.DataLoaded = ptr.To(false)
+		if len(event.Output) > 0 {
+			progress := &proto.NewReplicaProgress{}
+			if err := json.Unmarshal(event.Output, progress); err != nil {
+				return err
+			}
+			status.DataLoadProgress = progress
+		}
 		return nil
 	})
 }
@@ -434,7 +434,7 @@ var _ = Describe("service references", func() {
 			err := buildServiceReferencesWithoutResolve(testCtx.Ctx, reader, synthesizedComp, compDef, comp)
 			Expect(err).Should(Succeed())
 
-			svcFQDN := serviceFQDN("external", reader.objs[0].GetName())
+			svcFQDN := ServiceFQDN("external", reader.objs[0].GetName())
 
 			Expect(synthesizedComp.ServiceReferences).Should(HaveKey(serviceRefDeclaration.Name))
 			serviceDescriptor := synthesizedComp.ServiceReferences[serviceRefDeclaration.Name]
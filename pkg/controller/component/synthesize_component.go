@@ -255,6 +255,14 @@ func buildVolumeClaimTemplates(synthesizeComp *SynthesizedComponent, comp *appsv
 	if comp.Spec.VolumeClaimTemplates != nil {
 		synthesizeComp.VolumeClaimTemplates = ToVolumeClaimTemplates(comp.Spec.VolumeClaimTemplates)
 	}
+	for _, vct := range comp.Spec.VolumeClaimTemplates {
+		if vct.Autoscaling != nil {
+			if synthesizeComp.VolumeAutoscaling == nil {
+				synthesizeComp.VolumeAutoscaling = make(map[string]*appsv1.VolumeAutoscaling)
+			}
+			synthesizeComp.VolumeAutoscaling[vct.Name] = vct.Autoscaling
+		}
+	}
 }
 
 func mergeUserDefinedVolumes(synthesizedComp *SynthesizedComponent, comp *appsv1.Component) error {
@@ -44,6 +44,7 @@ type SynthesizedComponent struct {
 	PodSpec                          *corev1.PodSpec                        `json:"podSpec,omitempty"`
 	SidecarVars                      []kbappsv1.EnvVar                      // vars defined by sidecars
 	VolumeClaimTemplates             []corev1.PersistentVolumeClaimTemplate `json:"volumeClaimTemplates,omitempty"`
+	VolumeAutoscaling                map[string]*kbappsv1.VolumeAutoscaling `json:"volumeAutoscaling,omitempty"` // {vctName: autoscaling policy}
 	LogConfigs                       []kbappsv1.LogConfig                   `json:"logConfigs,omitempty"`
 	ConfigTemplates                  []kbappsv1.ComponentConfigSpec         `json:"configTemplates,omitempty"`
 	ScriptTemplates                  []kbappsv1.ComponentTemplateSpec       `json:"scriptTemplates,omitempty"`
@@ -557,7 +557,7 @@ func composeHostValueFromServices(obj any, fqdn bool) string {
 		if !fqdn {
 			return svc.Name
 		}
-		return serviceFQDN(svc.Namespace, svc.Name)
+		return ServiceFQDN(svc.Namespace, svc.Name)
 	}
 
 	svcNames := make([]string, 0)
@@ -1148,11 +1148,7 @@ func componentVarPodsGetter(ctx context.Context, cli client.Reader,
 		}
 	}
 
-	var templates []instanceset.InstanceTemplate
-	for i := range comp.Spec.Instances {
-		templates = append(templates, &comp.Spec.Instances[i])
-	}
-	names, err := instanceset.GenerateAllInstanceNames(comp.Name, comp.Spec.Replicas, templates, comp.Spec.OfflineInstances, workloads.Ordinals{})
+	names, err := generatePodNames(comp.Name, comp.Spec.Replicas, comp.Spec.Instances, comp.Spec.OfflineInstances)
 	if err != nil {
 		return "", err
 	}
@@ -1164,6 +1160,46 @@ func componentVarPodsGetter(ctx context.Context, cli client.Reader,
 	return strings.Join(names, ","), nil
 }
 
+func generatePodNames(compName string, replicas int32, instances []appsv1.InstanceTemplate, offlineInstances []string) ([]string, error) {
+	var templates []instanceset.InstanceTemplate
+	for i := range instances {
+		templates = append(templates, &instances[i])
+	}
+	return instanceset.GenerateAllInstanceNames(compName, replicas, templates, offlineInstances, workloads.Ordinals{})
+}
+
+// PodFQDNs returns the FQDNs of all pods of the synthesized component, the same as the podFQDNs var resolves to.
+func PodFQDNs(synthesizedComp *SynthesizedComponent) ([]string, error) {
+	compName := constant.GenerateClusterComponentName(synthesizedComp.ClusterName, synthesizedComp.Name)
+	names, err := generatePodNames(compName, synthesizedComp.Replicas, synthesizedComp.Instances, synthesizedComp.OfflineInstances)
+	if err != nil {
+		return nil, err
+	}
+	for i := range names {
+		names[i] = PodFQDN(synthesizedComp.Namespace, compName, names[i])
+	}
+	return names, nil
+}
+
+// ServiceFQDNs returns the FQDNs of the headless service and the component services of the synthesized component,
+// the per-pod services are not included.
+func ServiceFQDNs(synthesizedComp *SynthesizedComponent) []string {
+	names := []string{constant.GenerateDefaultComponentHeadlessServiceName(synthesizedComp.ClusterName, synthesizedComp.Name)}
+	for _, svc := range synthesizedComp.ComponentServices {
+		if svc.PodService != nil && *svc.PodService {
+			continue
+		}
+		names = append(names, constant.GenerateComponentServiceName(synthesizedComp.ClusterName, synthesizedComp.Name, svc.ServiceName))
+	}
+	fqdns := make([]string, 0, len(names))
+	for _, name := range names {
+		if !slices.Contains(fqdns, ServiceFQDN(synthesizedComp.Namespace, name)) {
+			fqdns = append(fqdns, ServiceFQDN(synthesizedComp.Namespace, name))
+		}
+	}
+	return fqdns
+}
+
 func componentVarPodsWithRoleGetter(ctx context.Context, cli client.Reader,
 	namespace, clusterName, compName, roles string, fqdn bool) (string, error) {
 	pods, err := ListOwnedPods(ctx, cli, namespace, clusterName, compName)
@@ -0,0 +1,133 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+)
+
+const (
+	volumeExpansionAnnotationKey = "apps.kubeblocks.io/volume-expansion"
+
+	defaultVolumeAutoscalingStep = "10%"
+
+	// the expanded size is rounded up to the multiple of 1Mi
+	volumeSizeRoundUpUnit = 1024 * 1024
+)
+
+// HasVolumeAutoscaling checks whether any volumes of the component have the autoscaling policy set.
+func HasVolumeAutoscaling(synthesizedComp *SynthesizedComponent) bool {
+	return len(AutoscaledVolumes(synthesizedComp)) > 0
+}
+
+// AutoscaledVolumes returns the names of volumes that have the autoscaling policy set.
+func AutoscaledVolumes(synthesizedComp *SynthesizedComponent) []string {
+	volumes := make([]string, 0)
+	for _, vct := range synthesizedComp.VolumeClaimTemplates {
+		if synthesizedComp.VolumeAutoscaling[vct.Name] != nil {
+			volumes = append(volumes, vct.Name)
+		}
+	}
+	return volumes
+}
+
+// VolumeAutoscalingCooldown returns the minimum interval between two expansions of the volume, which is not less than
+// the interval the volume usage is reported at, to avoid expanding the volume again by the usage measured before.
+func VolumeAutoscalingCooldown(policy appsv1.VolumeAutoscaling) time.Duration {
+	cooldown := policy.CooldownSeconds
+	if minCooldown := probeReportPeriodSeconds(defaultProbeReportPeriodSeconds); cooldown < minCooldown {
+		cooldown = minCooldown
+	}
+	return time.Duration(cooldown) * time.Second
+}
+
+// ExpandedVolumeSize returns the size of the volume after the next expansion by the autoscaling policy, it returns
+// false if the volume has reached the max size.
+func ExpandedVolumeSize(policy appsv1.VolumeAutoscaling, current resource.Quantity) (resource.Quantity, bool, error) {
+	step := policy.Step
+	if len(step) == 0 {
+		step = defaultVolumeAutoscalingStep
+	}
+	var delta int64
+	if strings.HasSuffix(step, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(step, "%"), 64)
+		if err != nil || percent <= 0 {
+			return resource.Quantity{}, false, fmt.Errorf("invalid autoscaling step %s", policy.Step)
+		}
+		delta = int64(math.Ceil(float64(current.Value()) * percent / 100))
+	} else {
+		quantity, err := resource.ParseQuantity(step)
+		if err != nil || quantity.Sign() <= 0 {
+			return resource.Quantity{}, false, fmt.Errorf("invalid autoscaling step %s", policy.Step)
+		}
+		delta = quantity.Value()
+	}
+
+	size := current.Value() + delta
+	if remainder := size % volumeSizeRoundUpUnit; remainder != 0 {
+		size += volumeSizeRoundUpUnit - remainder
+	}
+	if policy.MaxSize != nil && size > policy.MaxSize.Value() {
+		size = policy.MaxSize.Value()
+	}
+	if size <= current.Value() {
+		return current, false, nil
+	}
+	return *resource.NewQuantity(size, resource.BinarySI), true, nil
+}
+
+// GetVolumeExpansionTime returns the time of the last expansion of each volume made by the autoscaling policy.
+func GetVolumeExpansionTime(comp *appsv1.Component) (map[string]time.Time, error) {
+	volumes := make(map[string]time.Time)
+	value, ok := comp.Annotations[volumeExpansionAnnotationKey]
+	if !ok {
+		return volumes, nil
+	}
+	if err := json.Unmarshal([]byte(value), &volumes); err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
+// SetVolumeExpansionTime records the time of the last expansion of each volume made by the autoscaling policy.
+func SetVolumeExpansionTime(comp *appsv1.Component, volumes map[string]time.Time) error {
+	if len(volumes) == 0 {
+		delete(comp.Annotations, volumeExpansionAnnotationKey)
+		return nil
+	}
+	out, err := json.Marshal(volumes)
+	if err != nil {
+		return err
+	}
+	if comp.Annotations == nil {
+		comp.Annotations = make(map[string]string)
+	}
+	comp.Annotations[volumeExpansionAnnotationKey] = string(out)
+	return nil
+}
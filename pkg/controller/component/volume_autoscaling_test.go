@@ -0,0 +1,127 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+)
+
+var _ = Describe("volume autoscaling", func() {
+	Context("autoscaled volumes", func() {
+		It("has volume autoscaling", func() {
+			synthesizedComp := &SynthesizedComponent{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaimTemplate{
+					{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "log"}},
+				},
+			}
+			Expect(HasVolumeAutoscaling(synthesizedComp)).Should(BeFalse())
+
+			synthesizedComp.VolumeAutoscaling = map[string]*appsv1.VolumeAutoscaling{
+				"log":         {Threshold: 80},
+				"not-claimed": {Threshold: 80},
+			}
+			Expect(HasVolumeAutoscaling(synthesizedComp)).Should(BeTrue())
+			Expect(AutoscaledVolumes(synthesizedComp)).Should(Equal([]string{"log"}))
+		})
+	})
+
+	Context("expanded volume size", func() {
+		It("percentage step", func() {
+			size, ok, err := ExpandedVolumeSize(appsv1.VolumeAutoscaling{Threshold: 80, Step: "20%"}, resource.MustParse("10Gi"))
+			Expect(err).Should(BeNil())
+			Expect(ok).Should(BeTrue())
+			Expect(size.Cmp(resource.MustParse("12Gi"))).Should(Equal(0))
+		})
+
+		It("default step", func() {
+			size, ok, err := ExpandedVolumeSize(appsv1.VolumeAutoscaling{Threshold: 80}, resource.MustParse("1Gi"))
+			Expect(err).Should(BeNil())
+			Expect(ok).Should(BeTrue())
+			// rounded up to the multiple of 1Mi
+			Expect(size.Cmp(resource.MustParse("1127Mi"))).Should(Equal(0))
+		})
+
+		It("quantity step", func() {
+			size, ok, err := ExpandedVolumeSize(appsv1.VolumeAutoscaling{Threshold: 80, Step: "5Gi"}, resource.MustParse("10Gi"))
+			Expect(err).Should(BeNil())
+			Expect(ok).Should(BeTrue())
+			Expect(size.Cmp(resource.MustParse("15Gi"))).Should(Equal(0))
+		})
+
+		It("max size", func() {
+			maxSize := resource.MustParse("12Gi")
+			policy := appsv1.VolumeAutoscaling{Threshold: 80, Step: "5Gi", MaxSize: &maxSize}
+			size, ok, err := ExpandedVolumeSize(policy, resource.MustParse("10Gi"))
+			Expect(err).Should(BeNil())
+			Expect(ok).Should(BeTrue())
+			Expect(size.Cmp(maxSize)).Should(Equal(0))
+
+			_, ok, err = ExpandedVolumeSize(policy, maxSize)
+			Expect(err).Should(BeNil())
+			Expect(ok).Should(BeFalse())
+		})
+
+		It("invalid step", func() {
+			_, _, err := ExpandedVolumeSize(appsv1.VolumeAutoscaling{Threshold: 80, Step: "ten%"}, resource.MustParse("10Gi"))
+			Expect(err).ShouldNot(BeNil())
+			_, _, err = ExpandedVolumeSize(appsv1.VolumeAutoscaling{Threshold: 80, Step: "-1Gi"}, resource.MustParse("10Gi"))
+			Expect(err).ShouldNot(BeNil())
+		})
+	})
+
+	Context("cooldown", func() {
+		It("not less than the usage report period", func() {
+			Expect(VolumeAutoscalingCooldown(appsv1.VolumeAutoscaling{CooldownSeconds: 3600})).Should(Equal(time.Hour))
+			Expect(VolumeAutoscalingCooldown(appsv1.VolumeAutoscaling{CooldownSeconds: 10})).Should(Equal(defaultProbeReportPeriodSeconds * time.Second))
+			Expect(VolumeAutoscalingCooldown(appsv1.VolumeAutoscaling{})).Should(Equal(defaultProbeReportPeriodSeconds * time.Second))
+		})
+	})
+
+	Context("annotations", func() {
+		It("volume expansion time", func() {
+			comp := &appsv1.Component{}
+			expansions, err := GetVolumeExpansionTime(comp)
+			Expect(err).Should(BeNil())
+			Expect(expansions).Should(BeEmpty())
+
+			now := time.Now().Truncate(time.Second)
+			Expect(SetVolumeExpansionTime(comp, map[string]time.Time{"data": now})).Should(Succeed())
+			Expect(comp.Annotations).Should(HaveKey(volumeExpansionAnnotationKey))
+
+			expansions, err = GetVolumeExpansionTime(comp)
+			Expect(err).Should(BeNil())
+			Expect(expansions).Should(HaveLen(1))
+			Expect(expansions["data"].Equal(now)).Should(BeTrue())
+
+			Expect(SetVolumeExpansionTime(comp, nil)).Should(Succeed())
+			Expect(comp.Annotations).ShouldNot(HaveKey(volumeExpansionAnnotationKey))
+		})
+	})
+})
@@ -0,0 +1,214 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+const (
+	volumeProtectionProbe         = "volumeProtection"
+	volumeWatermarksAnnotationKey = "apps.kubeblocks.io/volume-watermarks"
+	readonlyReplicasAnnotationKey = "apps.kubeblocks.io/readonly-replicas"
+)
+
+// VolumeWatermarks is the volumes of a replica whose usage has crossed the watermarks. Only the crossings are recorded,
+// the component is not updated as long as the usage changes within the watermarks.
+type VolumeWatermarks struct {
+	// Exceeded is the volumes whose usage exceeds the high watermark.
+	Exceeded []string `json:"exceeded,omitempty"`
+
+	// Autoscaling is the volumes whose usage crosses the threshold of the autoscaling policy.
+	Autoscaling []string `json:"autoscaling,omitempty"`
+}
+
+func (w VolumeWatermarks) empty() bool {
+	return len(w.Exceeded) == 0 && len(w.Autoscaling) == 0
+}
+
+// VolumeProtectionEventHandler records the volumes of replicas whose usage reported by the kb-agent crosses the high
+// watermark or the autoscaling threshold in the annotations of the component, the component controller switches the
+// replicas between the read-only and read-write state, and expands the volumes accordingly.
+type VolumeProtectionEventHandler struct{}
+
+func (h *VolumeProtectionEventHandler) Handle(cli client.Client, reqCtx intctrlutil.RequestCtx, recorder record.EventRecorder, event *corev1.Event) error {
+	if !h.isVolumeProtectionEvent(event) {
+		return nil
+	}
+
+	ppEvent := &proto.ProbeEvent{}
+	if err := json.Unmarshal([]byte(event.Message), ppEvent); err != nil {
+		return err
+	}
+	if ppEvent.Code != 0 {
+		reqCtx.Log.Info("failed to measure the volume usage", "pod", event.InvolvedObject.Name, "message", ppEvent.Message)
+		return nil
+	}
+	usages := make([]proto.VolumeUsage, 0)
+	if err := json.Unmarshal(ppEvent.Output, &usages); err != nil {
+		return err
+	}
+
+	compKey := types.NamespacedName{
+		Namespace: event.InvolvedObject.Namespace,
+		Name:      ppEvent.Instance,
+	}
+	comp := &appsv1.Component{}
+	if err := cli.Get(reqCtx.Ctx, compKey, comp); err != nil {
+		return err
+	}
+	compCopy := comp.DeepCopy()
+
+	replicas, err := GetReplicasVolumeWatermarks(comp)
+	if err != nil {
+		return err
+	}
+	podNames, err := GenerateAllPodNames(comp.Spec.Replicas, comp.Spec.Instances, comp.Spec.OfflineInstances, comp.Name)
+	if err != nil {
+		return err
+	}
+	names := sets.New[string](podNames...)
+	for name := range replicas {
+		if !names.Has(name) {
+			delete(replicas, name)
+		}
+	}
+	if names.Has(event.InvolvedObject.Name) {
+		replicas[event.InvolvedObject.Name] = crossedVolumeWatermarks(comp, usages)
+	}
+	if err = SetReplicasVolumeWatermarks(comp, replicas); err != nil {
+		return err
+	}
+	if reflect.DeepEqual(comp.Annotations, compCopy.Annotations) {
+		return nil
+	}
+	return cli.Patch(reqCtx.Ctx, comp, client.MergeFrom(compCopy))
+}
+
+func (h *VolumeProtectionEventHandler) isVolumeProtectionEvent(event *corev1.Event) bool {
+	return event.ReportingController == proto.ProbeEventReportingController &&
+		event.Reason == volumeProtectionProbe && event.InvolvedObject.FieldPath == proto.ProbeEventFieldPath
+}
+
+// HasVolumeProtection checks whether any volumes of the component are protected by the high watermark.
+func HasVolumeProtection(synthesizedComp *SynthesizedComponent) bool {
+	return len(protectedVolumes(synthesizedComp)) > 0
+}
+
+// crossedVolumeWatermarks returns the volumes whose usage crosses the high watermark or the autoscaling threshold.
+func crossedVolumeWatermarks(comp *appsv1.Component, usages []proto.VolumeUsage) VolumeWatermarks {
+	thresholds := make(map[string]int32)
+	for _, vct := range comp.Spec.VolumeClaimTemplates {
+		if vct.Autoscaling != nil {
+			thresholds[vct.Name] = vct.Autoscaling.Threshold
+		}
+	}
+	watermarks := VolumeWatermarks{}
+	for _, usage := range usages {
+		if usage.Exceeded() {
+			watermarks.Exceeded = append(watermarks.Exceeded, usage.Name)
+		}
+		if threshold, ok := thresholds[usage.Name]; ok && usage.Usage >= int(threshold) {
+			watermarks.Autoscaling = append(watermarks.Autoscaling, usage.Name)
+		}
+	}
+	sort.Strings(watermarks.Exceeded)
+	sort.Strings(watermarks.Autoscaling)
+	return watermarks
+}
+
+// GetReplicasVolumeWatermarks returns the volumes of replicas whose usage has crossed the watermarks.
+func GetReplicasVolumeWatermarks(comp *appsv1.Component) (map[string]VolumeWatermarks, error) {
+	replicas := make(map[string]VolumeWatermarks)
+	value, ok := comp.Annotations[volumeWatermarksAnnotationKey]
+	if !ok {
+		return replicas, nil
+	}
+	if err := json.Unmarshal([]byte(value), &replicas); err != nil {
+		return nil, err
+	}
+	return replicas, nil
+}
+
+// SetReplicasVolumeWatermarks records the volumes of replicas whose usage has crossed the watermarks, the replicas
+// without any crossing are omitted.
+func SetReplicasVolumeWatermarks(comp *appsv1.Component, replicas map[string]VolumeWatermarks) error {
+	crossed := make(map[string]VolumeWatermarks)
+	for replica, watermarks := range replicas {
+		if !watermarks.empty() {
+			crossed[replica] = watermarks
+		}
+	}
+	if len(crossed) == 0 {
+		delete(comp.Annotations, volumeWatermarksAnnotationKey)
+		return nil
+	}
+	out, err := json.Marshal(crossed)
+	if err != nil {
+		return err
+	}
+	if comp.Annotations == nil {
+		comp.Annotations = make(map[string]string)
+	}
+	comp.Annotations[volumeWatermarksAnnotationKey] = string(out)
+	return nil
+}
+
+// GetReadonlyReplicas returns the replicas that have been switched to the read-only state to protect the volumes.
+func GetReadonlyReplicas(comp *appsv1.Component) (sets.Set[string], error) {
+	value, ok := comp.Annotations[readonlyReplicasAnnotationKey]
+	if !ok {
+		return sets.New[string](), nil
+	}
+	replicas := make([]string, 0)
+	if err := json.Unmarshal([]byte(value), &replicas); err != nil {
+		return nil, err
+	}
+	return sets.New[string](replicas...), nil
+}
+
+// SetReadonlyReplicas records the replicas that have been switched to the read-only state.
+func SetReadonlyReplicas(comp *appsv1.Component, replicas sets.Set[string]) error {
+	if replicas.Len() == 0 {
+		delete(comp.Annotations, readonlyReplicasAnnotationKey)
+		return nil
+	}
+	out, err := json.Marshal(sets.List(replicas))
+	if err != nil {
+		return err
+	}
+	if comp.Annotations == nil {
+		comp.Annotations = make(map[string]string)
+	}
+	comp.Annotations[readonlyReplicasAnnotationKey] = string(out)
+	return nil
+}
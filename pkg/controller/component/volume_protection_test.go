@@ -0,0 +1,257 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+var _ = Describe("volume protection", func() {
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+
+		// namespaced
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.ComponentSignature, true, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+	})
+
+	AfterEach(func() {
+		cleanEnv()
+	})
+
+	Context("annotations", func() {
+		var (
+			comp *appsv1.Component
+		)
+
+		BeforeEach(func() {
+			comp = &appsv1.Component{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster-comp",
+				},
+				Spec: appsv1.ComponentSpec{
+					Replicas: 3,
+				},
+			}
+		})
+
+		It("volume watermarks", func() {
+			watermarks, err := GetReplicasVolumeWatermarks(comp)
+			Expect(err).Should(BeNil())
+			Expect(watermarks).Should(BeEmpty())
+
+			watermarks = map[string]VolumeWatermarks{
+				"test-cluster-comp-0": {Exceeded: []string{"data"}, Autoscaling: []string{"data"}},
+				"test-cluster-comp-1": {},
+			}
+			Expect(SetReplicasVolumeWatermarks(comp, watermarks)).Should(Succeed())
+			Expect(comp.Annotations[volumeWatermarksAnnotationKey]).Should(Equal(`{"test-cluster-comp-0":{"exceeded":["data"],"autoscaling":["data"]}}`))
+
+			result, err := GetReplicasVolumeWatermarks(comp)
+			Expect(err).Should(BeNil())
+			Expect(result).Should(HaveLen(1))
+			Expect(result).Should(HaveKeyWithValue("test-cluster-comp-0", watermarks["test-cluster-comp-0"]))
+
+			Expect(SetReplicasVolumeWatermarks(comp, nil)).Should(Succeed())
+			Expect(comp.Annotations).ShouldNot(HaveKey(volumeWatermarksAnnotationKey))
+		})
+
+		It("crossed volume watermarks", func() {
+			comp.Spec.VolumeClaimTemplates = []appsv1.ClusterComponentVolumeClaimTemplate{
+				{Name: "data", Autoscaling: &appsv1.VolumeAutoscaling{Threshold: 80}},
+				{Name: "log"},
+			}
+			usage := func(data, log int) []proto.VolumeUsage {
+				return []proto.VolumeUsage{
+					{Name: "data", TotalBytes: 1024, Usage: data, HighWatermark: 90},
+					{Name: "log", TotalBytes: 1024, Usage: log, HighWatermark: 90},
+				}
+			}
+			Expect(crossedVolumeWatermarks(comp, usage(79, 50))).Should(Equal(VolumeWatermarks{}))
+			Expect(crossedVolumeWatermarks(comp, usage(80, 50))).Should(Equal(VolumeWatermarks{Autoscaling: []string{"data"}}))
+			Expect(crossedVolumeWatermarks(comp, usage(80, 95))).Should(Equal(VolumeWatermarks{
+				Exceeded:    []string{"log"},
+				Autoscaling: []string{"data"},
+			}))
+			Expect(crossedVolumeWatermarks(comp, usage(91, 95))).Should(Equal(VolumeWatermarks{
+				Exceeded:    []string{"data", "log"},
+				Autoscaling: []string{"data"},
+			}))
+		})
+
+		It("readonly replicas", func() {
+			replicas, err := GetReadonlyReplicas(comp)
+			Expect(err).Should(BeNil())
+			Expect(replicas.Len()).Should(Equal(0))
+
+			Expect(SetReadonlyReplicas(comp, sets.New("test-cluster-comp-1", "test-cluster-comp-0"))).Should(Succeed())
+			Expect(comp.Annotations[readonlyReplicasAnnotationKey]).Should(Equal(`["test-cluster-comp-0","test-cluster-comp-1"]`))
+
+			replicas, err = GetReadonlyReplicas(comp)
+			Expect(err).Should(BeNil())
+			Expect(sets.List(replicas)).Should(Equal([]string{"test-cluster-comp-0", "test-cluster-comp-1"}))
+
+			Expect(SetReadonlyReplicas(comp, sets.New[string]())).Should(Succeed())
+			Expect(comp.Annotations).ShouldNot(HaveKey(readonlyReplicasAnnotationKey))
+		})
+	})
+
+	Context("handle event", func() {
+		It("not volume protection event", func() {
+			h := &VolumeProtectionEventHandler{}
+
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx:      ctx,
+				Log:      logger,
+				Recorder: recorder,
+			}
+			event := &corev1.Event{
+				InvolvedObject: corev1.ObjectReference{
+					FieldPath: proto.ProbeEventFieldPath,
+				},
+				Reason:              availableProbe,
+				ReportingController: proto.ProbeEventReportingController,
+				Message:             "not a volume usage event",
+			}
+			Expect(h.Handle(k8sClient, reqCtx, reqCtx.Recorder, event)).Should(Succeed())
+		})
+
+		It("failed to measure", func() {
+			h := &VolumeProtectionEventHandler{}
+
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx:      ctx,
+				Log:      logger,
+				Recorder: recorder,
+			}
+			event := &corev1.Event{
+				InvolvedObject: corev1.ObjectReference{
+					FieldPath: proto.ProbeEventFieldPath,
+				},
+				Reason:              volumeProtectionProbe,
+				ReportingController: proto.ProbeEventReportingController,
+				Message:             `{"instance":"test-cluster-comp","probe":"volumeProtection","code":-1,"message":"failed to stat volume data"}`,
+			}
+			Expect(h.Handle(k8sClient, reqCtx, reqCtx.Recorder, event)).Should(Succeed())
+		})
+
+		It("record the volume watermarks crossed", func() {
+			compObj := testapps.NewComponentFactory(testCtx.DefaultNamespace, "test-cluster-comp", "test-compdef").
+				SetReplicas(2).
+				Create(&testCtx).
+				GetObject()
+			compKey := client.ObjectKeyFromObject(compObj)
+
+			By("a stale replica has been recorded")
+			Expect(testapps.GetAndChangeObj(&testCtx, compKey, func(comp *appsv1.Component) {
+				Expect(SetReplicasVolumeWatermarks(comp, map[string]VolumeWatermarks{
+					"test-cluster-comp-2": {Exceeded: []string{"data"}},
+				})).Should(Succeed())
+			})()).Should(Succeed())
+
+			h := &VolumeProtectionEventHandler{}
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx:      ctx,
+				Log:      logger,
+				Recorder: recorder,
+			}
+			usageEvent := func(podName string, usages []proto.VolumeUsage) *corev1.Event {
+				output, err := json.Marshal(usages)
+				Expect(err).Should(BeNil())
+				message, err := json.Marshal(proto.ProbeEvent{
+					Instance: compObj.Name,
+					Probe:    volumeProtectionProbe,
+					Output:   output,
+				})
+				Expect(err).Should(BeNil())
+				return &corev1.Event{
+					InvolvedObject: corev1.ObjectReference{
+						Namespace: compObj.Namespace,
+						Name:      podName,
+						FieldPath: proto.ProbeEventFieldPath,
+					},
+					Reason:              volumeProtectionProbe,
+					ReportingController: proto.ProbeEventReportingController,
+					Message:             string(message),
+				}
+			}
+
+			By("the usage of a replica exceeds the high watermark")
+			usages := []proto.VolumeUsage{{Name: "data", TotalBytes: 1024, Usage: 91, HighWatermark: 90}}
+			Expect(h.Handle(k8sClient, reqCtx, reqCtx.Recorder, usageEvent("test-cluster-comp-0", usages))).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, compKey, func(g Gomega, comp *appsv1.Component) {
+				replicas, err := GetReplicasVolumeWatermarks(comp)
+				g.Expect(err).Should(BeNil())
+				g.Expect(replicas).Should(HaveLen(1))
+				g.Expect(replicas).Should(HaveKeyWithValue("test-cluster-comp-0", VolumeWatermarks{Exceeded: []string{"data"}}))
+			})).Should(Succeed())
+
+			By("the usage changes but still exceeds the high watermark")
+			comp := &appsv1.Component{}
+			Expect(k8sClient.Get(ctx, compKey, comp)).Should(Succeed())
+			usages = []proto.VolumeUsage{{Name: "data", TotalBytes: 1024, Usage: 95, HighWatermark: 90}}
+			Expect(h.Handle(k8sClient, reqCtx, reqCtx.Recorder, usageEvent("test-cluster-comp-0", usages))).Should(Succeed())
+			Consistently(testapps.CheckObj(&testCtx, compKey, func(g Gomega, obj *appsv1.Component) {
+				g.Expect(obj.ResourceVersion).Should(Equal(comp.ResourceVersion))
+			})).Should(Succeed())
+
+			By("report the usage of a replica not belonging to the component")
+			Expect(h.Handle(k8sClient, reqCtx, reqCtx.Recorder, usageEvent("test-cluster-comp-3", usages))).Should(Succeed())
+			Consistently(testapps.CheckObj(&testCtx, compKey, func(g Gomega, comp *appsv1.Component) {
+				replicas, err := GetReplicasVolumeWatermarks(comp)
+				g.Expect(err).Should(BeNil())
+				g.Expect(replicas).ShouldNot(HaveKey("test-cluster-comp-3"))
+			})).Should(Succeed())
+
+			By("the usage drops below the high watermark")
+			usages = []proto.VolumeUsage{{Name: "data", TotalBytes: 1024, Usage: 50, HighWatermark: 90}}
+			Expect(h.Handle(k8sClient, reqCtx, reqCtx.Recorder, usageEvent("test-cluster-comp-0", usages))).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, compKey, func(g Gomega, comp *appsv1.Component) {
+				replicas, err := GetReplicasVolumeWatermarks(comp)
+				g.Expect(err).Should(BeNil())
+				g.Expect(replicas).Should(BeEmpty())
+			})).Should(Succeed())
+		})
+	})
+})
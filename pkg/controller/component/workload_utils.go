@@ -185,7 +185,7 @@ func PodFQDN(namespace, compName, podName string) string {
 	return fmt.Sprintf("%s.%s-headless.%s.svc.%s", podName, compName, namespace, clusterDomain())
 }
 
-func serviceFQDN(namespace, serviceName string) string {
+func ServiceFQDN(namespace, serviceName string) string {
 	return fmt.Sprintf("%s.%s.svc.%s", serviceName, namespace, clusterDomain())
 }
 
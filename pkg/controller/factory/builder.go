@@ -25,7 +25,10 @@ import (
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
@@ -339,3 +342,52 @@ func BuildRoleBinding(synthesizedComp *component.SynthesizedComponent, saName st
 		}).
 		GetObject()
 }
+
+// BuildPodDisruptionBudget builds a PodDisruptionBudget for the instances of the component.
+// It returns nil if the component has no more than one replica, since any voluntary disruption makes it unavailable.
+//
+// If any member can vote, the budget keeps a majority of the voting members available to hold the quorum,
+// the voting members are counted from the members status of the running workload. Two voting members can't
+// tolerate any disruption, so no replica can be disrupted in that case, and the nodes can't be drained.
+// Otherwise, or if the voting members are unknown, at most one replica can be disrupted at a time.
+func BuildPodDisruptionBudget(synthesizedComp *component.SynthesizedComponent,
+	its *workloads.InstanceSet, members []workloads.MemberStatus) *policyv1.PodDisruptionBudget {
+	replicas := int32(1)
+	if its.Spec.Replicas != nil {
+		replicas = *its.Spec.Replicas
+	}
+	if replicas <= 1 {
+		return nil
+	}
+
+	maxUnavailable := int32(1)
+	if voters := votingMembers(its, members); voters > 0 {
+		maxUnavailable = (voters - 1) / 2
+	}
+
+	return builder.NewPDBBuilder(its.Namespace, its.Name).
+		AddLabelsInMap(constant.GetCompLabels(synthesizedComp.ClusterName, synthesizedComp.Name)).
+		AddLabelsInMap(synthesizedComp.StaticLabels).
+		AddAnnotationsInMap(synthesizedComp.StaticAnnotations).
+		AddSelectorsInMap(its.Spec.Selector.MatchLabels).
+		SetMaxUnavailable(intstr.FromInt32(maxUnavailable)).
+		SetUnhealthyPodEvictionPolicy(policyv1.AlwaysAllow).
+		GetObject()
+}
+
+// votingMembers returns the number of members whose role can vote.
+func votingMembers(its *workloads.InstanceSet, members []workloads.MemberStatus) int32 {
+	canVote := sets.New[string]()
+	for _, role := range its.Spec.Roles {
+		if role.CanVote {
+			canVote.Insert(role.Name)
+		}
+	}
+	voters := int32(0)
+	for _, member := range members {
+		if member.ReplicaRole != nil && canVote.Has(member.ReplicaRole.Name) {
+			voters++
+		}
+	}
+	return voters
+}
@@ -207,6 +207,69 @@ var _ = Describe("builder", func() {
 			Expect(sa.Name).Should(Equal(expectName))
 		})
 
+		It("builds pdb correctly", func() {
+			compDef, cluster, synthesizedComp := newClusterObjs(nil)
+			its, err := BuildInstanceSet(synthesizedComp, compDef)
+			Expect(err).Should(BeNil())
+			members := func(roles ...string) []workloads.MemberStatus {
+				var status []workloads.MemberStatus
+				for i, role := range roles {
+					status = append(status, workloads.MemberStatus{
+						PodName:     fmt.Sprintf("%s-%d", its.Name, i),
+						ReplicaRole: &workloads.ReplicaRole{Name: role},
+					})
+				}
+				return status
+			}
+
+			By("no pdb for a single replica")
+			Expect(BuildPodDisruptionBudget(synthesizedComp, its, members("leader"))).Should(BeNil())
+
+			By("keep the quorum of the voters")
+			cluster.Spec.ComponentSpecs[0].Replicas = 5
+			synthesizedComp = newAllFieldsSynthesizedComponent(compDef, cluster)
+			its, err = BuildInstanceSet(synthesizedComp, compDef)
+			Expect(err).Should(BeNil())
+			pdb := BuildPodDisruptionBudget(synthesizedComp, its, members("leader", "follower", "follower", "follower", "follower"))
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(pdb.Name).Should(Equal(its.Name))
+			Expect(pdb.Spec.Selector.MatchLabels).Should(Equal(its.Spec.Selector.MatchLabels))
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(2))
+
+			By("count the voting members only")
+			pdb = BuildPodDisruptionBudget(synthesizedComp, its, members("leader", "follower", "follower", "learner", "learner"))
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(1))
+
+			By("one replica at a time if the voting members are unknown")
+			pdb = BuildPodDisruptionBudget(synthesizedComp, its, nil)
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(1))
+
+			By("ignore the minimum replicas")
+			compDef.Spec.ReplicasLimit = &appsv1.ReplicasLimit{MinReplicas: 5, MaxReplicas: 16}
+			pdb = BuildPodDisruptionBudget(synthesizedComp, its, members("leader", "follower", "follower", "follower", "follower"))
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(2))
+
+			By("one replica at a time without voters")
+			for i := range its.Spec.Roles {
+				its.Spec.Roles[i].CanVote = false
+			}
+			pdb = BuildPodDisruptionBudget(synthesizedComp, its, members("leader", "follower", "follower", "follower", "follower"))
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(1))
+
+			By("no disruption with two voters")
+			cluster.Spec.ComponentSpecs[0].Replicas = 2
+			synthesizedComp = newAllFieldsSynthesizedComponent(compDef, cluster)
+			its, err = BuildInstanceSet(synthesizedComp, compDef)
+			Expect(err).Should(BeNil())
+			pdb = BuildPodDisruptionBudget(synthesizedComp, its, members("leader", "follower"))
+			Expect(pdb).ShouldNot(BeNil())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).Should(Equal(0))
+		})
+
 		It("builds rolebinding correctly", func() {
 			_, cluster, synthesizedComp := newClusterObjs(nil)
 			expectName := fmt.Sprintf("kb-%s", cluster.Name)
@@ -0,0 +1,222 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+)
+
+// reconcileCanary advances the canary rollout of the InstanceSet and records the progress in status.canaryStatus.
+// It returns the max number of instances that can be updated in this round, and how long to wait before the next
+// reconciliation if the rollout is paused for a while.
+func reconcileCanary(tree *kubebuilderx.ObjectTree, its *workloads.InstanceSet, itsExt *instanceSetExt, pods []*corev1.Pod) (int, time.Duration, error) {
+	replicas := len(pods)
+	strategy := its.Spec.CanaryStrategy
+	if strategy == nil || len(strategy.Steps) == 0 {
+		its.Status.CanaryStatus = nil
+		return replicas, 0, nil
+	}
+
+	updated, unavailable, inflight := 0, 0, 0
+	for _, pod := range pods {
+		isPodUpdated, err := IsPodUpdated(its, pod)
+		if err != nil {
+			return 0, 0, err
+		}
+		switch {
+		case isPodUpdated:
+			updated++
+			if isTerminating(pod) || !isRunningAndAvailable(pod, its.Spec.MinReadySeconds) {
+				unavailable++
+			}
+		case isTerminating(pod):
+			// the pod is being recreated
+			inflight++
+		}
+	}
+
+	// 1. start a new rollout if the instance templates are changed
+	revision := buildCanaryRevision(itsExt)
+	status := its.Status.CanaryStatus
+	if status == nil || status.Revision != revision {
+		stableRevision := ""
+		if status != nil {
+			stableRevision = status.StableRevision
+			if status.Phase == workloads.CanaryCompletedPhase {
+				stableRevision = status.Revision
+			}
+		}
+		status = &workloads.CanaryStatus{
+			Revision:       revision,
+			StableRevision: stableRevision,
+			Phase:          workloads.CanaryProgressingPhase,
+		}
+		if revision == stableRevision {
+			// rolling back to the stable revision, skip all the steps
+			status.CurrentStepIndex = int32(len(strategy.Steps))
+		}
+		its.Status.CanaryStatus = status
+	}
+
+	// 2. handle the abort command, and drop the promote command if the rollout is finished. The promote command is
+	//    kept while the rollout is progressing, and consumed once the current step is paused.
+	if abort, ok := its.Annotations[constant.CanaryAbortAnnotationKey]; ok {
+		delete(its.Annotations, constant.CanaryAbortAnnotationKey)
+		switch {
+		case abort != "true":
+		case isCanaryFinished(status):
+			recordCanaryEvent(tree, its, corev1.EventTypeWarning, EventReasonCanaryCommandIgnored,
+				fmt.Sprintf("the abort command is ignored as the rollout is %s", status.Phase))
+		default:
+			status.Phase = workloads.CanaryAbortedPhase
+			status.PauseStartTime = nil
+			status.Message = fmt.Sprintf("the rollout is aborted at step %d", status.CurrentStepIndex)
+			recordCanaryEvent(tree, its, corev1.EventTypeWarning, EventReasonCanaryAborted, status.Message)
+		}
+	}
+	if promote, ok := its.Annotations[constant.CanaryPromoteAnnotationKey]; ok && (promote != "true" || isCanaryFinished(status)) {
+		delete(its.Annotations, constant.CanaryPromoteAnnotationKey)
+		if promote == "true" {
+			recordCanaryEvent(tree, its, corev1.EventTypeWarning, EventReasonCanaryCommandIgnored,
+				fmt.Sprintf("the promote command is ignored as the rollout is %s", status.Phase))
+		}
+	}
+
+	// 3. check whether the rollout is done or aborted
+	if updated == replicas {
+		status.Phase = workloads.CanaryCompletedPhase
+		status.CurrentStepReplicas = int32(replicas)
+		status.PauseStartTime = nil
+		status.Message = ""
+		return replicas, 0, nil
+	}
+	if status.Phase == workloads.CanaryAbortedPhase {
+		return 0, 0, nil
+	}
+
+	// 4. pause after the instances of the current step are updated, and move on when the pause ends
+	for {
+		target, err := canaryStepReplicas(strategy, status.CurrentStepIndex, replicas)
+		if err != nil {
+			return 0, 0, err
+		}
+		status.CurrentStepReplicas = int32(target)
+		if status.Phase == workloads.CanaryProgressingPhase {
+			if int(status.CurrentStepIndex) >= len(strategy.Steps) || updated < target || unavailable > 0 {
+				status.Message = ""
+				budget := target - updated - inflight
+				if budget < 0 {
+					budget = 0
+				}
+				return budget, 0, nil
+			}
+			now := metav1.Now()
+			status.Phase = workloads.CanaryPausedPhase
+			status.PauseStartTime = &now
+			status.Message = fmt.Sprintf("the rollout is paused at step %d with %d instance(s) updated", status.CurrentStepIndex, updated)
+			recordCanaryEvent(tree, its, corev1.EventTypeNormal, EventReasonCanaryPaused, status.Message)
+		}
+
+		if its.Annotations[constant.CanaryPromoteAnnotationKey] == "true" {
+			delete(its.Annotations, constant.CanaryPromoteAnnotationKey)
+			promoteCanary(status)
+			continue
+		}
+
+		pauseSeconds := strategy.Steps[status.CurrentStepIndex].PauseSeconds
+		if pauseSeconds == nil {
+			status.Message = fmt.Sprintf("the rollout is paused at step %d, waiting to be promoted", status.CurrentStepIndex)
+			return 0, 0, nil
+		}
+		if status.PauseStartTime == nil {
+			now := metav1.Now()
+			status.PauseStartTime = &now
+		}
+		remaining := time.Until(status.PauseStartTime.Add(time.Duration(*pauseSeconds) * time.Second))
+		if remaining > 0 {
+			return 0, remaining, nil
+		}
+		if unavailable > 0 {
+			status.Message = fmt.Sprintf("the rollout is paused at step %d as %d updated instance(s) are not available", status.CurrentStepIndex, unavailable)
+			return 0, 0, nil
+		}
+		promoteCanary(status)
+	}
+}
+
+func isCanaryFinished(status *workloads.CanaryStatus) bool {
+	return status.Phase == workloads.CanaryCompletedPhase || status.Phase == workloads.CanaryAbortedPhase
+}
+
+func promoteCanary(status *workloads.CanaryStatus) {
+	status.CurrentStepIndex++
+	status.Phase = workloads.CanaryProgressingPhase
+	status.PauseStartTime = nil
+	status.Message = ""
+}
+
+// canaryStepReplicas returns the total number of instances that should be updated in the given step,
+// all the instances should be updated once all the steps are done.
+func canaryStepReplicas(strategy *workloads.CanaryStrategy, stepIndex int32, replicas int) (int, error) {
+	if int(stepIndex) >= len(strategy.Steps) {
+		return replicas, nil
+	}
+	step := strategy.Steps[stepIndex]
+	target, err := intstr.GetScaledValueFromIntOrPercent(&step.Replicas, replicas, true)
+	if err != nil {
+		return 0, err
+	}
+	if target < 0 {
+		target = 0
+	}
+	if target > replicas {
+		target = replicas
+	}
+	return target, nil
+}
+
+// buildCanaryRevision builds a revision of all the instance templates, including the fields that can be updated
+// in-place, which are excluded by the revisions in status.updateRevisions.
+func buildCanaryRevision(itsExt *instanceSetExt) string {
+	templates := make(map[string]corev1.PodTemplateSpec)
+	for _, template := range buildInstanceTemplateExts(itsExt) {
+		templates[template.Name] = template.PodTemplateSpec
+	}
+	hf := fnv.New32()
+	DeepHashObject(hf, templates)
+	return rand.SafeEncodeString(fmt.Sprint(hf.Sum32()))
+}
+
+func recordCanaryEvent(tree *kubebuilderx.ObjectTree, its *workloads.InstanceSet, eventType, reason, message string) {
+	if tree != nil && tree.EventRecorder != nil {
+		tree.EventRecorder.Event(its, eventType, reason, message)
+	}
+}
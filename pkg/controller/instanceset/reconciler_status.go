@@ -34,6 +34,7 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	"github.com/apecloud/kubeblocks/pkg/controller/multicluster"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
 
@@ -194,6 +195,9 @@ func (r *statusReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	// TODO(free6om): should put this field to the spec
 	setReadyWithPrimary(its, podList)
 
+	// 6. set the status of each data-plane k8s cluster
+	setDataClustersStatus(its, podList, tree.GetMultiClusterRecorder())
+
 	if its.Spec.MinReadySeconds > 0 && availableReplicas != readyReplicas {
 		return kubebuilderx.RetryAfter(time.Second), nil
 	}
@@ -343,3 +347,67 @@ func sortMembersStatus(membersStatus []workloads.MemberStatus, rolePriorityMap m
 	}
 	baseSort(membersStatus, getNameNOrdinalFunc, getRolePriorityFunc, true)
 }
+
+// setDataClustersStatus breaks the status down to the data-plane k8s clusters in the multi-cluster mode.
+// The pods are attributed to the data-plane k8s clusters they are read from, or placed on if they are not read yet.
+func setDataClustersStatus(its *workloads.InstanceSet, pods []*corev1.Pod, recorder *multicluster.Recorder) {
+	contexts := placementContexts(its)
+	if len(contexts) == 0 {
+		its.Status.DataClusters = nil
+		return
+	}
+
+	statuses := map[string]*workloads.DataClusterStatus{}
+	statusOf := func(context string) *workloads.DataClusterStatus {
+		if statuses[context] == nil {
+			statuses[context] = &workloads.DataClusterStatus{Context: context}
+		}
+		return statuses[context]
+	}
+	for _, context := range contexts {
+		statusOf(context)
+	}
+	for _, pod := range pods {
+		context := recorder.ContextOf(pod)
+		if len(context) == 0 {
+			context = pod.Annotations[constant.KBAppMultiClusterPlacementKey]
+		}
+		if len(context) == 0 || !isCreated(pod) {
+			continue
+		}
+		status := statusOf(context)
+		status.Replicas++
+		if isContainersReady(pod) && isRunningAndReady(pod) && !isTerminating(pod) {
+			status.ReadyReplicas++
+			if isRunningAndAvailable(pod, its.Spec.MinReadySeconds) {
+				status.AvailableReplicas++
+			}
+		}
+		if roleName := getRoleName(pod); len(roleName) > 0 {
+			if status.Roles == nil {
+				status.Roles = map[string]int32{}
+			}
+			status.Roles[roleName]++
+		}
+	}
+	for context, err := range recorder.Errors() {
+		statusOf(context).LastError = err.Error()
+	}
+
+	dataClusters := make([]workloads.DataClusterStatus, 0, len(statuses))
+	for _, status := range statuses {
+		dataClusters = append(dataClusters, *status)
+	}
+	sort.Slice(dataClusters, func(i, j int) bool {
+		return dataClusters[i].Context < dataClusters[j].Context
+	})
+	its.Status.DataClusters = dataClusters
+}
+
+func placementContexts(its *workloads.InstanceSet) []string {
+	p := its.Annotations[constant.KBAppMultiClusterPlacementKey]
+	if len(strings.TrimSpace(p)) == 0 {
+		return nil
+	}
+	return strings.Split(p, ",")
+}
@@ -396,6 +396,44 @@ var _ = Describe("status reconciler test", func() {
 		})
 	})
 
+	Context("setDataClustersStatus function", func() {
+		It("should work well", func() {
+			pod := func(name, context, role string, phase corev1.PodPhase) *corev1.Pod {
+				pod := builder.NewPodBuilder(namespace, name).
+					AddLabels(RoleLabelKey, role).
+					AddAnnotations(constant.KBAppMultiClusterPlacementKey, context).
+					GetObject()
+				pod.Status.Phase = phase
+				return pod
+			}
+			pods := []*corev1.Pod{
+				pod("pod-0", "east", "leader", corev1.PodRunning),
+				pod("pod-1", "east", "follower", corev1.PodPending),
+				pod("pod-2", "west", "", ""),
+			}
+			pods[0].Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+
+			By("single-cluster mode")
+			setDataClustersStatus(its, pods, nil)
+			Expect(its.Status.DataClusters).Should(BeNil())
+
+			By("multi-cluster mode")
+			its.Annotations = map[string]string{constant.KBAppMultiClusterPlacementKey: "west,east"}
+			setDataClustersStatus(its, pods, nil)
+			Expect(its.Status.DataClusters).Should(Equal([]workloads.DataClusterStatus{
+				{
+					Context:       "east",
+					Replicas:      2,
+					ReadyReplicas: 1,
+					Roles:         map[string]int32{"leader": 1, "follower": 1},
+				},
+				{
+					Context: "west",
+				},
+			}))
+		})
+	})
+
 	Context("sortMembersStatus function", func() {
 		It("should work well", func() {
 			// 2(learner)->1(learner)->4(logger)->0(follower)->3(leader)
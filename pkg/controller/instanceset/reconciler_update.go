@@ -37,6 +37,7 @@ import (
 
 // updateReconciler handles the updates of instances based on the UpdateStrategy.
 // Currently, two update strategies are supported: 'OnDelete' and 'RollingUpdate'.
+// A 'RollingUpdate' can be further divided into canary steps by the CanaryStrategy.
 type updateReconciler struct{}
 
 var _ kubebuilderx.Reconciler = &updateReconciler{}
@@ -111,6 +112,12 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	}
 	unavailable := maxUnavailable - currentUnavailable
 
+	// if a canary rollout is specified, canaryCount limits the Pods can be updated in the current step.
+	canaryCount, canaryRetryAfter, err := reconcileCanary(tree, its, itsExt, oldPodList)
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+
 	// if it's a roleful InstanceSet, we use updateCount to represent Pods can be updated according to the spec.memberUpdateStrategy.
 	updateCount := len(oldPodList)
 	if len(its.Spec.Roles) > 0 {
@@ -130,7 +137,7 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	needRetry := false
 	sortObjects(oldPodList, priorities, false)
 	for _, pod := range oldPodList {
-		if updatingPods >= updateCount || updatingPods >= unavailable {
+		if updatingPods >= updateCount || updatingPods >= unavailable || updatingPods >= canaryCount {
 			break
 		}
 		if updatedPods >= partition {
@@ -196,6 +203,9 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	if needRetry {
 		return kubebuilderx.RetryAfter(2 * time.Second), nil
 	}
+	if canaryRetryAfter > 0 {
+		return kubebuilderx.RetryAfter(canaryRetryAfter), nil
+	}
 	return kubebuilderx.Continue, nil
 }
 
@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/builder"
 	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
 )
@@ -246,6 +247,108 @@ var _ = Describe("update reconciler test", func() {
 			Expect(err).Should(BeNil())
 			Expect(res).Should(Equal(kubebuilderx.Continue))
 			expectUpdatedPods(strictInPlaceTree, []string{})
+
+			By("reconcile with CanaryStrategy")
+			canaryStrategy := &workloads.CanaryStrategy{
+				Steps: []workloads.CanaryStep{
+					{Replicas: intstr.FromInt32(1)},
+					{Replicas: intstr.FromString("50%")},
+				},
+			}
+			canaryMaxUnavailable := intstr.FromInt32(3)
+			setCanaryStrategy := func(tree *kubebuilderx.ObjectTree) *workloads.InstanceSet {
+				root, ok := tree.GetRoot().(*workloads.InstanceSet)
+				Expect(ok).Should(BeTrue())
+				root.Spec.CanaryStrategy = canaryStrategy
+				root.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+						MaxUnavailable: &canaryMaxUnavailable,
+					},
+				}
+				return root
+			}
+			// order: bar-hello-0, bar-foo-1, bar-foo-0, bar-3, bar-2, bar-1, bar-0
+			// expected: only bar-hello-0 being deleted in the first step
+			canaryTree, err := tree.DeepCopy()
+			Expect(err).Should(BeNil())
+			root = setCanaryStrategy(canaryTree)
+			res, err = reconciler.Reconcile(canaryTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			expectUpdatedPods(canaryTree, []string{"bar-hello-0"})
+			Expect(root.Status.CanaryStatus).ShouldNot(BeNil())
+			Expect(root.Status.CanaryStatus.Phase).Should(Equal(workloads.CanaryProgressingPhase))
+			Expect(root.Status.CanaryStatus.CurrentStepIndex).Should(BeEquivalentTo(0))
+			Expect(root.Status.CanaryStatus.CurrentStepReplicas).Should(BeEquivalentTo(1))
+			canaryStatus := root.Status.CanaryStatus.DeepCopy()
+
+			By("keep the promote command while the rollout is progressing")
+			progressingTree, err := canaryTree.DeepCopy()
+			Expect(err).Should(BeNil())
+			root, ok = progressingTree.GetRoot().(*workloads.InstanceSet)
+			Expect(ok).Should(BeTrue())
+			root.Annotations = map[string]string{constant.CanaryPromoteAnnotationKey: "true"}
+			res, err = reconciler.Reconcile(progressingTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			Expect(root.Annotations).Should(HaveKey(constant.CanaryPromoteAnnotationKey))
+			Expect(root.Status.CanaryStatus.Phase).Should(Equal(workloads.CanaryProgressingPhase))
+			Expect(root.Status.CanaryStatus.CurrentStepIndex).Should(BeEquivalentTo(0))
+
+			By("pause the rollout after the first step is done")
+			canaryTree, err = tree.DeepCopy()
+			Expect(err).Should(BeNil())
+			root = setCanaryStrategy(canaryTree)
+			root.Status.CanaryStatus = canaryStatus
+			pod := builder.NewPodBuilder(namespace, "bar-hello-0").GetObject()
+			object, err := canaryTree.Get(pod)
+			Expect(err).Should(BeNil())
+			pod, ok = object.(*corev1.Pod)
+			Expect(ok).Should(BeTrue())
+			makePodLatestRevision(pod)
+			res, err = reconciler.Reconcile(canaryTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			expectUpdatedPods(canaryTree, []string{})
+			Expect(root.Status.CanaryStatus.Phase).Should(Equal(workloads.CanaryPausedPhase))
+			Expect(root.Status.CanaryStatus.PauseStartTime).ShouldNot(BeNil())
+
+			By("abort the paused rollout")
+			abortTree, err := canaryTree.DeepCopy()
+			Expect(err).Should(BeNil())
+			root, ok = abortTree.GetRoot().(*workloads.InstanceSet)
+			Expect(ok).Should(BeTrue())
+			root.Annotations = map[string]string{constant.CanaryAbortAnnotationKey: "true"}
+			res, err = reconciler.Reconcile(abortTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			expectUpdatedPods(abortTree, []string{})
+			Expect(root.Annotations).ShouldNot(HaveKey(constant.CanaryAbortAnnotationKey))
+			Expect(root.Status.CanaryStatus.Phase).Should(Equal(workloads.CanaryAbortedPhase))
+
+			By("ignore the promote command once the rollout is aborted")
+			root.Annotations = map[string]string{constant.CanaryPromoteAnnotationKey: "true"}
+			res, err = reconciler.Reconcile(abortTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			expectUpdatedPods(abortTree, []string{})
+			Expect(root.Annotations).ShouldNot(HaveKey(constant.CanaryPromoteAnnotationKey))
+			Expect(root.Status.CanaryStatus.Phase).Should(Equal(workloads.CanaryAbortedPhase))
+
+			By("promote the paused rollout")
+			// the second step updates 50% (round up) of the instances
+			// expected: bar-foo-1, bar-foo-0, bar-3 being deleted
+			root, ok = canaryTree.GetRoot().(*workloads.InstanceSet)
+			Expect(ok).Should(BeTrue())
+			root.Annotations = map[string]string{constant.CanaryPromoteAnnotationKey: "true"}
+			res, err = reconciler.Reconcile(canaryTree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			expectUpdatedPods(canaryTree, []string{"bar-foo-1", "bar-foo-0", "bar-3"})
+			Expect(root.Annotations).ShouldNot(HaveKey(constant.CanaryPromoteAnnotationKey))
+			Expect(root.Status.CanaryStatus.Phase).Should(Equal(workloads.CanaryProgressingPhase))
+			Expect(root.Status.CanaryStatus.CurrentStepIndex).Should(BeEquivalentTo(1))
+			Expect(root.Status.CanaryStatus.CurrentStepReplicas).Should(BeEquivalentTo(4))
 		})
 	})
 })
@@ -58,8 +58,11 @@ const (
 )
 
 const (
-	EventReasonInvalidSpec   = "InvalidSpec"
-	EventReasonStrictInPlace = "StrictInPlace"
+	EventReasonInvalidSpec          = "InvalidSpec"
+	EventReasonStrictInPlace        = "StrictInPlace"
+	EventReasonCanaryPaused         = "CanaryPaused"
+	EventReasonCanaryAborted        = "CanaryAborted"
+	EventReasonCanaryCommandIgnored = "CanaryCommandIgnored"
 )
 
 const (
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	"github.com/apecloud/kubeblocks/pkg/controller/multicluster"
 )
 
 type ObjectTree struct {
@@ -43,6 +44,9 @@ type ObjectTree struct {
 
 	// finalizer to protect all objects of this tree
 	finalizer string
+
+	// records the data-plane k8s clusters the objects of this tree are read from in the multi-cluster mode
+	mcRecorder *multicluster.Recorder
 }
 
 type TreeLoader interface {
@@ -113,6 +117,7 @@ func (t *ObjectTree) DeepCopy() (*ObjectTree, error) {
 	}
 	out.children = children
 	out.finalizer = t.finalizer
+	out.mcRecorder = t.mcRecorder
 	out.EventRecorder = t.EventRecorder
 	out.Logger = t.Logger
 	return out, nil
@@ -198,6 +203,14 @@ func (t *ObjectTree) SetFinalizer(finalizer string) {
 	t.finalizer = finalizer
 }
 
+func (t *ObjectTree) GetMultiClusterRecorder() *multicluster.Recorder {
+	return t.mcRecorder
+}
+
+func (t *ObjectTree) SetMultiClusterRecorder(recorder *multicluster.Recorder) {
+	t.mcRecorder = recorder
+}
+
 func (t *ObjectTree) GetFinalizer() string {
 	return t.finalizer
 }
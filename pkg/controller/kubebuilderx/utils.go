@@ -58,6 +58,8 @@ func ReadObjectTree[T client.Object](ctx context.Context, reader client.Reader,
 
 	// init placement
 	ctx = intoContext(ctx, placement(root))
+	ctx, recorder := multicluster.WithRecorder(ctx)
+	tree.SetMultiClusterRecorder(recorder)
 
 	// read child objects
 	inNS := client.InNamespace(req.Namespace)
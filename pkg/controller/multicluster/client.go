@@ -104,6 +104,9 @@ func (c *clientReader) List(ctx context.Context, list client.ObjectList, opts ..
 		objs := reflect.ValueOf(list).Elem().FieldByName("Items")
 		if !objs.IsZero() {
 			for i := 0; i < objs.Len(); i++ {
+				if o, ok := objs.Index(i).Addr().Interface().(client.Object); ok {
+					recordObject(ctx, cc.context, o)
+				}
 				objects = reflect.Append(objects, objs.Index(i))
 			}
 		}
@@ -265,6 +268,7 @@ func allOf(mctx mcontext, ctx context.Context, obj client.Object, request func(c
 	var err, uerr error
 	for _, cc := range resolvedClients(mctx, ctx, obj, opts) {
 		if e := request(cc, obj); e != nil {
+			recordError(ctx, cc.context, e)
 			switch {
 			case !isUnavailableError(e) && err == nil:
 				err = e
@@ -291,8 +295,10 @@ func anyOf_(mctx mcontext, ctx context.Context, obj client.Object, request func(
 	var err, uerr error
 	for _, cc := range resolvedClients(mctx, ctx, obj, opts) {
 		e := request(cc, obj)
+		recordError(ctx, cc.context, e)
 		switch {
 		case e == nil:
+			recordObject(ctx, cc.context, obj)
 			return nil
 		case !isUnavailableError(e) && err == nil:
 			err = e
@@ -312,8 +318,10 @@ func anyOfWithMultiCheck(mctx mcontext, ctx context.Context, obj client.Object,
 	for _, cc := range resolvedClients(mctx, ctx, obj, opts) {
 		o := obj.DeepCopyObject().(client.Object)
 		e := request(cc, o)
+		recordError(ctx, cc.context, e)
 		switch {
 		case e == nil:
+			recordObject(ctx, cc.context, o)
 			objs = append(objs, o)
 		case !isUnavailableError(e) && err == nil:
 			err = e
@@ -0,0 +1,195 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrlmanager "sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	defaultHealthProbeInterval  = 30 * time.Second
+	defaultHealthProbeTimeout   = 10 * time.Second
+	defaultHealthProbeThreshold = 3
+	healthEventBufferSize       = 1024
+)
+
+// Health is the observed health of a data-plane k8s cluster.
+type Health struct {
+	Available bool
+
+	// The last time the availability changed.
+	LastTransitionTime time.Time
+
+	// The allocatable resources of the ready and schedulable nodes, as of the last successful probe.
+	Capacity corev1.ResourceList
+
+	Message string
+}
+
+// IsAvailable tells whether the data-plane k8s cluster is available.
+// A data-plane k8s cluster that has not been probed yet is considered to be available.
+func IsAvailable(health map[string]Health, context string) bool {
+	h, ok := health[context]
+	return !ok || h.Available
+}
+
+// UnavailableFor returns the duration that the data-plane k8s cluster has been unavailable for,
+// or zero if it is available.
+func UnavailableFor(health map[string]Health, context string, now time.Time) time.Duration {
+	if IsAvailable(health, context) {
+		return 0
+	}
+	return now.Sub(health[context].LastTransitionTime)
+}
+
+type healthChecker struct {
+	clients   map[string]client.Client
+	interval  time.Duration
+	timeout   time.Duration
+	threshold int
+
+	mu       sync.RWMutex
+	health   map[string]Health
+	failures map[string]int
+
+	events chan event.GenericEvent
+}
+
+var _ ctrlmanager.Runnable = &healthChecker{}
+
+func newHealthChecker(clients map[string]client.Client) *healthChecker {
+	return &healthChecker{
+		clients:   clients,
+		interval:  defaultHealthProbeInterval,
+		timeout:   defaultHealthProbeTimeout,
+		threshold: defaultHealthProbeThreshold,
+		health:    make(map[string]Health),
+		failures:  make(map[string]int),
+		events:    make(chan event.GenericEvent, healthEventBufferSize),
+	}
+}
+
+func (c *healthChecker) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, c.probeAll, c.interval)
+	return nil
+}
+
+func (c *healthChecker) get() map[string]Health {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	health := make(map[string]Health, len(c.health))
+	for k, v := range c.health {
+		health[k] = v
+	}
+	return health
+}
+
+func (c *healthChecker) probeAll(ctx context.Context) {
+	for k, cli := range c.clients {
+		capacity, err := c.probe(ctx, cli)
+		if c.update(k, capacity, err, time.Now()) {
+			c.notify(k)
+		}
+	}
+}
+
+func (c *healthChecker) probe(ctx context.Context, cli client.Client) (corev1.ResourceList, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	nodes := &corev1.NodeList{}
+	if err := cli.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	return allocatable(nodes.Items), nil
+}
+
+// update records the probe result of the context, and returns whether the watchers should be notified,
+// that is the availability changed or the context is still unavailable.
+func (c *healthChecker) update(context string, capacity corev1.ResourceList, err error, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h, ok := c.health[context]
+	if !ok {
+		h = Health{Available: true, LastTransitionTime: now}
+	}
+	if err == nil {
+		c.failures[context] = 0
+		changed := !h.Available
+		h.Available, h.Capacity, h.Message = true, capacity, ""
+		if changed {
+			h.LastTransitionTime = now
+		}
+		c.health[context] = h
+		return changed
+	}
+
+	c.failures[context]++
+	h.Message = fmt.Sprintf("probe failed: %s", err.Error())
+	if h.Available && (c.failures[context] >= c.threshold || isUnavailableError(err)) {
+		h.Available, h.LastTransitionTime = false, now
+	}
+	c.health[context] = h
+	return !h.Available
+}
+
+func (c *healthChecker) notify(context string) {
+	obj := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: context}}
+	select {
+	case c.events <- event.GenericEvent{Object: obj}:
+	default:
+		// the watchers are busy, and they will be notified in the next round if it is still unavailable
+	}
+}
+
+func allocatable(nodes []corev1.Node) corev1.ResourceList {
+	cpu, memory := resource.Quantity{}, resource.Quantity{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !isNodeReady(node) {
+			continue
+		}
+		cpu.Add(node.Status.Allocatable[corev1.ResourceCPU])
+		memory.Add(node.Status.Allocatable[corev1.ResourceMemory])
+	}
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    cpu,
+		corev1.ResourceMemory: memory,
+	}
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -41,11 +41,19 @@ type Manager interface {
 	Own(b *builder.Builder, obj, owner client.Object) Manager
 
 	Watch(b *builder.Builder, obj client.Object, eventHandler handler.EventHandler) Manager
+
+	// GetHealth returns the observed health of the data-plane k8s clusters.
+	GetHealth() map[string]Health
+
+	// WatchHealth notifies the event handler with the name of the data-plane k8s cluster,
+	// when its availability changes or while it is unavailable.
+	WatchHealth(b *builder.Builder, eventHandler handler.EventHandler) Manager
 }
 
 type manager struct {
 	cli    client.Client
 	caches map[string]cache.Cache
+	health *healthChecker
 }
 
 var _ Manager = &manager{}
@@ -66,6 +74,9 @@ func (m *manager) Bind(mgr ctrl.Manager) error {
 			}
 		}
 	}
+	if err := mgr.Add(m.health); err != nil {
+		return fmt.Errorf("failed to bind health checker to Manager: %s", err.Error())
+	}
 	return nil
 }
 
@@ -87,3 +98,12 @@ func (m *manager) Watch(b *builder.Builder, obj client.Object, eventHandler hand
 	}
 	return m
 }
+
+func (m *manager) GetHealth() map[string]Health {
+	return m.health.get()
+}
+
+func (m *manager) WatchHealth(b *builder.Builder, eventHandler handler.EventHandler) Manager {
+	b.WatchesRawSource(&source.Channel{Source: m.health.events}, eventHandler)
+	return m
+}
@@ -21,8 +21,12 @@ package multicluster
 
 import (
 	"context"
+	"math/rand"
+	"slices"
 	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -64,6 +68,64 @@ func Assign(ctx context.Context, obj client.Object, ordinal func() int) client.O
 	return obj
 }
 
+// Select picks at most n data-plane k8s clusters out of the candidates to place on.
+// The available ones are picked only, and the ones with more allocatable capacity are preferred.
+// If none of the candidates is available, they are picked as is to wait for them to recover.
+func Select(candidates []string, health map[string]Health, n int) []string {
+	available := make([]string, 0)
+	for _, c := range candidates {
+		if IsAvailable(health, c) {
+			available = append(available, c)
+		}
+	}
+	if len(available) == 0 {
+		available = slices.Clone(candidates)
+	}
+
+	// shuffle to spread the placements out among the data-plane k8s clusters with the same capacity
+	slices.Sort(available)
+	rand.Shuffle(len(available), func(i, j int) {
+		available[i], available[j] = available[j], available[i]
+	})
+	slices.SortStableFunc(available, func(a, b string) int {
+		return compareCapacity(health[b].Capacity, health[a].Capacity)
+	})
+	return available[:min(n, len(available))]
+}
+
+// RePlace replaces the data-plane k8s clusters in the placement that have been unavailable longer than
+// the grace period with the available candidates that are not in the placement yet.
+// The replacement takes the same position in the placement, so that the instances on the other ones are not moved.
+// It returns the new placement, and the replacements made keyed by the unavailable ones.
+func RePlace(placement, candidates []string, health map[string]Health, gracePeriod time.Duration, now time.Time) ([]string, map[string]string) {
+	result := slices.Clone(placement)
+	replacements := make(map[string]string)
+	for i, c := range placement {
+		if IsAvailable(health, c) || UnavailableFor(health, c, now) < gracePeriod {
+			continue
+		}
+		spares := make([]string, 0)
+		for _, candidate := range candidates {
+			if IsAvailable(health, candidate) && !slices.Contains(result, candidate) {
+				spares = append(spares, candidate)
+			}
+		}
+		if len(spares) == 0 {
+			continue
+		}
+		result[i] = Select(spares, health, 1)[0]
+		replacements[c] = result[i]
+	}
+	return result, replacements
+}
+
+func compareCapacity(a, b corev1.ResourceList) int {
+	if r := a.Cpu().Cmp(*b.Cpu()); r != 0 {
+		return r
+	}
+	return a.Memory().Cmp(*b.Memory())
+}
+
 func setPlacementKey(obj client.Object, context string) {
 	// has been set
 	if obj.GetAnnotations() != nil && obj.GetAnnotations()[constant.KBAppMultiClusterPlacementKey] != "" {
@@ -0,0 +1,149 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package multicluster
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("placement", func() {
+	var (
+		now = time.Now()
+	)
+
+	capacity := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+
+	Context("select", func() {
+		It("prefers the available ones with more capacity", func() {
+			health := map[string]Health{
+				"a": {Available: true, Capacity: capacity("4", "8Gi")},
+				"b": {Available: false, Capacity: capacity("32", "64Gi")},
+				"c": {Available: true, Capacity: capacity("16", "32Gi")},
+				"d": {Available: true, Capacity: capacity("16", "64Gi")},
+			}
+			Expect(Select([]string{"a", "b", "c", "d"}, health, 2)).Should(Equal([]string{"d", "c"}))
+			Expect(Select([]string{"a", "b", "c", "d"}, health, 5)).Should(Equal([]string{"d", "c", "a"}))
+		})
+
+		It("takes the ones not probed yet as available", func() {
+			health := map[string]Health{
+				"a": {Available: false},
+			}
+			Expect(Select([]string{"a", "b"}, health, 2)).Should(Equal([]string{"b"}))
+		})
+
+		It("picks all candidates if none is available", func() {
+			health := map[string]Health{
+				"a": {Available: false},
+				"b": {Available: false},
+			}
+			Expect(Select([]string{"a", "b"}, health, 2)).Should(ConsistOf("a", "b"))
+		})
+	})
+
+	Context("re-place", func() {
+		It("waits within the grace period", func() {
+			health := map[string]Health{
+				"a": {Available: false, LastTransitionTime: now.Add(-time.Minute)},
+			}
+			p, replacements := RePlace([]string{"a", "b"}, []string{"a", "b", "c"}, health, 5*time.Minute, now)
+			Expect(p).Should(Equal([]string{"a", "b"}))
+			Expect(replacements).Should(BeEmpty())
+		})
+
+		It("re-places the unavailable ones at the same position", func() {
+			health := map[string]Health{
+				"a": {Available: false, LastTransitionTime: now.Add(-10 * time.Minute)},
+				"c": {Available: true, Capacity: capacity("4", "8Gi")},
+				"d": {Available: true, Capacity: capacity("8", "8Gi")},
+			}
+			p, replacements := RePlace([]string{"a", "b"}, []string{"a", "b", "c", "d"}, health, 5*time.Minute, now)
+			Expect(p).Should(Equal([]string{"d", "b"}))
+			Expect(replacements).Should(HaveKeyWithValue("a", "d"))
+		})
+
+		It("keeps the unavailable ones if there is no spare", func() {
+			health := map[string]Health{
+				"a": {Available: false, LastTransitionTime: now.Add(-10 * time.Minute)},
+				"c": {Available: false, LastTransitionTime: now.Add(-10 * time.Minute)},
+			}
+			p, replacements := RePlace([]string{"a", "b"}, []string{"a", "b", "c"}, health, 5*time.Minute, now)
+			Expect(p).Should(Equal([]string{"a", "b"}))
+			Expect(replacements).Should(BeEmpty())
+		})
+	})
+
+	Context("health", func() {
+		It("marks unavailable after consecutive failures", func() {
+			checker := newHealthChecker(nil)
+			err := fmt.Errorf("connection refused")
+			for i := 1; i < defaultHealthProbeThreshold; i++ {
+				Expect(checker.update("a", nil, err, now)).Should(BeFalse())
+				Expect(IsAvailable(checker.get(), "a")).Should(BeTrue())
+			}
+			Expect(checker.update("a", nil, err, now)).Should(BeTrue())
+			Expect(IsAvailable(checker.get(), "a")).Should(BeFalse())
+			Expect(UnavailableFor(checker.get(), "a", now.Add(time.Minute))).Should(Equal(time.Minute))
+
+			By("recover")
+			Expect(checker.update("a", capacity("4", "8Gi"), nil, now.Add(time.Minute))).Should(BeTrue())
+			Expect(IsAvailable(checker.get(), "a")).Should(BeTrue())
+			Expect(checker.update("a", capacity("4", "8Gi"), nil, now.Add(2*time.Minute))).Should(BeFalse())
+		})
+
+		It("marks the disabled ones unavailable at once", func() {
+			checker := newHealthChecker(nil)
+			Expect(checker.update("a", nil, &unavailableError{context: "a", call: "List"}, now)).Should(BeTrue())
+			Expect(IsAvailable(checker.get(), "a")).Should(BeFalse())
+		})
+
+		It("sums up the allocatable of ready and schedulable nodes", func() {
+			node := func(cpu string, ready corev1.ConditionStatus, unschedulable bool) corev1.Node {
+				return corev1.Node{
+					Spec: corev1.NodeSpec{Unschedulable: unschedulable},
+					Status: corev1.NodeStatus{
+						Allocatable: capacity(cpu, "1Gi"),
+						Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+					},
+				}
+			}
+			c := allocatable([]corev1.Node{
+				node("2", corev1.ConditionTrue, false),
+				node("4", corev1.ConditionTrue, false),
+				node("8", corev1.ConditionFalse, false),
+				node("16", corev1.ConditionTrue, true),
+			})
+			Expect(c.Cpu().Cmp(resource.MustParse("6"))).Should(Equal(0))
+			Expect(c.Memory().Cmp(resource.MustParse("2Gi"))).Should(Equal(0))
+		})
+	})
+})
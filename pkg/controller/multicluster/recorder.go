@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithRecorder returns a context that records the results of the requests to the data-plane k8s clusters,
+// so that the objects read and the errors met can be attributed to the data-plane k8s clusters they come from.
+func WithRecorder(ctx context.Context) (context.Context, *Recorder) {
+	r := &Recorder{
+		errors:  make(map[string]error),
+		objects: make(map[types.UID]string),
+	}
+	return context.WithValue(ctx, recorderKey{}, r), r
+}
+
+// Recorder records the results of the requests to the data-plane k8s clusters.
+type Recorder struct {
+	mu      sync.RWMutex
+	errors  map[string]error
+	objects map[types.UID]string
+}
+
+type recorderKey struct{}
+
+// Errors returns the last error met for each data-plane k8s cluster.
+func (r *Recorder) Errors() map[string]error {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	errors := make(map[string]error, len(r.errors))
+	for k, v := range r.errors {
+		errors[k] = v
+	}
+	return errors
+}
+
+// ContextOf returns the data-plane k8s cluster the object is read from, or empty if it is unknown.
+func (r *Recorder) ContextOf(obj client.Object) string {
+	if r == nil || obj == nil || len(obj.GetUID()) == 0 {
+		return ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.objects[obj.GetUID()]
+}
+
+func (r *Recorder) recordError(context string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[context] = err
+}
+
+func (r *Recorder) recordObject(context string, obj client.Object) {
+	if len(obj.GetUID()) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.objects[obj.GetUID()] = context
+}
+
+func recorderFromContext(ctx context.Context) *Recorder {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// recordError records the error met for the data-plane k8s cluster, the requests to the control plane are ignored.
+// The not found errors are expected when an object is looked up across the data-plane k8s clusters, so they are ignored too.
+func recordError(ctx context.Context, context string, err error) {
+	if err == nil || apierrors.IsNotFound(err) {
+		return
+	}
+	if r := recorderFromContext(ctx); r != nil && len(context) > 0 {
+		r.recordError(context, err)
+	}
+}
+
+// recordObject records the data-plane k8s cluster the object is read from, the control plane is ignored.
+func recordObject(ctx context.Context, context string, obj client.Object) {
+	if r := recorderFromContext(ctx); r != nil && obj != nil && len(context) > 0 {
+		r.recordObject(context, obj)
+	}
+}
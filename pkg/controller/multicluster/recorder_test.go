@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package multicluster
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("recorder", func() {
+	pod := func(name, uid string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				UID:       types.UID("uid-" + uid),
+			},
+		}
+	}
+
+	newClient := func() client.Client {
+		setupScheme(clientgoscheme.Scheme)
+		control := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+		east := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(pod("pod-0", "0")).Build()
+		west := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(pod("pod-1", "1")).Build()
+		return NewClient(control, map[string]client.Client{
+			"east":  east,
+			"west":  west,
+			"north": newUnavailableClient("north"),
+		})
+	}
+
+	It("attributes the objects listed and the errors met to the data-plane k8s clusters", func() {
+		cli := newClient()
+		ctx, recorder := WithRecorder(IntoContext(context.Background(), "east,west,north"))
+
+		pods := &corev1.PodList{}
+		Expect(cli.List(ctx, pods, InDataContext())).Should(Succeed())
+		Expect(pods.Items).Should(HaveLen(2))
+		for i := range pods.Items {
+			switch pods.Items[i].Name {
+			case "pod-0":
+				Expect(recorder.ContextOf(&pods.Items[i])).Should(Equal("east"))
+			case "pod-1":
+				Expect(recorder.ContextOf(&pods.Items[i])).Should(Equal("west"))
+			}
+		}
+		errors := recorder.Errors()
+		Expect(errors).Should(HaveLen(1))
+		Expect(errors).Should(HaveKey("north"))
+	})
+
+	It("ignores the not found errors met when getting an object", func() {
+		cli := newClient()
+		ctx, recorder := WithRecorder(IntoContext(context.Background(), "east,west"))
+
+		obj := &corev1.Pod{}
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "pod-1"}, obj, InDataContext())).Should(Succeed())
+		Expect(recorder.ContextOf(obj)).Should(Equal("west"))
+		Expect(recorder.Errors()).Should(BeEmpty())
+	})
+
+	It("records nothing without a recorder", func() {
+		cli := newClient()
+		ctx := IntoContext(context.Background(), "east,west")
+
+		pods := &corev1.PodList{}
+		Expect(cli.List(ctx, pods, InDataContext())).Should(Succeed())
+		var recorder *Recorder
+		Expect(recorder.ContextOf(&pods.Items[0])).Should(BeEmpty())
+		Expect(recorder.Errors()).Should(BeNil())
+	})
+})
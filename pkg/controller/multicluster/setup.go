@@ -79,6 +79,7 @@ func Setup(scheme *runtime.Scheme, cfg *rest.Config, cli client.Client, kubeConf
 	return &manager{
 		cli:    NewClient(cli, clients()),
 		caches: caches(),
+		health: newHealthChecker(clients()),
 	}, nil
 }
 
@@ -22,13 +22,19 @@ package plan
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -38,42 +44,113 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
 )
 
-// ComposeTLSSecret composes a TSL secret object.
+const (
+	defaultTLSCertValidity = 36500 * 24 * time.Hour
+)
+
+// CertManagerCertificateGVK is the GroupVersionKind of the cert-manager Certificate.
+var CertManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// ComposeTLSCASecret composes the secret of the CA that signs the TLS certificates of the component.
+// The CA is kept across the rotations of the certificates, so that the clients trusting it keep working
+// after the certificates are re-issued. It is stored apart from the TLS secret to keep its key out of the pods.
+func ComposeTLSCASecret(synthesizedComp component.SynthesizedComponent) (*v1.Secret, error) {
+	const spliter = "___spliter___"
+	caTpl := fmt.Sprintf(`
+	{{- $ca := genCA "KubeBlocks" %d -}}
+	{{- $ca.Cert -}}
+	{{- print "%s" -}}
+	{{- $ca.Key -}}
+`, int(defaultTLSCertValidity.Hours()/24), spliter)
+	out, err := buildFromTemplate(caTpl, nil)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(out, spliter)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("generate TLS CA failed with cluster name %s, component name %s in namespace %s", synthesizedComp.ClusterName, synthesizedComp.Name, synthesizedComp.Namespace)
+	}
+	secret := buildTLSSecret(synthesizedComp, GenerateTLSCASecretName(synthesizedComp.ClusterName, synthesizedComp.Name))
+	secret.StringData[constant.CAName] = parts[0]
+	secret.StringData[constant.CAKeyName] = parts[1]
+	return secret, nil
+}
+
+// ComposeTLSSecret composes a TLS secret object, whose certificate is signed by the CA in the caSecret.
 // REVIEW/TODO:
-//  1. missing public function doc
-//  2. should avoid using Go template to call a function, this is too hacky & costly,
+//  1. should avoid using Go template to call a function, this is too hacky & costly,
 //     should just call underlying registered Go template function.
-func ComposeTLSSecret(synthesizedComp component.SynthesizedComponent) (*v1.Secret, error) {
+func ComposeTLSSecret(synthesizedComp component.SynthesizedComponent, caSecret *v1.Secret) (*v1.Secret, error) {
+	caCert, caKey := tlsSecretData(caSecret, constant.CAName), tlsSecretData(caSecret, constant.CAKeyName)
+	if len(caCert) == 0 || len(caKey) == 0 {
+		return nil, errors.Errorf("the CA certificate or key is missing in secret %s", caSecret.Name)
+	}
 	secret := BuildTLSSecret(synthesizedComp)
 	// use ca gen cert
 	// IP: 127.0.0.1 and ::1
 	// DNS: localhost and *.<clusterName>-<componentName>-headless.<namespace>.svc.cluster.local
 	const spliter = "___spliter___"
+	days := tlsCertValidityDays(synthesizedComp)
 	SignedCertTpl := fmt.Sprintf(`
-	{{- $ca := genCA "KubeBlocks" 36500 -}}
-	{{- $cert := genSignedCert "%s peer" (list "127.0.0.1" "::1") (list "localhost" "*.%s-%s-headless.%s.svc.cluster.local") 36500 $ca -}}
-	{{- $ca.Cert -}}
-	{{- print "%s" -}}
+	{{- $ca := buildCustomCert "%s" "%s" -}}
+	{{- $cert := genSignedCert "%s peer" (list "127.0.0.1" "::1") (list "localhost" "*.%s-%s-headless.%s.svc.cluster.local") %d $ca -}}
 	{{- $cert.Cert -}}
 	{{- print "%s" -}}
 	{{- $cert.Key -}}
-`, synthesizedComp.Name, synthesizedComp.ClusterName, synthesizedComp.Name, synthesizedComp.Namespace, spliter, spliter)
+`, base64.StdEncoding.EncodeToString(caCert), base64.StdEncoding.EncodeToString(caKey),
+		synthesizedComp.Name, synthesizedComp.ClusterName, synthesizedComp.Name, synthesizedComp.Namespace, days, spliter)
 	out, err := buildFromTemplate(SignedCertTpl, nil)
 	if err != nil {
 		return nil, err
 	}
 	parts := strings.Split(out, spliter)
-	if len(parts) != 3 {
+	if len(parts) != 2 {
 		return nil, errors.Errorf("generate TLS certificates failed with cluster name %s, component name %s in namespace %s", synthesizedComp.ClusterName, synthesizedComp.Name, synthesizedComp.Namespace)
 	}
-	secret.StringData[constant.CAName] = parts[0]
-	secret.StringData[constant.CertName] = parts[1]
-	secret.StringData[constant.KeyName] = parts[2]
+	secret.StringData[constant.CAName] = string(caCert)
+	secret.StringData[constant.CertName] = parts[0]
+	secret.StringData[constant.KeyName] = parts[1]
 	return secret, nil
 }
 
+// ComposeKBAgentCerts issues a CA exclusive to the kb-agents of the component, and the certificate signed by it,
+// which is used by the kb-agents as both the server and the client certificate.
+func ComposeKBAgentCerts(synthesizedComp component.SynthesizedComponent) (map[string][]byte, error) {
+	const spliter = "___spliter___"
+	days := int(defaultTLSCertValidity.Hours() / 24)
+	signedCertTpl := fmt.Sprintf(`
+	{{- $ca := genCA "KubeBlocks kb-agent" %d -}}
+	{{- $cert := genSignedCert "%s-%s kbagent" nil (list "*.%s-%s-headless.%s.svc") %d $ca -}}
+	{{- $ca.Cert -}}
+	{{- print "%s" -}}
+	{{- $cert.Cert -}}
+	{{- print "%s" -}}
+	{{- $cert.Key -}}
+`, days, synthesizedComp.ClusterName, synthesizedComp.Name, synthesizedComp.ClusterName, synthesizedComp.Name, synthesizedComp.Namespace, days, spliter, spliter)
+	out, err := buildFromTemplate(signedCertTpl, nil)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(out, spliter)
+	if len(parts) != 3 {
+		return nil, errors.Errorf("generate kb-agent certificates failed with cluster name %s, component name %s in namespace %s", synthesizedComp.ClusterName, synthesizedComp.Name, synthesizedComp.Namespace)
+	}
+	return map[string][]byte{
+		constant.CAName:   []byte(parts[0]),
+		constant.CertName: []byte(parts[1]),
+		constant.KeyName:  []byte(parts[2]),
+	}, nil
+}
+
 func BuildTLSSecret(synthesizedComp component.SynthesizedComponent) *v1.Secret {
-	name := GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name)
+	return buildTLSSecret(synthesizedComp, GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name))
+}
+
+func buildTLSSecret(synthesizedComp component.SynthesizedComponent, name string) *v1.Secret {
 	return builder.NewSecretBuilder(synthesizedComp.Namespace, name).
 		AddLabelsInMap(constant.GetCompLabels(synthesizedComp.ClusterName, synthesizedComp.Name)).
 		AddLabelsInMap(synthesizedComp.DynamicLabels).
@@ -88,6 +165,132 @@ func GenerateTLSSecretName(clusterName, componentName string) string {
 	return clusterName + "-" + componentName + "-tls-certs"
 }
 
+func GenerateTLSCASecretName(clusterName, componentName string) string {
+	return clusterName + "-" + componentName + "-tls-ca"
+}
+
+// BuildCertManagerCertificate builds a cert-manager Certificate which issues the certificates into the TLS secret of the component.
+// The SANs of the certificate include the FQDNs of the component services and pods.
+func BuildCertManagerCertificate(synthesizedComp component.SynthesizedComponent) (*unstructured.Unstructured, error) {
+	issuerRef := synthesizedComp.TLSConfig.Issuer.IssuerRef
+	if issuerRef == nil {
+		return nil, errors.New("issuer.issuerRef shouldn't be nil when issuer is CertManager")
+	}
+	podFQDNs, err := component.PodFQDNs(&synthesizedComp)
+	if err != nil {
+		return nil, err
+	}
+	dnsNames := []any{"localhost"}
+	for _, name := range append(component.ServiceFQDNs(&synthesizedComp), podFQDNs...) {
+		dnsNames = append(dnsNames, name)
+	}
+
+	name := GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name)
+	labels := constant.GetCompLabels(synthesizedComp.ClusterName, synthesizedComp.Name)
+	secretLabels := map[string]any{}
+	for k, v := range labels {
+		secretLabels[k] = v
+	}
+	spec := map[string]any{
+		"secretName": name,
+		"secretTemplate": map[string]any{
+			"labels": secretLabels,
+		},
+		"commonName":  fmt.Sprintf("%s peer", synthesizedComp.Name),
+		"dnsNames":    dnsNames,
+		"ipAddresses": []any{"127.0.0.1", "::1"},
+		"usages":      []any{"server auth", "client auth"},
+		"issuerRef": map[string]any{
+			"name":  issuerRef.Name,
+			"kind":  defaultIfEmpty(issuerRef.Kind, "Issuer"),
+			"group": defaultIfEmpty(issuerRef.Group, CertManagerCertificateGVK.Group),
+		},
+	}
+	if duration := synthesizedComp.TLSConfig.Issuer.Duration; duration != nil {
+		spec["duration"] = duration.Duration.String()
+	}
+	if renewBefore := synthesizedComp.TLSConfig.Issuer.RenewBefore; renewBefore != nil {
+		spec["renewBefore"] = renewBefore.Duration.String()
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(CertManagerCertificateGVK)
+	obj.SetNamespace(synthesizedComp.Namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	if err = unstructured.SetNestedField(obj.Object, spec, "spec"); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func defaultIfEmpty(val, defaultVal string) string {
+	if len(val) == 0 {
+		return defaultVal
+	}
+	return val
+}
+
+// IsTLSCertRenewalDue checks whether the certificate in the TLS secret issued by KubeBlocks
+// has entered the renewal window, or is missing or malformed and should be re-issued.
+func IsTLSCertRenewalDue(synthesizedComp component.SynthesizedComponent, secret *v1.Secret, now time.Time) bool {
+	notAfter, err := GetTLSCertNotAfter(secret)
+	if err != nil {
+		return true
+	}
+	return !now.Add(tlsCertRenewBefore(synthesizedComp)).Before(notAfter)
+}
+
+// GetTLSCertNotAfter returns the expiration time of the certificate in the TLS secret.
+func GetTLSCertNotAfter(secret *v1.Secret) (time.Time, error) {
+	data := tlsSecretData(secret, constant.CertName)
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, errors.Errorf("failed to decode the PEM-encoded certificate in secret %s", secret.Name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse the certificate in secret %s", secret.Name)
+	}
+	return cert.NotAfter, nil
+}
+
+// tlsSecretData returns the data of the key in the secret, which may not have been written to the data yet.
+func tlsSecretData(secret *v1.Secret, key string) []byte {
+	if data := secret.Data[key]; len(data) > 0 {
+		return data
+	}
+	return []byte(secret.StringData[key])
+}
+
+// tlsCertValidity returns the validity of the certificates issued by KubeBlocks. The certificates are issued
+// in whole days, which is required by the API, the duration is still truncated to whole days and is one day
+// at least for the objects created before, and the renewal window is derived from the same value.
+func tlsCertValidity(synthesizedComp component.SynthesizedComponent) time.Duration {
+	tls := synthesizedComp.TLSConfig
+	if tls == nil || tls.Issuer == nil || tls.Issuer.Duration == nil || tls.Issuer.Duration.Duration <= 0 {
+		return defaultTLSCertValidity
+	}
+	return max(tls.Issuer.Duration.Duration.Truncate(24*time.Hour), 24*time.Hour)
+}
+
+func tlsCertValidityDays(synthesizedComp component.SynthesizedComponent) int {
+	return int(tlsCertValidity(synthesizedComp) / (24 * time.Hour))
+}
+
+// tlsCertRenewBefore returns the renewal window of the certificates.
+// A window not less than the validity would make the certificates due right after they are issued,
+// and re-issue them on every reconciliation, so it falls back to the default in that case.
+func tlsCertRenewBefore(synthesizedComp component.SynthesizedComponent) time.Duration {
+	tls := synthesizedComp.TLSConfig
+	validity := tlsCertValidity(synthesizedComp)
+	if tls == nil || tls.Issuer == nil || tls.Issuer.RenewBefore == nil ||
+		tls.Issuer.RenewBefore.Duration <= 0 || tls.Issuer.RenewBefore.Duration >= validity {
+		return validity / 3
+	}
+	return tls.Issuer.RenewBefore.Duration
+}
+
 func buildFromTemplate(tpl string, vars interface{}) (string, error) {
 	fmap := sprig.TxtFuncMap()
 	t := template.Must(template.New("tls").Funcs(fmap).Parse(tpl))
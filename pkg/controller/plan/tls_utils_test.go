@@ -21,7 +21,11 @@ package plan
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -29,6 +33,8 @@ import (
 	"github.com/golang/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -48,7 +54,13 @@ var _ = Describe("TLSUtilsTest", func() {
 				ClusterName: "bar",
 				Name:        "test",
 			}
-			secret, err := ComposeTLSSecret(synthesizedComp)
+			caSecret, err := ComposeTLSCASecret(synthesizedComp)
+			Expect(err).Should(BeNil())
+			Expect(caSecret.Name).Should(Equal(fmt.Sprintf("%s-%s-tls-ca", synthesizedComp.ClusterName, synthesizedComp.Name)))
+			Expect(caSecret.StringData[constant.CAName]).ShouldNot(BeZero())
+			Expect(caSecret.StringData[constant.CAKeyName]).ShouldNot(BeZero())
+
+			secret, err := ComposeTLSSecret(synthesizedComp, caSecret)
 			Expect(err).Should(BeNil())
 			Expect(secret).ShouldNot(BeNil())
 			Expect(secret.Name).Should(Equal(fmt.Sprintf("%s-%s-tls-certs", synthesizedComp.ClusterName, synthesizedComp.Name)))
@@ -60,6 +72,159 @@ var _ = Describe("TLSUtilsTest", func() {
 			Expect(secret.StringData[constant.CAName]).ShouldNot(BeZero())
 			Expect(secret.StringData[constant.CertName]).ShouldNot(BeZero())
 			Expect(secret.StringData[constant.KeyName]).ShouldNot(BeZero())
+			Expect(secret.StringData).ShouldNot(HaveKey(constant.CAKeyName))
+
+			By("re-issuing the certificate with the same CA")
+			reissued, err := ComposeTLSSecret(synthesizedComp, caSecret)
+			Expect(err).Should(BeNil())
+			Expect(reissued.StringData[constant.CAName]).Should(Equal(secret.StringData[constant.CAName]))
+			Expect(reissued.StringData[constant.CertName]).ShouldNot(Equal(secret.StringData[constant.CertName]))
+
+			By("checking the re-issued certificate is signed by the CA")
+			roots := x509.NewCertPool()
+			Expect(roots.AppendCertsFromPEM([]byte(caSecret.StringData[constant.CAName]))).Should(BeTrue())
+			block, _ := pem.Decode([]byte(reissued.StringData[constant.CertName]))
+			Expect(block).ShouldNot(BeNil())
+			cert, err := x509.ParseCertificate(block.Bytes)
+			Expect(err).Should(BeNil())
+			_, err = cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "localhost"})
+			Expect(err).Should(BeNil())
+
+			By("failing without the CA key")
+			delete(caSecret.StringData, constant.CAKeyName)
+			_, err = ComposeTLSSecret(synthesizedComp, caSecret)
+			Expect(err).ShouldNot(BeNil())
+		})
+	})
+
+	Context("IsTLSCertRenewalDue function", func() {
+		It("should work well", func() {
+			synthesizedComp := component.SynthesizedComponent{
+				Namespace:   testCtx.DefaultNamespace,
+				ClusterName: "bar",
+				Name:        "test",
+				TLSConfig: &appsv1.TLSConfig{
+					Enable: true,
+					Issuer: &appsv1.Issuer{
+						Name:        appsv1.IssuerKubeBlocks,
+						Duration:    &metav1.Duration{Duration: 30 * 24 * time.Hour},
+						RenewBefore: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+					},
+				},
+			}
+			caSecret, err := ComposeTLSCASecret(synthesizedComp)
+			Expect(err).Should(BeNil())
+			secret, err := ComposeTLSSecret(synthesizedComp, caSecret)
+			Expect(err).Should(BeNil())
+
+			notAfter, err := GetTLSCertNotAfter(secret)
+			Expect(err).Should(BeNil())
+			Expect(notAfter).Should(BeTemporally("~", time.Now().Add(30*24*time.Hour), time.Hour))
+
+			By("not in the renewal window")
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now())).Should(BeFalse())
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now().Add(22*24*time.Hour))).Should(BeFalse())
+
+			By("in the renewal window")
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now().Add(24*24*time.Hour))).Should(BeTrue())
+
+			By("default renewal window")
+			synthesizedComp.TLSConfig.Issuer.RenewBefore = nil
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now().Add(19*24*time.Hour))).Should(BeFalse())
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now().Add(21*24*time.Hour))).Should(BeTrue())
+
+			By("the renewal window not less than the validity falls back to the default")
+			synthesizedComp.TLSConfig.Issuer.RenewBefore = &metav1.Duration{Duration: 60 * 24 * time.Hour}
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now())).Should(BeFalse())
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now().Add(19*24*time.Hour))).Should(BeFalse())
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now().Add(21*24*time.Hour))).Should(BeTrue())
+
+			By("the validity not in whole days is truncated, and so is the renewal window derived from it")
+			synthesizedComp.TLSConfig.Issuer.Duration = &metav1.Duration{Duration: 36 * time.Hour}
+			synthesizedComp.TLSConfig.Issuer.RenewBefore = &metav1.Duration{Duration: 30 * time.Hour}
+			secret, err = ComposeTLSSecret(synthesizedComp, caSecret)
+			Expect(err).Should(BeNil())
+			notAfter, err = GetTLSCertNotAfter(secret)
+			Expect(err).Should(BeNil())
+			Expect(notAfter).Should(BeTemporally("~", time.Now().Add(24*time.Hour), time.Hour))
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now())).Should(BeFalse())
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now().Add(17*time.Hour))).Should(BeTrue())
+
+			By("malformed certificate")
+			secret.StringData[constant.CertName] = "foo"
+			Expect(IsTLSCertRenewalDue(synthesizedComp, secret, time.Now())).Should(BeTrue())
+		})
+	})
+
+	Context("BuildCertManagerCertificate function", func() {
+		It("should work well", func() {
+			synthesizedComp := component.SynthesizedComponent{
+				Namespace:   testCtx.DefaultNamespace,
+				ClusterName: "bar",
+				Name:        "test",
+				Replicas:    2,
+				TLSConfig: &appsv1.TLSConfig{
+					Enable: true,
+					Issuer: &appsv1.Issuer{
+						Name:     appsv1.IssuerCertManager,
+						Duration: &metav1.Duration{Duration: 30 * 24 * time.Hour},
+					},
+				},
+			}
+			_, err := BuildCertManagerCertificate(synthesizedComp)
+			Expect(err).ShouldNot(BeNil())
+
+			synthesizedComp.TLSConfig.Issuer.IssuerRef = &appsv1.CertManagerIssuerRef{
+				Name: "ca-issuer",
+				Kind: "ClusterIssuer",
+			}
+			cert, err := BuildCertManagerCertificate(synthesizedComp)
+			Expect(err).Should(BeNil())
+			Expect(cert.GroupVersionKind()).Should(Equal(CertManagerCertificateGVK))
+			Expect(cert.GetName()).Should(Equal(GenerateTLSSecretName("bar", "test")))
+
+			secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+			Expect(secretName).Should(Equal(GenerateTLSSecretName("bar", "test")))
+			issuerKind, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")
+			Expect(issuerKind).Should(Equal("ClusterIssuer"))
+			issuerGroup, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "group")
+			Expect(issuerGroup).Should(Equal("cert-manager.io"))
+			duration, _, _ := unstructured.NestedString(cert.Object, "spec", "duration")
+			Expect(duration).Should(Equal("720h0m0s"))
+			_, found, _ := unstructured.NestedString(cert.Object, "spec", "renewBefore")
+			Expect(found).Should(BeFalse())
+
+			dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+			Expect(dnsNames).Should(HaveLen(4))
+			Expect(dnsNames).Should(ContainElements("localhost",
+				HavePrefix(fmt.Sprintf("bar-test-headless.%s.svc", testCtx.DefaultNamespace)),
+				HavePrefix(fmt.Sprintf("bar-test-0.bar-test-headless.%s.svc", testCtx.DefaultNamespace)),
+				HavePrefix(fmt.Sprintf("bar-test-1.bar-test-headless.%s.svc", testCtx.DefaultNamespace))))
+		})
+	})
+
+	Context("ComposeKBAgentCerts function", func() {
+		It("should work well", func() {
+			synthesizedComp := component.SynthesizedComponent{
+				Namespace:   testCtx.DefaultNamespace,
+				ClusterName: "bar",
+				Name:        "test",
+			}
+			certs, err := ComposeKBAgentCerts(synthesizedComp)
+			Expect(err).Should(BeNil())
+			Expect(certs).Should(HaveKey(constant.CAName))
+
+			cert, err := tls.X509KeyPair(certs[constant.CertName], certs[constant.KeyName])
+			Expect(err).Should(BeNil())
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			Expect(err).Should(BeNil())
+			roots := x509.NewCertPool()
+			Expect(roots.AppendCertsFromPEM(certs[constant.CAName])).Should(BeTrue())
+			_, err = leaf.Verify(x509.VerifyOptions{
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			})
+			Expect(err).Should(BeNil())
 		})
 	})
 
@@ -0,0 +1,45 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package controllerutil
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+)
+
+// MaintenanceWindowOpen checks whether the maintenance window is open at the time, and returns the next opening time if not.
+func MaintenanceWindowOpen(window *appsv1.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	if window.Duration.Duration <= 0 {
+		return false, time.Time{}, fmt.Errorf("the duration should be greater than 0")
+	}
+	schedule, err := common.ParseCronExpression(window.CronExpression, window.TimeZone)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	// the window is open if it is started within the duration, otherwise the start is the next opening.
+	start := schedule.Next(now.Add(-window.Duration.Duration))
+	if !start.IsZero() && !start.After(now) {
+		return true, time.Time{}, nil
+	}
+	return false, start, nil
+}
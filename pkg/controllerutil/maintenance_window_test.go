@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package controllerutil
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+)
+
+func TestMaintenanceWindowOpen(t *testing.T) {
+	window := &appsv1.MaintenanceWindow{
+		CronExpression: "0 23 * * sat",
+		Duration:       metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone:       "Asia/Shanghai",
+	}
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextOpening := time.Date(2024, 6, 8, 23, 0, 0, 0, location)
+	// 2024-06-01 is a Saturday
+	for now, expected := range map[time.Time]bool{
+		time.Date(2024, 6, 1, 22, 59, 0, 0, location): false,
+		time.Date(2024, 6, 1, 23, 0, 0, 0, location):  true,
+		time.Date(2024, 6, 2, 0, 59, 0, 0, location):  true,
+		time.Date(2024, 6, 1, 15, 30, 0, 0, time.UTC): true,
+		time.Date(2024, 6, 2, 1, 0, 0, 0, location):   false,
+	} {
+		open, next, err := MaintenanceWindowOpen(window, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if open != expected {
+			t.Errorf("expect the window to be open: %v at %s, but got %v", expected, now, open)
+		}
+		if !open && now.Day() == 2 && !next.Equal(nextOpening) {
+			t.Errorf("expect the next opening at %s, but got %s", nextOpening, next)
+		}
+	}
+
+	window.TimeZone = "Invalid/Zone"
+	if _, _, err = MaintenanceWindowOpen(window, time.Now()); err == nil {
+		t.Error("expect an error for the invalid time zone")
+	}
+
+	window.TimeZone = ""
+	window.Duration = metav1.Duration{}
+	if _, _, err = MaintenanceWindowOpen(window, time.Now()); err == nil {
+		t.Error("expect an error for the zero duration")
+	}
+}
@@ -85,12 +85,16 @@ func (r *RestoreManager) GetBackupActionSetByNamespaced(reqCtx intctrlutil.Reque
 		}
 		return nil, err
 	}
+	return r.newBackupActionSet(reqCtx, cli, backup)
+}
+
+func (r *RestoreManager) newBackupActionSet(reqCtx intctrlutil.RequestCtx, cli client.Client, backup *dpv1alpha1.Backup) (*BackupActionSet, error) {
 	backupMethod := backup.Status.BackupMethod
 	if backupMethod == nil {
-		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`status.backupMethod of backup "%s" is empty`, backupName))
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`status.backupMethod of backup "%s" is empty`, backup.Name))
 	}
 	useVolumeSnapshot := backupMethod.SnapshotVolumes != nil && *backupMethod.SnapshotVolumes
-	actionSet, err := utils.GetActionSetByName(reqCtx, cli, backup.Status.BackupMethod.ActionSetName)
+	actionSet, err := utils.GetActionSetByName(reqCtx, cli, backupMethod.ActionSetName)
 	if err != nil {
 		return nil, err
 	}
@@ -116,6 +120,10 @@ func (r *RestoreManager) BuildIncrementalBackupActionSets(reqCtx intctrlutil.Req
 		if err != nil || backupSet == nil {
 			return err
 		}
+		if backupSet.Backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+			return intctrlutil.NewFatalError(fmt.Sprintf(`phase of backup "%s", the parent of incremental backup "%s", is not completed`,
+				backupSet.Backup.Name, sourceBackupSet.Backup.Name))
+		}
 		return r.BuildIncrementalBackupActionSets(reqCtx, cli, *backupSet)
 	}
 	// if reaches full backup, sort the BackupActionSets and return
@@ -142,8 +150,12 @@ func (r *RestoreManager) BuildIncrementalBackupActionSets(reqCtx intctrlutil.Req
 }
 
 func (r *RestoreManager) BuildContinuousRestoreManager(reqCtx intctrlutil.RequestCtx, cli client.Client, continuousBackupSet BackupActionSet) error {
-	restoreTime, _ := time.Parse(time.RFC3339, r.Restore.Spec.RestoreTime)
 	continuousBackup := continuousBackupSet.Backup
+	restoreTime, err := time.Parse(time.RFC3339, r.Restore.Spec.RestoreTime)
+	if err != nil {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`invalid restore time "%s" for continuous backup "%s": %s`,
+			r.Restore.Spec.RestoreTime, continuousBackup.Name, err.Error()))
+	}
 	checkRestoreTime := func() error {
 		startTime := continuousBackup.GetStartTime()
 		stopTime := continuousBackup.GetEndTime()
@@ -151,7 +163,8 @@ func (r *RestoreManager) BuildContinuousRestoreManager(reqCtx intctrlutil.Reques
 			return intctrlutil.NewFatalError(fmt.Sprintf(`startTimeStamp or completeTimeStamp of backup "%s" is empty`, continuousBackup.Name))
 		}
 		if restoreTime.Before(startTime.Time) || restoreTime.After(stopTime.Time) {
-			return intctrlutil.NewFatalError(fmt.Sprintf(`restore time out of the range for backup "%s"`, continuousBackup.Name))
+			return intctrlutil.NewFatalError(fmt.Sprintf(`restore time "%s" out of the range [%s, %s] for backup "%s"`,
+				restoreTime.UTC().Format(time.RFC3339), startTime.UTC().Format(time.RFC3339), stopTime.UTC().Format(time.RFC3339), continuousBackup.Name))
 		}
 		return nil
 	}
@@ -167,70 +180,118 @@ func (r *RestoreManager) BuildContinuousRestoreManager(reqCtx intctrlutil.Reques
 		}
 	}
 
-	fullBackupSet, err := r.getFullBackupActionSetForContinuous(reqCtx, cli, continuousBackup, metav1.NewTime(restoreTime))
-	if err != nil || fullBackupSet == nil {
+	baseBackupSets, err := r.getBaseBackupActionSetsForContinuous(reqCtx, cli, continuousBackup, restoreTime)
+	if err != nil {
 		return err
 	}
-	// set base backup
-	continuousBackupSet.BaseBackup = fullBackupSet.Backup
-	r.SetBackupSets(*fullBackupSet, continuousBackupSet)
+	// set base backup, the continuous backup is replayed since the last one stops.
+	continuousBackupSet.BaseBackup = baseBackupSets[len(baseBackupSets)-1].Backup
+	r.SetBackupSets(append(baseBackupSets, continuousBackupSet)...)
 	return nil
 }
 
-// getFullBackupActionSetForContinuous gets full backup and actionSet for continuous.
-func (r *RestoreManager) getFullBackupActionSetForContinuous(reqCtx intctrlutil.RequestCtx, cli client.Client, continuousBackup *dpv1alpha1.Backup, restoreTime metav1.Time) (*BackupActionSet, error) {
-	notFoundLatestFullBackup := func() (*BackupActionSet, error) {
-		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`can not found latest full backup based on backupPolicy "%s" and specified restoreTime "%s"`,
-			continuousBackup.Spec.BackupPolicyName, restoreTime))
+// getBaseBackupActionSetsForContinuous gets the base backups and actionSets for continuous, including the latest full
+// backup before the restore time and the incremental backups based on it.
+func (r *RestoreManager) getBaseBackupActionSetsForContinuous(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	continuousBackup *dpv1alpha1.Backup,
+	restoreTime time.Time) ([]BackupActionSet, error) {
+	fullBackups, err := r.listCompletedBackups(reqCtx, cli, continuousBackup, dpv1alpha1.BackupTypeFull)
+	if err != nil {
+		return nil, err
 	}
-	if continuousBackup.GetStartTime().IsZero() {
-		return notFoundLatestFullBackup()
+	incrementalBackups, err := r.listCompletedBackups(reqCtx, cli, continuousBackup, dpv1alpha1.BackupTypeIncremental)
+	if err != nil {
+		return nil, err
 	}
-	// 1. list completed full backups
-	backupItems, err := r.listCompletedFullBackups(reqCtx, cli, continuousBackup)
+	chain, err := resolveBaseBackupChain(fullBackups, incrementalBackups, continuousBackup, restoreTime)
 	if err != nil {
 		return nil, err
 	}
+	backupSets := make([]BackupActionSet, 0, len(chain))
+	for i := range chain {
+		backupSet, err := r.newBackupActionSet(reqCtx, cli, chain[i])
+		if err != nil {
+			return nil, err
+		}
+		backupSets = append(backupSets, *backupSet)
+	}
+	return backupSets, nil
+}
 
-	// sort by completed time in descending order
-	sort.Slice(backupItems, func(i, j int) bool {
-		i, j = j, i
-		return CompareWithBackupStopTime(backupItems[i], backupItems[j])
-	})
-
-	// 2. get the latest backup object
-	var latestFullBackup *dpv1alpha1.Backup
-	for _, item := range backupItems {
-		fullBackupStopTime := item.GetEndTime()
-		// latest full backup rules:
-		// 1. Full backup's stopTime must after Continuous backup's startTime.
-		//    Even if the seconds are the same, the data may not be continuous.
-		// 2. RestoreTime should after the Full backup's stopTime.
-		if fullBackupStopTime != nil &&
-			!restoreTime.Before(fullBackupStopTime) &&
-			!fullBackupStopTime.Before(continuousBackup.GetStartTime()) {
-			latestFullBackup = &item
-			break
+// resolveBaseBackupChain resolves the base backups to restore before replaying the continuous backup to the restore time.
+// It picks the latest full backup that stops before the restore time, then walks down the incremental backups whose
+// parent is the one picked, and verifies that the continuous backup covers the time from the full backup to the restore time.
+func resolveBaseBackupChain(fullBackups, incrementalBackups []dpv1alpha1.Backup,
+	continuousBackup *dpv1alpha1.Backup,
+	restoreTime time.Time) ([]*dpv1alpha1.Backup, error) {
+	stoppedBefore := func(backup *dpv1alpha1.Backup) bool {
+		stopTime := backup.GetEndTime()
+		return stopTime != nil && !stopTime.IsZero() && !restoreTime.Before(stopTime.Time)
+	}
+	formatTime := func(t *metav1.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	// 1. get the latest full backup stopped before the restore time
+	var fullBackup *dpv1alpha1.Backup
+	for i := range fullBackups {
+		if !stoppedBefore(&fullBackups[i]) {
+			continue
+		}
+		if fullBackup == nil || CompareWithBackupStopTime(*fullBackup, fullBackups[i]) {
+			fullBackup = &fullBackups[i]
 		}
 	}
-	if latestFullBackup == nil {
-		return notFoundLatestFullBackup()
+	if fullBackup == nil {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`can not found latest full backup based on backupPolicy "%s" and specified restoreTime "%s"`,
+			continuousBackup.Spec.BackupPolicyName, restoreTime.UTC().Format(time.RFC3339)))
 	}
-	// 3. get the action set
-	var actionSetName string
-	if latestFullBackup.Status.BackupMethod != nil {
-		actionSetName = latestFullBackup.Status.BackupMethod.ActionSetName
+
+	// 2. the continuous backup should cover the time since the full backup stops, even if the seconds are the same,
+	//    the data may not be continuous.
+	continuousStartTime := continuousBackup.GetStartTime()
+	if continuousStartTime == nil || continuousStartTime.IsZero() {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`start time of continuous backup "%s" is empty`, continuousBackup.Name))
 	}
-	actionSet, err := utils.GetActionSetByName(reqCtx, cli, actionSetName)
-	if err != nil {
-		return nil, err
+	if fullBackup.GetEndTime().Before(continuousStartTime) {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`gap between %s and %s: the latest full backup "%s" stops before the continuous backup "%s" starts`,
+			formatTime(fullBackup.GetEndTime()), formatTime(continuousStartTime), fullBackup.Name, continuousBackup.Name))
+	}
+
+	// 3. walk down the incremental backups based on the full backup, to shorten the time to replay
+	children := map[string][]*dpv1alpha1.Backup{}
+	for i := range incrementalBackups {
+		parent := incrementalBackups[i].Spec.ParentBackupName
+		children[parent] = append(children[parent], &incrementalBackups[i])
 	}
-	return &BackupActionSet{Backup: latestFullBackup, ActionSet: actionSet}, nil
+	chain := []*dpv1alpha1.Backup{fullBackup}
+	visited := map[string]bool{fullBackup.Name: true}
+	for {
+		var next *dpv1alpha1.Backup
+		for _, child := range children[chain[len(chain)-1].Name] {
+			if visited[child.Name] || !stoppedBefore(child) {
+				continue
+			}
+			if next == nil || CompareWithBackupStopTime(*next, *child) {
+				next = child
+			}
+		}
+		if next == nil {
+			break
+		}
+		visited[next.Name] = true
+		chain = append(chain, next)
+	}
+	return chain, nil
 }
 
-func (r *RestoreManager) listCompletedFullBackups(reqCtx intctrlutil.RequestCtx, cli client.Client, continuousBackup *dpv1alpha1.Backup) ([]dpv1alpha1.Backup, error) {
+func (r *RestoreManager) listCompletedBackups(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	continuousBackup *dpv1alpha1.Backup,
+	backupType dpv1alpha1.BackupType) ([]dpv1alpha1.Backup, error) {
 	matchingLabels := map[string]string{
-		dptypes.BackupTypeLabelKey: string(dpv1alpha1.BackupTypeFull),
+		dptypes.BackupTypeLabelKey: string(backupType),
 	}
 	if clusterUID := continuousBackup.Labels[dptypes.ClusterUIDLabelKey]; clusterUID != "" {
 		matchingLabels[dptypes.ClusterUIDLabelKey] = clusterUID
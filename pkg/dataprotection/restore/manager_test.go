@@ -360,4 +360,64 @@ var _ = Describe("Backup Deleter Test", func() {
 		})
 	})
 
+	Context("resolve the base backups for continuous backup", func() {
+		baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		at := func(hours int) *metav1.Time {
+			t := metav1.NewTime(baseTime.Add(time.Duration(hours) * time.Hour))
+			return &t
+		}
+		newBackup := func(name, parent string, start, stop int) dpv1alpha1.Backup {
+			return dpv1alpha1.Backup{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       dpv1alpha1.BackupSpec{ParentBackupName: parent, BackupPolicyName: "policy"},
+				Status: dpv1alpha1.BackupStatus{
+					Phase:     dpv1alpha1.BackupPhaseCompleted,
+					TimeRange: &dpv1alpha1.BackupTimeRange{Start: at(start), End: at(stop)},
+				},
+			}
+		}
+		names := func(backups []*dpv1alpha1.Backup) []string {
+			l := make([]string, 0)
+			for _, b := range backups {
+				l = append(l, b.Name)
+			}
+			return l
+		}
+		continuous := newBackup("continuous", "", 0, 100)
+
+		It("picks the latest full backup before the restore time and walks down the incremental backups", func() {
+			fulls := []dpv1alpha1.Backup{
+				newBackup("full-1", "", 1, 2),
+				newBackup("full-2", "", 10, 11),
+				newBackup("full-3", "", 50, 51),
+			}
+			incrementals := []dpv1alpha1.Backup{
+				newBackup("inc-1", "full-2", 20, 21),
+				newBackup("inc-2", "inc-1", 30, 31),
+				newBackup("inc-3", "inc-1", 32, 33),
+				newBackup("inc-4", "inc-3", 45, 46),
+				newBackup("inc-5", "full-1", 35, 36),
+			}
+			chain, err := resolveBaseBackupChain(fulls, incrementals, &continuous, at(40).Time)
+			Expect(err).Should(Succeed())
+			Expect(names(chain)).Should(Equal([]string{"full-2", "inc-1", "inc-3"}))
+		})
+
+		It("fails if there is no full backup before the restore time", func() {
+			fulls := []dpv1alpha1.Backup{newBackup("full-1", "", 50, 51)}
+			_, err := resolveBaseBackupChain(fulls, nil, &continuous, at(40).Time)
+			Expect(err).Should(HaveOccurred())
+			Expect(intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal)).Should(BeTrue())
+		})
+
+		It("fails with the gap between the full backup and the continuous backup", func() {
+			continuous := newBackup("continuous", "", 20, 100)
+			fulls := []dpv1alpha1.Backup{newBackup("full-1", "", 10, 11)}
+			_, err := resolveBaseBackupChain(fulls, nil, &continuous, at(40).Time)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(fmt.Sprintf("gap between %s and %s",
+				at(11).UTC().Format(time.RFC3339), at(20).UTC().Format(time.RFC3339))))
+		})
+	})
+
 })
@@ -141,6 +141,7 @@ const (
 const (
 	BackupKind             = "Backup"
 	RestoreKind            = "Restore"
+	BackupScheduleKind     = "BackupSchedule"
 	DataprotectionAPIGroup = "dataprotection.kubeblocks.io"
 	KopiaRepoFolderName    = "kopia"
 )
@@ -94,6 +94,8 @@ var ComponentVersionSignature = func(appsv1.ComponentVersion, *appsv1.ComponentV
 }
 var SidecarDefinitionSignature = func(appsv1.SidecarDefinition, *appsv1.SidecarDefinition, appsv1.SidecarDefinitionList, *appsv1.SidecarDefinitionList) {
 }
+var ClusterDisasterRecoverySignature = func(appsv1.ClusterDisasterRecovery, *appsv1.ClusterDisasterRecovery, appsv1.ClusterDisasterRecoveryList, *appsv1.ClusterDisasterRecoveryList) {
+}
 var ClusterSignature = func(_ appsv1.Cluster, _ *appsv1.Cluster, _ appsv1.ClusterList, _ *appsv1.ClusterList) {
 }
 var ComponentSignature = func(appsv1.Component, *appsv1.Component, appsv1.ComponentList, *appsv1.ComponentList) {
@@ -104,6 +106,12 @@ var OpsDefinitionSignature = func(_ opsv1alpha1.OpsDefinition, _ *opsv1alpha1.Op
 }
 var OpsRequestSignature = func(_ opsv1alpha1.OpsRequest, _ *opsv1alpha1.OpsRequest, _ opsv1alpha1.OpsRequestList, _ *opsv1alpha1.OpsRequestList) {
 }
+var OpsApprovalPolicySignature = func(_ opsv1alpha1.OpsApprovalPolicy, _ *opsv1alpha1.OpsApprovalPolicy, _ opsv1alpha1.OpsApprovalPolicyList, _ *opsv1alpha1.OpsApprovalPolicyList) {
+}
+var ScheduledOpsRequestSignature = func(_ opsv1alpha1.ScheduledOpsRequest, _ *opsv1alpha1.ScheduledOpsRequest, _ opsv1alpha1.ScheduledOpsRequestList, _ *opsv1alpha1.ScheduledOpsRequestList) {
+}
+var OpsPipelineSignature = func(_ opsv1alpha1.OpsPipeline, _ *opsv1alpha1.OpsPipeline, _ opsv1alpha1.OpsPipelineList, _ *opsv1alpha1.OpsPipelineList) {
+}
 var ConfigConstraintSignature = func(_ appsv1beta1.ConfigConstraint, _ *appsv1beta1.ConfigConstraint, _ appsv1beta1.ConfigConstraintList, _ *appsv1beta1.ConfigConstraintList) {
 }
 var ConfigurationSignature = func(_ appsv1alpha1.Configuration, _ *appsv1alpha1.Configuration, _ appsv1alpha1.ConfigurationList, _ *appsv1alpha1.ConfigurationList) {
@@ -21,6 +21,7 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"time"
@@ -36,6 +37,14 @@ type Client interface {
 	Action(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error)
 }
 
+// Credentials are presented by the client to authenticate itself to the kb-agent.
+type Credentials struct {
+	// Token is the bearer token presented in each request.
+	Token string
+	// TLSConfig enables TLS to connect to the kb-agent, with the client certificate.
+	TLSConfig *tls.Config
+}
+
 // HACK: for unit test only.
 var mockClient Client
 var mockClientError error
@@ -54,7 +63,7 @@ func GetMockClient() Client {
 	return mockClient
 }
 
-func NewClient(endpoint func() (string, int32, error)) (Client, error) {
+func NewClient(endpoint func() (string, int32, error), credentials *Credentials) (Client, error) {
 	if mockClient != nil || mockClientError != nil {
 		return mockClient, mockClientError
 	}
@@ -75,14 +84,41 @@ func NewClient(endpoint func() (string, int32, error)) (Client, error) {
 		Dial:                dialer.Dial,
 		TLSHandshakeTimeout: defaultConnectTimeout,
 	}
+	scheme := "http"
+	if credentials != nil && credentials.TLSConfig != nil {
+		transport.TLSClientConfig = credentials.TLSConfig
+		scheme = "https"
+	}
 	cli := &http.Client{
 		// don't set timeout at client level
 		// Timeout:   time.Second * 30,
 		Transport: transport,
 	}
 	return &httpClient{
-		host:   host,
-		port:   port,
-		client: cli,
+		scheme:      scheme,
+		host:        host,
+		port:        port,
+		client:      cli,
+		credentials: credentials,
+	}, nil
+}
+
+func NewGRPCClient(endpoint func() (string, int32, error), credentials *Credentials) (Client, error) {
+	if mockClient != nil || mockClientError != nil {
+		return mockClient, mockClientError
+	}
+
+	host, port, err := endpoint()
+	if err != nil {
+		return nil, err
+	}
+	if host == "" && port == 0 {
+		return nil, nil
+	}
+
+	return &grpcClient{
+		host:        host,
+		port:        port,
+		credentials: credentials,
 	}, nil
 }
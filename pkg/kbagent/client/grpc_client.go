@@ -0,0 +1,201 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	grpccredentials "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto/pb"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
+)
+
+const (
+	// grpcConnIdleTimeout is how long an unused connection is kept in the pool before being closed.
+	grpcConnIdleTimeout = 10 * time.Minute
+)
+
+// the clients are created per call, so the connections to the kb-agents are pooled and shared by them.
+var grpcConns = &grpcConnPool{conns: map[string]*pooledGRPCConn{}}
+
+type grpcClient struct {
+	host        string
+	port        int32
+	credentials *Credentials
+}
+
+var _ Client = &grpcClient{}
+
+func (c *grpcClient) Action(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+	rsp := proto.ActionResponse{}
+	conn, err := grpcConns.get(net.JoinHostPort(c.host, strconv.Itoa(int(c.port))), c.credentials)
+	if err != nil {
+		return rsp, err
+	}
+
+	out, err := pb.NewKBAgentClient(conn).Action(ctx, actionRequest(req))
+	if err != nil {
+		return c.error(rsp, err)
+	}
+	rsp.Output = out.Output
+	return rsp, nil
+}
+
+type pooledGRPCConn struct {
+	conn *grpc.ClientConn
+	// fingerprint of the credentials the connection is created with
+	fingerprint string
+	lastUsed    time.Time
+}
+
+type grpcConnPool struct {
+	mutex sync.Mutex
+	conns map[string]*pooledGRPCConn
+}
+
+// get returns the connection to the address, a new connection is created if there is no one or
+// the credentials have changed. The connections idle for a while are closed, since the pods may have gone.
+func (p *grpcConnPool) get(addr string, credentials *Credentials) (*grpc.ClientConn, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	for key, pc := range p.conns {
+		if key != addr && now.Sub(pc.lastUsed) > grpcConnIdleTimeout {
+			_ = pc.conn.Close()
+			delete(p.conns, key)
+		}
+	}
+
+	fingerprint := credentialsFingerprint(credentials)
+	if pc, ok := p.conns[addr]; ok {
+		if pc.fingerprint == fingerprint {
+			pc.lastUsed = now
+			return pc.conn, nil
+		}
+		_ = pc.conn.Close()
+		delete(p.conns, addr)
+	}
+
+	conn, err := newGRPCConn(addr, credentials)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[addr] = &pooledGRPCConn{conn: conn, fingerprint: fingerprint, lastUsed: now}
+	return conn, nil
+}
+
+func newGRPCConn(addr string, credentials *Credentials) (*grpc.ClientConn, error) {
+	transportCredentials := insecure.NewCredentials()
+	if credentials != nil && credentials.TLSConfig != nil {
+		transportCredentials = grpccredentials.NewTLS(credentials.TLSConfig)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: defaultConnectTimeout,
+		}),
+	}
+	if credentials != nil && len(credentials.Token) > 0 {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials(credentials.Token)))
+	}
+	return grpc.NewClient(addr, opts...)
+}
+
+// credentialsFingerprint identifies the credentials by the token and client certificate.
+func credentialsFingerprint(credentials *Credentials) string {
+	if credentials == nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(credentials.Token))
+	if credentials.TLSConfig != nil {
+		h.Write([]byte("tls"))
+		for _, cert := range credentials.TLSConfig.Certificates {
+			for _, der := range cert.Certificate {
+				h.Write(der)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// tokenCredentials presents the bearer token in the metadata of each call.
+type tokenCredentials string
+
+var _ grpccredentials.PerRPCCredentials = tokenCredentials("")
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{util.AuthorizationMetadataKey: util.BearerToken(string(t))}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// error returns the errors of kb-agent in the response as the HTTP client does, and the transport errors as is.
+func (c *grpcClient) error(rsp proto.ActionResponse, err error) (proto.ActionResponse, error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return rsp, err
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == proto.ErrorDomain {
+			rsp.Error = info.Reason
+			rsp.Message = st.Message()
+			return rsp, nil
+		}
+	}
+	return rsp, err
+}
+
+func actionRequest(req proto.ActionRequest) *pb.ActionRequest {
+	r := &pb.ActionRequest{
+		Action:     req.Action,
+		Parameters: req.Parameters,
+	}
+	if req.NonBlocking != nil {
+		r.NonBlocking = *req.NonBlocking
+	}
+	if req.TimeoutSeconds != nil {
+		r.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.RetryPolicy != nil {
+		r.RetryPolicy = &pb.RetryPolicy{
+			MaxRetries:                int32(req.RetryPolicy.MaxRetries),
+			RetryIntervalMilliseconds: req.RetryPolicy.RetryInterval.Milliseconds(),
+		}
+	}
+	return r
+}
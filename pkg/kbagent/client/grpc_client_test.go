@@ -0,0 +1,193 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package client
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto/pb"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
+)
+
+type mockKBAgentServer struct {
+	pb.UnimplementedKBAgentServer
+
+	authorization string
+}
+
+func (s *mockKBAgentServer) Action(ctx context.Context, req *pb.ActionRequest) (*pb.ActionResponse, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(util.AuthorizationMetadataKey); len(values) > 0 {
+			s.authorization = values[0]
+		}
+	}
+	switch req.Action {
+	case "echo":
+		return &pb.ActionResponse{Output: []byte(req.Parameters["message"])}, nil
+	case "fail":
+		st, _ := status.New(codes.Internal, "action failed").
+			WithDetails(&errdetails.ErrorInfo{Reason: proto.Error2Type(proto.ErrFailed), Domain: proto.ErrorDomain})
+		return nil, st.Err()
+	default:
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+}
+
+var _ = Describe("gRPC client", func() {
+	var (
+		agent  *mockKBAgentServer
+		server *grpc.Server
+		host   string
+		port   int32
+	)
+
+	endpoint := func() (string, int32, error) {
+		return host, port, nil
+	}
+
+	BeforeEach(func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).Should(BeNil())
+		host, port = "127.0.0.1", int32(listener.Addr().(*net.TCPAddr).Port)
+
+		agent = &mockKBAgentServer{}
+		server = grpc.NewServer()
+		pb.RegisterKBAgentServer(server, agent)
+		go func() {
+			defer GinkgoRecover()
+			_ = server.Serve(listener)
+		}()
+	})
+
+	AfterEach(func() {
+		server.Stop()
+	})
+
+	Context("action", func() {
+		It("ok", func() {
+			cli, err := NewGRPCClient(endpoint, nil)
+			Expect(err).Should(BeNil())
+			rsp, err := cli.Action(ctx, proto.ActionRequest{Action: "echo", Parameters: map[string]string{"message": "hello"}})
+			Expect(err).Should(BeNil())
+			Expect(rsp.Error).Should(BeEmpty())
+			Expect(rsp.Output).Should(Equal([]byte("hello")))
+		})
+
+		It("kb-agent error", func() {
+			cli, err := NewGRPCClient(endpoint, nil)
+			Expect(err).Should(BeNil())
+			rsp, err := cli.Action(ctx, proto.ActionRequest{Action: "fail"})
+			Expect(err).Should(BeNil())
+			Expect(rsp.Error).Should(Equal(proto.Error2Type(proto.ErrFailed)))
+			Expect(rsp.Message).Should(Equal("action failed"))
+		})
+
+		It("transport error", func() {
+			cli, err := NewGRPCClient(endpoint, nil)
+			Expect(err).Should(BeNil())
+			_, err = cli.Action(ctx, proto.ActionRequest{Action: "unknown"})
+			Expect(err).ShouldNot(BeNil())
+			Expect(status.Code(err)).Should(Equal(codes.Unimplemented))
+
+			server.Stop()
+			_, err = cli.Action(ctx, proto.ActionRequest{Action: "echo"})
+			Expect(err).ShouldNot(BeNil())
+			Expect(status.Code(err)).Should(Equal(codes.Unavailable))
+		})
+
+		It("token", func() {
+			cli, err := NewGRPCClient(endpoint, &Credentials{Token: "test-token"})
+			Expect(err).Should(BeNil())
+			_, err = cli.Action(ctx, proto.ActionRequest{Action: "echo"})
+			Expect(err).Should(BeNil())
+			Expect(agent.authorization).Should(Equal(util.BearerToken("test-token")))
+		})
+
+		It("no endpoint", func() {
+			cli, err := NewGRPCClient(func() (string, int32, error) { return "", 0, nil }, nil)
+			Expect(err).Should(BeNil())
+			Expect(cli).Should(BeNil())
+		})
+	})
+
+	Context("connection", func() {
+		addr := func() string {
+			return net.JoinHostPort(host, strconv.Itoa(int(port)))
+		}
+
+		It("reuse", func() {
+			for i := 0; i < 2; i++ {
+				cli, err := NewGRPCClient(endpoint, &Credentials{Token: "test-token"})
+				Expect(err).Should(BeNil())
+				_, err = cli.Action(ctx, proto.ActionRequest{Action: "echo"})
+				Expect(err).Should(BeNil())
+			}
+			conn := grpcConns.conns[addr()].conn
+
+			cli, err := NewGRPCClient(endpoint, &Credentials{Token: "test-token"})
+			Expect(err).Should(BeNil())
+			_, err = cli.Action(ctx, proto.ActionRequest{Action: "echo"})
+			Expect(err).Should(BeNil())
+			Expect(grpcConns.conns[addr()].conn).Should(BeIdenticalTo(conn))
+		})
+
+		It("credentials changed", func() {
+			cli, err := NewGRPCClient(endpoint, &Credentials{Token: "test-token"})
+			Expect(err).Should(BeNil())
+			_, err = cli.Action(ctx, proto.ActionRequest{Action: "echo"})
+			Expect(err).Should(BeNil())
+			conn := grpcConns.conns[addr()].conn
+
+			cli, err = NewGRPCClient(endpoint, &Credentials{Token: "test-token-rotated"})
+			Expect(err).Should(BeNil())
+			_, err = cli.Action(ctx, proto.ActionRequest{Action: "echo"})
+			Expect(err).Should(BeNil())
+			Expect(grpcConns.conns[addr()].conn).ShouldNot(BeIdenticalTo(conn))
+			Expect(agent.authorization).Should(Equal(util.BearerToken("test-token-rotated")))
+		})
+
+		It("idle", func() {
+			cli, err := NewGRPCClient(endpoint, nil)
+			Expect(err).Should(BeNil())
+			_, err = cli.Action(ctx, proto.ActionRequest{Action: "echo"})
+			Expect(err).Should(BeNil())
+			idleAddr := addr()
+			grpcConns.conns[idleAddr].lastUsed = time.Now().Add(-grpcConnIdleTimeout - time.Second)
+
+			_, err = grpcConns.get("127.0.0.1:1", nil)
+			Expect(err).Should(BeNil())
+			Expect(grpcConns.conns).ShouldNot(HaveKey(idleAddr))
+			Expect(grpcConns.conns).Should(HaveKey("127.0.0.1:1"))
+		})
+	})
+})
@@ -25,19 +25,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
 )
 
 const (
-	urlTemplate = "http://%s:%d%s"
+	urlTemplate = "%s://%s%s"
 )
 
 type httpClient struct {
-	host   string
-	port   int32
-	client *http.Client
+	scheme      string
+	host        string
+	port        int32
+	client      *http.Client
+	credentials *Credentials
 }
 
 var _ Client = &httpClient{}
@@ -50,7 +55,7 @@ func (c *httpClient) Action(ctx context.Context, req proto.ActionRequest) (proto
 		return rsp, err
 	}
 
-	url := fmt.Sprintf(urlTemplate, c.host, c.port, proto.ServiceAction.URI)
+	url := fmt.Sprintf(urlTemplate, c.scheme, net.JoinHostPort(c.host, strconv.Itoa(int(c.port))), proto.ServiceAction.URI)
 	payload, err := c.request(ctx, http.MethodPost, url, bytes.NewReader(data))
 	if err != nil {
 		return rsp, err
@@ -65,6 +70,9 @@ func (c *httpClient) request(ctx context.Context, method, url string, body io.Re
 	if err != nil {
 		return nil, err
 	}
+	if c.credentials != nil && len(c.credentials.Token) > 0 {
+		req.Header.Set(util.AuthorizationHeader, util.BearerToken(c.credentials.Token))
+	}
 
 	rsp, err := c.client.Do(req)
 	if err != nil {
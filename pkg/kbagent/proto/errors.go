@@ -21,8 +21,13 @@ package proto
 
 import (
 	"errors"
+
+	"google.golang.org/grpc/codes"
 )
 
+// ErrorDomain is the domain of the error details carried by the gRPC status.
+const ErrorDomain = "kbagent.kubeblocks.io"
+
 var (
 	ErrNotDefined     = errors.New("notDefined")
 	ErrNotImplemented = errors.New("notImplemented")
@@ -84,3 +89,29 @@ func Type2Error(errType string) error {
 		return ErrUnknown
 	}
 }
+
+// Error2Code maps the error to the status code of the gRPC API.
+func Error2Code(err error) codes.Code {
+	switch {
+	case err == nil:
+		return codes.OK
+	case errors.Is(err, ErrNotDefined):
+		return codes.NotFound
+	case errors.Is(err, ErrNotImplemented):
+		return codes.Unimplemented
+	case errors.Is(err, ErrBadRequest):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrInProgress):
+		return codes.Unavailable
+	case errors.Is(err, ErrBusy):
+		return codes.ResourceExhausted
+	case errors.Is(err, ErrTimedOut):
+		return codes.DeadlineExceeded
+	case errors.Is(err, ErrFailed):
+		return codes.Aborted
+	case errors.Is(err, ErrInternalError):
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,268 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.9
+// source: kbagent.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// KBAgentClient is the client API for KBAgent service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KBAgentClient interface {
+	Action(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*ActionResponse, error)
+	StreamAction(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (KBAgent_StreamActionClient, error)
+	Probe(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (*ProbeResponse, error)
+	Task(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (KBAgent_TaskClient, error)
+}
+
+type kBAgentClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKBAgentClient(cc grpc.ClientConnInterface) KBAgentClient {
+	return &kBAgentClient{cc}
+}
+
+func (c *kBAgentClient) Action(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
+	out := new(ActionResponse)
+	err := c.cc.Invoke(ctx, "/kbagent.KBAgent/Action", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kBAgentClient) StreamAction(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (KBAgent_StreamActionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &KBAgent_ServiceDesc.Streams[0], "/kbagent.KBAgent/StreamAction", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kBAgentStreamActionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type KBAgent_StreamActionClient interface {
+	Recv() (*ActionOutput, error)
+	grpc.ClientStream
+}
+
+type kBAgentStreamActionClient struct {
+	grpc.ClientStream
+}
+
+func (x *kBAgentStreamActionClient) Recv() (*ActionOutput, error) {
+	m := new(ActionOutput)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *kBAgentClient) Probe(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (*ProbeResponse, error) {
+	out := new(ProbeResponse)
+	err := c.cc.Invoke(ctx, "/kbagent.KBAgent/Probe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kBAgentClient) Task(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (KBAgent_TaskClient, error) {
+	stream, err := c.cc.NewStream(ctx, &KBAgent_ServiceDesc.Streams[1], "/kbagent.KBAgent/Task", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kBAgentTaskClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type KBAgent_TaskClient interface {
+	Recv() (*TaskEvent, error)
+	grpc.ClientStream
+}
+
+type kBAgentTaskClient struct {
+	grpc.ClientStream
+}
+
+func (x *kBAgentTaskClient) Recv() (*TaskEvent, error) {
+	m := new(TaskEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// KBAgentServer is the server API for KBAgent service.
+// All implementations must embed UnimplementedKBAgentServer
+// for forward compatibility
+type KBAgentServer interface {
+	Action(context.Context, *ActionRequest) (*ActionResponse, error)
+	StreamAction(*ActionRequest, KBAgent_StreamActionServer) error
+	Probe(context.Context, *ProbeRequest) (*ProbeResponse, error)
+	Task(*TaskRequest, KBAgent_TaskServer) error
+	mustEmbedUnimplementedKBAgentServer()
+}
+
+// UnimplementedKBAgentServer must be embedded to have forward compatible implementations.
+type UnimplementedKBAgentServer struct {
+}
+
+func (UnimplementedKBAgentServer) Action(context.Context, *ActionRequest) (*ActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Action not implemented")
+}
+func (UnimplementedKBAgentServer) StreamAction(*ActionRequest, KBAgent_StreamActionServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAction not implemented")
+}
+func (UnimplementedKBAgentServer) Probe(context.Context, *ProbeRequest) (*ProbeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Probe not implemented")
+}
+func (UnimplementedKBAgentServer) Task(*TaskRequest, KBAgent_TaskServer) error {
+	return status.Errorf(codes.Unimplemented, "method Task not implemented")
+}
+func (UnimplementedKBAgentServer) mustEmbedUnimplementedKBAgentServer() {}
+
+// UnsafeKBAgentServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KBAgentServer will
+// result in compilation errors.
+type UnsafeKBAgentServer interface {
+	mustEmbedUnimplementedKBAgentServer()
+}
+
+func RegisterKBAgentServer(s grpc.ServiceRegistrar, srv KBAgentServer) {
+	s.RegisterService(&KBAgent_ServiceDesc, srv)
+}
+
+func _KBAgent_Action_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KBAgentServer).Action(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kbagent.KBAgent/Action",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KBAgentServer).Action(ctx, req.(*ActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KBAgent_StreamAction_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ActionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KBAgentServer).StreamAction(m, &kBAgentStreamActionServer{stream})
+}
+
+type KBAgent_StreamActionServer interface {
+	Send(*ActionOutput) error
+	grpc.ServerStream
+}
+
+type kBAgentStreamActionServer struct {
+	grpc.ServerStream
+}
+
+func (x *kBAgentStreamActionServer) Send(m *ActionOutput) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _KBAgent_Probe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KBAgentServer).Probe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kbagent.KBAgent/Probe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KBAgentServer).Probe(ctx, req.(*ProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KBAgent_Task_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TaskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KBAgentServer).Task(m, &kBAgentTaskServer{stream})
+}
+
+type KBAgent_TaskServer interface {
+	Send(*TaskEvent) error
+	grpc.ServerStream
+}
+
+type kBAgentTaskServer struct {
+	grpc.ServerStream
+}
+
+func (x *kBAgentTaskServer) Send(m *TaskEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// KBAgent_ServiceDesc is the grpc.ServiceDesc for KBAgent service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KBAgent_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kbagent.KBAgent",
+	HandlerType: (*KBAgentServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Action",
+			Handler:    _KBAgent_Action_Handler,
+		},
+		{
+			MethodName: "Probe",
+			Handler:    _KBAgent_Probe_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAction",
+			Handler:       _KBAgent_StreamAction_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Task",
+			Handler:       _KBAgent_Task_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "kbagent.proto",
+}
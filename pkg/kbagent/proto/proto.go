@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package proto
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -70,6 +71,25 @@ type Probe struct {
 	SuccessThreshold    int32  `json:"successThreshold,omitempty"`
 	FailureThreshold    int32  `json:"failureThreshold,omitempty"`
 	ReportPeriodSeconds int32  `json:"reportPeriodSeconds,omitempty"`
+	// the volumes to measure the usage, the probe is built-in and has no action to run if specified
+	Volumes []VolumeWatermark `json:"volumes,omitempty"`
+}
+
+type VolumeWatermark struct {
+	Name          string `json:"name"`
+	MountPath     string `json:"mountPath"`
+	HighWatermark int    `json:"highWatermark"`
+}
+
+type VolumeUsage struct {
+	Name          string `json:"name"`
+	TotalBytes    int64  `json:"totalBytes"`
+	Usage         int    `json:"usage"` // the percentage of the used space
+	HighWatermark int    `json:"highWatermark"`
+}
+
+func (u VolumeUsage) Exceeded() bool {
+	return u.HighWatermark > 0 && u.Usage >= u.HighWatermark
 }
 
 type ProbeEvent struct {
@@ -108,4 +128,39 @@ type NewReplicaTask struct {
 	Replicas       string            `json:"replicas"`             // replicas to load the data
 	Parameters     map[string]string `json:"parameters,omitempty"` // parameters for data dump and load
 	TimeoutSeconds *int32            `json:"timeoutSeconds,omitempty"`
+	Compression    string            `json:"compression,omitempty"` // the compression of the data transferred, "zstd" or none
+}
+
+type NewReplicaProgress struct {
+	TransferredBytes int64  `json:"transferredBytes"`
+	ExpectedBytes    int64  `json:"expectedBytes,omitempty"`    // the expected size reported by the dataDump action
+	BytesPerSecond   int64  `json:"bytesPerSecond,omitempty"`   // the average throughput since the transfer started
+	RemainingSeconds *int64 `json:"remainingSeconds,omitempty"` // the estimated time to finish the transfer
+}
+
+func (p NewReplicaProgress) String() string {
+	msg := fmt.Sprintf("transferred %s", formatBytes(p.TransferredBytes))
+	if p.ExpectedBytes > 0 {
+		msg += fmt.Sprintf(" of %s (%d%%)", formatBytes(p.ExpectedBytes), min(p.TransferredBytes*100/p.ExpectedBytes, 100))
+	}
+	if p.BytesPerSecond > 0 {
+		msg += fmt.Sprintf(", %s/s", formatBytes(p.BytesPerSecond))
+	}
+	if p.RemainingSeconds != nil {
+		msg += fmt.Sprintf(", ETA %s", time.Duration(*p.RemainingSeconds)*time.Second)
+	}
+	return msg
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
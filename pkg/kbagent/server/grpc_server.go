@@ -0,0 +1,305 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto/pb"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
+)
+
+type grpcServer struct {
+	pb.UnimplementedKBAgentServer
+
+	logger   logr.Logger
+	config   Config
+	services []service.Service
+	server   *grpc.Server
+}
+
+var _ Server = &grpcServer{}
+
+func (s *grpcServer) StartNonBlocking() error {
+	s.logger.Info("starting the gRPC server")
+
+	tlsConfig, err := tlsConfig(s.config)
+	if err != nil {
+		return errors.Wrap(err, "load TLS config error")
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%v", s.config.Address, s.config.GRPCPort))
+	if err != nil {
+		s.logger.Error(err, "listen gRPC server error", "address", s.config.Address, "port", s.config.GRPCPort)
+		return err
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.logUnary, s.authUnary),
+		grpc.ChainStreamInterceptor(s.logStream, s.authStream),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.server = grpc.NewServer(opts...)
+	pb.RegisterKBAgentServer(s.server, s)
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			panic(err)
+		}
+	}()
+	return nil
+}
+
+func (s *grpcServer) Close() error {
+	if s.server != nil {
+		s.server.GracefulStop()
+	}
+	return nil
+}
+
+func (s *grpcServer) Action(ctx context.Context, req *pb.ActionRequest) (*pb.ActionResponse, error) {
+	handler, ok := s.service(proto.ServiceAction.Kind).(service.ActionHandler)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "has no action service defined")
+	}
+	output, err := handler.HandleAction(ctx, actionRequest(req))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.ActionResponse{Output: output}, nil
+}
+
+func (s *grpcServer) StreamAction(req *pb.ActionRequest, stream pb.KBAgent_StreamActionServer) error {
+	handler, ok := s.service(proto.ServiceAction.Kind).(service.ActionHandler)
+	if !ok {
+		return status.Error(codes.Unimplemented, "has no action service defined")
+	}
+	// the stdout and stderr are copied by different goroutines, and the stream is not safe to send concurrently
+	mutex := &sync.Mutex{}
+	stdout := &streamWriter{mutex: mutex, send: func(p []byte) error {
+		return stream.Send(&pb.ActionOutput{Stdout: p})
+	}}
+	stderr := &streamWriter{mutex: mutex, send: func(p []byte) error {
+		return stream.Send(&pb.ActionOutput{Stderr: p})
+	}}
+	if err := handler.StreamAction(stream.Context(), actionRequest(req), stdout, stderr); err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *grpcServer) Probe(ctx context.Context, req *pb.ProbeRequest) (*pb.ProbeResponse, error) {
+	handler, ok := s.service(proto.ServiceProbe.Kind).(service.ProbeHandler)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "has no probe service defined")
+	}
+	event, err := handler.LatestProbeEvent(req.Probe)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.ProbeResponse{
+		Instance: event.Instance,
+		Probe:    event.Probe,
+		Code:     event.Code,
+		Output:   event.Output,
+		Message:  event.Message,
+	}, nil
+}
+
+func (s *grpcServer) Task(req *pb.TaskRequest, stream pb.KBAgent_TaskServer) error {
+	actionService := s.service(proto.ServiceAction.Kind)
+	if actionService == nil {
+		return status.Error(codes.Unimplemented, "has no action service defined")
+	}
+	if req.NewReplica == nil {
+		return statusError(errors.Wrap(proto.ErrBadRequest, "the task to run is not specified"))
+	}
+	notify := func(event proto.TaskEvent) error {
+		return stream.Send(taskEvent(event))
+	}
+	if err := service.RunTask(stream.Context(), s.logger, actionService, task(req), notify); err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *grpcServer) service(kind string) service.Service {
+	for i, svc := range s.services {
+		if svc.Kind() == kind {
+			return s.services[i]
+		}
+	}
+	return nil
+}
+
+func (s *grpcServer) logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+	s.logCall(info.FullMethod, start, err)
+	return rsp, err
+}
+
+func (s *grpcServer) logStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	s.logCall(info.FullMethod, start, err)
+	return err
+}
+
+func (s *grpcServer) authUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *grpcServer) authStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authenticate(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (s *grpcServer) authenticate(ctx context.Context) error {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(util.AuthorizationMetadataKey); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	if !util.Authenticate(s.config.Token, authorization) {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return nil
+}
+
+func (s *grpcServer) logCall(method string, start time.Time, err error) {
+	if s.config.Logging {
+		s.logger.Info("gRPC API Called",
+			"method", method,
+			"code", status.Code(err).String(),
+			"cost", time.Since(start).Milliseconds(),
+		)
+	}
+}
+
+type streamWriter struct {
+	mutex *sync.Mutex
+	send  func([]byte) error
+}
+
+var _ io.Writer = &streamWriter{}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	// the buffer may be reused by the caller after the write returns, copy it before sending
+	if err := w.send(append([]byte(nil), p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// statusError converts the error to the gRPC status, the error type is carried by the error details,
+// which distinguishes the errors of kb-agent from the transport errors at the client side.
+func statusError(err error) error {
+	st := status.New(proto.Error2Code(err), err.Error())
+	if std, err1 := st.WithDetails(&errdetails.ErrorInfo{Reason: proto.Error2Type(err), Domain: proto.ErrorDomain}); err1 == nil {
+		st = std
+	}
+	return st.Err()
+}
+
+func actionRequest(req *pb.ActionRequest) *proto.ActionRequest {
+	r := &proto.ActionRequest{
+		Action:     req.Action,
+		Parameters: req.Parameters,
+	}
+	if req.NonBlocking {
+		r.NonBlocking = &req.NonBlocking
+	}
+	if req.TimeoutSeconds > 0 {
+		r.TimeoutSeconds = &req.TimeoutSeconds
+	}
+	if req.RetryPolicy != nil {
+		r.RetryPolicy = &proto.RetryPolicy{
+			MaxRetries:    int(req.RetryPolicy.MaxRetries),
+			RetryInterval: time.Duration(req.RetryPolicy.RetryIntervalMilliseconds) * time.Millisecond,
+		}
+	}
+	return r
+}
+
+func task(req *pb.TaskRequest) proto.Task {
+	t := proto.Task{
+		Instance:            req.Instance,
+		Task:                req.Task,
+		UID:                 req.Uid,
+		NotifyAtFinish:      true,
+		ReportPeriodSeconds: req.ReportPeriodSeconds,
+	}
+	if req.NewReplica != nil {
+		t.NewReplica = &proto.NewReplicaTask{
+			Remote:      req.NewReplica.Remote,
+			Port:        req.NewReplica.Port,
+			Replicas:    req.NewReplica.Replicas,
+			Parameters:  req.NewReplica.Parameters,
+			Compression: req.NewReplica.Compression,
+		}
+		if req.NewReplica.TimeoutSeconds > 0 {
+			t.NewReplica.TimeoutSeconds = &req.NewReplica.TimeoutSeconds
+		}
+	}
+	return t
+}
+
+func taskEvent(event proto.TaskEvent) *pb.TaskEvent {
+	e := &pb.TaskEvent{
+		Instance:  event.Instance,
+		Task:      event.Task,
+		Uid:       event.UID,
+		Replica:   event.Replica,
+		StartTime: event.StartTime.UnixMilli(),
+		Code:      event.Code,
+		Output:    event.Output,
+		Message:   event.Message,
+	}
+	if !event.EndTime.IsZero() {
+		e.EndTime = event.EndTime.UnixMilli()
+	}
+	return e
+}
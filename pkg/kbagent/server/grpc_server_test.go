@@ -0,0 +1,309 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto/pb"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
+)
+
+var _ = Describe("gRPC server", func() {
+	var (
+		server Server
+		conn   *grpc.ClientConn
+		client pb.KBAgentClient
+	)
+
+	newServices := func() []service.Service {
+		services, err := service.New(logr.Discard(), []proto.Action{
+			{
+				Name: "echo",
+				Exec: &proto.ExecAction{
+					Commands: []string{"/bin/bash", "-c", "echo -n hello"},
+				},
+			},
+			{
+				Name: "stream",
+				Exec: &proto.ExecAction{
+					Commands: []string{"/bin/bash", "-c", "echo -n stdout && echo -n stderr >&2"},
+				},
+			},
+			{
+				Name: "fail",
+				Exec: &proto.ExecAction{
+					Commands: []string{"/bin/bash", "-c", "exit 1"},
+				},
+			},
+		}, nil, nil, nil)
+		Expect(err).Should(BeNil())
+		return services
+	}
+
+	errorReason := func(err error) string {
+		st, ok := status.FromError(err)
+		Expect(ok).Should(BeTrue())
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				Expect(info.Domain).Should(Equal(proto.ErrorDomain))
+				return info.Reason
+			}
+		}
+		return ""
+	}
+
+	BeforeEach(func() {
+		config := Config{
+			Address:  "127.0.0.1",
+			GRPCPort: freePort(),
+		}
+		server = NewGRPCServer(logr.Discard(), config, newServices())
+		Expect(server.StartNonBlocking()).Should(Succeed())
+
+		var err error
+		conn, err = grpc.NewClient(fmt.Sprintf("%s:%d", config.Address, config.GRPCPort),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).Should(BeNil())
+		client = pb.NewKBAgentClient(conn)
+	})
+
+	AfterEach(func() {
+		Expect(conn.Close()).Should(Succeed())
+		Expect(server.Close()).Should(Succeed())
+	})
+
+	Context("action", func() {
+		It("ok", func() {
+			rsp, err := client.Action(ctx, &pb.ActionRequest{Action: "echo"})
+			Expect(err).Should(BeNil())
+			Expect(rsp.Output).Should(Equal([]byte("hello")))
+		})
+
+		It("not defined", func() {
+			_, err := client.Action(ctx, &pb.ActionRequest{Action: "not-defined"})
+			Expect(err).ShouldNot(BeNil())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+			Expect(errorReason(err)).Should(Equal(proto.Error2Type(proto.ErrNotDefined)))
+		})
+
+		It("fail", func() {
+			_, err := client.Action(ctx, &pb.ActionRequest{Action: "fail"})
+			Expect(err).ShouldNot(BeNil())
+			Expect(errorReason(err)).Should(Equal(proto.Error2Type(proto.ErrFailed)))
+		})
+	})
+
+	Context("stream action", func() {
+		It("stdout and stderr", func() {
+			stream, err := client.StreamAction(ctx, &pb.ActionRequest{Action: "stream"})
+			Expect(err).Should(BeNil())
+			var stdout, stderr []byte
+			for {
+				out, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				Expect(err).Should(BeNil())
+				stdout = append(stdout, out.Stdout...)
+				stderr = append(stderr, out.Stderr...)
+			}
+			Expect(string(stdout)).Should(Equal("stdout"))
+			Expect(string(stderr)).Should(Equal("stderr"))
+		})
+
+		It("fail", func() {
+			stream, err := client.StreamAction(ctx, &pb.ActionRequest{Action: "fail"})
+			Expect(err).Should(BeNil())
+			_, err = stream.Recv()
+			Expect(err).ShouldNot(BeNil())
+			Expect(errorReason(err)).Should(Equal(proto.Error2Type(proto.ErrFailed)))
+		})
+	})
+
+	Context("probe", func() {
+		It("not defined", func() {
+			_, err := client.Probe(ctx, &pb.ProbeRequest{Probe: "roleProbe"})
+			Expect(err).ShouldNot(BeNil())
+			Expect(status.Code(err)).Should(Equal(codes.NotFound))
+		})
+	})
+
+	Context("task", func() {
+		It("bad request", func() {
+			stream, err := client.Task(ctx, &pb.TaskRequest{Task: "newReplica"})
+			Expect(err).Should(BeNil())
+			_, err = stream.Recv()
+			Expect(err).ShouldNot(BeNil())
+			Expect(status.Code(err)).Should(Equal(codes.InvalidArgument))
+			Expect(errorReason(err)).Should(Equal(proto.Error2Type(proto.ErrBadRequest)))
+		})
+	})
+})
+
+var _ = Describe("gRPC server authentication", func() {
+	var (
+		server Server
+		addr   string
+	)
+
+	start := func(config Config) {
+		config.Address = "127.0.0.1"
+		config.GRPCPort = freePort()
+		services, err := service.New(logr.Discard(), []proto.Action{
+			{
+				Name: "echo",
+				Exec: &proto.ExecAction{
+					Commands: []string{"/bin/bash", "-c", "echo -n hello"},
+				},
+			},
+		}, nil, nil, nil)
+		Expect(err).Should(BeNil())
+		server = NewGRPCServer(logr.Discard(), config, services)
+		Expect(server.StartNonBlocking()).Should(Succeed())
+		addr = fmt.Sprintf("%s:%d", config.Address, config.GRPCPort)
+	}
+
+	// call calls the echo action through both the unary and stream APIs, and returns the errors of them.
+	call := func(transportCredentials credentials.TransportCredentials, token string) (error, error) {
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(transportCredentials))
+		Expect(err).Should(BeNil())
+		defer conn.Close()
+		client := pb.NewKBAgentClient(conn)
+
+		callCtx := ctx
+		if len(token) > 0 {
+			callCtx = metadata.AppendToOutgoingContext(ctx, util.AuthorizationMetadataKey, util.BearerToken(token))
+		}
+		rsp, unaryErr := client.Action(callCtx, &pb.ActionRequest{Action: "echo"})
+		if unaryErr == nil {
+			Expect(rsp.Output).Should(Equal([]byte("hello")))
+		}
+		var streamErr error
+		stream, err := client.StreamAction(callCtx, &pb.ActionRequest{Action: "echo"})
+		if err != nil {
+			streamErr = err
+		} else {
+			for {
+				if _, err = stream.Recv(); err != nil {
+					if err != io.EOF {
+						streamErr = err
+					}
+					break
+				}
+			}
+		}
+		return unaryErr, streamErr
+	}
+
+	AfterEach(func() {
+		Expect(server.Close()).Should(Succeed())
+	})
+
+	Context("token", func() {
+		BeforeEach(func() {
+			start(Config{Token: "test-token"})
+		})
+
+		It("missing token", func() {
+			unaryErr, streamErr := call(insecure.NewCredentials(), "")
+			Expect(status.Code(unaryErr)).Should(Equal(codes.Unauthenticated))
+			Expect(status.Code(streamErr)).Should(Equal(codes.Unauthenticated))
+		})
+
+		It("invalid token", func() {
+			unaryErr, streamErr := call(insecure.NewCredentials(), "invalid-token")
+			Expect(status.Code(unaryErr)).Should(Equal(codes.Unauthenticated))
+			Expect(status.Code(streamErr)).Should(Equal(codes.Unauthenticated))
+		})
+
+		It("valid token", func() {
+			unaryErr, streamErr := call(insecure.NewCredentials(), "test-token")
+			Expect(unaryErr).Should(BeNil())
+			Expect(streamErr).Should(BeNil())
+		})
+	})
+
+	Context("mutual TLS", func() {
+		var (
+			ca *testCA
+		)
+
+		clientCredentials := func(ca *testCA) credentials.TransportCredentials {
+			certPEM, keyPEM := ca.issue("client")
+			tlsConfig, err := util.ClientTLSConfig(ca.certPEM, certPEM, keyPEM)
+			Expect(err).Should(BeNil())
+			return credentials.NewTLS(tlsConfig)
+		}
+
+		BeforeEach(func() {
+			ca = newTestCA("ca")
+			start(serverTLSConfig(ca, Config{Token: "test-token"}))
+		})
+
+		It("valid client certificate", func() {
+			unaryErr, streamErr := call(clientCredentials(ca), "test-token")
+			Expect(unaryErr).Should(BeNil())
+			Expect(streamErr).Should(BeNil())
+		})
+
+		It("token is still required", func() {
+			unaryErr, streamErr := call(clientCredentials(ca), "")
+			Expect(status.Code(unaryErr)).Should(Equal(codes.Unauthenticated))
+			Expect(status.Code(streamErr)).Should(Equal(codes.Unauthenticated))
+		})
+
+		It("client certificate issued by another CA", func() {
+			unaryErr, streamErr := call(clientCredentials(newTestCA("another-ca")), "test-token")
+			Expect(status.Code(unaryErr)).Should(Equal(codes.Unavailable))
+			Expect(status.Code(streamErr)).Should(Equal(codes.Unavailable))
+		})
+
+		It("no client certificate", func() {
+			unaryErr, streamErr := call(credentials.NewTLS(&tls.Config{
+				InsecureSkipVerify: true, // #nosec G402
+			}), "test-token")
+			Expect(status.Code(unaryErr)).Should(Equal(codes.Unavailable))
+			Expect(status.Code(streamErr)).Should(Equal(codes.Unavailable))
+		})
+
+		It("plaintext", func() {
+			unaryErr, streamErr := call(insecure.NewCredentials(), "test-token")
+			Expect(status.Code(unaryErr)).Should(Equal(codes.Unavailable))
+			Expect(status.Code(streamErr)).Should(Equal(codes.Unavailable))
+		})
+	})
+})
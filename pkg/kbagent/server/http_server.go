@@ -21,6 +21,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -31,6 +32,7 @@ import (
 	"github.com/valyala/fasthttp"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
 )
 
 const (
@@ -62,10 +64,17 @@ func (s *httpServer) StartNonBlocking() error {
 		}
 		listeners = append(listeners, l)
 	} else {
+		tlsConfig, err := tlsConfig(s.config)
+		if err != nil {
+			return errors.Join(errors.New("load TLS config error"), err)
+		}
 		l, err := net.Listen("tcp", fmt.Sprintf("%s:%v", s.config.Address, s.config.Port))
 		if err != nil {
 			s.logger.Error(err, "listen HTTP server error", "address", s.config.Address, "port", s.config.Port)
 		} else {
+			if tlsConfig != nil {
+				l = tls.NewListener(l, tlsConfig)
+			}
 			listeners = append(listeners, l)
 		}
 	}
@@ -127,15 +136,17 @@ func (s *httpServer) registerService(router *fasthttprouter.Router, svc service.
 
 func (s *httpServer) dispatcher(svc service.Service) func(*fasthttp.RequestCtx) {
 	return func(reqCtx *fasthttp.RequestCtx) {
-		ctx := context.Background()
-		body := reqCtx.PostBody()
-
-		output, err := svc.HandleRequest(ctx, body)
 		statusCode := fasthttp.StatusOK
-		if err != nil {
-			statusCode = fasthttp.StatusInternalServerError
+		if !util.Authenticate(s.config.Token, string(reqCtx.Request.Header.Peek(util.AuthorizationHeader))) {
+			statusCode = fasthttp.StatusUnauthorized
+			httpRespond(reqCtx, statusCode, nil, errors.New("unauthorized"))
+		} else {
+			output, err := svc.HandleRequest(context.Background(), reqCtx.PostBody())
+			if err != nil {
+				statusCode = fasthttp.StatusInternalServerError
+			}
+			httpRespond(reqCtx, statusCode, output, err)
 		}
-		httpRespond(reqCtx, statusCode, output, err)
 		if s.config.Logging {
 			s.logger.Info("HTTP API Called",
 				"user-agent", string(reqCtx.Request.Header.UserAgent()),
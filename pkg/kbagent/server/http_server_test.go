@@ -0,0 +1,180 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
+)
+
+var _ = Describe("HTTP server", func() {
+	var (
+		server Server
+	)
+
+	newServices := func() []service.Service {
+		services, err := service.New(logr.Discard(), []proto.Action{
+			{
+				Name: "echo",
+				Exec: &proto.ExecAction{
+					Commands: []string{"/bin/bash", "-c", "echo -n hello"},
+				},
+			},
+		}, nil, nil, nil)
+		Expect(err).Should(BeNil())
+		return services
+	}
+
+	start := func(config Config) string {
+		config.Address = "127.0.0.1"
+		config.Port = freePort()
+		server = NewHTTPServer(logr.Discard(), config, newServices())
+		Expect(server.StartNonBlocking()).Should(Succeed())
+		return fmt.Sprintf("%s:%d", config.Address, config.Port)
+	}
+
+	// call calls the echo action, and returns the status code and body of the response.
+	call := func(cli *http.Client, url, token string) (int, []byte, error) {
+		data, err := json.Marshal(proto.ActionRequest{Action: "echo"})
+		Expect(err).Should(BeNil())
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+proto.ServiceAction.URI, bytes.NewReader(data))
+		Expect(err).Should(BeNil())
+		if len(token) > 0 {
+			req.Header.Set(util.AuthorizationHeader, util.BearerToken(token))
+		}
+		rsp, err := cli.Do(req)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer rsp.Body.Close()
+		body, err := io.ReadAll(rsp.Body)
+		Expect(err).Should(BeNil())
+		return rsp.StatusCode, body, nil
+	}
+
+	output := func(body []byte) []byte {
+		rsp := proto.ActionResponse{}
+		Expect(json.Unmarshal(body, &rsp)).Should(Succeed())
+		Expect(rsp.Error).Should(BeEmpty())
+		return rsp.Output
+	}
+
+	AfterEach(func() {
+		if server != nil {
+			Expect(server.Close()).Should(Succeed())
+			server = nil
+		}
+	})
+
+	Context("token", func() {
+		It("not required", func() {
+			addr := start(Config{})
+			code, body, err := call(http.DefaultClient, "http://"+addr, "")
+			Expect(err).Should(BeNil())
+			Expect(code).Should(Equal(http.StatusOK))
+			Expect(output(body)).Should(Equal([]byte("hello")))
+		})
+
+		It("missing token", func() {
+			addr := start(Config{Token: "test-token"})
+			code, _, err := call(http.DefaultClient, "http://"+addr, "")
+			Expect(err).Should(BeNil())
+			Expect(code).Should(Equal(http.StatusUnauthorized))
+		})
+
+		It("invalid token", func() {
+			addr := start(Config{Token: "test-token"})
+			code, _, err := call(http.DefaultClient, "http://"+addr, "invalid-token")
+			Expect(err).Should(BeNil())
+			Expect(code).Should(Equal(http.StatusUnauthorized))
+		})
+
+		It("valid token", func() {
+			addr := start(Config{Token: "test-token"})
+			code, body, err := call(http.DefaultClient, "http://"+addr, "test-token")
+			Expect(err).Should(BeNil())
+			Expect(code).Should(Equal(http.StatusOK))
+			Expect(output(body)).Should(Equal([]byte("hello")))
+		})
+	})
+
+	Context("mutual TLS", func() {
+		var (
+			ca   *testCA
+			addr string
+		)
+
+		client := func(ca *testCA) *http.Client {
+			certPEM, keyPEM := ca.issue("client")
+			tlsConfig, err := util.ClientTLSConfig(ca.certPEM, certPEM, keyPEM)
+			Expect(err).Should(BeNil())
+			return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		}
+
+		BeforeEach(func() {
+			ca = newTestCA("ca")
+			addr = start(serverTLSConfig(ca, Config{Token: "test-token"}))
+		})
+
+		It("valid client certificate", func() {
+			code, body, err := call(client(ca), "https://"+addr, "test-token")
+			Expect(err).Should(BeNil())
+			Expect(code).Should(Equal(http.StatusOK))
+			Expect(output(body)).Should(Equal([]byte("hello")))
+		})
+
+		It("token is still required", func() {
+			code, _, err := call(client(ca), "https://"+addr, "")
+			Expect(err).Should(BeNil())
+			Expect(code).Should(Equal(http.StatusUnauthorized))
+		})
+
+		It("client certificate issued by another CA", func() {
+			_, _, err := call(client(newTestCA("another-ca")), "https://"+addr, "test-token")
+			Expect(err).ShouldNot(BeNil())
+		})
+
+		It("no client certificate", func() {
+			cli := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true, // #nosec G402
+			}}}
+			_, _, err := call(cli, "https://"+addr, "test-token")
+			Expect(err).ShouldNot(BeNil())
+		})
+
+		It("plain HTTP", func() {
+			_, _, err := call(http.DefaultClient, "http://"+addr, "test-token")
+			Expect(err).ShouldNot(BeNil())
+		})
+	})
+})
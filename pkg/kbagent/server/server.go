@@ -39,8 +39,16 @@ type Config struct {
 	UnixDomainSocket string
 	Port             int
 	StreamingPort    int
+	GRPCPort         int
 	Concurrency      int
 	Logging          bool
+
+	// the certificates to serve with mutual TLS, the clients should present certificates issued by the CA
+	TLSCertFile string
+	TLSKeyFile  string
+	TLSCAFile   string
+	// the bearer token required for each request
+	Token string
 }
 
 // NewHTTPServer returns a new HTTP server.
@@ -60,3 +68,11 @@ func NewStreamingServer(logger logr.Logger, config Config, service service.Servi
 		service: service,
 	}
 }
+
+func NewGRPCServer(logger logr.Logger, config Config, services []service.Service) Server {
+	return &grpcServer{
+		logger:   logger,
+		config:   config,
+		services: services,
+	}
+}
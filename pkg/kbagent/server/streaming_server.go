@@ -21,6 +21,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -50,12 +51,18 @@ func (s *streamingServer) StartNonBlocking() error {
 		return nil
 	}
 
-	var err1 error
+	tlsConfig, err1 := tlsConfig(s.config)
+	if err1 != nil {
+		return errors.Join(errors.New("load TLS config error"), err1)
+	}
 	s.listener, err1 = net.Listen("tcp", fmt.Sprintf("%s:%v", s.config.Address, s.config.StreamingPort))
 	if err1 != nil {
 		s.logger.Error(err1, "listen address", s.config.Address, "port", s.config.StreamingPort)
 		return err1
 	}
+	if tlsConfig != nil {
+		s.listener = tls.NewListener(s.listener, tlsConfig)
+	}
 
 	go func() {
 		var tempErr error
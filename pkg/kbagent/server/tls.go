@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// tlsConfig builds the TLS config of servers, which requires and verifies the client certificates.
+// It returns nil if TLS is not enabled.
+func tlsConfig(config Config) (*tls.Config, error) {
+	if len(config.TLSCertFile) == 0 && len(config.TLSKeyFile) == 0 && len(config.TLSCAFile) == 0 {
+		return nil, nil
+	}
+	if len(config.TLSCertFile) == 0 || len(config.TLSKeyFile) == 0 || len(config.TLSCAFile) == 0 {
+		return nil, errors.New("the cert, key and CA files are all required to enable TLS")
+	}
+	load := func() (*tls.Config, error) {
+		cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		ca, err := os.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid CA certificate found in %s", config.TLSCAFile)
+		}
+		return &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+			ClientCAs:    clientCAs,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+		}, nil
+	}
+	if _, err := load(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// load the certificates for each connection, since they may be re-issued
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return load()
+		},
+	}, nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type testCA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+}
+
+func newTestCA(name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).Should(BeNil())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	Expect(err).Should(BeNil())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).Should(BeNil())
+	return &testCA{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue issues a certificate usable for both the server and client authentication.
+func (ca *testCA) issue(name string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).Should(BeNil())
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	Expect(err).Should(BeNil())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).Should(BeNil())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// serverTLSConfig writes the server certificates issued by the CA into files, and returns the server config with them.
+func serverTLSConfig(ca *testCA, config Config) Config {
+	dir := GinkgoT().TempDir()
+	certPEM, keyPEM := ca.issue("kbagent")
+	config.TLSCertFile = filepath.Join(dir, "tls.crt")
+	config.TLSKeyFile = filepath.Join(dir, "tls.key")
+	config.TLSCAFile = filepath.Join(dir, "ca.crt")
+	Expect(os.WriteFile(config.TLSCertFile, certPEM, 0600)).Should(Succeed())
+	Expect(os.WriteFile(config.TLSKeyFile, keyPEM, 0600)).Should(Succeed())
+	Expect(os.WriteFile(config.TLSCAFile, ca.certPEM, 0600)).Should(Succeed())
+	return config
+}
+
+var _ = Describe("TLS", func() {
+	It("not enabled", func() {
+		config, err := tlsConfig(Config{})
+		Expect(err).Should(BeNil())
+		Expect(config).Should(BeNil())
+	})
+
+	It("partial files", func() {
+		config := serverTLSConfig(newTestCA("ca"), Config{})
+		config.TLSCAFile = ""
+		_, err := tlsConfig(config)
+		Expect(err).ShouldNot(BeNil())
+	})
+
+	It("invalid CA", func() {
+		config := serverTLSConfig(newTestCA("ca"), Config{})
+		Expect(os.WriteFile(config.TLSCAFile, []byte("invalid"), 0600)).Should(Succeed())
+		_, err := tlsConfig(config)
+		Expect(err).ShouldNot(BeNil())
+	})
+
+	It("missing files", func() {
+		config := serverTLSConfig(newTestCA("ca"), Config{})
+		Expect(os.Remove(config.TLSKeyFile)).Should(Succeed())
+		_, err := tlsConfig(config)
+		Expect(err).ShouldNot(BeNil())
+	})
+
+	It("require and verify client certificates", func() {
+		ca := newTestCA("ca")
+		config, err := tlsConfig(serverTLSConfig(ca, Config{}))
+		Expect(err).Should(BeNil())
+		Expect(config).ShouldNot(BeNil())
+		Expect(config.GetConfigForClient).ShouldNot(BeNil())
+
+		conf, err := config.GetConfigForClient(nil)
+		Expect(err).Should(BeNil())
+		Expect(conf.ClientAuth).Should(Equal(tls.RequireAndVerifyClientCert))
+		Expect(conf.Certificates).Should(HaveLen(1))
+		Expect(conf.ClientCAs).ShouldNot(BeNil())
+	})
+
+	It("reload the re-issued certificates", func() {
+		ca := newTestCA("ca")
+		serverConfig := serverTLSConfig(ca, Config{})
+		config, err := tlsConfig(serverConfig)
+		Expect(err).Should(BeNil())
+		conf, err := config.GetConfigForClient(nil)
+		Expect(err).Should(BeNil())
+
+		certPEM, keyPEM := ca.issue("kbagent")
+		Expect(os.WriteFile(serverConfig.TLSCertFile, certPEM, 0600)).Should(Succeed())
+		Expect(os.WriteFile(serverConfig.TLSKeyFile, keyPEM, 0600)).Should(Succeed())
+		reloaded, err := config.GetConfigForClient(nil)
+		Expect(err).Should(BeNil())
+		Expect(reloaded.Certificates[0].Certificate[0]).ShouldNot(Equal(conf.Certificates[0].Certificate[0]))
+	})
+})
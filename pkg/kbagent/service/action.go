@@ -23,7 +23,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
+	"os/exec"
 	"strings"
 	"sync"
 
@@ -54,6 +56,9 @@ type actionService struct {
 
 	mutex          sync.Mutex
 	runningActions map[string]*runningAction
+
+	// the credentials to connect to the remote kb-agents, used by tasks
+	credentials *Credentials
 }
 
 type runningAction struct {
@@ -83,13 +88,42 @@ func (s *actionService) HandleRequest(ctx context.Context, payload []byte) ([]by
 	if err != nil {
 		return s.encode(nil, err), nil
 	}
+	resp, err := s.HandleAction(ctx, req)
+	return s.encode(resp, err), nil
+}
+
+func (s *actionService) HandleAction(ctx context.Context, req *proto.ActionRequest) ([]byte, error) {
 	resp, err := s.handleRequest(ctx, req)
 	result := string(resp)
 	if err != nil {
 		result = err.Error()
 	}
 	s.logger.Info("Action Executed", "action", req.Action, "result", result)
-	return s.encode(resp, err), nil
+	return resp, err
+}
+
+func (s *actionService) StreamAction(ctx context.Context, req *proto.ActionRequest, stdout, stderr io.Writer) error {
+	action, ok := s.actions[req.Action]
+	if !ok {
+		return errors.Wrapf(proto.ErrNotDefined, "%s is not defined", req.Action)
+	}
+	if action.Exec == nil {
+		return errors.Wrap(proto.ErrNotImplemented, "only exec action is supported")
+	}
+	errChan, err := runCommandX(ctx, action.Exec, req.Parameters, req.TimeoutSeconds, nil, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	err, ok = <-errChan
+	if !ok {
+		err = errors.New("runtime error: error chan closed unexpectedly")
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		err = errors.Wrapf(proto.ErrFailed, "exec exit %d", exitErr.ExitCode())
+	}
+	s.logger.Info("Action Streamed", "action", req.Action, "error", err)
+	return err
 }
 
 func (s *actionService) decode(payload []byte) (*proto.ActionRequest, error) {
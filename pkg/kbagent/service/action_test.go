@@ -20,10 +20,64 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package service
 
 import (
+	"bytes"
+
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 )
 
 var _ = Describe("action", func() {
 	Context("action", func() {
 	})
+
+	Context("stream action", func() {
+		var (
+			actionService *actionService
+		)
+
+		BeforeEach(func() {
+			var err error
+			actionService, err = newActionService(logr.New(nil), []proto.Action{
+				{
+					Name: "echo",
+					Exec: &proto.ExecAction{
+						Commands: []string{"/bin/bash", "-c", "echo -n stdout && echo -n stderr >&2"},
+					},
+				},
+				{
+					Name: "fail",
+					Exec: &proto.ExecAction{
+						Commands: []string{"/bin/bash", "-c", "exit 1"},
+					},
+				},
+			})
+			Expect(err).Should(BeNil())
+		})
+
+		It("not defined", func() {
+			err := actionService.StreamAction(ctx, &proto.ActionRequest{Action: "not-defined"}, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrNotDefined)).Should(BeTrue())
+		})
+
+		It("stdout and stderr", func() {
+			stdoutBuf := bytes.NewBuffer(make([]byte, 0, defaultBufferSize))
+			stderrBuf := bytes.NewBuffer(make([]byte, 0, defaultBufferSize))
+			err := actionService.StreamAction(ctx, &proto.ActionRequest{Action: "echo"}, stdoutBuf, stderrBuf)
+			Expect(err).Should(BeNil())
+			Expect(stdoutBuf.String()).Should(Equal("stdout"))
+			Expect(stderrBuf.String()).Should(Equal("stderr"))
+		})
+
+		It("fail", func() {
+			err := actionService.StreamAction(ctx, &proto.ActionRequest{Action: "fail"}, nil, nil)
+			Expect(err).ShouldNot(BeNil())
+			Expect(errors.Is(err, proto.ErrFailed)).Should(BeTrue())
+		})
+	})
 })
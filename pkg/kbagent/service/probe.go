@@ -26,6 +26,7 @@ import (
 	"net"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -48,7 +49,7 @@ func newProbeService(logger logr.Logger, actionService *actionService, probes []
 		runners:       make(map[string]*probeRunner),
 	}
 	for i, p := range probes {
-		if _, ok := actionService.actions[p.Action]; !ok {
+		if _, ok := actionService.actions[p.Action]; !ok && len(p.Volumes) == 0 {
 			return nil, fmt.Errorf("probe %s has no action defined", p.Action)
 		}
 		sp.probes[p.Action] = &probes[i]
@@ -95,6 +96,18 @@ func (s *probeService) HandleRequest(ctx context.Context, payload []byte) ([]byt
 	return nil, errors.Wrapf(proto.ErrNotImplemented, "service %s does not support request handling", s.Kind())
 }
 
+func (s *probeService) LatestProbeEvent(probe string) (*proto.ProbeEvent, error) {
+	runner, ok := s.runners[probe]
+	if !ok {
+		return nil, errors.Wrapf(proto.ErrNotDefined, "probe %s is not defined", probe)
+	}
+	event := runner.lastEvent.Load()
+	if event == nil {
+		return nil, errors.Wrapf(proto.ErrInProgress, "probe %s has no result yet", probe)
+	}
+	return event, nil
+}
+
 type probeRunner struct {
 	logger        logr.Logger
 	actionService *actionService
@@ -103,6 +116,7 @@ type probeRunner struct {
 	failedCount   int64
 	latestOutput  []byte
 	latestEvent   chan proto.ProbeEvent
+	lastEvent     atomic.Pointer[proto.ProbeEvent]
 }
 
 func (r *probeRunner) run(probe *proto.Probe) {
@@ -130,6 +144,9 @@ func (r *probeRunner) launchRunLoop(probe *proto.Probe) {
 
 func (r *probeRunner) runLoop(probe *proto.Probe) {
 	runOnce := func() ([]byte, error) {
+		if len(probe.Volumes) > 0 {
+			return measureVolumeUsage(probe.Volumes)
+		}
 		return r.actionService.handleRequest(context.Background(), &proto.ActionRequest{Action: probe.Action})
 	}
 
@@ -193,6 +210,7 @@ func (r *probeRunner) report(probe *proto.Probe, output []byte, err error) {
 	}
 
 	if latestEvent != nil {
+		r.lastEvent.Store(latestEvent)
 		select {
 		case r.latestEvent <- *latestEvent:
 		default:
@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package service
 
 import (
+	"encoding/json"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -102,6 +103,54 @@ var _ = Describe("probe", func() {
 			Expect(r.ticker).Should(BeNil())
 		})
 
+		It("volume usage", func() {
+			volumeProbes := []proto.Probe{
+				{
+					Action:        "volumeProtection",
+					PeriodSeconds: 1,
+					Volumes: []proto.VolumeWatermark{
+						{
+							Name:          "data",
+							MountPath:     GinkgoT().TempDir(),
+							HighWatermark: 90,
+						},
+					},
+				},
+			}
+			service, err := newProbeService(logr.New(nil), actionSvc, volumeProbes)
+			Expect(err).Should(BeNil())
+			Expect(service).ShouldNot(BeNil())
+
+			Expect(service.Start()).Should(Succeed())
+			Eventually(func(g Gomega) {
+				event, err := service.LatestProbeEvent("volumeProtection")
+				g.Expect(err).Should(BeNil())
+				g.Expect(event.Code).Should(BeEquivalentTo(0))
+
+				usages := make([]proto.VolumeUsage, 0)
+				g.Expect(json.Unmarshal(event.Output, &usages)).Should(Succeed())
+				g.Expect(usages).Should(HaveLen(1))
+				g.Expect(usages[0].Name).Should(Equal("data"))
+				g.Expect(usages[0].TotalBytes).Should(BeNumerically(">", 0))
+				g.Expect(usages[0].Usage).Should(BeNumerically("<=", 100))
+				g.Expect(usages[0].HighWatermark).Should(Equal(90))
+			}).WithTimeout(5 * time.Second).Should(Succeed())
+		})
+
+		It("volume usage - not mounted", func() {
+			_, err := measureVolumeUsage([]proto.VolumeWatermark{{Name: "data", MountPath: "/path/not/exist"}})
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("failed to stat volume data"))
+		})
+
+		It("usage percentage", func() {
+			Expect(usagePercentage(0, 0)).Should(Equal(0))
+			Expect(usagePercentage(100, 0)).Should(Equal(0))
+			Expect(usagePercentage(1000, 1)).Should(Equal(1))
+			Expect(usagePercentage(1000, 899)).Should(Equal(90))
+			Expect(usagePercentage(1000, 1000)).Should(Equal(100))
+		})
+
 		// TODO: more test cases
 	})
 })
@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+// measureVolumeUsage measures the space usage of the volumes mounted, the usage is rounded up to the percentage,
+// so the output only changes when the usage crosses a percentage point or the volume is resized.
+func measureVolumeUsage(volumes []proto.VolumeWatermark) ([]byte, error) {
+	usages := make([]proto.VolumeUsage, 0, len(volumes))
+	for _, v := range volumes {
+		total, used, err := statVolume(v.MountPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to stat volume %s", v.Name)
+		}
+		usages = append(usages, proto.VolumeUsage{
+			Name:          v.Name,
+			TotalBytes:    total,
+			Usage:         usagePercentage(total, used),
+			HighWatermark: v.HighWatermark,
+		})
+	}
+	return json.Marshal(usages)
+}
+
+func statVolume(path string) (int64, int64, error) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	// the same as df, the space reserved for the root user is not counted as available.
+	bsize := uint64(stat.Bsize)
+	used := (stat.Blocks - stat.Bfree) * bsize
+	total := used + stat.Bavail*bsize
+	return int64(total), int64(used), nil
+}
+
+func usagePercentage(total, used int64) int {
+	if total <= 0 {
+		return 0
+	}
+	return int((used*100 + total - 1) / total)
+}
@@ -21,6 +21,8 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
+	"io"
 	"net"
 
 	"github.com/go-logr/logr"
@@ -39,11 +41,30 @@ type Service interface {
 	HandleRequest(ctx context.Context, payload []byte) ([]byte, error)
 }
 
-func New(logger logr.Logger, actions []proto.Action, probes []proto.Probe, streaming []string) ([]Service, error) {
+// ActionHandler handles the typed action requests, it is implemented by the action service.
+type ActionHandler interface {
+	HandleAction(ctx context.Context, req *proto.ActionRequest) ([]byte, error)
+
+	StreamAction(ctx context.Context, req *proto.ActionRequest, stdout, stderr io.Writer) error
+}
+
+// ProbeHandler queries the latest results of probes, it is implemented by the probe service.
+type ProbeHandler interface {
+	LatestProbeEvent(probe string) (*proto.ProbeEvent, error)
+}
+
+// Credentials are presented by the kb-agent to the remote kb-agents, and the token is required by the local servers too.
+type Credentials struct {
+	Token     string
+	TLSConfig *tls.Config
+}
+
+func New(logger logr.Logger, actions []proto.Action, probes []proto.Probe, streaming []string, credentials *Credentials) ([]Service, error) {
 	sa, err := newActionService(logger, actions)
 	if err != nil {
 		return nil, err
 	}
+	sa.credentials = credentials
 	sp, err := newProbeService(logger, sa, probes)
 	if err != nil {
 		return nil, err
@@ -52,6 +73,9 @@ func New(logger logr.Logger, actions []proto.Action, probes []proto.Probe, strea
 	if err != nil {
 		return nil, err
 	}
+	if credentials != nil {
+		ss.token = credentials.Token
+	}
 	return []Service{sa, sp, ss}, nil
 }
 
@@ -63,3 +87,15 @@ func RunTasks(logger logr.Logger, service Service, tasks []proto.Task) error {
 	}
 	return st.runTasks(context.Background())
 }
+
+// RunTask runs the task directly and hands the task events over to the notify function
+// instead of sending them as kubernetes events.
+func RunTask(ctx context.Context, logger logr.Logger, service Service, task proto.Task, notify func(proto.TaskEvent) error) error {
+	st := &taskService{
+		logger:        logger,
+		actionService: service.(*actionService),
+		tasks:         []proto.Task{task},
+		eventSink:     notify,
+	}
+	return st.runTask(ctx, task)
+}
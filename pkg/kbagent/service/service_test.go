@@ -31,7 +31,7 @@ import (
 var _ = Describe("service", func() {
 	Context("new", func() {
 		It("empty", func() {
-			services, err := New(logr.New(nil), nil, nil, nil)
+			services, err := New(logr.New(nil), nil, nil, nil, nil)
 			Expect(err).Should(BeNil())
 			Expect(services).Should(HaveLen(3))
 			Expect(services[0]).ShouldNot(BeNil())
@@ -45,7 +45,7 @@ var _ = Describe("service", func() {
 					Name: "action",
 				},
 			}
-			services, err := New(logr.New(nil), actions, nil, nil)
+			services, err := New(logr.New(nil), actions, nil, nil, nil)
 			Expect(err).Should(BeNil())
 			Expect(services).Should(HaveLen(3))
 			Expect(services[0]).ShouldNot(BeNil())
@@ -64,7 +64,7 @@ var _ = Describe("service", func() {
 					Action: "action",
 				},
 			}
-			services, err := New(logr.New(nil), actions, probes, nil)
+			services, err := New(logr.New(nil), actions, probes, nil, nil)
 			Expect(err).Should(BeNil())
 			Expect(services).Should(HaveLen(3))
 			Expect(services[0]).ShouldNot(BeNil())
@@ -81,7 +81,7 @@ var _ = Describe("service", func() {
 			streamingActions := []string{
 				"action",
 			}
-			services, err := New(logr.New(nil), actions, nil, streamingActions)
+			services, err := New(logr.New(nil), actions, nil, streamingActions, nil)
 			Expect(err).Should(BeNil())
 			Expect(services).Should(HaveLen(3))
 			Expect(services[0]).ShouldNot(BeNil())
@@ -103,7 +103,7 @@ var _ = Describe("service", func() {
 					Action: "not-defined",
 				},
 			}
-			_, err := New(logr.New(nil), actions, probes, nil)
+			_, err := New(logr.New(nil), actions, probes, nil, nil)
 			Expect(err).ShouldNot(BeNil())
 		})
 
@@ -117,7 +117,7 @@ var _ = Describe("service", func() {
 				"action",
 				"not-defined",
 			}
-			_, err := New(logr.New(nil), actions, nil, streamingActions)
+			_, err := New(logr.New(nil), actions, nil, streamingActions, nil)
 			Expect(err).ShouldNot(BeNil())
 		})
 	})
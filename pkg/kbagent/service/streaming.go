@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package service
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -31,6 +32,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
 )
 
 const (
@@ -53,9 +55,26 @@ func newStreamingService(logger logr.Logger, actionService *actionService, strea
 	return ss, nil
 }
 
+// streamingHandshake is the handshake packet of the streaming connection, it reuses the action request
+// and is compatible with the remote that only understands the action request.
+type streamingHandshake struct {
+	proto.ActionRequest
+
+	// whether the requester supports the framed streaming protocol
+	Framing bool `json:"framing,omitempty"`
+	// the offset to resume the transfer from, and the CRC-32C checksum of the data before it
+	ResumeOffset   int64  `json:"resumeOffset,omitempty"`
+	ResumeChecksum uint32 `json:"resumeChecksum,omitempty"`
+	// the compression of the data frames, only zstd is supported for now
+	Compression string `json:"compression,omitempty"`
+	// the token to authenticate the requester
+	Token string `json:"token,omitempty"`
+}
+
 type streamingService struct {
 	logger           logr.Logger
 	streamingActions map[string]*proto.Action
+	token            string
 }
 
 var _ Service = &streamingService{}
@@ -78,6 +97,10 @@ func (s *streamingService) HandleConn(ctx context.Context, conn net.Conn) error
 		return err
 	}
 
+	if !util.Authenticate(s.token, util.BearerToken(req.Token)) {
+		return s.reject(conn, req, errors.New("unauthorized"))
+	}
+
 	action, ok := s.streamingActions[req.Action]
 	if !ok {
 		return fmt.Errorf("%s is not supported", req.Action)
@@ -90,9 +113,8 @@ func (s *streamingService) HandleRequest(ctx context.Context, payload []byte) ([
 	return nil, errors.Wrapf(proto.ErrNotImplemented, "service %s does not support request handling", s.Kind())
 }
 
-func (s *streamingService) handshake(ctx context.Context, conn net.Conn) (*proto.ActionRequest, error) {
-	// reuse the action request as the handshake packet, define a new one when needed
-	req := &proto.ActionRequest{}
+func (s *streamingService) handshake(ctx context.Context, conn net.Conn) (*streamingHandshake, error) {
+	req := &streamingHandshake{}
 	decoder := json.NewDecoder(conn)
 	if err := decoder.Decode(req); err != nil {
 		return nil, errors.Wrapf(proto.ErrBadRequest, "read and unmarshal action request error: %s", err.Error())
@@ -100,14 +122,80 @@ func (s *streamingService) handshake(ctx context.Context, conn net.Conn) (*proto
 	return req, nil
 }
 
-func (s *streamingService) streaming(ctx context.Context, conn net.Conn, action *proto.Action, req *proto.ActionRequest) error {
+// reject tells the requester that supports frames why the request is rejected, to avoid retrying.
+func (s *streamingService) reject(conn net.Conn, req *streamingHandshake, err error) error {
+	if req.Framing {
+		if fw, err1 := newFrameWriter(conn, 0); err1 == nil {
+			_ = fw.writeEnd(err)
+		}
+	}
+	return err
+}
+
+func (s *streamingService) streaming(ctx context.Context, conn net.Conn, action *proto.Action, req *streamingHandshake) error {
+	if !req.Framing {
+		return s.streamingRaw(ctx, conn, action, req)
+	}
+	return s.streamingFramed(ctx, conn, action, req)
+}
+
+func (s *streamingService) streamingRaw(ctx context.Context, conn net.Conn, action *proto.Action, req *streamingHandshake) error {
 	errChan, err1 := runCommandX(ctx, action.Exec, req.Parameters, req.TimeoutSeconds, nil, conn, nil)
 	if err1 != nil {
 		return err1
 	}
-	err2, ok := <-errChan
+	return s.wait(errChan)
+}
+
+func (s *streamingService) streamingFramed(ctx context.Context, conn net.Conn, action *proto.Action, req *streamingHandshake) error {
+	fw, err := newFrameWriter(conn, req.ResumeOffset)
+	if err != nil {
+		return err
+	}
+	if err = fw.setCompression(req.Compression); err != nil {
+		return s.end(fw, err)
+	}
+
+	// the dump is aborted if the data can't be written to the remote
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stdout := &resumeWriter{
+		w:        bufio.NewWriterSize(fw, streamingChunkSize),
+		offset:   req.ResumeOffset,
+		checksum: req.ResumeChecksum,
+		abort:    cancel,
+	}
+	stderr := &dataDumpSizeWriter{
+		report: func(size int64) {
+			if err1 := fw.writeMeta(streamingMeta{ExpectedSize: size}); err1 != nil {
+				s.logger.Error(err1, "failed to write the meta frame")
+			}
+		},
+	}
+	errChan, err := runCommandX(ctx, action.Exec, req.Parameters, req.TimeoutSeconds, nil, stdout, stderr)
+	if err == nil {
+		err = s.wait(errChan)
+	}
+	if stdout.err != nil {
+		err = stdout.err
+	}
+	if err == nil {
+		err = stdout.flush()
+	}
+	return s.end(fw, err)
+}
+
+func (s *streamingService) end(fw *frameWriter, err error) error {
+	if err1 := fw.writeEnd(err); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+func (s *streamingService) wait(errChan chan error) error {
+	err, ok := <-errChan
 	if !ok {
-		err2 = errors.New("runtime error: error chan closed unexpectedly")
+		err = errors.New("runtime error: error chan closed unexpectedly")
 	}
-	return err2
+	return err
 }
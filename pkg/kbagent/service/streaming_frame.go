@@ -0,0 +1,391 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// The framed streaming protocol is negotiated in the handshake, the remote that supports it writes the magic
+// header first, and then the frames. A frame is a type byte, a 4-byte big-endian length and the payload.
+// The remote that doesn't support it writes the raw data instead, which is detected by the magic header.
+//
+// The payload of a data frame starts with the sequence number, the offset of the chunk in the whole stream,
+// the CRC-32C checksum of the uncompressed chunk and the flags, and the chunk follows. The requester can resume
+// the transfer from the offset it has received by reconnecting with the offset and the checksum of received data.
+
+const (
+	streamingFrameMagic      = "KBSTRM01"
+	maxStreamingFrameSize    = 1 << 20
+	streamingFrameHeaderSize = 5
+	streamingChunkSize       = 256 * 1024
+
+	frameTypeData byte = 1
+	frameTypeMeta byte = 2
+	frameTypeEnd  byte = 3
+
+	// seq (8) | offset (8) | checksum (4) | flags (1)
+	dataFrameHeaderSize      = 21
+	dataFrameFlagZstd   byte = 1
+
+	streamingCompressionZstd = "zstd"
+
+	// dataDumpSizeKey is the key for the dataDump action to report the expected size of the data in bytes, by writing
+	// a line 'KB_DATA_DUMP_SIZE=<bytes>' to stderr.
+	dataDumpSizeKey = "KB_DATA_DUMP_SIZE"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+type streamingMeta struct {
+	ExpectedSize int64 `json:"expectedSize,omitempty"`
+}
+
+type streamingEnd struct {
+	Error string `json:"error,omitempty"`
+}
+
+type frameWriter struct {
+	mutex   sync.Mutex
+	w       io.Writer
+	seq     uint64
+	offset  int64
+	encoder *zstd.Encoder
+}
+
+func newFrameWriter(w io.Writer, offset int64) (*frameWriter, error) {
+	if _, err := io.WriteString(w, streamingFrameMagic); err != nil {
+		return nil, err
+	}
+	return &frameWriter{w: w, offset: offset}, nil
+}
+
+func (w *frameWriter) setCompression(compression string) error {
+	switch compression {
+	case "":
+		return nil
+	case streamingCompressionZstd:
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		w.encoder = encoder
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression: %s", compression)
+	}
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		n := min(len(p)-written, streamingChunkSize)
+		if err := w.writeData(p[written : written+n]); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return len(p), nil
+}
+
+func (w *frameWriter) writeData(chunk []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	header := make([]byte, dataFrameHeaderSize)
+	binary.BigEndian.PutUint64(header[0:], w.seq)
+	binary.BigEndian.PutUint64(header[8:], uint64(w.offset))
+	binary.BigEndian.PutUint32(header[16:], crc32.Checksum(chunk, crc32cTable))
+	data := chunk
+	if w.encoder != nil {
+		// fallback to the raw data if it is incompressible
+		if compressed := w.encoder.EncodeAll(chunk, nil); len(compressed) < len(chunk) {
+			header[20] = dataFrameFlagZstd
+			data = compressed
+		}
+	}
+	if err := w.writeFrameLocked(frameTypeData, append(header, data...)); err != nil {
+		return err
+	}
+	w.seq++
+	w.offset += int64(len(chunk))
+	return nil
+}
+
+func (w *frameWriter) writeMeta(meta streamingMeta) error {
+	payload, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return w.writeFrame(frameTypeMeta, payload)
+}
+
+func (w *frameWriter) writeEnd(err error) error {
+	end := streamingEnd{}
+	if err != nil {
+		end.Error = err.Error()
+	}
+	payload, err1 := json.Marshal(end)
+	if err1 != nil {
+		return err1
+	}
+	return w.writeFrame(frameTypeEnd, payload)
+}
+
+func (w *frameWriter) writeFrame(frameType byte, payload []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.writeFrameLocked(frameType, payload)
+}
+
+func (w *frameWriter) writeFrameLocked(frameType byte, payload []byte) error {
+	header := make([]byte, streamingFrameHeaderSize)
+	header[0] = frameType
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	if _, err := w.w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.w.Write(payload)
+	return err
+}
+
+// streamingRemoteError is the error reported by the remote in the end frame, it can't be recovered by resuming.
+type streamingRemoteError struct {
+	message string
+}
+
+func (e *streamingRemoteError) Error() string {
+	return fmt.Sprintf("the remote failed to dump data: %s", e.message)
+}
+
+// frameReader reads the data from the remote, and calls the onMeta function when the meta frame is received.
+// The data frames are verified in sequence, offset and checksum.
+type frameReader struct {
+	r       io.Reader
+	onMeta  func(streamingMeta)
+	decoder *zstd.Decoder
+	seq     uint64
+	offset  int64
+	data    []byte
+	done    bool
+}
+
+// newStreamingReader detects whether the remote writes frames, and returns the reader of the data starting from the offset.
+func newStreamingReader(r io.Reader, offset int64, onMeta func(streamingMeta)) (io.Reader, bool, error) {
+	magic := make([]byte, len(streamingFrameMagic))
+	n, err := io.ReadFull(r, magic)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, false, err
+	}
+	if n == len(magic) && string(magic) == streamingFrameMagic {
+		return &frameReader{r: r, onMeta: onMeta, offset: offset}, true, nil
+	}
+	// the remote doesn't support frames
+	return io.MultiReader(bytes.NewReader(magic[:n]), r), false, nil
+}
+
+func (r *frameReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.readFrame(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func (r *frameReader) readFrame() error {
+	header := make([]byte, streamingFrameHeaderSize)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.Wrap(io.ErrUnexpectedEOF, "the stream is closed before the end frame")
+		}
+		return err
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxStreamingFrameSize {
+		return fmt.Errorf("the frame size %d exceeds the limit", size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r.r, payload); err != nil {
+		return err
+	}
+	switch header[0] {
+	case frameTypeData:
+		return r.readData(payload)
+	case frameTypeMeta:
+		meta := streamingMeta{}
+		if err := json.Unmarshal(payload, &meta); err != nil {
+			return errors.Wrap(err, "unmarshal the meta frame error")
+		}
+		if r.onMeta != nil {
+			r.onMeta(meta)
+		}
+	case frameTypeEnd:
+		end := streamingEnd{}
+		if err := json.Unmarshal(payload, &end); err != nil {
+			return errors.Wrap(err, "unmarshal the end frame error")
+		}
+		if len(end.Error) > 0 {
+			return &streamingRemoteError{message: end.Error}
+		}
+		r.done = true
+	default:
+		return fmt.Errorf("unknown frame type: %d", header[0])
+	}
+	return nil
+}
+
+func (r *frameReader) readData(payload []byte) error {
+	if len(payload) < dataFrameHeaderSize {
+		return fmt.Errorf("the data frame is too short: %d", len(payload))
+	}
+	seq := binary.BigEndian.Uint64(payload[0:])
+	offset := int64(binary.BigEndian.Uint64(payload[8:]))
+	checksum := binary.BigEndian.Uint32(payload[16:])
+	flags := payload[20]
+	if seq != r.seq {
+		return fmt.Errorf("the data frame is out of sequence, expected: %d, actual: %d", r.seq, seq)
+	}
+	if offset != r.offset {
+		return fmt.Errorf("the data frame is out of offset, expected: %d, actual: %d", r.offset, offset)
+	}
+	data := payload[dataFrameHeaderSize:]
+	if flags&dataFrameFlagZstd != 0 {
+		if r.decoder == nil {
+			decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxStreamingFrameSize))
+			if err != nil {
+				return err
+			}
+			r.decoder = decoder
+		}
+		var err error
+		if data, err = r.decoder.DecodeAll(data, nil); err != nil {
+			return errors.Wrapf(err, "decompress the data frame %d error", seq)
+		}
+	}
+	if actual := crc32.Checksum(data, crc32cTable); actual != checksum {
+		return fmt.Errorf("the checksum of data frame %d mismatched, expected: %08x, actual: %08x", seq, checksum, actual)
+	}
+	r.seq++
+	r.offset += int64(len(data))
+	r.data = data
+	return nil
+}
+
+// resumeWriter skips the data before the resume offset, and verifies the checksum of the skipped data to make sure
+// the data dumped this time is identical to the data that has been transferred.
+type resumeWriter struct {
+	w        *bufio.Writer
+	offset   int64
+	checksum uint32
+	abort    func()
+	skipped  int64
+	crc      uint32
+	err      error
+}
+
+var _ io.Writer = &resumeWriter{}
+
+func (w *resumeWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n := len(p)
+	if w.skipped < w.offset {
+		skip := min(int64(len(p)), w.offset-w.skipped)
+		w.crc = crc32.Update(w.crc, crc32cTable, p[:skip])
+		w.skipped += skip
+		p = p[skip:]
+		if w.skipped == w.offset && w.crc != w.checksum {
+			return w.fail(fmt.Errorf("the checksum of the data before offset %d mismatched, the data dumped is not deterministic", w.offset))
+		}
+	}
+	if len(p) > 0 {
+		if _, err := w.w.Write(p); err != nil {
+			return w.fail(err)
+		}
+	}
+	return n, nil
+}
+
+// fail aborts the dump, the writer won't consume the data any more.
+func (w *resumeWriter) fail(err error) (int, error) {
+	w.err = err
+	if w.abort != nil {
+		w.abort()
+	}
+	return 0, err
+}
+
+func (w *resumeWriter) flush() error {
+	if w.skipped < w.offset {
+		return fmt.Errorf("the data dumped is shorter than the resume offset %d", w.offset)
+	}
+	return w.w.Flush()
+}
+
+// dataDumpSizeWriter scans the stderr of the dataDump action, and reports the expected size of the data.
+type dataDumpSizeWriter struct {
+	buf    []byte
+	report func(size int64)
+}
+
+var _ io.Writer = &dataDumpSizeWriter{}
+
+func (w *dataDumpSizeWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.scan(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) > bufio.MaxScanTokenSize {
+		w.buf = w.buf[:0] // the line is too long to be the size line
+	}
+	return len(p), nil
+}
+
+func (w *dataDumpSizeWriter) scan(line string) {
+	key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+	if !ok || key != dataDumpSizeKey {
+		return
+	}
+	if size, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil && size > 0 {
+		w.report(size)
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("streaming frame", func() {
+	Context("frame", func() {
+		It("data and meta", func() {
+			buf := &bytes.Buffer{}
+			fw, err := newFrameWriter(buf, 0)
+			Expect(err).Should(BeNil())
+			_, err = fw.Write([]byte("hello "))
+			Expect(err).Should(BeNil())
+			Expect(fw.writeMeta(streamingMeta{ExpectedSize: 11})).Should(Succeed())
+			_, err = fw.Write([]byte("world"))
+			Expect(err).Should(BeNil())
+			Expect(fw.writeEnd(nil)).Should(Succeed())
+
+			var expectedSize int64
+			reader, framed, err := newStreamingReader(buf, 0, func(meta streamingMeta) {
+				expectedSize = meta.ExpectedSize
+			})
+			Expect(err).Should(BeNil())
+			Expect(framed).Should(BeTrue())
+			data, err := io.ReadAll(reader)
+			Expect(err).Should(BeNil())
+			Expect(string(data)).Should(Equal("hello world"))
+			Expect(expectedSize).Should(Equal(int64(11)))
+		})
+
+		It("remote failed", func() {
+			buf := &bytes.Buffer{}
+			fw, err := newFrameWriter(buf, 0)
+			Expect(err).Should(BeNil())
+			_, err = fw.Write([]byte("hello"))
+			Expect(err).Should(BeNil())
+			Expect(fw.writeEnd(fmt.Errorf("dump failed"))).Should(Succeed())
+
+			reader, _, err := newStreamingReader(buf, 0, nil)
+			Expect(err).Should(BeNil())
+			_, err = io.ReadAll(reader)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("dump failed"))
+		})
+
+		It("closed without end", func() {
+			buf := &bytes.Buffer{}
+			fw, err := newFrameWriter(buf, 0)
+			Expect(err).Should(BeNil())
+			_, err = fw.Write([]byte("hello"))
+			Expect(err).Should(BeNil())
+
+			reader, _, err := newStreamingReader(buf, 0, nil)
+			Expect(err).Should(BeNil())
+			_, err = io.ReadAll(reader)
+			Expect(err).ShouldNot(BeNil())
+		})
+
+		It("raw", func() {
+			for _, raw := range []string{"", "raw", "raw data without frames"} {
+				reader, framed, err := newStreamingReader(bytes.NewBufferString(raw), 0, nil)
+				Expect(err).Should(BeNil())
+				Expect(framed).Should(BeFalse())
+				data, err := io.ReadAll(reader)
+				Expect(err).Should(BeNil())
+				Expect(string(data)).Should(Equal(raw))
+			}
+		})
+	})
+
+	Context("data frame", func() {
+		dataOf := func(size int) []byte {
+			data := make([]byte, size)
+			for i := range data {
+				data[i] = byte(i % 7)
+			}
+			return data
+		}
+
+		It("compression", func() {
+			data := dataOf(streamingChunkSize*2 + 100)
+			buf := &bytes.Buffer{}
+			fw, err := newFrameWriter(buf, 0)
+			Expect(err).Should(BeNil())
+			Expect(fw.setCompression(streamingCompressionZstd)).Should(Succeed())
+			_, err = fw.Write(data)
+			Expect(err).Should(BeNil())
+			Expect(fw.writeEnd(nil)).Should(Succeed())
+			Expect(buf.Len()).Should(BeNumerically("<", len(data)))
+
+			reader, _, err := newStreamingReader(buf, 0, nil)
+			Expect(err).Should(BeNil())
+			received, err := io.ReadAll(reader)
+			Expect(err).Should(BeNil())
+			Expect(received).Should(Equal(data))
+		})
+
+		It("unsupported compression", func() {
+			fw, err := newFrameWriter(&bytes.Buffer{}, 0)
+			Expect(err).Should(BeNil())
+			Expect(fw.setCompression("lz4")).ShouldNot(Succeed())
+		})
+
+		It("checksum mismatched", func() {
+			buf := &bytes.Buffer{}
+			fw, err := newFrameWriter(buf, 0)
+			Expect(err).Should(BeNil())
+			_, err = fw.Write([]byte("hello world"))
+			Expect(err).Should(BeNil())
+			Expect(fw.writeEnd(nil)).Should(Succeed())
+
+			stream := buf.Bytes()
+			stream[len(streamingFrameMagic)+streamingFrameHeaderSize+dataFrameHeaderSize] ^= 0xff
+			reader, _, err := newStreamingReader(bytes.NewReader(stream), 0, nil)
+			Expect(err).Should(BeNil())
+			_, err = io.ReadAll(reader)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("checksum"))
+		})
+
+		It("offset mismatched", func() {
+			buf := &bytes.Buffer{}
+			fw, err := newFrameWriter(buf, 5)
+			Expect(err).Should(BeNil())
+			_, err = fw.Write([]byte("world"))
+			Expect(err).Should(BeNil())
+
+			reader, _, err := newStreamingReader(buf, 0, nil)
+			Expect(err).Should(BeNil())
+			_, err = io.ReadAll(reader)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("offset"))
+		})
+	})
+
+	Context("resume", func() {
+		It("skip the data before the offset", func() {
+			buf := &bytes.Buffer{}
+			w := &resumeWriter{
+				w:        bufio.NewWriter(buf),
+				offset:   6,
+				checksum: crc32.Checksum([]byte("hello "), crc32cTable),
+			}
+			_, err := w.Write([]byte("hel"))
+			Expect(err).Should(BeNil())
+			_, err = w.Write([]byte("lo world"))
+			Expect(err).Should(BeNil())
+			Expect(w.flush()).Should(Succeed())
+			Expect(buf.String()).Should(Equal("world"))
+		})
+
+		It("not deterministic", func() {
+			aborted := false
+			w := &resumeWriter{
+				w:        bufio.NewWriter(&bytes.Buffer{}),
+				offset:   6,
+				checksum: crc32.Checksum([]byte("hello "), crc32cTable),
+				abort:    func() { aborted = true },
+			}
+			_, err := w.Write([]byte("world hello"))
+			Expect(err).ShouldNot(BeNil())
+			Expect(aborted).Should(BeTrue())
+		})
+
+		It("shorter than the offset", func() {
+			w := &resumeWriter{w: bufio.NewWriter(&bytes.Buffer{}), offset: 6}
+			_, err := w.Write([]byte("hello"))
+			Expect(err).Should(BeNil())
+			Expect(w.flush()).ShouldNot(Succeed())
+		})
+
+		It("reconnect and resume", func() {
+			data := []byte("hello world, hello kubeblocks")
+			connects := 0
+			reader := &resumableReader{
+				ctx:    context.Background(),
+				logger: logr.Discard(),
+				connect: func(ctx context.Context, offset int64, checksum uint32) (net.Conn, io.Reader, bool, error) {
+					connects++
+					Expect(checksum).Should(Equal(crc32.Checksum(data[:offset], crc32cTable)))
+					buf := &bytes.Buffer{}
+					fw, err := newFrameWriter(buf, offset)
+					Expect(err).Should(BeNil())
+					_, err = fw.Write(data[offset:min(offset+10, int64(len(data)))])
+					Expect(err).Should(BeNil())
+					if offset+10 >= int64(len(data)) {
+						Expect(fw.writeEnd(nil)).Should(Succeed())
+					}
+					// the connection is broken after every 10 bytes
+					reader, framed, err := newStreamingReader(buf, offset, nil)
+					return nil, reader, framed, err
+				},
+			}
+			Expect(reader.open()).Should(Succeed())
+			received, err := io.ReadAll(reader)
+			Expect(err).Should(BeNil())
+			Expect(received).Should(Equal(data))
+			Expect(connects).Should(Equal(3))
+		})
+
+		It("remote failed", func() {
+			reader := &resumableReader{
+				ctx:    context.Background(),
+				logger: logr.Discard(),
+				connect: func(ctx context.Context, offset int64, checksum uint32) (net.Conn, io.Reader, bool, error) {
+					buf := &bytes.Buffer{}
+					fw, err := newFrameWriter(buf, offset)
+					Expect(err).Should(BeNil())
+					Expect(fw.writeEnd(fmt.Errorf("dump failed"))).Should(Succeed())
+					reader, framed, err := newStreamingReader(buf, offset, nil)
+					return nil, reader, framed, err
+				},
+			}
+			Expect(reader.open()).Should(Succeed())
+			_, err := io.ReadAll(reader)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("dump failed"))
+		})
+	})
+
+	Context("data dump size", func() {
+		It("report", func() {
+			var size int64
+			w := &dataDumpSizeWriter{report: func(s int64) { size = s }}
+			_, _ = w.Write([]byte("dumping...\nKB_DATA_DUMP_SIZE=10"))
+			Expect(size).Should(Equal(int64(0)))
+			_, _ = w.Write([]byte("24\nfoo=bar\n"))
+			Expect(size).Should(Equal(int64(1024)))
+		})
+	})
+
+	Context("progress", func() {
+		It("snapshot", func() {
+			now := time.Now()
+			p := &transferProgress{start: now.Add(-10 * time.Second)}
+			p.transferred.Store(1000)
+
+			progress := p.snapshot(now)
+			Expect(progress.TransferredBytes).Should(Equal(int64(1000)))
+			Expect(progress.BytesPerSecond).Should(Equal(int64(100)))
+			Expect(progress.RemainingSeconds).Should(BeNil())
+
+			p.expected.Store(3000)
+			progress = p.snapshot(now)
+			Expect(progress.RemainingSeconds).ShouldNot(BeNil())
+			Expect(*progress.RemainingSeconds).Should(Equal(int64(20)))
+			Expect(progress.String()).Should(Equal("transferred 1000B of 2.9KiB (33%), 100B/s, ETA 20s"))
+		})
+	})
+})
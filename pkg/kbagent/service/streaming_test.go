@@ -0,0 +1,169 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+var _ = Describe("streaming", func() {
+	Context("streaming", func() {
+		var (
+			streamingService *streamingService
+		)
+
+		BeforeEach(func() {
+			actionService, err := newActionService(logr.New(nil), []proto.Action{
+				{
+					Name: "dump",
+					Exec: &proto.ExecAction{
+						Commands: []string{"/bin/bash", "-c", "echo -n hello world"},
+					},
+				},
+				{
+					Name: "random-dump",
+					Exec: &proto.ExecAction{
+						Commands: []string{"/bin/bash", "-c", "head -c 4096 /dev/urandom"},
+					},
+				},
+			})
+			Expect(err).Should(BeNil())
+			streamingService, err = newStreamingService(logr.New(nil), actionService, []string{"dump", "random-dump"})
+			Expect(err).Should(BeNil())
+			streamingService.token = "token"
+		})
+
+		request := func(req streamingHandshake) (string, error) {
+			server, client := net.Pipe()
+			defer client.Close()
+			go func() {
+				defer server.Close()
+				_ = streamingService.HandleConn(ctx, server)
+			}()
+
+			data, err := json.Marshal(req)
+			Expect(err).Should(BeNil())
+			_, err = client.Write(data)
+			Expect(err).Should(BeNil())
+
+			reader, _, err := newStreamingReader(client, req.ResumeOffset, nil)
+			Expect(err).Should(BeNil())
+			output, err := io.ReadAll(reader)
+			return string(output), err
+		}
+
+		It("unauthorized", func() {
+			_, err := request(streamingHandshake{
+				ActionRequest: proto.ActionRequest{Action: "dump"},
+				Framing:       true,
+				Token:         "invalid",
+			})
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("unauthorized"))
+		})
+
+		It("resume", func() {
+			output, err := request(streamingHandshake{
+				ActionRequest:  proto.ActionRequest{Action: "dump"},
+				Framing:        true,
+				Token:          "token",
+				ResumeOffset:   6,
+				ResumeChecksum: crc32.Checksum([]byte("hello "), crc32cTable),
+				Compression:    streamingCompressionZstd,
+			})
+			Expect(err).Should(BeNil())
+			Expect(output).Should(Equal("world"))
+		})
+
+		It("resume - not deterministic", func() {
+			_, err := request(streamingHandshake{
+				ActionRequest:  proto.ActionRequest{Action: "dump"},
+				Framing:        true,
+				Token:          "token",
+				ResumeOffset:   6,
+				ResumeChecksum: crc32.Checksum([]byte("world "), crc32cTable),
+			})
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("not deterministic"))
+		})
+
+		It("resume - fail the transfer if not deterministic", func() {
+			connects := 0
+			reader := &resumableReader{
+				ctx:    ctx,
+				logger: logr.Discard(),
+				connect: func(ctx context.Context, offset int64, checksum uint32) (net.Conn, io.Reader, bool, error) {
+					connects++
+					server, client := net.Pipe()
+					go func() {
+						defer server.Close()
+						_ = streamingService.HandleConn(ctx, server)
+					}()
+					data, err := json.Marshal(streamingHandshake{
+						ActionRequest:  proto.ActionRequest{Action: "random-dump"},
+						Framing:        true,
+						Token:          "token",
+						ResumeOffset:   offset,
+						ResumeChecksum: checksum,
+					})
+					Expect(err).Should(BeNil())
+					_, err = client.Write(data)
+					Expect(err).Should(BeNil())
+					reader, framed, err := newStreamingReader(client, offset, nil)
+					if connects == 1 {
+						// the first connection is broken after 1024 bytes
+						reader = &brokenReader{r: reader, remaining: 1024}
+					}
+					return client, reader, framed, err
+				},
+			}
+			Expect(reader.open()).Should(Succeed())
+			_, err := io.ReadAll(reader)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("not deterministic"))
+			// resumed once, and not retried after the mismatch
+			Expect(connects).Should(Equal(2))
+		})
+	})
+})
+
+type brokenReader struct {
+	r         io.Reader
+	remaining int
+}
+
+func (r *brokenReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	n, err := r.r.Read(p[:min(len(p), r.remaining)])
+	r.remaining -= n
+	return n, err
+}
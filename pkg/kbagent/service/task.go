@@ -39,6 +39,7 @@ type taskService struct {
 	logger        logr.Logger
 	actionService *actionService
 	tasks         []proto.Task
+	eventSink     func(proto.TaskEvent) error
 }
 
 type task interface {
@@ -154,6 +155,9 @@ func (s *taskService) wait(ch chan error) error {
 }
 
 func (s *taskService) notify(task proto.Task, event proto.TaskEvent, sync bool) error {
+	if s.eventSink != nil {
+		return s.eventSink(event)
+	}
 	msg, err := json.Marshal(&event)
 	if err == nil {
 		return util.SendEventWithMessage(&s.logger, "task", string(msg), sync)
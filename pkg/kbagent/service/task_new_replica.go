@@ -21,12 +21,20 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
 	"net"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/util"
@@ -36,6 +44,9 @@ const (
 	newReplicaDataDump              = "dataDump"
 	newReplicaDataLoad              = "dataLoad"
 	newReplicaConnectTimeoutSeconds = 10
+	newReplicaMaxResumeRetries      = 10
+	newReplicaResumeBackoff         = time.Second
+	newReplicaMaxResumeBackoff      = 30 * time.Second
 
 	targetPodNameEnv = "KB_TARGET_POD_NAME"
 )
@@ -44,6 +55,7 @@ type newReplicaTask struct {
 	logger        logr.Logger
 	actionService *actionService
 	task          *proto.NewReplicaTask
+	progress      transferProgress
 }
 
 var _ task = &newReplicaTask{}
@@ -54,32 +66,76 @@ func (s *newReplicaTask) run(ctx context.Context) (chan error, error) {
 		return nil, fmt.Errorf("%s is not supported", newReplicaDataLoad)
 	}
 
-	conn, err := s.handshake(ctx)
-	if err != nil {
+	reader := &resumableReader{
+		ctx:     ctx,
+		logger:  s.logger,
+		connect: s.connect,
+	}
+	if err := reader.open(); err != nil {
 		return nil, err
 	}
+	s.progress.start = time.Now()
 
-	return runCommandX(ctx, action.Exec, s.task.Parameters, s.task.TimeoutSeconds, conn, nil, nil)
+	errChan, err := runCommandX(ctx, action.Exec, s.task.Parameters, s.task.TimeoutSeconds, &countingReader{r: reader, progress: &s.progress}, nil, nil)
+	if err != nil {
+		reader.close()
+		return nil, err
+	}
+	doneChan := make(chan error, 1)
+	go func() {
+		defer close(doneChan)
+		defer reader.close()
+		if err, ok := <-errChan; ok {
+			doneChan <- err
+		}
+	}()
+	return doneChan, nil
 }
 
 func (s *newReplicaTask) status(ctx context.Context, event *proto.TaskEvent) {
-	// TODO: query the progress
+	progress := s.progress.snapshot(time.Now())
 	event.Code = 0
-	event.Output = nil
-	event.Message = ""
+	event.Output, _ = json.Marshal(progress)
+	event.Message = progress.String()
+}
+
+// connect connects to the remote and requests the data starting from the offset.
+func (s *newReplicaTask) connect(ctx context.Context, offset int64, checksum uint32) (net.Conn, io.Reader, bool, error) {
+	conn, err := s.handshake(ctx, offset, checksum)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	reader, framed, err := newStreamingReader(conn, offset, func(meta streamingMeta) {
+		if meta.ExpectedSize > 0 {
+			s.progress.expected.Store(meta.ExpectedSize)
+		}
+	})
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, false, err
+	}
+	return conn, reader, framed, nil
 }
 
-func (s *newReplicaTask) handshake(ctx context.Context) (net.Conn, error) {
+func (s *newReplicaTask) handshake(ctx context.Context, offset int64, checksum uint32) (net.Conn, error) {
 	conn, err := s.connectToRemote(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// reuse the action request as the handshake packet, define a new one when needed
-	req := proto.ActionRequest{
-		Action:         newReplicaDataDump,
-		Parameters:     s.task.Parameters,
-		TimeoutSeconds: s.task.TimeoutSeconds,
+	req := streamingHandshake{
+		ActionRequest: proto.ActionRequest{
+			Action:         newReplicaDataDump,
+			Parameters:     maps.Clone(s.task.Parameters),
+			TimeoutSeconds: s.task.TimeoutSeconds,
+		},
+		Framing:        true,
+		ResumeOffset:   offset,
+		ResumeChecksum: checksum,
+		Compression:    s.task.Compression,
+	}
+	if credentials := s.actionService.credentials; credentials != nil {
+		req.Token = credentials.Token
 	}
 	if req.Parameters == nil {
 		req.Parameters = make(map[string]string)
@@ -87,19 +143,22 @@ func (s *newReplicaTask) handshake(ctx context.Context) (net.Conn, error) {
 	req.Parameters[targetPodNameEnv] = util.PodName()
 	data, err := json.Marshal(req)
 	if err != nil {
+		_ = conn.Close()
 		return nil, err
 	}
 	if len(data) > maxStreamingHandshakePacketSize {
+		_ = conn.Close()
 		return nil, fmt.Errorf("handshake packet size is too large: %d", len(data))
 	}
 
 	ret, err := conn.Write(data)
+	if err == nil && ret != len(data) {
+		err = fmt.Errorf("write streaming handshake request to remote error")
+	}
 	if err != nil {
+		_ = conn.Close()
 		return nil, err
 	}
-	if ret != len(data) {
-		return nil, fmt.Errorf("write streaming handshake request to remote error")
-	}
 
 	return conn, nil
 }
@@ -114,5 +173,138 @@ func (s *newReplicaTask) connectToRemote(ctx context.Context) (net.Conn, error)
 	dialer := &net.Dialer{
 		Timeout: newReplicaConnectTimeoutSeconds * time.Second,
 	}
-	return dialer.Dial("tcp", fmt.Sprintf("%s:%d", s.task.Remote, s.task.Port))
+	address := net.JoinHostPort(s.task.Remote, strconv.Itoa(int(s.task.Port)))
+	if credentials := s.actionService.credentials; credentials != nil && credentials.TLSConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: credentials.TLSConfig}
+		return tlsDialer.DialContext(ctx, "tcp", address)
+	}
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+type transferProgress struct {
+	start       time.Time
+	transferred atomic.Int64
+	expected    atomic.Int64
+}
+
+func (p *transferProgress) snapshot(now time.Time) proto.NewReplicaProgress {
+	progress := proto.NewReplicaProgress{
+		TransferredBytes: p.transferred.Load(),
+		ExpectedBytes:    p.expected.Load(),
+	}
+	if elapsed := now.Sub(p.start).Seconds(); !p.start.IsZero() && elapsed >= 1 {
+		progress.BytesPerSecond = int64(float64(progress.TransferredBytes) / elapsed)
+	}
+	if progress.ExpectedBytes > 0 && progress.BytesPerSecond > 0 {
+		remaining := max(progress.ExpectedBytes-progress.TransferredBytes, 0)
+		progress.RemainingSeconds = ptr.To(remaining / progress.BytesPerSecond)
+	}
+	return progress
+}
+
+type countingReader struct {
+	r        io.Reader
+	progress *transferProgress
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.progress.transferred.Add(int64(n))
+	return n, err
+}
+
+// resumableReader reads the data from the remote, and resumes the transfer from the offset that has been received
+// by reconnecting to the remote, if the connection is broken or the data received is corrupted.
+type resumableReader struct {
+	ctx     context.Context
+	logger  logr.Logger
+	connect func(ctx context.Context, offset int64, checksum uint32) (net.Conn, io.Reader, bool, error)
+
+	conn     net.Conn
+	r        io.Reader
+	framed   bool
+	offset   int64
+	checksum uint32
+	retries  int
+	lastErr  error
+}
+
+func (r *resumableReader) open() error {
+	conn, reader, framed, err := r.connect(r.ctx, r.offset, r.checksum)
+	if err != nil {
+		return err
+	}
+	r.conn, r.r, r.framed = conn, reader, framed
+	return nil
+}
+
+func (r *resumableReader) Read(p []byte) (int, error) {
+	for {
+		if r.r == nil {
+			if err := r.resume(); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		n, err := r.r.Read(p)
+		if n > 0 {
+			r.checksum = crc32.Update(r.checksum, crc32cTable, p[:n])
+			r.offset += int64(n)
+			r.retries = 0
+		}
+		if err == nil || errors.Is(err, io.EOF) {
+			return n, err
+		}
+		r.close()
+		if !r.resumable(err) {
+			return n, err
+		}
+		r.logger.Info("the data transfer is interrupted, will resume it", "offset", r.offset, "error", err.Error())
+		r.lastErr = err
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (r *resumableReader) resumable(err error) bool {
+	// the remote that doesn't support frames can't resume the transfer
+	if !r.framed || r.ctx.Err() != nil {
+		return false
+	}
+	// the errors reported by the remote are not retried, including that the data re-dumped mismatches
+	// the checksum of the data received, which means the dump is not deterministic and can't be resumed.
+	var remoteErr *streamingRemoteError
+	return !errors.As(err, &remoteErr)
+}
+
+func (r *resumableReader) resume() error {
+	for {
+		if r.retries >= newReplicaMaxResumeRetries {
+			return errors.Wrapf(r.lastErr, "the data transfer is interrupted and failed to resume after %d retries", r.retries)
+		}
+		backoff := min(newReplicaResumeBackoff<<r.retries, newReplicaMaxResumeBackoff)
+		r.retries++
+		select {
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		case <-time.After(backoff):
+		}
+		if err := r.open(); err != nil {
+			r.lastErr = err
+			continue
+		}
+		if !r.framed {
+			r.close()
+			return fmt.Errorf("the remote doesn't support to resume the data transfer")
+		}
+		return nil
+	}
+}
+
+func (r *resumableReader) close() {
+	if r.conn != nil {
+		_ = r.conn.Close()
+	}
+	r.conn, r.r = nil, nil
 }
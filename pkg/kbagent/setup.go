@@ -42,9 +42,19 @@ const (
 
 	DefaultHTTPPortName      = "http"
 	DefaultStreamingPortName = "streaming"
+	DefaultGRPCPortName      = "grpc"
 
 	DefaultHTTPPort      = 3501
 	DefaultStreamingPort = 3502
+	DefaultGRPCPort      = 3503
+
+	// TokenEnvName is the env of the bearer token required by the servers and presented to the remote kb-agents.
+	TokenEnvName = "KB_AGENT_TOKEN"
+	// TokenSecretKey is the key of the token in the auth secret of the component.
+	TokenSecretKey = "token"
+
+	TLSVolumeName = "kbagent-tls"
+	TLSMountPath  = "/etc/kbagent/tls"
 
 	actionEnvName    = "KB_AGENT_ACTION"
 	probeEnvName     = "KB_AGENT_PROBE"
@@ -129,8 +139,12 @@ func UpdateEnv4Worker(envVars map[string]string, f func(proto.Task) *proto.Task)
 func Launch(logger logr.Logger, config server.Config) (bool, error) {
 	envVars := util.EnvL2M(os.Environ())
 
+	if len(config.Token) == 0 {
+		config.Token = envVars[TokenEnvName]
+	}
+
 	// initialize kb-agent
-	services, err := initialize(logger, envVars)
+	services, err := initialize(logger, config, envVars)
 	if err != nil {
 		return false, errors.Wrap(err, "init action handlers failed")
 	}
@@ -140,7 +154,7 @@ func Launch(logger logr.Logger, config server.Config) (bool, error) {
 	return false, runAsWorker(logger, services, envVars)
 }
 
-func initialize(logger logr.Logger, envVars map[string]string) ([]service.Service, error) {
+func initialize(logger logr.Logger, config server.Config, envVars map[string]string) ([]service.Service, error) {
 	da, dp, ds := getActionProbeNStreamingEnvValues(envVars)
 	if len(da) == 0 {
 		return nil, nil
@@ -155,7 +169,36 @@ func initialize(logger logr.Logger, envVars map[string]string) ([]service.Servic
 	if len(ds) > 0 {
 		streaming = strings.Split(ds, ",")
 	}
-	return service.New(logger, actions, probes, streaming)
+
+	credentials, err := buildCredentials(config)
+	if err != nil {
+		return nil, err
+	}
+	return service.New(logger, actions, probes, streaming, credentials)
+}
+
+// buildCredentials builds the credentials to connect to the remote kb-agents, they share the same certificates and token.
+func buildCredentials(config server.Config) (*service.Credentials, error) {
+	credentials := &service.Credentials{
+		Token: config.Token,
+	}
+	if len(config.TLSCertFile) == 0 {
+		return credentials, nil
+	}
+	var pems [][]byte
+	for _, file := range []string{config.TLSCAFile, config.TLSCertFile, config.TLSKeyFile} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "read TLS certificates failed")
+		}
+		pems = append(pems, data)
+	}
+	tlsConfig, err := util.ClientTLSConfig(pems[0], pems[1], pems[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "load TLS certificates failed")
+	}
+	credentials.TLSConfig = tlsConfig
+	return credentials, nil
 }
 
 func getActionProbeNStreamingEnvValues(envVars map[string]string) (string, string, string) {
@@ -202,6 +245,9 @@ func runAsServer(logger logr.Logger, config server.Config, services []service.Se
 	if config.Port == config.StreamingPort {
 		return errors.New("HTTP port and streaming port are the same")
 	}
+	if config.GRPCPort > 0 && (config.GRPCPort == config.Port || config.GRPCPort == config.StreamingPort) {
+		return errors.New("gRPC port conflicts with the HTTP port or streaming port")
+	}
 
 	// start all services first
 	for i := range services {
@@ -225,6 +271,15 @@ func runAsServer(logger logr.Logger, config server.Config, services []service.Se
 	if err != nil {
 		return errors.Wrap(err, "failed to start the streaming server")
 	}
+
+	// start the gRPC server
+	if config.GRPCPort > 0 {
+		grpcServer := server.NewGRPCServer(logger, config, services)
+		err = grpcServer.StartNonBlocking()
+		if err != nil {
+			return errors.Wrap(err, "failed to start the gRPC server")
+		}
+	}
 	return nil
 }
 
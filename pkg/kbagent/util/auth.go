@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+const (
+	AuthorizationHeader = "Authorization"
+	// AuthorizationMetadataKey is the key of the gRPC metadata, which is always in lowercase.
+	AuthorizationMetadataKey = "authorization"
+
+	bearerPrefix = "Bearer "
+)
+
+// BearerToken formats the token as the value of the authorization header.
+func BearerToken(token string) string {
+	return bearerPrefix + token
+}
+
+// Authenticate checks the bearer token presented in the authorization header, it always passes if no token is required.
+func Authenticate(token, authorization string) bool {
+	if len(token) == 0 {
+		return true
+	}
+	presented, ok := strings.CutPrefix(authorization, bearerPrefix)
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(presented)) == 1
+}
+
+// ClientTLSConfig builds the TLS config to connect to the kb-agent with the client certificate.
+// The kb-agent is addressed by the pod IP which is not in its certificate, so the server certificate is
+// verified against the CA only, the CA is issued for the component exclusively.
+func ClientTLSConfig(caPEM, certPEM, keyPEM []byte) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid CA certificate found")
+	}
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		// the server certificate is verified in VerifyPeerCertificate
+		InsecureSkipVerify: true, // #nosec G402
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyCertChain(roots, rawCerts)
+		},
+	}, nil
+}
+
+func verifyCertChain(roots *x509.CertPool, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("no certificate presented by the server")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	return err
+}
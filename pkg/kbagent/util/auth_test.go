@@ -0,0 +1,70 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"crypto/x509"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("auth", func() {
+	Context("authenticate", func() {
+		It("no token required", func() {
+			Expect(Authenticate("", "")).Should(BeTrue())
+			Expect(Authenticate("", BearerToken("any"))).Should(BeTrue())
+		})
+
+		It("missing token", func() {
+			Expect(Authenticate("test-token", "")).Should(BeFalse())
+		})
+
+		It("invalid token", func() {
+			Expect(Authenticate("test-token", BearerToken("invalid-token"))).Should(BeFalse())
+			Expect(Authenticate("test-token", BearerToken("test-token-suffix"))).Should(BeFalse())
+			Expect(Authenticate("test-token", BearerToken(""))).Should(BeFalse())
+		})
+
+		It("not a bearer token", func() {
+			Expect(Authenticate("test-token", "test-token")).Should(BeFalse())
+			Expect(Authenticate("test-token", "Basic test-token")).Should(BeFalse())
+		})
+
+		It("valid token", func() {
+			Expect(Authenticate("test-token", BearerToken("test-token"))).Should(BeTrue())
+		})
+	})
+
+	Context("client TLS config", func() {
+		It("invalid key pair", func() {
+			_, err := ClientTLSConfig(nil, []byte("invalid"), []byte("invalid"))
+			Expect(err).ShouldNot(BeNil())
+		})
+
+		It("no certificate presented by the server", func() {
+			Expect(verifyCertChain(x509.NewCertPool(), nil)).ShouldNot(Succeed())
+		})
+
+		It("invalid certificate presented by the server", func() {
+			Expect(verifyCertChain(x509.NewCertPool(), [][]byte{[]byte("invalid")})).ShouldNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// the max interval to recheck the approval, in case the OpsApprovalPolicy is changed.
+const approvalRecheckInterval = 5 * time.Minute
+
+var _ error = &WaitForApprovalErr{}
+
+// WaitForApprovalErr indicates that the OpsRequest waits for the approval required by the OpsApprovalPolicy.
+type WaitForApprovalErr struct {
+	policyName string
+	deadline   time.Time
+}
+
+func (e *WaitForApprovalErr) Error() string {
+	return fmt.Sprintf("wait for the approval required by OpsApprovalPolicy %s", e.policyName)
+}
+
+// RequeueAfter returns the duration to recheck the approval.
+func (e *WaitForApprovalErr) RequeueAfter() time.Duration {
+	if e.deadline.IsZero() {
+		return approvalRecheckInterval
+	}
+	return max(time.Second, min(approvalRecheckInterval, time.Until(e.deadline)))
+}
+
+// waitForApproval checks whether the OpsRequest is selected by an OpsApprovalPolicy and has been approved.
+// The OpsRequest stays in the PendingApproval phase until a user other than the creator approves it,
+// and fails if it is not approved within the timeout of the policy.
+func waitForApproval(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	opsRequest := opsRes.OpsRequest
+	if opsRequest.Status.Approval != nil {
+		return nil
+	}
+	policy, err := getOpsApprovalPolicy(reqCtx, cli, opsRes)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		if opsRequest.Status.Phase == opsv1alpha1.OpsPendingApprovalPhase {
+			// the policy is deleted or no longer selects the OpsRequest.
+			return PatchOpsStatus(reqCtx.Ctx, cli, opsRes, opsv1alpha1.OpsPendingPhase)
+		}
+		return nil
+	}
+	if approver := opsRequest.Annotations[constant.OpsApprovedByAnnotationKey]; len(approver) > 0 {
+		// nothing protects the annotations without the webhook, they may be written by the creator itself.
+		if !viper.GetBool(constant.CfgEnableOpsApprovalWebhook) {
+			condition := opsv1alpha1.NewApprovalWithoutWebhookCondition(opsRequest, approver)
+			opsRequest.SetStatusCondition(*condition)
+			return intctrlutil.NewFatalError(condition.Message)
+		}
+		// the creator is recorded by the webhook, the approval without it may be written by the creator itself.
+		if creator := opsRequest.Annotations[constant.OpsCreatedByAnnotationKey]; len(creator) == 0 || creator == approver {
+			condition := opsv1alpha1.NewApprovalRejectedCondition(opsRequest, approver)
+			opsRequest.SetStatusCondition(*condition)
+			return intctrlutil.NewFatalError(condition.Message)
+		}
+		opsDeepCopy := opsRequest.DeepCopy()
+		opsRequest.Status.Approval = &opsv1alpha1.OpsApproval{
+			PolicyName:        policy.Name,
+			Approver:          approver,
+			ApprovalTimestamp: metav1.Now(),
+		}
+		return PatchOpsStatusWithOpsDeepCopy(reqCtx.Ctx, cli, opsRes, opsDeepCopy, opsv1alpha1.OpsPendingPhase,
+			opsv1alpha1.NewApprovedCondition(opsRequest, approver))
+	}
+
+	waitingCondition := meta.FindStatusCondition(opsRequest.Status.Conditions, opsv1alpha1.ConditionTypeApproved)
+	if waitingCondition == nil || waitingCondition.Reason != opsv1alpha1.ReasonWaitForApproval {
+		if err = PatchOpsStatus(reqCtx.Ctx, cli, opsRes, opsv1alpha1.OpsPendingApprovalPhase,
+			opsv1alpha1.NewWaitForApprovalCondition(opsRequest, policy.Name)); err != nil {
+			return err
+		}
+		waitingCondition = meta.FindStatusCondition(opsRequest.Status.Conditions, opsv1alpha1.ConditionTypeApproved)
+	}
+	var deadline time.Time
+	if timeout := policy.Spec.ApprovalTimeoutSeconds; timeout != nil && *timeout > 0 {
+		deadline = waitingCondition.LastTransitionTime.Add(time.Duration(*timeout) * time.Second)
+		if !time.Now().Before(deadline) {
+			condition := opsv1alpha1.NewApprovalExpiredCondition(opsRequest, *timeout)
+			opsRequest.SetStatusCondition(*condition)
+			return intctrlutil.NewFatalError(condition.Message)
+		}
+	}
+	return &WaitForApprovalErr{policyName: policy.Name, deadline: deadline}
+}
+
+// getOpsApprovalPolicy returns the first OpsApprovalPolicy in name order that selects the OpsRequest, or nil if not selected.
+func getOpsApprovalPolicy(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*opsv1alpha1.OpsApprovalPolicy, error) {
+	policyList := &opsv1alpha1.OpsApprovalPolicyList{}
+	if err := cli.List(reqCtx.Ctx, policyList); err != nil {
+		return nil, err
+	}
+	if len(policyList.Items) == 0 {
+		return nil, nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Name: opsRes.OpsRequest.Namespace}, namespace); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(policyList.Items, func(a, b opsv1alpha1.OpsApprovalPolicy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for i := range policyList.Items {
+		policy := &policyList.Items[i]
+		matched, err := policy.Matches(opsRes.OpsRequest.Spec.Type, namespace.Labels, opsRes.Cluster.Labels)
+		if err != nil {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf("invalid OpsApprovalPolicy %s: %s", policy.Name, err.Error()))
+		}
+		if matched {
+			return policy, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testops "github.com/apecloud/kubeblocks/pkg/testutil/operations"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+var _ = Describe("Ops approval", func() {
+
+	var (
+		randomStr   = testCtx.GetRandomStr()
+		compDefName = "test-compdef-" + randomStr
+		clusterName = "test-cluster-" + randomStr
+	)
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+
+		// delete cluster(and all dependent sub-resources), cluster definition
+		testapps.ClearClusterResourcesWithRemoveFinalizerOption(&testCtx)
+
+		// delete rest resources
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		// namespaced
+		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.InstanceSetSignature, true, inNS, ml)
+		// non-namespaced
+		testapps.ClearResources(&testCtx, generics.OpsApprovalPolicySignature, ml)
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(cleanEnv)
+
+	Context("Test OpsRequest", func() {
+		var (
+			opsRes  *OpsResource
+			cluster *appsv1.Cluster
+			reqCtx  intctrlutil.RequestCtx
+		)
+
+		BeforeEach(func() {
+			reqCtx = intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+			viper.Set(constant.CfgEnableOpsApprovalWebhook, true)
+		})
+
+		AfterEach(func() {
+			viper.Set(constant.CfgEnableOpsApprovalWebhook, false)
+		})
+
+		It("Test restart OpsRequest with an approval policy", func() {
+			By("init operations resources and an approval policy selecting the cluster")
+			opsRes, _, cluster = initOperationsResources(compDefName, clusterName)
+			Expect(testapps.ChangeObj(&testCtx, cluster, func(obj *appsv1.Cluster) {
+				obj.Labels["tier"] = "critical"
+			})).Should(Succeed())
+			opsRes.Cluster = cluster
+			policy := &opsv1alpha1.OpsApprovalPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "approval-policy-" + randomStr},
+				Spec: opsv1alpha1.OpsApprovalPolicySpec{
+					ClusterSelector:        &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "critical"}},
+					OpsTypes:               []opsv1alpha1.OpsType{opsv1alpha1.RestartType},
+					ApprovalTimeoutSeconds: pointer.Int32(3600),
+				},
+			}
+			testapps.CreateK8sResource(&testCtx, policy)
+
+			By("expect the restart OpsRequest to wait for the approval")
+			opsRes.OpsRequest = createRestartOpsObj(clusterName, "restart-ops-"+randomStr)
+			_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest),
+				func(g Gomega, fetched *opsv1alpha1.OpsRequest) {
+					g.Expect(fetched.Status.Phase).To(Equal(opsv1alpha1.OpsPendingApprovalPhase))
+					condition := meta.FindStatusCondition(fetched.Status.Conditions, opsv1alpha1.ConditionTypeApproved)
+					g.Expect(condition).ShouldNot(BeNil())
+					g.Expect(condition.Reason).Should(Equal(opsv1alpha1.ReasonWaitForApproval))
+				})).Should(Succeed())
+
+			By("expect the restart OpsRequest to start after being approved")
+			Expect(testapps.ChangeObj(&testCtx, opsRes.OpsRequest, func(ops *opsv1alpha1.OpsRequest) {
+				// the annotations are recorded by the webhook.
+				ops.Annotations = map[string]string{
+					constant.OpsCreatedByAnnotationKey:  "creator",
+					constant.OpsApprovedByAnnotationKey: "approver",
+				}
+			})).Should(Succeed())
+			_, err = GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest),
+				func(g Gomega, fetched *opsv1alpha1.OpsRequest) {
+					g.Expect(fetched.Status.Phase).To(Equal(opsv1alpha1.OpsCreatingPhase))
+					g.Expect(fetched.Status.Approval).ShouldNot(BeNil())
+					g.Expect(fetched.Status.Approval.Approver).Should(Equal("approver"))
+					g.Expect(fetched.Status.Approval.PolicyName).Should(Equal(policy.Name))
+				})).Should(Succeed())
+
+			By("expect the OpsRequest to fail if the approval expires")
+			opsRes.OpsRequest = createRestartOpsObj(clusterName, "restart-ops-expired-"+randomStr)
+			_, err = GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(testapps.ChangeObjStatus(&testCtx, opsRes.OpsRequest, func() {
+				condition := meta.FindStatusCondition(opsRes.OpsRequest.Status.Conditions, opsv1alpha1.ConditionTypeApproved)
+				condition.LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+			})).Should(Succeed())
+			_, err = GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest),
+				func(g Gomega, fetched *opsv1alpha1.OpsRequest) {
+					g.Expect(fetched.Status.Phase).To(Equal(opsv1alpha1.OpsFailedPhase))
+					condition := meta.FindStatusCondition(fetched.Status.Conditions, opsv1alpha1.ConditionTypeApproved)
+					g.Expect(condition).ShouldNot(BeNil())
+					g.Expect(condition.Reason).Should(Equal(opsv1alpha1.ReasonApprovalExpired))
+				})).Should(Succeed())
+		})
+
+		It("Test restart OpsRequest approved by itself", func() {
+			By("init operations resources and an approval policy selecting the cluster")
+			opsRes, _, cluster = initOperationsResources(compDefName, clusterName)
+			opsRes.Cluster = cluster
+			policy := &opsv1alpha1.OpsApprovalPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "approval-policy-" + randomStr},
+				Spec: opsv1alpha1.OpsApprovalPolicySpec{
+					OpsTypes: []opsv1alpha1.OpsType{opsv1alpha1.RestartType},
+				},
+			}
+			testapps.CreateK8sResource(&testCtx, policy)
+
+			expectRejected := func(opsName string, annotations map[string]string) {
+				opsRes.OpsRequest = createRestartOpsObj(clusterName, opsName)
+				_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+				Expect(err).ShouldNot(HaveOccurred())
+				Eventually(testops.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(opsv1alpha1.OpsPendingApprovalPhase))
+
+				Expect(testapps.ChangeObj(&testCtx, opsRes.OpsRequest, func(ops *opsv1alpha1.OpsRequest) {
+					ops.Annotations = annotations
+				})).Should(Succeed())
+				_, err = GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+				Expect(err).ShouldNot(HaveOccurred())
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest),
+					func(g Gomega, fetched *opsv1alpha1.OpsRequest) {
+						g.Expect(fetched.Status.Phase).To(Equal(opsv1alpha1.OpsFailedPhase))
+						g.Expect(fetched.Status.Approval).Should(BeNil())
+						condition := meta.FindStatusCondition(fetched.Status.Conditions, opsv1alpha1.ConditionTypeApproved)
+						g.Expect(condition).ShouldNot(BeNil())
+						g.Expect(condition.Reason).Should(Equal(opsv1alpha1.ReasonApprovalRejected))
+					})).Should(Succeed())
+			}
+
+			By("expect the approval written without the creator recorded by the webhook to be rejected")
+			expectRejected("restart-ops-self-written-"+randomStr, map[string]string{
+				constant.OpsApprovedByAnnotationKey: "creator",
+			})
+
+			By("expect the approval by the creator to be rejected")
+			expectRejected("restart-ops-self-approved-"+randomStr, map[string]string{
+				constant.OpsCreatedByAnnotationKey:  "creator",
+				constant.OpsApprovedByAnnotationKey: "creator",
+			})
+
+			By("expect the approval to be rejected without the webhook, even if the creator and approver are different")
+			viper.Set(constant.CfgEnableOpsApprovalWebhook, false)
+			expectRejected("restart-ops-without-webhook-"+randomStr, map[string]string{
+				constant.OpsCreatedByAnnotationKey:  "someone",
+				constant.OpsApprovedByAnnotationKey: "approver",
+			})
+		})
+	})
+})
@@ -0,0 +1,300 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/instanceset"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// dryRunActionOpsTypes are the types whose action only updates the Cluster or the workloads,
+// which can be performed against the dry-run client to compute the changes.
+var dryRunActionOpsTypes = []opsv1alpha1.OpsType{
+	opsv1alpha1.StartType,
+	opsv1alpha1.StopType,
+	opsv1alpha1.RestartType,
+	opsv1alpha1.VerticalScalingType,
+	opsv1alpha1.HorizontalScalingType,
+	opsv1alpha1.VolumeExpansionType,
+	opsv1alpha1.UpgradeType,
+	opsv1alpha1.ExposeType,
+}
+
+// dryRun computes the impact of the OpsRequest without mutating the Cluster, and completes the OpsRequest.
+func (opsMgr *OpsManager) dryRun(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	opsBehaviour OpsBehaviour) error {
+	opsRequest := opsRes.OpsRequest
+	result := &opsv1alpha1.OpsDryRunResult{}
+	if !slices.Contains(dryRunActionOpsTypes, opsRequest.Spec.Type) {
+		result.Message = fmt.Sprintf("the changes of the %s OpsRequest are not computed by a dry run", opsRequest.Spec.Type)
+	} else {
+		clusterPatch, err := dryRunClusterPatch(reqCtx, cli, opsRes, opsBehaviour.OpsHandler)
+		if err != nil {
+			return err
+		}
+		result.ClusterPatch = clusterPatch
+	}
+
+	var (
+		compNames []string
+		action    = opsv1alpha1.PodRecreateAction
+	)
+	switch opsRequest.Spec.Type {
+	case opsv1alpha1.RestartType:
+		action = opsv1alpha1.PodRestartAction
+		for _, compOps := range opsRequest.Spec.RestartList {
+			compNames = append(compNames, compOps.ComponentName)
+		}
+	case opsv1alpha1.VerticalScalingType:
+		for _, compOps := range opsRequest.Spec.VerticalScalingList {
+			compNames = append(compNames, compOps.ComponentName)
+		}
+	case opsv1alpha1.UpgradeType:
+		if opsRequest.Spec.Upgrade != nil {
+			for _, compOps := range opsRequest.Spec.Upgrade.Components {
+				compNames = append(compNames, compOps.ComponentName)
+			}
+		}
+	case opsv1alpha1.ReconfiguringType:
+		reAction, ok := opsBehaviour.OpsHandler.(*reconfigureAction)
+		if !ok {
+			break
+		}
+		parameters, err := reAction.changedParameters(reqCtx, cli, opsRes)
+		if err != nil {
+			return err
+		}
+		result.Parameters = parameters
+		action = opsv1alpha1.PodRestartAction
+		for _, param := range parameters {
+			if !param.Dynamic && !slices.Contains(compNames, param.ComponentName) {
+				compNames = append(compNames, param.ComponentName)
+			}
+		}
+	}
+	for _, compName := range compNames {
+		pods, err := dryRunPodUpdates(reqCtx.Ctx, cli, opsRes.Cluster, compName, action)
+		if err != nil {
+			return err
+		}
+		result.Pods = append(result.Pods, pods...)
+	}
+
+	opsDeepCopy := opsRequest.DeepCopy()
+	opsRequest.Status.DryRunResult = result
+	return PatchOpsStatusWithOpsDeepCopy(reqCtx.Ctx, cli, opsRes, opsDeepCopy, opsv1alpha1.OpsSucceedPhase,
+		opsv1alpha1.NewDryRunCompletedCondition(opsRequest))
+}
+
+// dryRunClusterPatch performs the action of the OpsRequest against the dry-run client,
+// and returns the changes to the Cluster in the form of a JSON merge patch.
+func dryRunClusterPatch(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, opsHandler OpsHandler) (string, error) {
+	dryRunOpsRes := &OpsResource{
+		OpsDef:     opsRes.OpsDef,
+		OpsRequest: opsRes.OpsRequest.DeepCopy(),
+		Cluster:    opsRes.Cluster.DeepCopy(),
+		// the events of the dry run are dropped.
+		Recorder:       &record.FakeRecorder{},
+		ToClusterPhase: opsRes.ToClusterPhase,
+	}
+	if dryRunOpsRes.OpsRequest.Status.StartTimestamp.IsZero() {
+		dryRunOpsRes.OpsRequest.Status.StartTimestamp = opsRes.OpsRequest.CreationTimestamp
+	}
+	if err := opsHandler.Action(reqCtx, &dryRunClient{Client: cli}, dryRunOpsRes); err != nil {
+		return "", err
+	}
+	toJSON := func(cluster *appsv1.Cluster) ([]byte, error) {
+		return json.Marshal(map[string]any{
+			"metadata": map[string]any{
+				"labels":      cluster.Labels,
+				"annotations": cluster.Annotations,
+			},
+			"spec": cluster.Spec,
+		})
+	}
+	original, err := toJSON(opsRes.Cluster)
+	if err != nil {
+		return "", err
+	}
+	modified, err := toJSON(dryRunOpsRes.Cluster)
+	if err != nil {
+		return "", err
+	}
+	patch, err := jsonpatch.CreateMergePatch(original, modified)
+	if err != nil {
+		return "", err
+	}
+	if string(patch) == "{}" {
+		return "", nil
+	}
+	return string(patch), nil
+}
+
+// dryRunPodUpdates lists the Pods of the component in the order that the InstanceSet updates them.
+func dryRunPodUpdates(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1.Cluster,
+	compName string,
+	action opsv1alpha1.PodUpdateAction) ([]opsv1alpha1.DryRunPod, error) {
+	itsList := &workloads.InstanceSetList{}
+	if err := cli.List(ctx, itsList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			constant.AppInstanceLabelKey:                          cluster.Name,
+			component.GetComponentNameLabelKey(cluster, compName): compName,
+		}); err != nil {
+		return nil, err
+	}
+	var result []opsv1alpha1.DryRunPod
+	for i := range itsList.Items {
+		its := &itsList.Items[i]
+		podList := &corev1.PodList{}
+		if err := cli.List(ctx, podList, client.InNamespace(its.Namespace),
+			client.MatchingLabels(its.Spec.Selector.MatchLabels)); err != nil {
+			return nil, err
+		}
+		for _, podName := range planPodUpdates(its, podList.Items) {
+			result = append(result, opsv1alpha1.DryRunPod{
+				ComponentName: compName,
+				PodName:       podName,
+				Action:        action,
+			})
+		}
+	}
+	return result, nil
+}
+
+// planPodUpdates simulates the update plan of the InstanceSet and returns the names of the Pods in the update order.
+// The Pods are considered ready after they are updated.
+func planPodUpdates(its *workloads.InstanceSet, pods []corev1.Pod) []string {
+	sortedPods := make([]corev1.Pod, len(pods))
+	for i := range pods {
+		pod := pods[i].DeepCopy()
+		pod.DeletionTimestamp = nil
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		sortedPods[i] = *pod
+	}
+	instanceset.SortPods(sortedPods, instanceset.ComposeRolePriorityMap(its.Spec.Roles), false)
+	simulatedPods := make([]*corev1.Pod, len(sortedPods))
+	for i := range sortedPods {
+		simulatedPods[i] = &sortedPods[i]
+	}
+
+	var podNames []string
+	updated := map[string]bool{}
+	isPodUpdated := func(_ *workloads.InstanceSet, pod *corev1.Pod) (bool, error) {
+		return updated[pod.Name], nil
+	}
+	for len(updated) < len(simulatedPods) {
+		podsToBeUpdated, err := instanceset.NewUpdatePlan(*its, simulatedPods, isPodUpdated).Execute()
+		if err != nil || len(podsToBeUpdated) == 0 {
+			break
+		}
+		for _, pod := range podsToBeUpdated {
+			if !updated[pod.Name] {
+				updated[pod.Name] = true
+				podNames = append(podNames, pod.Name)
+			}
+		}
+	}
+	// the Pods not planned, e.g. blocked by the Pods without a role, are updated at last.
+	for _, pod := range simulatedPods {
+		if !updated[pod.Name] {
+			podNames = append(podNames, pod.Name)
+		}
+	}
+	return podNames
+}
+
+// dryRunClient reads through the underlying client and discards all the writes.
+type dryRunClient struct {
+	client.Client
+}
+
+var _ client.Client = &dryRunClient{}
+
+func (c *dryRunClient) Create(_ context.Context, _ client.Object, _ ...client.CreateOption) error {
+	return nil
+}
+
+func (c *dryRunClient) Delete(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+	return nil
+}
+
+func (c *dryRunClient) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	return nil
+}
+
+func (c *dryRunClient) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return nil
+}
+
+func (c *dryRunClient) DeleteAllOf(_ context.Context, _ client.Object, _ ...client.DeleteAllOfOption) error {
+	return nil
+}
+
+func (c *dryRunClient) Status() client.SubResourceWriter {
+	return &dryRunSubResourceClient{}
+}
+
+func (c *dryRunClient) SubResource(subResource string) client.SubResourceClient {
+	return &dryRunSubResourceClient{reader: c.Client.SubResource(subResource)}
+}
+
+type dryRunSubResourceClient struct {
+	reader client.SubResourceReader
+}
+
+var _ client.SubResourceClient = &dryRunSubResourceClient{}
+
+func (c *dryRunSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	if c.reader == nil {
+		return fmt.Errorf("the sub resource can not be read")
+	}
+	return c.reader.Get(ctx, obj, subResource, opts...)
+}
+
+func (c *dryRunSubResourceClient) Create(_ context.Context, _ client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	return nil
+}
+
+func (c *dryRunSubResourceClient) Update(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+	return nil
+}
+
+func (c *dryRunSubResourceClient) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	return nil
+}
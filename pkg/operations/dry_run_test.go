@@ -0,0 +1,161 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testops "github.com/apecloud/kubeblocks/pkg/testutil/operations"
+)
+
+var _ = Describe("DryRun OpsRequest", func() {
+
+	var (
+		randomStr   = testCtx.GetRandomStr()
+		compDefName = "test-compdef-" + randomStr
+		clusterName = "test-cluster-" + randomStr
+		reqCtx      intctrlutil.RequestCtx
+	)
+
+	cleanEnv := func() {
+		reqCtx = intctrlutil.RequestCtx{Ctx: ctx}
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+		// delete cluster(and all dependent sub-resources), cluster definition
+		testapps.ClearClusterResourcesWithRemoveFinalizerOption(&testCtx)
+
+		// delete rest resources
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		// namespaced
+		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.InstanceSetSignature, inNS, ml)
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(cleanEnv)
+
+	Context("Test OpsRequest", func() {
+		It("Test dry run of VerticalScaling OpsRequest", func() {
+			By("init operations resources")
+			opsRes, _, _ := initOperationsResources(compDefName, clusterName)
+			its := testapps.MockInstanceSetComponent(&testCtx, clusterName, defaultCompName)
+			testapps.MockInstanceSetPods(&testCtx, its, opsRes.Cluster, defaultCompName)
+
+			By("create a dry-run VerticalScaling ops")
+			ops := testops.NewOpsRequestObj("vertical-scaling-ops-"+randomStr, testCtx.DefaultNamespace,
+				clusterName, opsv1alpha1.VerticalScalingType)
+			ops.Spec.DryRun = true
+			ops.Spec.VerticalScalingList = []opsv1alpha1.VerticalScaling{
+				{
+					ComponentOps: opsv1alpha1.ComponentOps{ComponentName: defaultCompName},
+					ResourceRequirements: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("600m"),
+						},
+					},
+				},
+			}
+			opsRes.OpsRequest = testops.CreateOpsRequest(ctx, testCtx, ops)
+			// set ops phase to Pending
+			opsRes.OpsRequest.Status.Phase = opsv1alpha1.OpsPendingPhase
+
+			By("expect the OpsRequest succeeds with the dry-run result")
+			_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(ops), func(g Gomega, ops *opsv1alpha1.OpsRequest) {
+				g.Expect(ops.Status.Phase).Should(Equal(opsv1alpha1.OpsSucceedPhase))
+				g.Expect(meta.IsStatusConditionTrue(ops.Status.Conditions, opsv1alpha1.ConditionTypeDryRun)).Should(BeTrue())
+				g.Expect(ops.Status.DryRunResult).ShouldNot(BeNil())
+				g.Expect(ops.Status.DryRunResult.ClusterPatch).Should(ContainSubstring(`"cpu":"600m"`))
+				g.Expect(ops.Status.DryRunResult.Pods).Should(HaveLen(3))
+				// the leader is updated at last.
+				g.Expect(ops.Status.DryRunResult.Pods[2].PodName).Should(Equal(clusterName + "-" + defaultCompName + "-0"))
+				for _, pod := range ops.Status.DryRunResult.Pods {
+					g.Expect(pod.Action).Should(Equal(opsv1alpha1.PodRecreateAction))
+				}
+			})).Should(Succeed())
+
+			By("expect the cluster is not changed")
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.Cluster), func(g Gomega, cluster *appsv1.Cluster) {
+				g.Expect(cluster.Spec.ComponentSpecs[0].Resources.Limits.Cpu().String()).ShouldNot(Equal("600m"))
+				g.Expect(cluster.Annotations).ShouldNot(HaveKey(constant.OpsRequestAnnotationKey))
+			})).Should(Succeed())
+		})
+
+		It("Test the update order of the pods", func() {
+			its := &workloads.InstanceSet{
+				Spec: workloads.InstanceSetSpec{
+					Roles: []workloads.ReplicaRole{
+						{Name: "leader", AccessMode: workloads.ReadWriteMode, CanVote: true, IsLeader: true},
+						{Name: "follower", AccessMode: workloads.ReadonlyMode, CanVote: true},
+					},
+				},
+			}
+			newPods := func(leader int) []corev1.Pod {
+				var pods []corev1.Pod
+				for i := 0; i < 3; i++ {
+					role := "follower"
+					if i == leader {
+						role = "leader"
+					}
+					pods = append(pods, corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   fmt.Sprintf("pod-%d", i),
+							Labels: map[string]string{constant.RoleLabelKey: role},
+						},
+					})
+				}
+				return pods
+			}
+
+			By("update the pods serially and the leader at last")
+			serial := workloads.SerialUpdateStrategy
+			its.Spec.MemberUpdateStrategy = &serial
+			Expect(planPodUpdates(its, newPods(0))).Should(Equal([]string{"pod-2", "pod-1", "pod-0"}))
+			Expect(planPodUpdates(its, newPods(1))).Should(Equal([]string{"pod-2", "pod-0", "pod-1"}))
+
+			By("update the followers in parallel and the leader at last")
+			bestEffortParallel := workloads.BestEffortParallelUpdateStrategy
+			its.Spec.MemberUpdateStrategy = &bestEffortParallel
+			Expect(planPodUpdates(its, newPods(0))[2]).Should(Equal("pod-0"))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// the max interval to recheck the maintenance window, in case the window of the cluster is changed.
+const maintenanceWindowRecheckInterval = 5 * time.Minute
+
+var _ error = &WaitForMaintenanceWindowErr{}
+
+// WaitForMaintenanceWindowErr indicates that the disruptive OpsRequest waits for the maintenance window of the cluster.
+type WaitForMaintenanceWindowErr struct {
+	clusterName string
+	nextOpening time.Time
+}
+
+func (e *WaitForMaintenanceWindowErr) Error() string {
+	return fmt.Sprintf("wait for the maintenance window of cluster %s, which opens at %s", e.clusterName, e.nextOpening.Format(time.RFC3339))
+}
+
+// RequeueAfter returns the duration to recheck the maintenance window.
+func (e *WaitForMaintenanceWindowErr) RequeueAfter() time.Duration {
+	if e.nextOpening.IsZero() {
+		return maintenanceWindowRecheckInterval
+	}
+	return max(time.Second, min(maintenanceWindowRecheckInterval, time.Until(e.nextOpening)))
+}
+
+// alwaysDisruptive is used by the OpsRequests which always interrupt the service.
+func alwaysDisruptive(intctrlutil.RequestCtx, client.Client, *OpsResource) (bool, error) {
+	return true, nil
+}
+
+// waitForMaintenanceWindow checks whether the disruptive OpsRequest is in the maintenance window of the cluster.
+// The OpsRequest stays Pending with the WaitForMaintenanceWindow condition until the window opens.
+func waitForMaintenanceWindow(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, opsBehaviour OpsBehaviour) error {
+	var (
+		opsRequest = opsRes.OpsRequest
+		window     = opsRes.Cluster.Spec.MaintenanceWindow
+	)
+	if window == nil || opsBehaviour.IsDisruptive == nil || opsRequest.Force() {
+		return nil
+	}
+	disruptive, err := opsBehaviour.IsDisruptive(reqCtx, cli, opsRes)
+	if err != nil || !disruptive {
+		return err
+	}
+	open, nextOpening, err := intctrlutil.MaintenanceWindowOpen(window, time.Now())
+	if err != nil {
+		return intctrlutil.NewFatalError(fmt.Sprintf("invalid maintenance window of cluster %s: %s", opsRes.Cluster.Name, err.Error()))
+	}
+
+	waitingCondition := meta.FindStatusCondition(opsRequest.Status.Conditions, opsv1alpha1.ConditionTypeWaitForMaintenanceWindow)
+	if open {
+		if waitingCondition != nil && waitingCondition.Status == metav1.ConditionTrue {
+			// it will be patched along with the phase transition
+			opsRequest.SetStatusCondition(*opsv1alpha1.NewInMaintenanceWindowCondition(opsRequest))
+		}
+		return nil
+	}
+	condition := opsv1alpha1.NewWaitForMaintenanceWindowCondition(opsRequest, nextOpening)
+	if waitingCondition == nil || waitingCondition.Message != condition.Message {
+		if err = PatchOpsStatus(reqCtx.Ctx, cli, opsRes, opsv1alpha1.OpsPendingPhase, condition); err != nil {
+			return err
+		}
+	}
+	return &WaitForMaintenanceWindowErr{clusterName: opsRes.Cluster.Name, nextOpening: nextOpening}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+var _ = Describe("Maintenance window", func() {
+
+	var (
+		randomStr   = testCtx.GetRandomStr()
+		compDefName = "test-compdef-" + randomStr
+		clusterName = "test-cluster-" + randomStr
+	)
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+
+		// delete cluster(and all dependent sub-resources), cluster definition
+		testapps.ClearClusterResourcesWithRemoveFinalizerOption(&testCtx)
+
+		// delete rest resources
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		// namespaced
+		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.InstanceSetSignature, true, inNS, ml)
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(cleanEnv)
+
+	Context("Test OpsRequest", func() {
+		var (
+			opsRes  *OpsResource
+			cluster *appsv1.Cluster
+			reqCtx  intctrlutil.RequestCtx
+		)
+
+		BeforeEach(func() {
+			reqCtx = intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+		})
+
+		It("Test restart OpsRequest in the maintenance window", func() {
+			By("init operations resources with a maintenance window opening in 2 hours")
+			opsRes, _, cluster = initOperationsResources(compDefName, clusterName)
+			opening := time.Now().UTC().Add(2 * time.Hour)
+			Expect(testapps.ChangeObj(&testCtx, cluster, func(obj *appsv1.Cluster) {
+				obj.Spec.MaintenanceWindow = &appsv1.MaintenanceWindow{
+					CronExpression: fmt.Sprintf("%d %d * * *", opening.Minute(), opening.Hour()),
+					Duration:       metav1.Duration{Duration: time.Hour},
+				}
+			})).Should(Succeed())
+			opsRes.Cluster = cluster
+
+			By("expect the restart OpsRequest to wait for the maintenance window")
+			opsRes.OpsRequest = createRestartOpsObj(clusterName, "restart-ops-"+randomStr)
+			_, err := GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest),
+				func(g Gomega, fetched *opsv1alpha1.OpsRequest) {
+					g.Expect(fetched.Status.Phase).To(Equal(opsv1alpha1.OpsPendingPhase))
+					condition := meta.FindStatusCondition(fetched.Status.Conditions, opsv1alpha1.ConditionTypeWaitForMaintenanceWindow)
+					g.Expect(condition).ShouldNot(BeNil())
+					g.Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+					g.Expect(condition.Reason).Should(Equal(opsv1alpha1.ReasonOutOfMaintenanceWindow))
+				})).Should(Succeed())
+
+			By("expect the restart OpsRequest to start when the maintenance window opens")
+			Expect(testapps.ChangeObj(&testCtx, cluster, func(obj *appsv1.Cluster) {
+				obj.Spec.MaintenanceWindow.CronExpression = "@hourly"
+			})).Should(Succeed())
+			opsRes.Cluster = cluster
+			_, err = GetOpsManager().Do(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest),
+				func(g Gomega, fetched *opsv1alpha1.OpsRequest) {
+					g.Expect(fetched.Status.Phase).To(Equal(opsv1alpha1.OpsCreatingPhase))
+					condition := meta.FindStatusCondition(fetched.Status.Conditions, opsv1alpha1.ConditionTypeWaitForMaintenanceWindow)
+					g.Expect(condition).ShouldNot(BeNil())
+					g.Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+				})).Should(Succeed())
+		})
+	})
+})
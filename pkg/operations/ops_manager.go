@@ -71,7 +71,15 @@ func (opsMgr *OpsManager) Do(reqCtx intctrlutil.RequestCtx, cli client.Client, o
 		return &ctrl.Result{}, patchValidateErrorCondition(reqCtx.Ctx, cli, opsRes, err.Error())
 	}
 
-	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase {
+	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase && opsRequest.Spec.DryRun {
+		// a dry run computes the impact of the operation and completes without being enqueued.
+		if err = opsMgr.dryRun(reqCtx, cli, opsRes, opsBehaviour); intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return &ctrl.Result{}, patchValidateErrorCondition(reqCtx.Ctx, cli, opsRes, err.Error())
+		}
+		return &ctrl.Result{}, err
+	}
+
+	if opsRequest.Status.Phase == opsv1alpha1.OpsPendingPhase || opsRequest.Status.Phase == opsv1alpha1.OpsPendingApprovalPhase {
 		if opsRequest.Spec.Cancel {
 			return &ctrl.Result{}, PatchOpsStatus(reqCtx.Ctx, cli, opsRes, opsv1alpha1.OpsCancelledPhase)
 		}
@@ -81,6 +89,12 @@ func (opsMgr *OpsManager) Do(reqCtx intctrlutil.RequestCtx, cli client.Client, o
 			if _, ok := err.(*WaitForClusterPhaseErr); ok {
 				return intctrlutil.ResultToP(intctrlutil.RequeueAfter(time.Second, reqCtx.Log, "wait cluster to a right phase"))
 			}
+			if e, ok := err.(*WaitForMaintenanceWindowErr); ok {
+				return intctrlutil.ResultToP(intctrlutil.RequeueAfter(e.RequeueAfter(), reqCtx.Log, "wait for the maintenance window"))
+			}
+			if e, ok := err.(*WaitForApprovalErr); ok {
+				return intctrlutil.ResultToP(intctrlutil.RequeueAfter(e.RequeueAfter(), reqCtx.Log, "wait for the approval"))
+			}
 			return nil, err
 		}
 		return intctrlutil.ResultToP(intctrlutil.Reconciled())
@@ -106,6 +120,14 @@ func (opsMgr *OpsManager) doPreConditionAndTransPhaseToCreating(reqCtx intctrlut
 	cli client.Client,
 	opsRes *OpsResource,
 	opsBehaviour OpsBehaviour) error {
+	// the operations selected by an OpsApprovalPolicy wait for the approval before anything else.
+	if err := waitForApproval(reqCtx, cli, opsRes); err != nil {
+		return err
+	}
+	// the disruptive operations wait for the maintenance window before being enqueued, to not block the others in the queue.
+	if err := waitForMaintenanceWindow(reqCtx, cli, opsRes, opsBehaviour); err != nil {
+		return err
+	}
 	if opsBehaviour.QueueByCluster || opsBehaviour.QueueBySelf {
 		// if ToClusterPhase is not empty, enqueue OpsRequest to the cluster Annotation.
 		opsRecorde, err := enqueueOpsRequestToClusterAnnotation(reqCtx.Ctx, cli, opsRes, opsBehaviour)
@@ -34,8 +34,6 @@ import (
 	opsutil "github.com/apecloud/kubeblocks/pkg/operations/util"
 )
 
-const opsRequestQueueLimitSize = 20
-
 // DequeueOpsRequestInClusterAnnotation when OpsRequest.status.phase is Succeeded or Failed
 // we should remove the OpsRequest Annotation of cluster, then unlock cluster
 func DequeueOpsRequestInClusterAnnotation(ctx context.Context, cli client.Client, opsRes *OpsResource) error {
@@ -116,8 +114,8 @@ func enqueueOpsRequestToClusterAnnotation(ctx context.Context, cli client.Client
 	switch index {
 	case -1:
 		// if not exists but reach the queue limit size, throw an error
-		if len(opsRequestSlice) >= opsRequestQueueLimitSize {
-			return nil, intctrlutil.NewFatalError(fmt.Sprintf("The opsRequest queue is limited to a size of %d", opsRequestQueueLimitSize))
+		if len(opsRequestSlice) >= opsutil.OpsRequestQueueLimitSize {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf("The opsRequest queue is limited to a size of %d", opsutil.OpsRequestQueueLimitSize))
 		}
 		// if not exists, enqueue
 		if opsRequestSlice == nil {
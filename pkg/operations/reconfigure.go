@@ -30,6 +30,7 @@ import (
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
 	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/configuration/core"
 	configctrl "github.com/apecloud/kubeblocks/pkg/controller/configuration"
@@ -49,6 +50,7 @@ func init() {
 		ToClusterPhase: appsv1.UpdatingClusterPhase,
 		QueueByCluster: true,
 		OpsHandler:     &reAction,
+		IsDisruptive:   reAction.isDisruptive,
 	}
 	opsManager.RegisterOps(opsv1alpha1.ReconfiguringType, reconfigureBehaviour)
 }
@@ -58,6 +60,59 @@ func (r *reconfigureAction) ActionStartedCondition(reqCtx intctrlutil.RequestCtx
 	return opsv1alpha1.NewReconfigureCondition(opsRes.OpsRequest), nil
 }
 
+// isDisruptive checks whether any static parameter or the whole file is updated, which restarts the instances.
+func (r *reconfigureAction) isDisruptive(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (bool, error) {
+	parameters, err := r.changedParameters(reqCtx, cli, opsRes)
+	if err != nil {
+		return false, err
+	}
+	for _, param := range parameters {
+		if !param.Dynamic {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// changedParameters lists the parameters changed by the OpsRequest, and checks whether they are dynamic.
+// The parameters without a ConfigConstraint and the replaced files are considered static.
+func (r *reconfigureAction) changedParameters(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) ([]opsv1alpha1.DryRunParameter, error) {
+	var parameters []opsv1alpha1.DryRunParameter
+	for _, reconfigure := range opsRes.OpsRequest.Spec.Reconfigures {
+		for _, configSpec := range reconfigure.Configurations {
+			fetcher, err := r.syncDependResources(reqCtx, cli, opsRes, configSpec, reconfigure.ComponentName)
+			if err != nil {
+				return nil, err
+			}
+			var ccSpec *appsv1beta1.ConfigConstraintSpec
+			item := fetcher.ConfigurationObj.Spec.GetConfigurationItem(configSpec.Name)
+			if item != nil && item.ConfigSpec != nil && item.ConfigSpec.ConfigConstraintRef != "" {
+				cc := &appsv1beta1.ConfigConstraint{}
+				if err = cli.Get(reqCtx.Ctx, client.ObjectKey{Name: item.ConfigSpec.ConfigConstraintRef}, cc); err != nil {
+					return nil, err
+				}
+				ccSpec = &cc.Spec
+			}
+			for _, key := range configSpec.Keys {
+				parameter := opsv1alpha1.DryRunParameter{
+					ComponentName:     reconfigure.ComponentName,
+					ConfigurationName: configSpec.Name,
+					FileName:          key.Key,
+				}
+				if len(key.FileContent) > 0 {
+					parameters = append(parameters, parameter)
+				}
+				for _, param := range key.Parameters {
+					parameter.Name = param.Key
+					parameter.Dynamic = ccSpec != nil && core.IsDynamicParameter(param.Key, ccSpec)
+					parameters = append(parameters, parameter)
+				}
+			}
+		}
+	}
+	return parameters, nil
+}
+
 func (r *reconfigureAction) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
 	return nil
 }
@@ -48,6 +48,7 @@ func init() {
 		ToClusterPhase:    appsv1.UpdatingClusterPhase,
 		QueueByCluster:    true,
 		OpsHandler:        restartOpsHandler{},
+		IsDisruptive:      alwaysDisruptive,
 	}
 
 	opsMgr := GetOpsManager()
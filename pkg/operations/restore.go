@@ -182,6 +182,18 @@ func (r RestoreOpsHandler) restoreClusterFromBackup(reqCtx intctrlutil.RequestCt
 }
 
 func (r RestoreOpsHandler) getClusterObjFromBackup(backup *dpv1alpha1.Backup, opsRequest *opsv1alpha1.OpsRequest) (*appsv1.Cluster, error) {
+	restoreSpec := opsRequest.Spec.GetRestore()
+	// set the restore annotation to cluster
+	restoreAnnotation, err := restore.GetRestoreFromBackupAnnotation(backup, restoreSpec.VolumeRestorePolicy, restoreSpec.RestorePointInTime, restoreSpec.Env, restoreSpec.DeferPostReadyUntilClusterRunning)
+	if err != nil {
+		return nil, err
+	}
+	return BuildClusterFromBackup(backup, opsRequest.Spec.GetClusterName(), restoreAnnotation)
+}
+
+// BuildClusterFromBackup builds a new cluster from the cluster snapshot of the backup, which will be restored
+// from the backup by the restore annotation.
+func BuildClusterFromBackup(backup *dpv1alpha1.Backup, clusterName, restoreAnnotation string) (*appsv1.Cluster, error) {
 	cluster := &appsv1.Cluster{}
 	// use the cluster snapshot to restore firstly
 	clusterString, ok := backup.Annotations[constant.ClusterSnapshotAnnotationKey]
@@ -191,17 +203,11 @@ func (r RestoreOpsHandler) getClusterObjFromBackup(backup *dpv1alpha1.Backup, op
 	if err := json.Unmarshal([]byte(clusterString), &cluster); err != nil {
 		return nil, err
 	}
-	restoreSpec := opsRequest.Spec.GetRestore()
-	// set the restore annotation to cluster
-	restoreAnnotation, err := restore.GetRestoreFromBackupAnnotation(backup, restoreSpec.VolumeRestorePolicy, restoreSpec.RestorePointInTime, restoreSpec.Env, restoreSpec.DeferPostReadyUntilClusterRunning)
-	if err != nil {
-		return nil, err
-	}
 	if cluster.Annotations == nil {
 		cluster.Annotations = map[string]string{}
 	}
 	cluster.Annotations[constant.RestoreFromBackupAnnotationKey] = restoreAnnotation
-	cluster.Name = opsRequest.Spec.GetClusterName()
+	cluster.Name = clusterName
 	// Reset cluster services
 	var services []appsv1.ClusterService
 	for i := range cluster.Spec.Services {
@@ -223,11 +229,11 @@ func (r RestoreOpsHandler) getClusterObjFromBackup(backup *dpv1alpha1.Backup, op
 	for i := range cluster.Spec.ComponentSpecs {
 		cluster.Spec.ComponentSpecs[i].OfflineInstances = nil
 	}
-	r.rebuildShardAccountSecrets(cluster)
+	rebuildShardAccountSecrets(cluster)
 	return cluster, nil
 }
 
-func (r RestoreOpsHandler) rebuildShardAccountSecrets(cluster *appsv1.Cluster) {
+func rebuildShardAccountSecrets(cluster *appsv1.Cluster) {
 	if len(cluster.Spec.Shardings) == 0 {
 		return
 	}
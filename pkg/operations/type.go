@@ -69,6 +69,10 @@ type OpsBehaviour struct {
 	// QueueWithSelf indicates that the operation is queued for execution within opsType scope.
 	QueueBySelf bool
 
+	// IsDisruptive checks whether the operation interrupts the service,
+	// the disruptive operations are only started within the maintenance window of the cluster.
+	IsDisruptive func(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (bool, error)
+
 	OpsHandler OpsHandler
 }
 
@@ -20,16 +20,19 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package operations
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
@@ -45,6 +48,7 @@ func init() {
 		ToClusterPhase:    appsv1.UpdatingClusterPhase,
 		QueueByCluster:    true,
 		OpsHandler:        upgradeOpsHandler{},
+		IsDisruptive:      alwaysDisruptive,
 	}
 
 	opsMgr := GetOpsManager()
@@ -119,7 +123,137 @@ func (u upgradeOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cl
 		compStatus *opsv1alpha1.OpsRequestComponentStatus) (expectProgressCount int32, completedCount int32, err error) {
 		return handleComponentStatusProgress(reqCtx, cli, opsRes, pgRes, compStatus, podApplyCompOps)
 	}
-	return compOpsHelper.reconcileActionWithComponentOps(reqCtx, cli, opsRes, "upgrade", handleUpgradeProgress)
+	opsPhase, requeueAfter, err := compOpsHelper.reconcileActionWithComponentOps(reqCtx, cli, opsRes, "upgrade", handleUpgradeProgress)
+	if err != nil || upgradeSpec.AutoRollback == nil || opsPhase == opsv1alpha1.OpsRunningPhase && !u.rollbackDeadlineExceeded(opsRes) {
+		return opsPhase, requeueAfter, err
+	}
+	return u.rollbackUnavailableComponents(reqCtx, cli, opsRes, opsPhase, requeueAfter)
+}
+
+// rollbackDeadlineExceeded checks whether the deadline for the upgraded components to become available is exceeded.
+func (u upgradeOpsHandler) rollbackDeadlineExceeded(opsRes *OpsResource) bool {
+	return !time.Now().Before(u.rollbackDeadline(opsRes))
+}
+
+func (u upgradeOpsHandler) rollbackDeadline(opsRes *OpsResource) time.Time {
+	timeout := time.Duration(opsRes.OpsRequest.Spec.Upgrade.AutoRollback.AvailableTimeoutSeconds) * time.Second
+	return opsRes.OpsRequest.Status.StartTimestamp.Add(timeout)
+}
+
+// rollbackUnavailableComponents restores the last ComponentDefinition and ServiceVersion of the components that
+// fail during the upgrade or are not available by the deadline, and the OpsRequest fails with the reason.
+// The upgrade succeeds only if all the upgraded components are available.
+func (u upgradeOpsHandler) rollbackUnavailableComponents(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	opsPhase opsv1alpha1.OpsPhase,
+	requeueAfter time.Duration) (opsv1alpha1.OpsPhase, time.Duration, error) {
+	unavailableComps, err := u.getUnavailableComponents(reqCtx, cli, opsRes)
+	if err != nil {
+		return opsv1alpha1.OpsRunningPhase, 0, err
+	}
+	if len(unavailableComps) == 0 {
+		return opsPhase, requeueAfter, nil
+	}
+	if opsPhase == opsv1alpha1.OpsSucceedPhase && !u.rollbackDeadlineExceeded(opsRes) {
+		// the upgrade is applied, wait for the components to become available.
+		return opsv1alpha1.OpsRunningPhase, time.Until(u.rollbackDeadline(opsRes)), nil
+	}
+
+	var (
+		opsRequest      = opsRes.OpsRequest
+		lastConfigs     = opsRequest.Status.LastConfiguration.Components
+		rollbackComps   []opsv1alpha1.UpgradeComponent
+		rollbackCompSet = map[string]struct{}{}
+	)
+	for _, v := range opsRequest.Spec.Upgrade.Components {
+		if _, ok := unavailableComps[v.ComponentName]; !ok {
+			continue
+		}
+		lastConfig := lastConfigs[v.ComponentName]
+		rollbackComps = append(rollbackComps, opsv1alpha1.UpgradeComponent{
+			ComponentOps:            v.ComponentOps,
+			ComponentDefinitionName: &lastConfig.ComponentDefinitionName,
+			ServiceVersion:          &lastConfig.ServiceVersion,
+		})
+		rollbackCompSet[v.ComponentName] = struct{}{}
+	}
+	compOpsHelper := newComponentOpsHelper(rollbackComps)
+	if err = compOpsHelper.updateClusterComponentsAndShardings(opsRes.Cluster, func(compSpec *appsv1.ClusterComponentSpec, obj ComponentOpsInterface) error {
+		rollbackComp := obj.(opsv1alpha1.UpgradeComponent)
+		compSpec.ComponentDef = *rollbackComp.ComponentDefinitionName
+		compSpec.ServiceVersion = *rollbackComp.ServiceVersion
+		return nil
+	}); err != nil {
+		return opsv1alpha1.OpsRunningPhase, 0, err
+	}
+	if err = cli.Update(reqCtx.Ctx, opsRes.Cluster); err != nil {
+		return opsv1alpha1.OpsRunningPhase, 0, err
+	}
+
+	// record the reasons of the rollback.
+	opsDeepCopy := opsRequest.DeepCopy()
+	compNames := make([]string, 0, len(rollbackComps))
+	for _, v := range rollbackComps {
+		compNames = append(compNames, v.ComponentName)
+		compStatus := opsRequest.Status.Components[v.ComponentName]
+		compStatus.Reason = opsv1alpha1.ReasonUpgradeRolledBack
+		compStatus.Message = fmt.Sprintf(`rolled back to the ComponentDefinition "%s" and the ServiceVersion "%s": %s`,
+			*v.ComponentDefinitionName, *v.ServiceVersion, unavailableComps[v.ComponentName])
+		opsRequest.Status.Components[v.ComponentName] = compStatus
+	}
+	condition := opsv1alpha1.NewUpgradeRolledBackCondition(opsRequest, compNames)
+	opsRequest.SetStatusCondition(*condition)
+	if err = cli.Status().Patch(reqCtx.Ctx, opsRequest, client.MergeFrom(opsDeepCopy)); err != nil {
+		return opsv1alpha1.OpsRunningPhase, 0, err
+	}
+	opsRes.Recorder.Event(opsRequest, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	return opsv1alpha1.OpsFailedPhase, 0, errors.New(condition.Message)
+}
+
+// getUnavailableComponents returns the upgraded components that are not available, with the reasons.
+// A sharding is unavailable if any of its shards is unavailable.
+func (u upgradeOpsHandler) getUnavailableComponents(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource) (map[string]string, error) {
+	unavailableComps := map[string]string{}
+	for _, v := range opsRes.OpsRequest.Spec.Upgrade.Components {
+		var comps []appsv1.Component
+		if opsRes.Cluster.Spec.GetShardingByName(v.ComponentName) != nil {
+			shardingComps, err := intctrlutil.ListShardingComponents(reqCtx.Ctx, cli, opsRes.Cluster, v.ComponentName)
+			if err != nil {
+				return nil, err
+			}
+			comps = shardingComps
+		} else {
+			comp, err := component.GetComponentByName(reqCtx.Ctx, cli, opsRes.Cluster.Namespace,
+				constant.GenerateClusterComponentName(opsRes.Cluster.Name, v.ComponentName))
+			if err != nil {
+				return nil, err
+			}
+			comps = []appsv1.Component{*comp}
+		}
+		for _, comp := range comps {
+			if available, message := u.componentAvailable(&comp); !available {
+				unavailableComps[v.ComponentName] = message
+				break
+			}
+		}
+	}
+	return unavailableComps, nil
+}
+
+// componentAvailable checks the Available condition of the component, which is evaluated by the policy of the ComponentDefinition.
+func (u upgradeOpsHandler) componentAvailable(comp *appsv1.Component) (bool, string) {
+	condition := meta.FindStatusCondition(comp.Status.Conditions, appsv1.ConditionTypeAvailable)
+	switch {
+	case condition == nil || condition.ObservedGeneration < comp.Generation:
+		return false, fmt.Sprintf("the availability of the component %s is not evaluated", comp.Name)
+	case condition.Status != metav1.ConditionTrue:
+		return false, condition.Message
+	default:
+		return true, ""
+	}
 }
 
 // SaveLastConfiguration records last configuration to the OpsRequest.status.lastConfiguration
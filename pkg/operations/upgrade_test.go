@@ -20,15 +20,20 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package operations
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
 	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
@@ -64,6 +69,7 @@ var _ = Describe("Upgrade OpsRequest", func() {
 		ml := client.HasLabels{testCtx.TestObjLabelKey}
 		// namespaced
 		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.ComponentSignature, inNS, ml)
 	}
 
 	BeforeEach(cleanEnv)
@@ -266,6 +272,58 @@ var _ = Describe("Upgrade OpsRequest", func() {
 			expectOpsSucceed(reqCtx, opsRes, defaultCompName)
 		})
 
+		It("Test upgrade OpsRequest with auto-rollback", func() {
+			By("init operations resources")
+			compDef1, compDef2, opsRes := initOpsResWithComponentDef(false)
+			comp := testapps.NewComponentFactory(testCtx.DefaultNamespace,
+				constant.GenerateClusterComponentName(clusterName, defaultCompName), compDef2.Name).
+				Create(&testCtx).GetObject()
+
+			By("create Upgrade Ops with auto-rollback")
+			opsRes.OpsRequest = createUpgradeOpsRequest(opsRes.Cluster, opsv1alpha1.Upgrade{
+				Components: []opsv1alpha1.UpgradeComponent{
+					{
+						ComponentOps:            opsv1alpha1.ComponentOps{ComponentName: defaultCompName},
+						ComponentDefinitionName: &compDef2.Name,
+					},
+				},
+				AutoRollback: &opsv1alpha1.UpgradeAutoRollback{AvailableTimeoutSeconds: 60},
+			})
+			reqCtx := intctrlutil.RequestCtx{Ctx: ctx}
+			makeUpgradeOpsIsRunning(reqCtx, opsRes)
+
+			By("expect the ops to wait for the component to become available")
+			mockPodsAppliedImage(opsRes.Cluster, release2)
+			mockComponentIsOperating(opsRes.Cluster, appsv1.RunningComponentPhase, defaultCompName)
+			Expect(testapps.ChangeObjStatus(&testCtx, comp, func() {
+				meta.SetStatusCondition(&comp.Status.Conditions, metav1.Condition{
+					Type:               appsv1.ConditionTypeAvailable,
+					Status:             metav1.ConditionFalse,
+					ObservedGeneration: comp.Generation,
+					Reason:             "Unavailable",
+					Message:            "the component crashes",
+				})
+			})).Should(Succeed())
+			_, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testops.GetOpsRequestPhase(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest))).Should(Equal(opsv1alpha1.OpsRunningPhase))
+
+			By("expect the component to be rolled back after the deadline")
+			Expect(testapps.ChangeObjStatus(&testCtx, opsRes.OpsRequest, func() {
+				opsRes.OpsRequest.Status.StartTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			})).Should(Succeed())
+			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.OpsRequest), func(g Gomega, ops *opsv1alpha1.OpsRequest) {
+				g.Expect(ops.Status.Phase).Should(Equal(opsv1alpha1.OpsFailedPhase))
+				g.Expect(meta.IsStatusConditionTrue(ops.Status.Conditions, opsv1alpha1.ConditionTypeRolledBack)).Should(BeTrue())
+				g.Expect(ops.Status.Components[defaultCompName].Message).Should(ContainSubstring("the component crashes"))
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsRes.Cluster), func(g Gomega, cluster *appsv1.Cluster) {
+				g.Expect(cluster.Spec.ComponentSpecs[0].ComponentDef).Should(Equal(compDef1.Name))
+			})).Should(Succeed())
+		})
+
 		It("Test upgrade OpsRequest with ComponentDef and ComponentVersion", func() {
 			By("init operations resources")
 			_, compDef2, opsRes := initOpsResWithComponentDef(true)
@@ -32,6 +32,9 @@ import (
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/constant"
 )
 
+// OpsRequestQueueLimitSize is the max number of OpsRequests in the queue of a cluster.
+const OpsRequestQueueLimitSize = 20
+
 func SetOpsRequestToCluster(cluster *appsv1.Cluster, opsRequestSlice []opsv1alpha1.OpsRecorder) {
 	if cluster.Annotations == nil {
 		cluster.Annotations = map[string]string{}
@@ -46,6 +46,7 @@ func init() {
 		OpsHandler:        vsHandler,
 		QueueByCluster:    true,
 		CancelFunc:        vsHandler.Cancel,
+		IsDisruptive:      alwaysDisruptive,
 	}
 
 	opsMgr := GetOpsManager()
@@ -180,6 +180,10 @@ func MockKBAgentContainer() corev1.Container {
 				Name:          kbagent.DefaultStreamingPortName,
 				ContainerPort: kbagent.DefaultStreamingPort,
 			},
+			{
+				Name:          kbagent.DefaultGRPCPortName,
+				ContainerPort: kbagent.DefaultGRPCPort,
+			},
 		},
 	}
 }